@@ -117,6 +117,93 @@ func TestDesirePaths_RewriteFields_DoesNotRewriteAfterDoubleDash(t *testing.T) {
 	}
 }
 
+func TestDesirePaths_RewriteOutputFormat(t *testing.T) {
+	parser, _, err := newParser("test")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+	cases := []struct {
+		in   []string
+		want []string
+	}{
+		{
+			in:   []string{"drive", "ls", "--output", "csv"},
+			want: []string{"drive", "ls", "--output-format=csv"},
+		},
+		{
+			in:   []string{"--output=yaml", "tasks", "lists"},
+			want: []string{"--output-format=yaml", "tasks", "lists"},
+		},
+		{
+			in:   []string{"gmail", "attachment", "m1", "a1", "--output", "./file.pdf"},
+			want: []string{"gmail", "attachment", "m1", "a1", "--output", "./file.pdf"},
+		},
+		{
+			in:   []string{"open", "--", "--output", "csv"},
+			want: []string{"open", "--", "--output", "csv"},
+		},
+		// Commands with their own --output path keep it, even for format names.
+		{
+			in:   []string{"-a", "me@example.com", "cal", "export", "--output", "csv"},
+			want: []string{"-a", "me@example.com", "cal", "export", "--output", "csv"},
+		},
+		{
+			in:   []string{"gmail", "thread", "export", "t1", "--output=md"},
+			want: []string{"gmail", "thread", "export", "t1", "--output=md"},
+		},
+		{
+			in:   []string{"gmail", "attachment", "m1", "a1", "--output", "yaml"},
+			want: []string{"gmail", "attachment", "m1", "a1", "--output", "yaml"},
+		},
+	}
+	for _, tc := range cases {
+		if got := rewriteOutputFormatArgs(tc.in, parser.Model); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("unexpected rewrite: got=%v want=%v", got, tc.want)
+		}
+	}
+}
+
+func TestDesirePaths_RewriteTimeout(t *testing.T) {
+	parser, _, err := newParser("test")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+	cases := []struct {
+		in   []string
+		want []string
+	}{
+		{
+			in:   []string{"drive", "ls", "--timeout", "2m"},
+			want: []string{"drive", "ls", "--http-timeout", "2m"},
+		},
+		{
+			in:   []string{"--timeout=90s", "gmail", "search", "x"},
+			want: []string{"--http-timeout=90s", "gmail", "search", "x"},
+		},
+		{
+			in:   []string{"auth", "add", "me@example.com", "--timeout", "5m"},
+			want: []string{"auth", "add", "me@example.com", "--timeout", "5m"},
+		},
+		{
+			in:   []string{"-a", "me@example.com", "login", "--timeout=5m"},
+			want: []string{"-a", "me@example.com", "login", "--timeout=5m"},
+		},
+		{
+			in:   []string{"auth", "status", "--timeout", "5m"},
+			want: []string{"auth", "status", "--http-timeout", "5m"},
+		},
+		{
+			in:   []string{"auth", "manage", "--timeout=1m"},
+			want: []string{"auth", "manage", "--timeout=1m"},
+		},
+	}
+	for _, tc := range cases {
+		if got := rewriteTimeoutArgs(tc.in, parser.Model); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("unexpected rewrite: got=%v want=%v", got, tc.want)
+		}
+	}
+}
+
 func TestDesirePaths_RewriteFields_KeepsCalendarEventsAlias(t *testing.T) {
 	in := []string{"-a", "foo@example.com", "cal", "ls", "--fields", "items(id)"}
 	got := rewriteDesirePathArgs(in)
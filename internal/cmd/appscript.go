@@ -23,7 +23,7 @@ type AppScriptCmd struct {
 }
 
 type AppScriptGetCmd struct {
-	ScriptID string `arg:"" name:"scriptId" help:"Script ID"`
+	ScriptID string `arg:"" stdin:"" name:"scriptId" help:"Script ID"`
 }
 
 func (c *AppScriptGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -71,7 +71,7 @@ func (c *AppScriptGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type AppScriptContentCmd struct {
-	ScriptID string `arg:"" name:"scriptId" help:"Script ID"`
+	ScriptID string `arg:"" stdin:"" name:"scriptId" help:"Script ID"`
 }
 
 func (c *AppScriptContentCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -112,7 +112,7 @@ func (c *AppScriptContentCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type AppScriptRunCmd struct {
-	ScriptID string `arg:"" name:"scriptId" help:"Script ID"`
+	ScriptID string `arg:"" stdin:"" name:"scriptId" help:"Script ID"`
 	Function string `arg:"" name:"function" help:"Function name to run"`
 	Params   string `name:"params" help:"JSON array of function parameters" default:"[]"`
 	DevMode  bool   `name:"dev-mode" help:"Run latest saved code if you own the script"`
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+var openHTTPCache = googleapi.OpenDiskCache
+
+type CacheCmd struct {
+	Stats CacheStatsCmd `cmd:"" aliases:"info,status" help:"Show cache size and entry counts"`
+	Clear CacheClearCmd `cmd:"" aliases:"purge,rm" help:"Remove cached responses (all accounts, or --account only)"`
+}
+
+type CacheStatsCmd struct{}
+
+func (c *CacheStatsCmd) Run(ctx context.Context) error {
+	cache, err := openHTTPCache()
+	if err != nil {
+		return err
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, stats)
+	}
+
+	u := ui.FromContext(ctx)
+	u.Out().Printf("dir\t%s", stats.Dir)
+	u.Out().Printf("entries\t%d", stats.Entries)
+	u.Out().Printf("bytes\t%d", stats.Bytes)
+	if stats.Oldest != nil {
+		u.Out().Printf("oldest\t%s", stats.Oldest.Local().Format(time.RFC3339))
+	}
+	if stats.Newest != nil {
+		u.Out().Printf("newest\t%s", stats.Newest.Local().Format(time.RFC3339))
+	}
+
+	accounts := make([]string, 0, len(stats.Accounts))
+	for account := range stats.Accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	for _, account := range accounts {
+		u.Out().Printf("account\t%s\t%d", account, stats.Accounts[account])
+	}
+
+	return nil
+}
+
+type CacheClearCmd struct{}
+
+func (c *CacheClearCmd) Run(ctx context.Context, flags *RootFlags) error {
+	// Only an explicit --account narrows the clear; there is no default-account fallback here.
+	account := strings.TrimSpace(flags.Account)
+	if account != "" {
+		if resolved, ok, err := resolveAccountAlias(account); err != nil {
+			return err
+		} else if ok {
+			account = resolved
+		}
+	}
+
+	cache, err := openHTTPCache()
+	if err != nil {
+		return err
+	}
+
+	if err := dryRunExit(ctx, flags, "cache.clear", map[string]any{
+		"dir":     cache.Dir,
+		"account": account,
+	}); err != nil {
+		return err
+	}
+
+	removed, err := cache.Clear(account)
+	if err != nil {
+		return err
+	}
+
+	return writeResult(ctx, ui.FromContext(ctx),
+		kv("cleared", true),
+		kv("removed", removed),
+		kv("account", account),
+	)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestCacheCmd_StatsAndClear(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	cache, err := googleapi.OpenDiskCache()
+	if err != nil {
+		t.Fatalf("open cache: %v", err)
+	}
+	for _, account := range []string{"a@b.com", "c@d.com"} {
+		rt := googleapi.NewCacheTransport(srv.Client().Transport, cache, account, time.Hour)
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/gmail/v1/users/me/labels", nil)
+		resp, rtErr := rt.RoundTrip(req)
+		if rtErr != nil {
+			t.Fatalf("round trip: %v", rtErr)
+		}
+		_ = resp.Body.Close()
+	}
+
+	out := captureStdout(t, func() {
+		if execErr := Execute([]string{"--json", "cache", "stats"}); execErr != nil {
+			t.Fatalf("stats: %v", execErr)
+		}
+	})
+	var stats googleapi.CacheStats
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("parse stats: %v\nout=%q", err, out)
+	}
+	if stats.Entries != 2 || stats.Accounts["a@b.com"] != 1 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+
+	out = captureStdout(t, func() {
+		if execErr := Execute([]string{"--json", "--account", "a@b.com", "cache", "clear"}); execErr != nil {
+			t.Fatalf("clear: %v", execErr)
+		}
+	})
+	if !strings.Contains(out, `"removed": 1`) {
+		t.Fatalf("unexpected clear output: %q", out)
+	}
+
+	out = captureStdout(t, func() {
+		if execErr := Execute([]string{"--plain", "cache", "stats"}); execErr != nil {
+			t.Fatalf("stats: %v", execErr)
+		}
+	})
+	if !strings.Contains(out, "entries\t1") || !strings.Contains(out, "account\tc@d.com\t1") {
+		t.Fatalf("unexpected plain stats: %q", out)
+	}
+}
+
+func TestExecute_InvalidCacheTTL(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--cache-ttl", "soon", "cache", "stats"})
+		if ExitCode(err) != 2 {
+			t.Fatalf("expected usage exit 2, got %v", err)
+		}
+	})
+}
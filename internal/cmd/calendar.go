@@ -20,7 +20,13 @@ type CalendarCmd struct {
 	Create          CalendarCreateCmd          `cmd:"" name:"create" aliases:"add,new" help:"Create an event"`
 	Update          CalendarUpdateCmd          `cmd:"" name:"update" aliases:"edit,set" help:"Update an event"`
 	Delete          CalendarDeleteCmd          `cmd:"" name:"delete" aliases:"rm,del,remove" help:"Delete an event"`
+	Export          CalendarExportCmd          `cmd:"" name:"export" help:"Export events as iCalendar (.ics)"`
+	Import          CalendarImportCmd          `cmd:"" name:"import" help:"Import events from an iCalendar (.ics) file"`
+	Apply           CalendarApplyCmd           `cmd:"" name:"apply" help:"Create, update and delete events to match a YAML file"`
+	Changes         CalendarChangesCmd         `cmd:"" name:"changes" help:"List events created, updated or cancelled since the last run"`
+	Watch           CalendarWatchCmd           `cmd:"" name:"watch" help:"Push notifications for calendar changes, forwarded to a webhook"`
 	FreeBusy        CalendarFreeBusyCmd        `cmd:"" name:"freebusy" help:"Get free/busy"`
+	FindTime        CalendarFindTimeCmd        `cmd:"" name:"find-time" aliases:"findtime,slots" help:"Find free slots for all attendees and optionally book one"`
 	Respond         CalendarRespondCmd         `cmd:"" name:"respond" aliases:"rsvp,reply" help:"Respond to an event invitation"`
 	ProposeTime     CalendarProposeTimeCmd     `cmd:"" name:"propose-time" help:"Generate URL to propose a new meeting time (browser-only feature)"`
 	Colors          CalendarColorsCmd          `cmd:"" name:"colors" help:"Show calendar colors"`
@@ -65,6 +71,10 @@ func (c *CalendarCalendarsCmd) Run(ctx context.Context, flags *RootFlags) error
 		return r.Items, r.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var items []*calendar.CalendarListEntry
 	nextPageToken := ""
 	if c.All {
@@ -108,7 +118,7 @@ func (c *CalendarCalendarsCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type CalendarAclCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID string `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
 	Max        int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page       string `name:"page" aliases:"cursor" help:"Page token"`
 	All        bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
@@ -147,6 +157,10 @@ func (c *CalendarAclCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return r.Items, r.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var items []*calendar.AclRule
 	nextPageToken := ""
 	if c.All {
@@ -196,7 +210,7 @@ func (c *CalendarAclCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type CalendarEventsCmd struct {
-	CalendarID        string   `arg:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+	CalendarID        string   `arg:"" stdin:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
 	Cal               []string `name:"cal" help:"Calendar ID or name (can be repeated)"`
 	Calendars         string   `name:"calendars" help:"Comma-separated calendar IDs, names, or indices from 'calendar calendars'"`
 	From              string   `name:"from" help:"Start time (RFC3339, date, or relative: today, tomorrow, monday)"`
@@ -283,8 +297,8 @@ func (c *CalendarEventsCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type CalendarEventCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
-	EventID    string `arg:"" name:"eventId" help:"Event ID"`
+	CalendarID string `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
+	EventID    string `arg:"" stdin:"" name:"eventId" help:"Event ID"`
 }
 
 func (c *CalendarEventCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -0,0 +1,585 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	gapi "google.golang.org/api/googleapi"
+	"gopkg.in/yaml.v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Events as declarative config: `apply` converges a calendar to a file. Each
+// managed event carries its file id in a private extended property (--key), so
+// edits update it in place and entries removed from the file are deleted.
+
+const defaultCalendarApplyKey = "gogApplyId"
+
+type calendarApplyFile struct {
+	TimeZone string              `yaml:"timeZone,omitempty" json:"timeZone,omitempty"`
+	Events   []calendarEventSpec `yaml:"events" json:"events"`
+}
+
+type calendarEventSpec struct {
+	ID           string            `yaml:"id" json:"id"`
+	Summary      string            `yaml:"summary" json:"summary"`
+	Description  string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Location     string            `yaml:"location,omitempty" json:"location,omitempty"`
+	Start        string            `yaml:"start" json:"start"`
+	End          string            `yaml:"end,omitempty" json:"end,omitempty"`
+	TimeZone     string            `yaml:"timeZone,omitempty" json:"timeZone,omitempty"`
+	Attendees    []string          `yaml:"attendees,omitempty" json:"attendees,omitempty"`
+	Recurrence   []string          `yaml:"recurrence,omitempty" json:"recurrence,omitempty"`
+	Reminders    []string          `yaml:"reminders,omitempty" json:"reminders,omitempty"`
+	Color        string            `yaml:"color,omitempty" json:"color,omitempty"`
+	Visibility   string            `yaml:"visibility,omitempty" json:"visibility,omitempty"`
+	Transparency string            `yaml:"transparency,omitempty" json:"transparency,omitempty"`
+	PrivateProps map[string]string `yaml:"privateProps,omitempty" json:"privateProps,omitempty"`
+	SharedProps  map[string]string `yaml:"sharedProps,omitempty" json:"sharedProps,omitempty"`
+}
+
+type CalendarApplyCmd struct {
+	File        string `arg:"" name:"file" help:"Events file (YAML or JSON, '-' for stdin)"`
+	Calendar    string `name:"calendar" help:"Calendar ID" default:"primary"`
+	Key         string `name:"key" help:"Private extended property holding each event's file id" default:"gogApplyId"`
+	KeepExtra   bool   `name:"keep-extra" help:"Do not delete managed events that are missing from the file"`
+	SendUpdates string `name:"send-updates" help:"Notification mode: all, externalOnly, none (default: none)"`
+}
+
+type calendarApplyChange struct {
+	ID       string          `json:"id"`
+	EventID  string          `json:"eventId,omitempty"`
+	Summary  string          `json:"summary,omitempty"`
+	Start    string          `json:"start,omitempty"`
+	Fields   []string        `json:"fields,omitempty"`
+	Event    *calendar.Event `json:"event,omitempty"`
+	existing *calendar.Event
+}
+
+type calendarApplyPlan struct {
+	Create    []*calendarApplyChange `json:"create"`
+	Update    []*calendarApplyChange `json:"update"`
+	Delete    []*calendarApplyChange `json:"delete"`
+	Unchanged int                    `json:"unchanged"`
+}
+
+func (c *CalendarApplyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	file, err := readCalendarApplyFile(c.File)
+	if err != nil {
+		return err
+	}
+	key := strings.TrimSpace(c.Key)
+	if key == "" {
+		return usage("empty --key")
+	}
+	sendUpdates, err := validateSendUpdates(c.SendUpdates)
+	if err != nil {
+		return err
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID, err := resolveCalendarID(ctx, svc, c.Calendar)
+	if err != nil {
+		return err
+	}
+	if calendarID == "" {
+		return usage("empty --calendar")
+	}
+
+	tz := strings.TrimSpace(file.TimeZone)
+	if tz == "" {
+		if tz, _, err = getCalendarLocation(ctx, svc, calendarID); err != nil {
+			return err
+		}
+	}
+	desired := make([]*calendar.Event, len(file.Events))
+	for i, spec := range file.Events {
+		if desired[i], err = spec.toEvent(key, tz); err != nil {
+			return usagef("event %q: %v", spec.ID, err)
+		}
+	}
+
+	existing, err := listCalendarApplyEvents(ctx, svc, calendarID, key)
+	if err != nil {
+		return err
+	}
+	plan := planCalendarApply(desired, existing, key)
+	if c.KeepExtra {
+		plan.Delete = nil
+	}
+
+	// Dry runs print the plan; JSON mode gets it as the request payload.
+	var planPayload any
+	if outfmt.IsJSON(ctx) {
+		planPayload = map[string]any{"calendarId": calendarID, "plan": plan}
+	} else if flags != nil && flags.DryRun {
+		printCalendarApplyPlan(u, plan)
+	}
+	if dryRunErr := dryRunExit(ctx, flags, "calendar.apply", planPayload); dryRunErr != nil {
+		return dryRunErr
+	}
+	if len(plan.Delete) > 0 {
+		if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("delete %d calendar event(s) not in %s", len(plan.Delete), c.File)); confirmErr != nil {
+			return confirmErr
+		}
+	}
+
+	for _, ch := range plan.Create {
+		call := svc.Events.Insert(calendarID, ch.Event).Context(ctx)
+		if sendUpdates != "" {
+			call = call.SendUpdates(sendUpdates)
+		}
+		created, createErr := call.Do()
+		if createErr != nil {
+			return fmt.Errorf("create event %q: %w", ch.ID, createErr)
+		}
+		ch.EventID = created.Id
+		if !outfmt.IsJSON(ctx) {
+			u.Out().Printf("+ %s\t%s\t%s", ch.ID, created.Id, sanitizeTab(ch.Summary))
+		}
+	}
+	for _, ch := range plan.Update {
+		call := svc.Events.Update(calendarID, ch.EventID, mergeCalendarApplyEvent(ch.existing, ch.Event)).Context(ctx)
+		if sendUpdates != "" {
+			call = call.SendUpdates(sendUpdates)
+		}
+		if _, updateErr := call.Do(); updateErr != nil {
+			return fmt.Errorf("update event %q (%s): %w", ch.ID, ch.EventID, updateErr)
+		}
+		if !outfmt.IsJSON(ctx) {
+			u.Out().Printf("~ %s\t%s\t%s", ch.ID, ch.EventID, strings.Join(ch.Fields, ","))
+		}
+	}
+	for _, ch := range plan.Delete {
+		call := svc.Events.Delete(calendarID, ch.EventID).Context(ctx)
+		if sendUpdates != "" {
+			call = call.SendUpdates(sendUpdates)
+		}
+		if delErr := call.Do(); delErr != nil && !isNotFoundAPIError(delErr) && !isGoneAPIError(delErr) {
+			return fmt.Errorf("delete event %q (%s): %w", ch.ID, ch.EventID, delErr)
+		}
+		if !outfmt.IsJSON(ctx) {
+			u.Out().Printf("- %s\t%s\t%s", ch.ID, ch.EventID, sanitizeTab(ch.Summary))
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"calendarId": calendarID,
+			"created":    calendarApplyIDs(plan.Create),
+			"updated":    calendarApplyIDs(plan.Update),
+			"deleted":    calendarApplyIDs(plan.Delete),
+			"unchanged":  plan.Unchanged,
+		})
+	}
+	u.Err().Printf("%d created, %d updated, %d deleted, %d unchanged", len(plan.Create), len(plan.Update), len(plan.Delete), plan.Unchanged)
+	return nil
+}
+
+func isGoneAPIError(err error) bool {
+	var gerr *gapi.Error
+	return errors.As(err, &gerr) && gerr.Code == http.StatusGone
+}
+
+func calendarApplyIDs(changes []*calendarApplyChange) []map[string]string {
+	out := make([]map[string]string, 0, len(changes))
+	for _, ch := range changes {
+		out = append(out, map[string]string{"id": ch.ID, "eventId": ch.EventID})
+	}
+	return out
+}
+
+// listCalendarApplyEvents returns the series and single events carrying key.
+// The API can only filter on key=value, so the whole calendar is scanned.
+func listCalendarApplyEvents(ctx context.Context, svc *calendar.Service, calendarID, key string) ([]*calendar.Event, error) {
+	all, err := collectAllPages("", func(pageToken string) ([]*calendar.Event, string, error) {
+		call := svc.Events.List(calendarID).SingleEvents(false).MaxResults(2500).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	managed := make([]*calendar.Event, 0, len(all))
+	for _, e := range all {
+		if e.RecurringEventId == "" && calendarApplyID(e, key) != "" {
+			managed = append(managed, e)
+		}
+	}
+	return managed, nil
+}
+
+func calendarApplyID(e *calendar.Event, key string) string {
+	if e.ExtendedProperties == nil {
+		return ""
+	}
+	return e.ExtendedProperties.Private[key]
+}
+
+// planCalendarApply matches desired events to existing ones by their key
+// property. Existing duplicates of an id collapse to the first one.
+func planCalendarApply(desired, existing []*calendar.Event, key string) calendarApplyPlan {
+	plan := calendarApplyPlan{
+		Create: []*calendarApplyChange{},
+		Update: []*calendarApplyChange{},
+		Delete: []*calendarApplyChange{},
+	}
+
+	current := make(map[string]*calendar.Event, len(existing))
+	for _, e := range existing {
+		id := calendarApplyID(e, key)
+		if _, dup := current[id]; dup {
+			plan.Delete = append(plan.Delete, newCalendarApplyChange(id, e))
+			continue
+		}
+		current[id] = e
+	}
+
+	for _, want := range desired {
+		id := calendarApplyID(want, key)
+		have, ok := current[id]
+		if !ok {
+			plan.Create = append(plan.Create, &calendarApplyChange{ID: id, Summary: want.Summary, Start: eventStart(want), Event: want})
+			continue
+		}
+		delete(current, id)
+		fields := calendarApplyDiff(calendarApplyState(want), calendarApplyState(have))
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		ch := newCalendarApplyChange(id, have)
+		ch.Fields, ch.Event = fields, want
+		plan.Update = append(plan.Update, ch)
+	}
+
+	for _, e := range existing {
+		id := calendarApplyID(e, key)
+		if current[id] == e {
+			plan.Delete = append(plan.Delete, newCalendarApplyChange(id, e))
+		}
+	}
+	return plan
+}
+
+func newCalendarApplyChange(id string, e *calendar.Event) *calendarApplyChange {
+	return &calendarApplyChange{ID: id, EventID: e.Id, Summary: e.Summary, Start: eventStart(e), existing: e}
+}
+
+// calendarApplyState flattens the fields a file manages into comparable
+// strings, normalizing API defaults (e.g. opaque, default reminders).
+func calendarApplyState(e *calendar.Event) map[string]string {
+	state := map[string]string{
+		"summary":      strings.TrimSpace(e.Summary),
+		"description":  strings.TrimSpace(e.Description),
+		"location":     strings.TrimSpace(e.Location),
+		"start":        calendarApplyTime(e.Start),
+		"end":          calendarApplyTime(e.End),
+		"recurrence":   strings.Join(e.Recurrence, "\n"),
+		"color":        e.ColorId,
+		"visibility":   strings.TrimPrefix(e.Visibility, "default"),
+		"transparency": strings.TrimPrefix(e.Transparency, transparencyOpaque),
+	}
+
+	var attendees []string
+	for _, a := range e.Attendees {
+		if a == nil || (a.Organizer && a.Self) {
+			continue
+		}
+		entry := strings.ToLower(strings.TrimSpace(a.Email))
+		if a.Optional {
+			entry += ";optional"
+		}
+		attendees = append(attendees, entry)
+	}
+	sort.Strings(attendees)
+	state["attendees"] = strings.Join(attendees, ",")
+
+	if e.Reminders != nil && !e.Reminders.UseDefault {
+		reminders := make([]string, 0, len(e.Reminders.Overrides))
+		for _, r := range e.Reminders.Overrides {
+			reminders = append(reminders, fmt.Sprintf("%s:%d", r.Method, r.Minutes))
+		}
+		sort.Strings(reminders)
+		state["reminders"] = strings.Join(reminders, ",")
+	}
+
+	if p := e.ExtendedProperties; p != nil {
+		if len(p.Private) > 0 {
+			data, _ := json.Marshal(p.Private)
+			state["privateProps"] = string(data)
+		}
+		if len(p.Shared) > 0 {
+			data, _ := json.Marshal(p.Shared)
+			state["sharedProps"] = string(data)
+		}
+	}
+	return state
+}
+
+// calendarApplyTime compares timed events by instant and zone, so a local
+// time from the file matches the offset form the API returns.
+func calendarApplyTime(dt *calendar.EventDateTime) string {
+	if dt == nil {
+		return ""
+	}
+	if dt.Date != "" {
+		return dt.Date
+	}
+	t, err := time.Parse(time.RFC3339, dt.DateTime)
+	if err != nil {
+		loc, locErr := time.LoadLocation(dt.TimeZone)
+		if locErr != nil {
+			return dt.DateTime + " " + dt.TimeZone
+		}
+		if t, err = parseLocalDateTime(dt.DateTime, loc); err != nil {
+			return dt.DateTime + " " + dt.TimeZone
+		}
+	}
+	return t.UTC().Format(time.RFC3339) + " " + dt.TimeZone
+}
+
+func calendarApplyDiff(want, have map[string]string) []string {
+	var fields []string
+	for k, v := range want {
+		if have[k] != v {
+			fields = append(fields, k)
+		}
+	}
+	for k := range have {
+		if _, ok := want[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// mergeCalendarApplyEvent applies the managed fields to the calendar's copy,
+// keeping attendee responses and everything the file does not describe.
+func mergeCalendarApplyEvent(existing, want *calendar.Event) *calendar.Event {
+	merged := *existing
+	merged.Summary = want.Summary
+	merged.Description = want.Description
+	merged.Location = want.Location
+	merged.Start = want.Start
+	merged.End = want.End
+	merged.Recurrence = want.Recurrence
+	merged.ColorId = want.ColorId
+	merged.Visibility = want.Visibility
+	merged.Transparency = want.Transparency
+	merged.ExtendedProperties = want.ExtendedProperties
+	merged.Reminders = want.Reminders
+	if merged.Reminders == nil {
+		merged.Reminders = &calendar.EventReminders{UseDefault: true}
+	}
+
+	previous := make(map[string]*calendar.EventAttendee, len(existing.Attendees))
+	var attendees []*calendar.EventAttendee
+	for _, a := range existing.Attendees {
+		if a == nil {
+			continue
+		}
+		if a.Organizer && a.Self {
+			attendees = append(attendees, a)
+			continue
+		}
+		previous[strings.ToLower(a.Email)] = a
+	}
+	for _, a := range want.Attendees {
+		if prev, ok := previous[strings.ToLower(a.Email)]; ok {
+			kept := *prev
+			kept.Optional = a.Optional
+			attendees = append(attendees, &kept)
+			continue
+		}
+		attendees = append(attendees, a)
+	}
+	merged.Attendees = attendees
+	return &merged
+}
+
+// toEvent builds the API event. Date-only start/end make an all-day event;
+// times without an offset are local to timeZone (event, file, or calendar).
+func (s calendarEventSpec) toEvent(key, defaultTZ string) (*calendar.Event, error) {
+	tz := strings.TrimSpace(s.TimeZone)
+	if tz == "" {
+		tz = defaultTZ
+	}
+	start, err := calendarApplyDateTime(s.Start, tz)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	endValue := strings.TrimSpace(s.End)
+	if endValue == "" && start.Date != "" {
+		day, _ := time.Parse("2006-01-02", start.Date)
+		endValue = day.AddDate(0, 0, 1).Format("2006-01-02")
+	}
+	if endValue == "" {
+		return nil, errors.New("missing end")
+	}
+	end, err := calendarApplyDateTime(endValue, tz)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+	if (start.Date == "") != (end.Date == "") {
+		return nil, errors.New("start and end must both be dates or both be times")
+	}
+
+	color, err := validateColorId(s.Color)
+	if err != nil {
+		return nil, err
+	}
+	visibility, err := validateVisibility(s.Visibility)
+	if err != nil {
+		return nil, err
+	}
+	transparency, err := validateTransparency(s.Transparency)
+	if err != nil {
+		return nil, err
+	}
+	reminders, err := buildReminders(s.Reminders)
+	if err != nil {
+		return nil, err
+	}
+
+	private := make([]string, 0, len(s.PrivateProps)+1)
+	for k, v := range s.PrivateProps {
+		private = append(private, k+"="+v)
+	}
+	private = append(private, key+"="+s.ID)
+	shared := make([]string, 0, len(s.SharedProps))
+	for k, v := range s.SharedProps {
+		shared = append(shared, k+"="+v)
+	}
+
+	return &calendar.Event{
+		Summary:            strings.TrimSpace(s.Summary),
+		Description:        strings.TrimSpace(s.Description),
+		Location:           strings.TrimSpace(s.Location),
+		Start:              start,
+		End:                end,
+		Attendees:          buildAttendees(strings.Join(s.Attendees, ",")),
+		Recurrence:         buildRecurrence(s.Recurrence),
+		Reminders:          reminders,
+		ColorId:            color,
+		Visibility:         visibility,
+		Transparency:       transparency,
+		ExtendedProperties: buildExtendedProperties(private, shared),
+	}, nil
+}
+
+func calendarApplyDateTime(value, tz string) (*calendar.EventDateTime, error) {
+	value = strings.TrimSpace(value)
+	if _, err := time.Parse("2006-01-02", value); err == nil {
+		return &calendar.EventDateTime{Date: value}, nil
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return &calendar.EventDateTime{DateTime: value, TimeZone: tz}, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeZone %q", tz)
+	}
+	t, err := parseLocalDateTime(value, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &calendar.EventDateTime{DateTime: t.Format(time.RFC3339), TimeZone: tz}, nil
+}
+
+func parseLocalDateTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use YYYY-MM-DD, YYYY-MM-DDTHH:MM, or RFC3339)", value)
+}
+
+func printCalendarApplyPlan(u *ui.UI, plan calendarApplyPlan) {
+	if u == nil {
+		return
+	}
+	for _, ch := range plan.Create {
+		u.Out().Printf("+ event\t%s\t%s\t%s", ch.ID, ch.Start, sanitizeTab(ch.Summary))
+	}
+	for _, ch := range plan.Update {
+		u.Out().Printf("~ event\t%s\t%s\t%s", ch.ID, ch.EventID, strings.Join(ch.Fields, ","))
+	}
+	for _, ch := range plan.Delete {
+		u.Out().Printf("- event\t%s\t%s\t%s", ch.ID, ch.EventID, sanitizeTab(ch.Summary))
+	}
+	u.Err().Printf("%d to create, %d to update, %d to delete, %d unchanged", len(plan.Create), len(plan.Update), len(plan.Delete), plan.Unchanged)
+}
+
+func readCalendarApplyFile(path string) (calendarApplyFile, error) {
+	var file calendarApplyFile
+	path = strings.TrimSpace(path)
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = config.ExpandPath(path)
+		if err != nil {
+			return file, err
+		}
+		data, err = os.ReadFile(path) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return file, err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if decErr := dec.Decode(&file); decErr != nil && !errors.Is(decErr, io.EOF) {
+		return file, usagef("parse %s: %v", path, decErr)
+	}
+
+	seen := map[string]bool{}
+	for i, spec := range file.Events {
+		id := strings.TrimSpace(spec.ID)
+		switch {
+		case id == "":
+			return file, usagef("event %d: missing id", i+1)
+		case seen[id]:
+			return file, usagef("event %d: duplicate id %q", i+1, id)
+		case strings.TrimSpace(spec.Start) == "":
+			return file, usagef("event %q: missing start", id)
+		}
+		seen[id] = true
+		file.Events[i].ID = id
+	}
+	return file, nil
+}
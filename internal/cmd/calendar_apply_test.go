@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestCalendarEventSpecToEvent(t *testing.T) {
+	e, err := calendarEventSpec{
+		ID:        "standup",
+		Summary:   "Standup",
+		Start:     "2030-01-07T09:30",
+		End:       "2030-01-07T09:45",
+		Attendees: []string{"a@b.com", "c@d.com;optional"},
+		Reminders: []string{"popup:10m"},
+	}.toEvent("k", "Europe/Berlin")
+	if err != nil {
+		t.Fatalf("toEvent: %v", err)
+	}
+	if e.Start.DateTime != "2030-01-07T09:30:00+01:00" || e.Start.TimeZone != "Europe/Berlin" || e.ExtendedProperties.Private["k"] != "standup" {
+		t.Fatalf("unexpected event: %+v %+v", e.Start, e.ExtendedProperties)
+	}
+	if len(e.Attendees) != 2 || !e.Attendees[1].Optional || e.Reminders.Overrides[0].Minutes != 10 {
+		t.Fatalf("unexpected attendees/reminders: %+v %+v", e.Attendees, e.Reminders)
+	}
+
+	allDay, err := calendarEventSpec{ID: "off", Summary: "Off", Start: "2030-01-07"}.toEvent("k", "UTC")
+	if err != nil || allDay.Start.Date != "2030-01-07" || allDay.End.Date != "2030-01-08" {
+		t.Fatalf("unexpected all-day event: %+v %+v (%v)", allDay.Start, allDay.End, err)
+	}
+
+	for _, bad := range []calendarEventSpec{
+		{ID: "x", Start: "2030-01-07T09:00"},
+		{ID: "x", Start: "2030-01-07", End: "2030-01-07T10:00"},
+		{ID: "x", Start: "tomorrow", End: "2030-01-07T10:00"},
+		{ID: "x", Start: "2030-01-07T09:00", End: "2030-01-07T10:00", Color: "99"},
+	} {
+		if _, err := bad.toEvent("k", "UTC"); err == nil {
+			t.Fatalf("expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestPlanCalendarApply(t *testing.T) {
+	spec := func(id, summary string) *calendar.Event {
+		e, err := calendarEventSpec{ID: id, Summary: summary, Start: "2030-01-07T09:00", End: "2030-01-07T10:00"}.toEvent("k", "UTC")
+		if err != nil {
+			t.Fatalf("toEvent: %v", err)
+		}
+		return e
+	}
+	existing := func(eventID, id, summary string) *calendar.Event {
+		return &calendar.Event{
+			Id:                 eventID,
+			Summary:            summary,
+			Start:              &calendar.EventDateTime{DateTime: "2030-01-07T09:00:00Z", TimeZone: "UTC"},
+			End:                &calendar.EventDateTime{DateTime: "2030-01-07T10:00:00Z", TimeZone: "UTC"},
+			Transparency:       "opaque",
+			Reminders:          &calendar.EventReminders{UseDefault: true},
+			Attendees:          []*calendar.EventAttendee{{Email: "me@b.com", Organizer: true, Self: true}},
+			ExtendedProperties: &calendar.EventExtendedProperties{Private: map[string]string{"k": id}},
+		}
+	}
+
+	plan := planCalendarApply(
+		[]*calendar.Event{spec("same", "Same"), spec("edit", "New title"), spec("new", "New")},
+		[]*calendar.Event{existing("e1", "same", "Same"), existing("e2", "edit", "Old title"), existing("e3", "gone", "Gone"), existing("e4", "same", "Same")},
+		"k",
+	)
+	if plan.Unchanged != 1 || len(plan.Create) != 1 || plan.Create[0].ID != "new" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if len(plan.Update) != 1 || plan.Update[0].EventID != "e2" || strings.Join(plan.Update[0].Fields, ",") != "summary" {
+		t.Fatalf("unexpected update: %+v", plan.Update)
+	}
+	var deleted []string
+	for _, ch := range plan.Delete {
+		deleted = append(deleted, ch.EventID)
+	}
+	if strings.Join(deleted, ",") != "e4,e3" {
+		t.Fatalf("unexpected deletes: %v", deleted)
+	}
+
+	merged := mergeCalendarApplyEvent(plan.Update[0].existing, plan.Update[0].Event)
+	if merged.Summary != "New title" || len(merged.Attendees) != 1 || !merged.Attendees[0].Organizer || !merged.Reminders.UseDefault {
+		t.Fatalf("unexpected merge: %+v", merged)
+	}
+}
+
+func TestCalendarApplyCmd(t *testing.T) {
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+
+	var (
+		mu      sync.Mutex
+		events  = map[string]*calendar.Event{}
+		nextID  int
+		changes []string
+	)
+	events["manual"] = &calendar.Event{Id: "manual", Summary: "Not managed", Start: &calendar.EventDateTime{Date: "2030-01-01"}, End: &calendar.EventDateTime{Date: "2030-01-02"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/calendar/v3")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/calendarList/primary":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "primary", "timeZone": "Europe/Berlin"})
+		case r.Method == http.MethodGet && path == "/calendars/primary/events":
+			items := make([]*calendar.Event, 0, len(events))
+			for _, e := range events {
+				items = append(items, e)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
+		case r.Method == http.MethodPost && path == "/calendars/primary/events":
+			var e calendar.Event
+			_ = json.NewDecoder(r.Body).Decode(&e)
+			nextID++
+			e.Id = fmt.Sprintf("ev%d", nextID)
+			events[e.Id] = &e
+			changes = append(changes, "create "+e.ExtendedProperties.Private[defaultCalendarApplyKey])
+			_ = json.NewEncoder(w).Encode(e)
+		case r.Method == http.MethodPut && strings.HasPrefix(path, "/calendars/primary/events/"):
+			var e calendar.Event
+			_ = json.NewDecoder(r.Body).Decode(&e)
+			id := strings.TrimPrefix(path, "/calendars/primary/events/")
+			e.Id = id
+			events[id] = &e
+			changes = append(changes, "update "+id)
+			_ = json.NewEncoder(w).Encode(e)
+		case r.Method == http.MethodDelete && strings.HasPrefix(path, "/calendars/primary/events/"):
+			id := strings.TrimPrefix(path, "/calendars/primary/events/")
+			delete(events, id)
+			changes = append(changes, "delete "+id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc := newCalendarServiceFromServer(t, srv)
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := ui.WithUI(context.Background(), u)
+	flags := &RootFlags{Account: "a@b.com", Force: true}
+	path := filepath.Join(t.TempDir(), "events.yaml")
+	apply := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		_ = captureStdout(t, func() {
+			if err := runKong(t, &CalendarApplyCmd{}, []string{path}, ctx, flags); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+		})
+	}
+
+	file := `events:
+  - id: standup
+    summary: Standup
+    start: 2030-01-07T09:30
+    end: 2030-01-07T09:45
+    recurrence: ["RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR"]
+    attendees: [team@b.com]
+    reminders: [popup:5m]
+  - id: offsite
+    summary: Offsite
+    start: 2030-02-01
+    end: 2030-02-03
+`
+	apply(file)
+	if strings.Join(changes, ",") != "create standup,create offsite" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+
+	// Re-applying the same file is a no-op.
+	changes = nil
+	apply(file)
+	if len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+
+	// Dry run shows the plan without touching the calendar.
+	edited := strings.Replace(file, "summary: Standup", "summary: Daily standup", 1)
+	edited = edited[:strings.Index(edited, "  - id: offsite")]
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out := captureStdout(t, func() {
+		jsonCtx := outfmt.WithMode(ctx, outfmt.Mode{JSON: true})
+		if err := runKong(t, &CalendarApplyCmd{}, []string{path}, jsonCtx, &RootFlags{Account: "a@b.com", DryRun: true}); ExitCode(err) != 0 {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	var dry struct {
+		Request struct {
+			Plan calendarApplyPlan `json:"plan"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal([]byte(out), &dry); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	plan := dry.Request.Plan
+	if len(changes) != 0 || len(plan.Create) != 0 || len(plan.Update) != 1 || len(plan.Delete) != 1 || plan.Delete[0].ID != "offsite" {
+		t.Fatalf("unexpected dry run: %s (changes %v)", out, changes)
+	}
+	if strings.Join(plan.Update[0].Fields, ",") != "summary" {
+		t.Fatalf("unexpected update fields: %v", plan.Update[0].Fields)
+	}
+
+	apply(edited)
+	if strings.Join(changes, ",") != "update ev1,delete ev2" || events["ev1"].Summary != "Daily standup" || events["manual"] == nil {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+
+	if err := os.WriteFile(path, []byte("events:\n  - id: x\n    sumary: typo\n    start: 2030-01-07\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := runKong(t, &CalendarApplyCmd{}, []string{path}, ctx, flags); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for an unknown field, got %v", err)
+	}
+}
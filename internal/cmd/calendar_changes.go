@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	calendarChangeCreated   = "created"
+	calendarChangeUpdated   = "updated"
+	calendarChangeCancelled = "cancelled"
+)
+
+// calendarSyncState is the incremental sync checkpoint for one account and
+// calendar. SyncedAtMs is the client time of the run that produced the token.
+type calendarSyncState struct {
+	Account    string `json:"account"`
+	CalendarID string `json:"calendarId"`
+	SyncToken  string `json:"syncToken"`
+	SyncedAtMs int64  `json:"syncedAtMs,omitempty"`
+}
+
+type calendarSyncStore struct {
+	path  string
+	state calendarSyncState
+}
+
+func calendarSyncStatePath(account, calendarID string) (string, error) {
+	dir, err := config.EnsureCalendarSyncDir()
+	if err != nil {
+		return "", err
+	}
+	name := sanitizeAccountForPath(account) + "--" + sanitizeAccountForPath(calendarID)
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// loadCalendarSyncStore returns the stored checkpoint, or an empty one when
+// the calendar has not been synced yet.
+func loadCalendarSyncStore(account, calendarID string) (*calendarSyncStore, error) {
+	path, err := calendarSyncStatePath(account, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	store := &calendarSyncStore{path: path, state: calendarSyncState{Account: account, CalendarID: calendarID}}
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from the config dir
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.state); err != nil {
+		return nil, fmt.Errorf("decode calendar sync state %s: %w", path, err)
+	}
+	return store, nil
+}
+
+func (s *calendarSyncStore) Save() error {
+	payload, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, append(payload, '\n'))
+}
+
+type calendarChange struct {
+	Change string          `json:"change"`
+	Event  *calendar.Event `json:"event"`
+}
+
+// syncCalendarEvents lists events changed since syncToken and returns the
+// token for the next run. An empty or expired (410 Gone) token falls back to
+// a full sync; resync reports the expired case.
+func syncCalendarEvents(ctx context.Context, svc *calendar.Service, calendarID, syncToken string) (events []*calendar.Event, nextToken string, resync bool, err error) {
+	events, nextToken, err = listCalendarEventsSince(ctx, svc, calendarID, syncToken)
+	if err != nil && syncToken != "" && isGoneAPIError(err) {
+		resync = true
+		events, nextToken, err = listCalendarEventsSince(ctx, svc, calendarID, "")
+	}
+	if err != nil {
+		return nil, "", resync, err
+	}
+	return events, nextToken, resync, nil
+}
+
+func listCalendarEventsSince(ctx context.Context, svc *calendar.Service, calendarID, syncToken string) ([]*calendar.Event, string, error) {
+	var events []*calendar.Event
+	pageToken := ""
+	for {
+		call := svc.Events.List(calendarID).SingleEvents(false).MaxResults(2500).Context(ctx)
+		if syncToken != "" {
+			call = call.SyncToken(syncToken)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		events = append(events, resp.Items...)
+		if resp.NextPageToken == "" {
+			if resp.NextSyncToken == "" {
+				return nil, "", errors.New("calendar API returned no sync token")
+			}
+			return events, resp.NextSyncToken, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// classifyCalendarChange tells created from updated events by their creation
+// time relative to the previous sync. Without a previous sync (zero since)
+// every event is new to the caller, so it is reported as created.
+func classifyCalendarChange(e *calendar.Event, since time.Time) string {
+	if e.Status == "cancelled" {
+		return calendarChangeCancelled
+	}
+	if since.IsZero() {
+		return calendarChangeCreated
+	}
+	created, err := time.Parse(time.RFC3339, e.Created)
+	if err == nil && created.After(since) {
+		return calendarChangeCreated
+	}
+	return calendarChangeUpdated
+}
+
+type CalendarChangesCmd struct {
+	CalendarID      string `arg:"" stdin:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+	IncludeExisting bool   `name:"include-existing" help:"On the first run, list all current events instead of only saving a starting point"`
+	Reset           bool   `name:"reset" help:"Forget the stored sync token and start over"`
+	NoSave          bool   `name:"no-save" aliases:"peek" help:"Show changes without advancing the stored sync token"`
+}
+
+func (c *CalendarChangesCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.CalendarID)
+	if calendarID == "" {
+		calendarID = primaryCalendarID
+	}
+	calendarID, err = resolveCalendarID(ctx, svc, calendarID)
+	if err != nil {
+		return err
+	}
+
+	store, err := loadCalendarSyncStore(account, calendarID)
+	if err != nil {
+		return err
+	}
+	if c.Reset {
+		store.state.SyncToken, store.state.SyncedAtMs = "", 0
+	}
+	initial := store.state.SyncToken == ""
+	since := time.UnixMilli(store.state.SyncedAtMs)
+	if store.state.SyncedAtMs == 0 {
+		since = time.Time{}
+	}
+
+	startedAt := time.Now()
+	events, nextToken, resync, err := syncCalendarEvents(ctx, svc, calendarID, store.state.SyncToken)
+	if err != nil {
+		return err
+	}
+	if resync {
+		u.Err().Println("Sync token expired; listing all events (full resync)")
+	}
+
+	// The first run only records a starting point unless asked otherwise.
+	changes := make([]calendarChange, 0, len(events))
+	if !initial || resync || c.IncludeExisting {
+		for _, e := range events {
+			changes = append(changes, calendarChange{Change: classifyCalendarChange(e, since), Event: e})
+		}
+	}
+
+	if !c.NoSave {
+		store.state.SyncToken = nextToken
+		store.state.SyncedAtMs = startedAt.UnixMilli()
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("save sync token: %w", err)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"calendarId": calendarID,
+			"initial":    initial,
+			"resync":     resync,
+			"changes":    changes,
+		})
+	}
+	if initial && !c.IncludeExisting {
+		u.Err().Printf("Saved a starting point for %s; the next run lists changes since now", calendarID)
+		return nil
+	}
+	if len(changes) == 0 {
+		u.Err().Println("No changes")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "CHANGE\tSTART\tSUMMARY\tID")
+	for _, ch := range changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ch.Change, eventStart(ch.Event), sanitizeTab(ch.Event.Summary), ch.Event.Id)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestClassifyCalendarChange(t *testing.T) {
+	since := time.Date(2030, 1, 7, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		event *calendar.Event
+		want  string
+	}{
+		{&calendar.Event{Status: "cancelled", Created: "2030-01-07T13:00:00Z"}, calendarChangeCancelled},
+		{&calendar.Event{Status: "confirmed", Created: "2030-01-07T13:00:00Z"}, calendarChangeCreated},
+		{&calendar.Event{Status: "confirmed", Created: "2030-01-01T13:00:00Z"}, calendarChangeUpdated},
+	} {
+		if got := classifyCalendarChange(tc.event, since); got != tc.want {
+			t.Fatalf("%+v: got %s, want %s", tc.event, got, tc.want)
+		}
+	}
+	if got := classifyCalendarChange(&calendar.Event{Created: "2030-01-07T13:00:00Z"}, time.Time{}); got != calendarChangeCreated {
+		t.Fatalf("without a previous sync every event is created, got %s", got)
+	}
+	if got := classifyCalendarChange(&calendar.Event{Status: "cancelled"}, time.Time{}); got != calendarChangeCancelled {
+		t.Fatalf("cancelled events stay cancelled without a previous sync, got %s", got)
+	}
+}
+
+func TestCalendarChangesCmd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+
+	var tokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet || strings.TrimPrefix(r.URL.Path, "/calendar/v3") != "/calendars/primary/events" {
+			http.NotFound(w, r)
+			return
+		}
+		token := r.URL.Query().Get("syncToken")
+		tokens = append(tokens, token+"/"+r.URL.Query().Get("pageToken"))
+		switch {
+		case token == "" && r.URL.Query().Get("pageToken") == "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items":         []map[string]any{{"id": "old", "status": "confirmed", "summary": "Old", "created": "2020-01-01T00:00:00Z"}},
+				"nextPageToken": "p2",
+			})
+		case token == "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items":         []map[string]any{{"id": "old2", "status": "confirmed", "summary": "Old 2", "created": "2020-01-01T00:00:00Z"}},
+				"nextSyncToken": "t1",
+			})
+		case token == "t1":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items": []map[string]any{
+					{"id": "new", "status": "confirmed", "summary": "New", "created": time.Now().Add(time.Hour).UTC().Format(time.RFC3339)},
+					{"id": "old", "status": "confirmed", "summary": "Old (moved)", "created": "2020-01-01T00:00:00Z"},
+					{"id": "old2", "status": "cancelled"},
+				},
+				"nextSyncToken": "t2",
+			})
+		default:
+			w.WriteHeader(http.StatusGone)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 410, "message": "Sync token is no longer valid, a full sync is required.", "errors": []map[string]any{{"reason": "fullSyncRequired"}}}})
+		}
+	}))
+	defer srv.Close()
+	svc := newCalendarServiceFromServer(t, srv)
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+
+	ctx := newCalendarJSONContext(t)
+	flags := &RootFlags{Account: "a@b.com"}
+	type result struct {
+		Initial bool             `json:"initial"`
+		Resync  bool             `json:"resync"`
+		Changes []calendarChange `json:"changes"`
+	}
+	run := func(args ...string) result {
+		t.Helper()
+		out := captureStdout(t, func() {
+			if err := runKong(t, &CalendarChangesCmd{}, args, ctx, flags); err != nil {
+				t.Fatalf("changes: %v", err)
+			}
+		})
+		var r result
+		if err := json.Unmarshal([]byte(out), &r); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		return r
+	}
+	summarize := func(r result) string {
+		parts := make([]string, 0, len(r.Changes))
+		for _, ch := range r.Changes {
+			parts = append(parts, ch.Change+":"+ch.Event.Id)
+		}
+		return strings.Join(parts, ",")
+	}
+
+	// The first run only saves a starting point.
+	if r := run(); !r.Initial || len(r.Changes) != 0 {
+		t.Fatalf("unexpected first run: %+v", r)
+	}
+	path, err := calendarSyncStatePath("a@b.com", "primary")
+	if err != nil {
+		t.Fatalf("calendarSyncStatePath: %v", err)
+	}
+	if data, readErr := os.ReadFile(path); readErr != nil || !strings.Contains(string(data), `"syncToken": "t1"`) {
+		t.Fatalf("expected stored token: %s (%v)", data, readErr)
+	}
+
+	// --no-save shows the changes but keeps the token.
+	if got := summarize(run("--no-save")); got != "created:new,updated:old,cancelled:old2" {
+		t.Fatalf("unexpected changes: %s", got)
+	}
+	if got := summarize(run()); got != "created:new,updated:old,cancelled:old2" {
+		t.Fatalf("unexpected changes: %s", got)
+	}
+
+	// An expired token triggers a full resync that lists everything.
+	r := run()
+	if !r.Resync || summarize(r) != "updated:old,updated:old2" {
+		t.Fatalf("unexpected resync: %+v", r)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), `"syncToken": "t1"`) {
+		t.Fatalf("expected the resync token to be stored: %s", data)
+	}
+
+	want := "/,/p2,t1/,t1/,t2/,/,/p2"
+	if got := strings.Join(tokens, ","); got != want {
+		t.Fatalf("unexpected requests: %s, want %s", got, want)
+	}
+
+	// A first run with --include-existing reports current events as created.
+	if r := run("--reset", "--include-existing"); !r.Initial || summarize(r) != "created:old,created:old2" {
+		t.Fatalf("unexpected first run with --include-existing: %+v", r)
+	}
+}
@@ -14,7 +14,7 @@ import (
 )
 
 type CalendarCreateCmd struct {
-	CalendarID            string   `arg:"" name:"calendarId" help:"Calendar ID"`
+	CalendarID            string   `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
 	Summary               string   `name:"summary" help:"Event summary/title"`
 	From                  string   `name:"from" help:"Start time (RFC3339)"`
 	To                    string   `name:"to" help:"End time (RFC3339)"`
@@ -310,8 +310,8 @@ func (c *CalendarCreateCmd) buildOutOfOfficeProperties() (*calendar.EventOutOfOf
 }
 
 type CalendarUpdateCmd struct {
-	CalendarID            string   `arg:"" name:"calendarId" help:"Calendar ID"`
-	EventID               string   `arg:"" name:"eventId" help:"Event ID"`
+	CalendarID            string   `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
+	EventID               string   `arg:"" stdin:"" name:"eventId" help:"Event ID"`
 	Summary               string   `name:"summary" help:"New summary/title (set empty to clear)"`
 	From                  string   `name:"from" help:"New start time (RFC3339; set empty to clear)"`
 	To                    string   `name:"to" help:"New end time (RFC3339; set empty to clear)"`
@@ -855,8 +855,8 @@ func resolveRecurringScope(scopeValue, originalStartTime string) (string, error)
 }
 
 type CalendarDeleteCmd struct {
-	CalendarID        string `arg:"" name:"calendarId" help:"Calendar ID"`
-	EventID           string `arg:"" name:"eventId" help:"Event ID"`
+	CalendarID        string `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
+	EventID           string `arg:"" stdin:"" name:"eventId" help:"Event ID"`
 	Scope             string `name:"scope" help:"For recurring events: single, future, all" default:"all"`
 	OriginalStartTime string `name:"original-start" help:"Original start time of instance (required for scope=single,future)"`
 	SendUpdates       string `name:"send-updates" help:"Notification mode: all, externalOnly, none (default: none)"`
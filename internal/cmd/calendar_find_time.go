@@ -0,0 +1,512 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CalendarFindTimeCmd struct {
+	Attendees         string   `name:"attendees" required:"" help:"Comma-separated attendee emails (append ;optional to not block slots)"`
+	Duration          string   `name:"duration" help:"Meeting length (e.g. 30m, 1h30m)" default:"30m"`
+	Within            string   `name:"within" help:"Search window: today, tomorrow, this week, next week, next N days, or a weekday (default: next 7 days)"`
+	From              string   `name:"from" help:"Window start (RFC3339, date, or relative; instead of --within)"`
+	To                string   `name:"to" help:"Window end (RFC3339, date, or relative; instead of --within)"`
+	WorkingHours      string   `name:"working-hours" help:"Working hours in each attendee's own timezone (HH:MM-HH:MM)" default:"09:00-17:00"`
+	Weekends          bool     `name:"weekends" help:"Also consider Saturdays and Sundays"`
+	Step              string   `name:"step" help:"Granularity of candidate start times" default:"15m"`
+	Max               int      `name:"max" aliases:"limit" help:"Max slots to show" default:"5"`
+	Timezone          string   `name:"timezone" help:"Your timezone (default: primary calendar timezone)"`
+	AttendeeTimezones []string `name:"attendee-timezone" help:"Timezone for an attendee whose calendar is not visible (email=Area/City, can be repeated)"`
+	Book              int      `name:"book" help:"Create an event in the slot with this rank"`
+	Calendar          string   `name:"calendar" help:"Calendar to book into" default:"primary"`
+	Summary           string   `name:"summary" help:"Event summary/title (required with --book)"`
+	Description       string   `name:"description" help:"Event description (with --book)"`
+	Location          string   `name:"location" help:"Event location (with --book)"`
+	WithMeet          bool     `name:"with-meet" help:"Add a Google Meet video conference (with --book)"`
+	SendUpdates       string   `name:"send-updates" help:"Notification mode for --book: all, externalOnly, none (default: none)"`
+}
+
+type findTimeAttendee struct {
+	Email          string `json:"email"`
+	Timezone       string `json:"timezone"`
+	TimezoneSource string `json:"timezoneSource"`
+	Optional       bool   `json:"optional,omitempty"`
+	Unavailable    string `json:"freeBusyError,omitempty"`
+	loc            *time.Location
+	busy           []findTimeInterval
+}
+
+type findTimeInterval struct {
+	start time.Time
+	end   time.Time
+}
+
+type findTimeSlot struct {
+	Rank         int               `json:"rank"`
+	Start        string            `json:"start"`
+	End          string            `json:"end"`
+	Score        int               `json:"score"`
+	OptionalBusy []string          `json:"optionalBusy,omitempty"`
+	Local        map[string]string `json:"local"`
+	start        time.Time
+	end          time.Time
+}
+
+// findTimeOptions are the constraints every candidate slot must satisfy.
+type findTimeOptions struct {
+	from     time.Time
+	to       time.Time
+	duration time.Duration
+	step     time.Duration
+	dayStart int // minutes after midnight
+	dayEnd   int
+	weekends bool
+}
+
+func (c *CalendarFindTimeCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	duration, err := parseFindTimeDuration("--duration", c.Duration)
+	if err != nil {
+		return err
+	}
+	step, err := parseFindTimeDuration("--step", c.Step)
+	if err != nil {
+		return err
+	}
+	dayStart, dayEnd, err := parseWorkingHours(c.WorkingHours)
+	if err != nil {
+		return err
+	}
+	if duration > time.Duration(dayEnd-dayStart)*time.Minute {
+		return usagef("--duration %s does not fit into --working-hours %s", c.Duration, c.WorkingHours)
+	}
+	overrides, err := parseAttendeeTimezones(c.AttendeeTimezones)
+	if err != nil {
+		return err
+	}
+	if c.Book < 0 {
+		return usage("--book must be a slot rank (1, 2, ...)")
+	}
+	if c.Book > 0 && strings.TrimSpace(c.Summary) == "" {
+		return usage("required with --book: --summary")
+	}
+	if c.Max <= 0 {
+		c.Max = 5
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	loc, err := getConfiguredTimezone(c.Timezone)
+	if err != nil {
+		return err
+	}
+	selfSource := "config"
+	if loc == nil {
+		selfSource = "calendar"
+		if loc, err = getUserTimezone(ctx, svc); err != nil {
+			return err
+		}
+	}
+
+	from, to, err := resolveFindTimeWindow(c.Within, c.From, c.To, time.Now().In(loc))
+	if err != nil {
+		return err
+	}
+
+	attendees := []*findTimeAttendee{{Email: account, Timezone: loc.String(), TimezoneSource: selfSource, loc: loc}}
+	seen := map[string]bool{strings.ToLower(account): true}
+	for _, spec := range splitCSV(c.Attendees) {
+		a := parseAttendee(spec)
+		if a == nil || seen[strings.ToLower(a.Email)] {
+			continue
+		}
+		seen[strings.ToLower(a.Email)] = true
+		attendee := &findTimeAttendee{Email: a.Email, Optional: a.Optional}
+		attendee.Timezone, attendee.loc, attendee.TimezoneSource = findTimeAttendeeLocation(ctx, svc, a.Email, overrides)
+		if attendee.loc == nil {
+			attendee.Timezone, attendee.loc, attendee.TimezoneSource = loc.String(), loc, "assumed"
+			u.Err().Printf("Warning: %s: calendar timezone not visible, assuming %s (use --attendee-timezone)", a.Email, loc)
+		}
+		attendees = append(attendees, attendee)
+	}
+	if len(attendees) == 1 {
+		return usage("no attendees provided")
+	}
+
+	if err := loadFindTimeBusy(ctx, svc, attendees, from, to); err != nil {
+		return err
+	}
+	for _, a := range attendees {
+		if a.Unavailable != "" {
+			u.Err().Printf("Warning: %s: free/busy unavailable (%s), treating as free", a.Email, a.Unavailable)
+		}
+	}
+
+	slots := findFreeSlots(attendees, findTimeOptions{
+		from:     from,
+		to:       to,
+		duration: duration,
+		step:     step,
+		dayStart: dayStart,
+		dayEnd:   dayEnd,
+		weekends: c.Weekends,
+	}, c.Max, loc)
+
+	if c.Book > 0 {
+		if c.Book > len(slots) {
+			return usagef("--book %d: only %d slot(s) found", c.Book, len(slots))
+		}
+		slot := slots[c.Book-1]
+		u.Err().Printf("Booking slot %d: %s - %s", slot.Rank, slot.start.Format("Mon 2006-01-02 15:04"), slot.end.Format("15:04 MST"))
+		create := &CalendarCreateCmd{
+			CalendarID:  c.Calendar,
+			Summary:     c.Summary,
+			From:        slot.Start,
+			To:          slot.End,
+			Description: c.Description,
+			Location:    c.Location,
+			Attendees:   c.Attendees,
+			WithMeet:    c.WithMeet,
+			SendUpdates: c.SendUpdates,
+		}
+		return create.Run(ctx, flags)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"timeMin":   from.Format(time.RFC3339),
+			"timeMax":   to.Format(time.RFC3339),
+			"timezone":  loc.String(),
+			"duration":  duration.String(),
+			"attendees": attendees,
+			"slots":     slots,
+		})
+	}
+
+	if len(slots) == 0 {
+		u.Err().Println("No free slots found")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "RANK\tSTART\tEND\tLOCAL")
+	for _, s := range slots {
+		var local []string
+		for _, a := range attendees {
+			if a.loc.String() != loc.String() {
+				local = append(local, a.Email+" "+s.Local[a.Email])
+			}
+		}
+		for _, email := range s.OptionalBusy {
+			local = append(local, email+" busy")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Rank, s.start.Format("Mon 2006-01-02 15:04"), s.end.Format("15:04"), sanitizeTab(strings.Join(local, "; ")))
+	}
+	return nil
+}
+
+func parseFindTimeDuration(flag, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || d < time.Minute || d%time.Minute != 0 {
+		return 0, usagef("invalid %s %q (use whole minutes, e.g. 30m or 1h30m)", flag, value)
+	}
+	return d, nil
+}
+
+// parseWorkingHours parses "HH:MM-HH:MM" into minutes after midnight.
+func parseWorkingHours(value string) (int, int, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(value), "-")
+	start, startErr := parseClockMinutes(startStr)
+	end, endErr := parseClockMinutes(endStr)
+	if !ok || startErr != nil || endErr != nil || end <= start {
+		return 0, 0, usagef("invalid --working-hours %q (use HH:MM-HH:MM, e.g. 09:00-17:00)", value)
+	}
+	return start, end, nil
+}
+
+func parseClockMinutes(value string) (int, error) {
+	hStr, mStr, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	h, hErr := strconv.Atoi(hStr)
+	m, mErr := strconv.Atoi(mStr)
+	if hErr != nil || mErr != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return h*60 + m, nil
+}
+
+func parseAttendeeTimezones(values []string) (map[string]*time.Location, error) {
+	out := make(map[string]*time.Location, len(values))
+	for _, v := range values {
+		email, tz, ok := strings.Cut(v, "=")
+		email = strings.ToLower(strings.TrimSpace(email))
+		if !ok || email == "" {
+			return nil, usagef("invalid --attendee-timezone %q (use email=Area/City)", v)
+		}
+		loc, _, err := parseTimezoneValue("--attendee-timezone", tz, false)
+		if err != nil {
+			return nil, usage(err.Error())
+		}
+		if loc == nil {
+			return nil, usagef("invalid --attendee-timezone %q (use email=Area/City)", v)
+		}
+		out[email] = loc
+	}
+	return out, nil
+}
+
+// resolveFindTimeWindow turns --within (or --from/--to) into a window that
+// never starts in the past.
+func resolveFindTimeWindow(within, fromExpr, toExpr string, now time.Time) (time.Time, time.Time, error) {
+	within = strings.ToLower(strings.Join(strings.Fields(within), " "))
+	fromExpr, toExpr = strings.TrimSpace(fromExpr), strings.TrimSpace(toExpr)
+	if within != "" && (fromExpr != "" || toExpr != "") {
+		return time.Time{}, time.Time{}, usage("use either --within or --from/--to")
+	}
+
+	loc := now.Location()
+	var from, to time.Time
+	switch {
+	case fromExpr != "" || toExpr != "":
+		from = now
+		if fromExpr != "" {
+			t, err := parseTimeExpr(fromExpr, now, loc)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %w", err)
+			}
+			from = t
+		}
+		to = endOfDay(from.AddDate(0, 0, 6))
+		if toExpr != "" {
+			t, err := parseTimeExprEndOfDay(toExpr, now, loc)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %w", err)
+			}
+			to = t
+		}
+	case within == "" || within == "next 7 days":
+		from, to = now, endOfDay(now.AddDate(0, 0, 6))
+	case within == "today":
+		from, to = now, endOfDay(now)
+	case within == "tomorrow":
+		from, to = startOfDay(now.AddDate(0, 0, 1)), endOfDay(now.AddDate(0, 0, 1))
+	case within == "this week":
+		from, to = now, endOfWeek(now, time.Monday)
+	case within == "next week":
+		next := now.AddDate(0, 0, 7)
+		from, to = startOfWeek(next, time.Monday), endOfWeek(next, time.Monday)
+	default:
+		if days, ok := parseWithinDays(within); ok {
+			from, to = now, endOfDay(now.AddDate(0, 0, days-1))
+			break
+		}
+		day, ok := parseWeekday(within, now)
+		if !ok {
+			return time.Time{}, time.Time{}, usagef("invalid --within %q (use today, tomorrow, this week, next week, next N days, or a weekday)", within)
+		}
+		from, to = day, endOfDay(day)
+	}
+
+	if from.Before(now) {
+		from = now
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, usage("search window is empty or in the past")
+	}
+	return from, to, nil
+}
+
+// parseWithinDays accepts "next N days", "N days" and "N d".
+func parseWithinDays(within string) (int, bool) {
+	s := strings.TrimPrefix(within, "next ")
+	for _, suffix := range []string{" days", " day", "days", "day", "d"} {
+		if n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(s, suffix))); err == nil && strings.HasSuffix(s, suffix) {
+			return n, n > 0
+		}
+	}
+	return 0, false
+}
+
+// findTimeAttendeeLocation looks up an attendee's calendar timezone: an
+// explicit override first, then the calendar list, then the calendar itself
+// (readable when it is shared with at least free/busy details).
+func findTimeAttendeeLocation(ctx context.Context, svc *calendar.Service, email string, overrides map[string]*time.Location) (string, *time.Location, string) {
+	if loc, ok := overrides[strings.ToLower(email)]; ok {
+		return loc.String(), loc, "flag"
+	}
+	if tz, loc, err := getCalendarLocation(ctx, svc, email); err == nil {
+		return tz, loc, "calendar"
+	}
+	cal, err := svc.Calendars.Get(email).Context(ctx).Do()
+	if err != nil || cal.TimeZone == "" {
+		return "", nil, ""
+	}
+	loc, err := time.LoadLocation(cal.TimeZone)
+	if err != nil {
+		return "", nil, ""
+	}
+	return cal.TimeZone, loc, "calendar"
+}
+
+func loadFindTimeBusy(ctx context.Context, svc *calendar.Service, attendees []*findTimeAttendee, from, to time.Time) error {
+	items := make([]*calendar.FreeBusyRequestItem, len(attendees))
+	for i, a := range attendees {
+		items[i] = &calendar.FreeBusyRequestItem{Id: a.Email}
+	}
+	resp, err := svc.Freebusy.Query(&calendar.FreeBusyRequest{
+		TimeMin: from.Format(time.RFC3339),
+		TimeMax: to.Format(time.RFC3339),
+		Items:   items,
+	}).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("freebusy query: %w", err)
+	}
+
+	for _, a := range attendees {
+		cal, ok := resp.Calendars[a.Email]
+		if !ok {
+			a.Unavailable = "notFound"
+			continue
+		}
+		if len(cal.Errors) > 0 {
+			reasons := make([]string, 0, len(cal.Errors))
+			for _, e := range cal.Errors {
+				reasons = append(reasons, e.Reason)
+			}
+			a.Unavailable = strings.Join(reasons, ", ")
+			continue
+		}
+		for _, b := range cal.Busy {
+			start, startErr := time.Parse(time.RFC3339, b.Start)
+			end, endErr := time.Parse(time.RFC3339, b.End)
+			if startErr != nil || endErr != nil {
+				continue
+			}
+			a.busy = append(a.busy, findTimeInterval{start: start, end: end})
+		}
+	}
+	return nil
+}
+
+// findFreeSlots returns up to max non-overlapping slots inside everyone's
+// working hours (each in their own timezone) where no required attendee is
+// busy. Slots are ranked by how many optional attendees are busy, then by
+// the smallest distance to anyone's start or end of day, then by time.
+func findFreeSlots(attendees []*findTimeAttendee, opts findTimeOptions, maxSlots int, loc *time.Location) []*findTimeSlot {
+	var candidates []*findTimeSlot
+	for start := alignSlotStart(opts.from.In(loc), opts.step); !start.Add(opts.duration).After(opts.to); start = start.Add(opts.step) {
+		end := start.Add(opts.duration)
+		slot := &findTimeSlot{start: start, end: end, Score: -1, Local: make(map[string]string, len(attendees))}
+		ok := true
+		for _, a := range attendees {
+			margin, inHours := workingMargin(start.In(a.loc), end.In(a.loc), opts)
+			busy := a.isBusy(start, end)
+			if !a.Optional && (!inHours || busy) {
+				ok = false
+				break
+			}
+			if a.Optional {
+				if busy || !inHours {
+					slot.OptionalBusy = append(slot.OptionalBusy, a.Email)
+				}
+			} else if slot.Score < 0 || margin < slot.Score {
+				slot.Score = margin
+			}
+			slot.Local[a.Email] = start.In(a.loc).Format("Mon 15:04") + "-" + end.In(a.loc).Format("15:04 MST")
+		}
+		if ok {
+			candidates = append(candidates, slot)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if len(a.OptionalBusy) != len(b.OptionalBusy) {
+			return len(a.OptionalBusy) < len(b.OptionalBusy)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.start.Before(b.start)
+	})
+
+	slots := make([]*findTimeSlot, 0, maxSlots)
+	for _, cand := range candidates {
+		if len(slots) == maxSlots {
+			break
+		}
+		overlaps := false
+		for _, s := range slots {
+			if cand.start.Before(s.end) && s.start.Before(cand.end) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		cand.Rank = len(slots) + 1
+		cand.Start = cand.start.Format(time.RFC3339)
+		cand.End = cand.end.Format(time.RFC3339)
+		slots = append(slots, cand)
+	}
+	return slots
+}
+
+// alignSlotStart rounds t up to the next multiple of step after local midnight.
+func alignSlotStart(t time.Time, step time.Duration) time.Time {
+	t = t.Truncate(time.Minute)
+	sinceMidnight := t.Sub(startOfDay(t))
+	if rem := sinceMidnight % step; rem != 0 {
+		t = t.Add(step - rem)
+	}
+	return t
+}
+
+// workingMargin reports whether [start, end) lies inside working hours on a
+// working day, and how many minutes it keeps from the nearer edge.
+func workingMargin(start, end time.Time, opts findTimeOptions) (int, bool) {
+	if !opts.weekends && (start.Weekday() == time.Saturday || start.Weekday() == time.Sunday) {
+		return 0, false
+	}
+	startMin := start.Hour()*60 + start.Minute()
+	endMin := startMin + int(end.Sub(start)/time.Minute)
+	if !sameDate(start, end.Add(-time.Nanosecond)) || startMin < opts.dayStart || endMin > opts.dayEnd {
+		return 0, false
+	}
+	return min(startMin-opts.dayStart, opts.dayEnd-endMin), true
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func (a *findTimeAttendee) isBusy(start, end time.Time) bool {
+	for _, b := range a.busy {
+		if start.Before(b.end) && b.start.Before(end) {
+			return true
+		}
+	}
+	return false
+}
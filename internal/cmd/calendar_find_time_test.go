@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestFindFreeSlots(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, ny) // Monday
+
+	attendees := []*findTimeAttendee{
+		{Email: "me@x.com", loc: ny, busy: []findTimeInterval{{day.Add(9 * time.Hour), day.Add(10 * time.Hour)}}},
+		{Email: "bob@y.com", loc: berlin},
+		{Email: "carol@y.com", loc: ny, Optional: true, busy: []findTimeInterval{{day.Add(10 * time.Hour), day.Add(11 * time.Hour)}}},
+	}
+	opts := findTimeOptions{
+		from:     day,
+		to:       endOfDay(day),
+		duration: 45 * time.Minute,
+		step:     15 * time.Minute,
+		dayStart: 9 * 60,
+		dayEnd:   17 * 60,
+	}
+
+	// Berlin's 17:00 is 11:00 in New York and me@x.com is busy until 10:00.
+	slots := findFreeSlots(attendees, opts, 5, ny)
+	if len(slots) != 1 {
+		t.Fatalf("expected one non-overlapping slot, got %d", len(slots))
+	}
+	s := slots[0]
+	if s.Rank != 1 || s.Start != "2030-01-07T10:00:00-05:00" || s.End != "2030-01-07T10:45:00-05:00" || s.Score != 15 {
+		t.Fatalf("unexpected slot: %+v", s)
+	}
+	if s.Local["bob@y.com"] != "Mon 16:00-16:45 CET" {
+		t.Fatalf("unexpected local time: %q", s.Local["bob@y.com"])
+	}
+	if len(s.OptionalBusy) != 1 || s.OptionalBusy[0] != "carol@y.com" {
+		t.Fatalf("expected carol to be flagged busy: %v", s.OptionalBusy)
+	}
+
+	// Without the Berlin attendee, slots in the middle of the day rank first.
+	slots = findFreeSlots(attendees[:1], opts, 3, ny)
+	if len(slots) != 3 || slots[0].Start != "2030-01-07T12:30:00-05:00" {
+		t.Fatalf("unexpected ranking: %+v", slots[0])
+	}
+	for i := 1; i < len(slots); i++ {
+		if slots[i].start.Before(slots[i-1].end) && slots[i-1].start.Before(slots[i].end) {
+			t.Fatalf("slots overlap: %+v %+v", slots[i-1], slots[i])
+		}
+	}
+
+	// Weekends are skipped unless requested.
+	opts.from, opts.to = day.AddDate(0, 0, -2), endOfDay(day.AddDate(0, 0, -2))
+	if got := findFreeSlots(attendees[:1], opts, 5, ny); len(got) != 0 {
+		t.Fatalf("expected no Saturday slots, got %d", len(got))
+	}
+	opts.weekends = true
+	if got := findFreeSlots(attendees[:1], opts, 5, ny); len(got) == 0 {
+		t.Fatalf("expected Saturday slots with weekends enabled")
+	}
+}
+
+func TestResolveFindTimeWindow(t *testing.T) {
+	now := time.Date(2030, 1, 9, 14, 7, 0, 0, time.UTC) // Wednesday
+	for _, tc := range []struct {
+		within   string
+		from, to string
+	}{
+		{"", "2030-01-09T14:07:00Z", "2030-01-15T23:59:59Z"},
+		{"today", "2030-01-09T14:07:00Z", "2030-01-09T23:59:59Z"},
+		{"Next  Week", "2030-01-14T00:00:00Z", "2030-01-20T23:59:59Z"},
+		{"this week", "2030-01-09T14:07:00Z", "2030-01-13T23:59:59Z"},
+		{"next 3 days", "2030-01-09T14:07:00Z", "2030-01-11T23:59:59Z"},
+		{"friday", "2030-01-11T00:00:00Z", "2030-01-11T23:59:59Z"},
+	} {
+		from, to, err := resolveFindTimeWindow(tc.within, "", "", now)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.within, err)
+		}
+		if got := from.Format(time.RFC3339); got != tc.from {
+			t.Fatalf("%q: from = %s, want %s", tc.within, got, tc.from)
+		}
+		if got := to.Format(time.RFC3339); got != tc.to {
+			t.Fatalf("%q: to = %s, want %s", tc.within, got, tc.to)
+		}
+	}
+
+	from, _, err := resolveFindTimeWindow("", "2030-01-01", "2030-01-10", now)
+	if err != nil || !from.Equal(now) {
+		t.Fatalf("expected the window to start now, got %s (%v)", from, err)
+	}
+	for _, bad := range [][3]string{{"someday", "", ""}, {"today", "2030-01-10", ""}, {"", "", "2030-01-01"}} {
+		if _, _, err := resolveFindTimeWindow(bad[0], bad[1], bad[2], now); err == nil {
+			t.Fatalf("expected an error for %v", bad)
+		}
+	}
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	start, end, err := parseWorkingHours("08:30-17:45")
+	if err != nil || start != 510 || end != 1065 {
+		t.Fatalf("unexpected: %d %d %v", start, end, err)
+	}
+	for _, bad := range []string{"", "9-17", "17:00-09:00", "09:00-25:00", "09:60-10:00"} {
+		if _, _, err := parseWorkingHours(bad); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestCalendarFindTimeCmd(t *testing.T) {
+	t.Setenv("GOG_TIMEZONE", "")
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+
+	var freeBusyReq calendar.FreeBusyRequest
+	var inserted calendar.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/calendar/v3")
+		switch {
+		case r.Method == http.MethodGet && path == "/users/me/calendarList/primary":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "me@x.com", "timeZone": "America/New_York"})
+		case r.Method == http.MethodGet && path == "/calendars/bob@y.com":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "bob@y.com", "timeZone": "Europe/Berlin"})
+		case r.Method == http.MethodPost && path == "/freeBusy":
+			_ = json.NewDecoder(r.Body).Decode(&freeBusyReq)
+			_ = json.NewEncoder(w).Encode(map[string]any{"calendars": map[string]any{
+				"me@x.com":  map[string]any{"busy": []map[string]any{{"start": "2030-01-07T14:00:00Z", "end": "2030-01-07T15:00:00Z"}}},
+				"bob@y.com": map[string]any{"busy": []map[string]any{}},
+				"ext@z.com": map[string]any{"errors": []map[string]any{{"domain": "global", "reason": "notFound"}}},
+			}})
+		case r.Method == http.MethodPost && path == "/calendars/primary/events":
+			_ = json.NewDecoder(r.Body).Decode(&inserted)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "ev1", "summary": inserted.Summary, "start": inserted.Start, "end": inserted.End})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc := newCalendarServiceFromServer(t, srv)
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+
+	ctx := newCalendarJSONContext(t)
+	flags := &RootFlags{Account: "me@x.com"}
+	args := []string{
+		"--attendees", "bob@y.com,ext@z.com;optional",
+		"--duration", "45m",
+		"--from", "2030-01-07",
+		"--to", "2030-01-07",
+		"--attendee-timezone", "ext@z.com=Asia/Tokyo",
+	}
+	out := captureStdout(t, func() {
+		if err := runKong(t, &CalendarFindTimeCmd{}, args, ctx, flags); err != nil {
+			t.Fatalf("find-time: %v", err)
+		}
+	})
+	if len(freeBusyReq.Items) != 3 || freeBusyReq.Items[0].Id != "me@x.com" || freeBusyReq.TimeMin != "2030-01-07T00:00:00-05:00" {
+		t.Fatalf("unexpected freebusy request: %+v", freeBusyReq)
+	}
+
+	var parsed struct {
+		Timezone  string              `json:"timezone"`
+		Attendees []*findTimeAttendee `json:"attendees"`
+		Slots     []*findTimeSlot     `json:"slots"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json: %v\n%s", err, out)
+	}
+	if parsed.Timezone != "America/New_York" || len(parsed.Attendees) != 3 {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if a := parsed.Attendees[1]; a.Timezone != "Europe/Berlin" || a.TimezoneSource != "calendar" {
+		t.Fatalf("unexpected attendee: %+v", a)
+	}
+	if a := parsed.Attendees[2]; a.Timezone != "Asia/Tokyo" || a.TimezoneSource != "flag" || a.Unavailable != "notFound" {
+		t.Fatalf("unexpected attendee: %+v", a)
+	}
+	if len(parsed.Slots) != 1 || parsed.Slots[0].Start != "2030-01-07T10:00:00-05:00" {
+		t.Fatalf("unexpected slots: %s", out)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &CalendarFindTimeCmd{}, append(args, "--book", "1", "--summary", "Sync"), ctx, flags); err != nil {
+			t.Fatalf("find-time --book: %v", err)
+		}
+	})
+	if inserted.Summary != "Sync" || inserted.Start.DateTime != "2030-01-07T10:00:00-05:00" || inserted.End.DateTime != "2030-01-07T10:45:00-05:00" {
+		t.Fatalf("unexpected booked event: %+v %+v", inserted.Start, inserted.End)
+	}
+	if len(inserted.Attendees) != 2 || inserted.Attendees[0].Email != "bob@y.com" || !inserted.Attendees[1].Optional {
+		t.Fatalf("unexpected attendees: %+v", inserted.Attendees)
+	}
+
+	if err := runKong(t, &CalendarFindTimeCmd{}, append(args, "--book", "2", "--summary", "Sync"), ctx, flags); err == nil {
+		t.Fatalf("expected an error when booking a missing rank")
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 type CalendarFreeBusyCmd struct {
-	CalendarIDs string `arg:"" name:"calendarIds" help:"Comma-separated calendar IDs"`
+	CalendarIDs string `arg:"" stdin:"" name:"calendarIds" help:"Comma-separated calendar IDs"`
 	From        string `name:"from" help:"Start time (RFC3339, required)"`
 	To          string `name:"to" help:"End time (RFC3339, required)"`
 }
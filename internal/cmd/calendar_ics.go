@@ -0,0 +1,848 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// iCalendar (RFC 5545) encoding and decoding for calendar export/import.
+
+const (
+	icsDateLayout     = "20060102"
+	icsLocalLayout    = "20060102T150405"
+	icsUTCLayout      = "20060102T150405Z"
+	icsMaxLineOctets  = 75
+	icsMaxGoogleAlarm = 5
+)
+
+var icsTZIDParam = regexp.MustCompile(`(?i)TZID=("[^"]+"|[^;:]+)`)
+
+type icsWriter struct {
+	b strings.Builder
+}
+
+// prop writes one content line, folded at 75 octets without splitting
+// UTF-8 sequences. value must already be escaped where TEXT requires it.
+func (w *icsWriter) prop(name, value string, params ...string) {
+	line := name
+	for _, p := range params {
+		if p != "" {
+			line += ";" + p
+		}
+	}
+	w.raw(line + ":" + value)
+}
+
+func (w *icsWriter) raw(line string) {
+	// Continuation lines start with a space that counts toward the limit.
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+func (w *icsWriter) String() string { return w.b.String() }
+
+func icsEscapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+func icsUnescapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// icsParam formats a parameter, quoting values that contain separators.
+func icsParam(name, value string) string {
+	value = strings.ReplaceAll(strings.TrimSpace(value), `"`, "'")
+	if value == "" {
+		return ""
+	}
+	if strings.ContainsAny(value, ";:,") {
+		value = `"` + value + `"`
+	}
+	return name + "=" + value
+}
+
+// writeICS serializes events as one VCALENDAR. Cancelled instances of
+// exported recurring events become EXDATEs on the series.
+func writeICS(events []*calendar.Event, calendarName, calendarTZ string) string {
+	masters := map[string]*calendar.Event{}
+	for _, e := range events {
+		if e != nil && len(e.Recurrence) > 0 {
+			masters[e.Id] = e
+		}
+	}
+	exdates := map[string][]string{}
+	kept := make([]*calendar.Event, 0, len(events))
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		if e.Status == "cancelled" {
+			if master := masters[e.RecurringEventId]; master != nil && e.OriginalStartTime != nil {
+				if params, value := icsDateTimeValue(e.OriginalStartTime, icsEventTZ(master, calendarTZ)); value != "" {
+					exdates[master.Id] = append(exdates[master.Id], icsJoinProp("EXDATE", value, params))
+				}
+			}
+			continue
+		}
+		kept = append(kept, e)
+	}
+
+	var body icsWriter
+	tzids := map[string]bool{}
+	for _, e := range kept {
+		writeICSEvent(&body, e, calendarTZ, exdates[e.Id], tzids)
+	}
+
+	var w icsWriter
+	w.prop("BEGIN", "VCALENDAR")
+	w.prop("VERSION", "2.0")
+	w.prop("PRODID", "-//gogcli//gog calendar export//EN")
+	w.prop("CALSCALE", "GREGORIAN")
+	if calendarName != "" {
+		w.prop("X-WR-CALNAME", icsEscapeText(calendarName))
+	}
+	if calendarTZ != "" {
+		w.prop("X-WR-TIMEZONE", calendarTZ)
+	}
+	names := make([]string, 0, len(tzids))
+	for name := range tzids {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeVTimezone(&w, name)
+	}
+	w.b.WriteString(body.String())
+	w.prop("END", "VCALENDAR")
+	return w.String()
+}
+
+func icsJoinProp(name, value string, params []string) string {
+	line := name
+	for _, p := range params {
+		line += ";" + p
+	}
+	return line + ":" + value
+}
+
+func icsEventTZ(e *calendar.Event, calendarTZ string) string {
+	if e.Start != nil && e.Start.TimeZone != "" {
+		return e.Start.TimeZone
+	}
+	return calendarTZ
+}
+
+// icsDateTimeValue returns the parameters and value for a DATE or
+// DATE-TIME property, in tz local time when tz is a known zone.
+func icsDateTimeValue(dt *calendar.EventDateTime, tz string) ([]string, string) {
+	if dt == nil {
+		return nil, ""
+	}
+	if dt.Date != "" {
+		d, err := time.Parse("2006-01-02", dt.Date)
+		if err != nil {
+			return nil, ""
+		}
+		return []string{"VALUE=DATE"}, d.Format(icsDateLayout)
+	}
+	t, err := time.Parse(time.RFC3339, dt.DateTime)
+	if err != nil {
+		return nil, ""
+	}
+	if dt.TimeZone != "" {
+		tz = dt.TimeZone
+	}
+	if tz != "" && tz != "UTC" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return []string{icsParam("TZID", tz)}, t.In(loc).Format(icsLocalLayout)
+		}
+	}
+	return nil, t.UTC().Format(icsUTCLayout)
+}
+
+func writeICSEvent(w *icsWriter, e *calendar.Event, calendarTZ string, exdates []string, tzids map[string]bool) {
+	tz := icsEventTZ(e, calendarTZ)
+	dateProp := func(name string, dt *calendar.EventDateTime) {
+		params, value := icsDateTimeValue(dt, tz)
+		if value == "" {
+			return
+		}
+		for _, p := range params {
+			if strings.HasPrefix(p, "TZID=") {
+				tzids[strings.Trim(strings.TrimPrefix(p, "TZID="), `"`)] = true
+			}
+		}
+		w.prop(name, value, params...)
+	}
+
+	w.prop("BEGIN", "VEVENT")
+	uid := e.ICalUID
+	if uid == "" {
+		uid = e.Id + "@google.com"
+	}
+	w.prop("UID", icsEscapeText(uid))
+	stamp := time.Now().UTC()
+	if updated, err := time.Parse(time.RFC3339, e.Updated); err == nil {
+		stamp = updated.UTC()
+	}
+	w.prop("DTSTAMP", stamp.Format(icsUTCLayout))
+	if created, err := time.Parse(time.RFC3339, e.Created); err == nil {
+		w.prop("CREATED", created.UTC().Format(icsUTCLayout))
+	}
+	if e.Updated != "" {
+		w.prop("LAST-MODIFIED", stamp.Format(icsUTCLayout))
+	}
+	dateProp("DTSTART", e.Start)
+	dateProp("DTEND", e.End)
+	if e.OriginalStartTime != nil {
+		dateProp("RECURRENCE-ID", e.OriginalStartTime)
+	}
+	for _, rule := range append(append([]string{}, e.Recurrence...), exdates...) {
+		for _, m := range icsTZIDParam.FindAllStringSubmatch(rule, -1) {
+			tzids[strings.Trim(m[1], `"`)] = true
+		}
+		w.raw(rule)
+	}
+	if e.Summary != "" {
+		w.prop("SUMMARY", icsEscapeText(e.Summary))
+	}
+	if e.Description != "" {
+		w.prop("DESCRIPTION", icsEscapeText(e.Description))
+	}
+	if e.Location != "" {
+		w.prop("LOCATION", icsEscapeText(e.Location))
+	}
+	if e.Status != "" {
+		w.prop("STATUS", strings.ToUpper(e.Status))
+	}
+	if e.Transparency == "transparent" {
+		w.prop("TRANSP", "TRANSPARENT")
+	} else {
+		w.prop("TRANSP", "OPAQUE")
+	}
+	switch e.Visibility {
+	case "private", "confidential", "public":
+		w.prop("CLASS", strings.ToUpper(e.Visibility))
+	}
+	if e.Sequence > 0 {
+		w.prop("SEQUENCE", strconv.FormatInt(e.Sequence, 10))
+	}
+	if e.HtmlLink != "" {
+		w.prop("URL", e.HtmlLink)
+	}
+	if o := e.Organizer; o != nil && o.Email != "" {
+		w.prop("ORGANIZER", "mailto:"+o.Email, icsParam("CN", o.DisplayName))
+	}
+	for _, a := range e.Attendees {
+		if a == nil || a.Email == "" {
+			continue
+		}
+		role := "REQ-PARTICIPANT"
+		if a.Optional {
+			role = "OPT-PARTICIPANT"
+		}
+		cutype := ""
+		if a.Resource {
+			cutype = "CUTYPE=RESOURCE"
+		}
+		w.prop("ATTENDEE", "mailto:"+a.Email,
+			icsParam("CN", a.DisplayName), cutype, "ROLE="+role,
+			"PARTSTAT="+icsPartstat(a.ResponseStatus), "RSVP=TRUE")
+	}
+	if cd := e.ConferenceData; cd != nil {
+		label := ""
+		if cd.ConferenceSolution != nil {
+			label = cd.ConferenceSolution.Name
+		}
+		for _, ep := range cd.EntryPoints {
+			if ep == nil || ep.Uri == "" {
+				continue
+			}
+			feature := map[string]string{"video": "VIDEO", "phone": "PHONE", "sip": "AUDIO"}[ep.EntryPointType]
+			if feature == "" {
+				feature = "MODERATOR"
+			}
+			if ep.EntryPointType == "video" {
+				w.prop("X-GOOGLE-CONFERENCE", ep.Uri)
+			}
+			w.prop("CONFERENCE", ep.Uri, "VALUE=URI", "FEATURE="+feature, icsParam("LABEL", label))
+		}
+	}
+	if e.Reminders != nil {
+		for _, r := range e.Reminders.Overrides {
+			if r == nil {
+				continue
+			}
+			w.prop("BEGIN", "VALARM")
+			if r.Method == "email" {
+				w.prop("ACTION", "EMAIL")
+				w.prop("SUMMARY", icsEscapeText(e.Summary))
+			} else {
+				w.prop("ACTION", "DISPLAY")
+			}
+			w.prop("DESCRIPTION", icsEscapeText(e.Summary))
+			w.prop("TRIGGER", icsFormatDuration(-time.Duration(r.Minutes)*time.Minute))
+			w.prop("END", "VALARM")
+		}
+	}
+	w.prop("END", "VEVENT")
+}
+
+func icsPartstat(status string) string {
+	switch status {
+	case "accepted":
+		return "ACCEPTED"
+	case "declined":
+		return "DECLINED"
+	case "tentative":
+		return "TENTATIVE"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+func icsResponseStatus(partstat string) string {
+	switch strings.ToUpper(partstat) {
+	case "ACCEPTED":
+		return "accepted"
+	case "DECLINED":
+		return "declined"
+	case "TENTATIVE":
+		return "tentative"
+	default:
+		return "needsAction"
+	}
+}
+
+// icsFormatDuration formats d as an RFC 5545 DURATION (e.g. -PT15M, P1D).
+func icsFormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	out := sign + "P"
+	if days > 0 {
+		out += fmt.Sprintf("%dD", days)
+	}
+	if d > 0 || days == 0 {
+		out += "T"
+		if h := d / time.Hour; h > 0 {
+			out += fmt.Sprintf("%dH", h)
+			d -= h * time.Hour
+		}
+		if m := d / time.Minute; m > 0 || d == 0 {
+			out += fmt.Sprintf("%dM", m)
+			d -= m * time.Minute
+		}
+		if s := d / time.Second; s > 0 {
+			out += fmt.Sprintf("%dS", s)
+		}
+	}
+	return out
+}
+
+var icsDurationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+func icsParseDuration(s string) (time.Duration, error) {
+	m := icsDurationRe.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil || s == "P" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] != "" {
+			n, _ := strconv.Atoi(m[i+2])
+			d += time.Duration(n) * unit
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// writeVTimezone describes a zone by this year's transitions, expressed as
+// yearly rules starting in 1970 so earlier and later events resolve too.
+func writeVTimezone(w *icsWriter, name string) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return
+	}
+	w.prop("BEGIN", "VTIMEZONE")
+	w.prop("TZID", name)
+	transitions := zoneTransitions(loc, time.Now().Year())
+	if len(transitions) == 0 {
+		abbrev, offset := time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, loc).Zone()
+		w.prop("BEGIN", "STANDARD")
+		w.prop("DTSTART", "19700101T000000")
+		w.prop("TZOFFSETFROM", icsOffset(offset))
+		w.prop("TZOFFSETTO", icsOffset(offset))
+		w.prop("TZNAME", abbrev)
+		w.prop("END", "STANDARD")
+	}
+	for _, tr := range transitions {
+		kind := "STANDARD"
+		if tr.At.In(loc).IsDST() {
+			kind = "DAYLIGHT"
+		}
+		// Observance onsets are wall-clock times in the previous offset.
+		local := tr.At.In(time.FixedZone("", tr.From))
+		nth := (local.Day()-1)/7 + 1
+		if local.AddDate(0, 0, 7).Month() != local.Month() {
+			nth = -1
+		}
+		start := nthWeekday(1970, local.Month(), nth, local.Weekday())
+		w.prop("BEGIN", kind)
+		w.prop("DTSTART", start.Format(icsDateLayout)+local.Format("T150405"))
+		w.prop("RRULE", fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(local.Month()), nth, icsWeekday(local.Weekday())))
+		w.prop("TZOFFSETFROM", icsOffset(tr.From))
+		w.prop("TZOFFSETTO", icsOffset(tr.To))
+		w.prop("TZNAME", tr.Abbrev)
+		w.prop("END", kind)
+	}
+	w.prop("END", "VTIMEZONE")
+}
+
+type zoneTransition struct {
+	At     time.Time
+	From   int
+	To     int
+	Abbrev string
+}
+
+// zoneTransitions finds the UTC offset changes of loc during year.
+func zoneTransitions(loc *time.Location, year int) []zoneTransition {
+	var out []zoneTransition
+	prev := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	_, prevOffset := prev.Zone()
+	for day := prev.AddDate(0, 0, 1); day.Year() == year; day = day.AddDate(0, 0, 1) {
+		if _, offset := day.Zone(); offset != prevOffset {
+			lo, hi := prev, day
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, o := mid.Zone(); o == prevOffset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			abbrev, _ := hi.Zone()
+			out = append(out, zoneTransition{At: hi.UTC(), From: prevOffset, To: offset, Abbrev: abbrev})
+			prevOffset = offset
+		}
+		prev = day
+	}
+	return out
+}
+
+// nthWeekday returns the nth (or, for -1, the last) weekday of a month.
+func nthWeekday(year int, month time.Month, nth int, weekday time.Weekday) time.Time {
+	if nth < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(weekday) + 7) % 7))
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(nth-1))
+}
+
+func icsWeekday(d time.Weekday) string {
+	return []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[d]
+}
+
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	out := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if s := seconds % 60; s != 0 {
+		out += fmt.Sprintf("%02d", s)
+	}
+	return out
+}
+
+type icsProp struct {
+	Name   string
+	Params map[string]string
+	Value  string
+	Raw    string
+}
+
+type icsComponent struct {
+	Name     string
+	Props    []icsProp
+	Children []*icsComponent
+}
+
+func (c *icsComponent) prop(name string) *icsProp {
+	for i := range c.Props {
+		if c.Props[i].Name == name {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+func (c *icsComponent) value(name string) string {
+	if p := c.prop(name); p != nil {
+		return p.Value
+	}
+	return ""
+}
+
+// parseICS unfolds and parses an iCalendar stream into its component tree.
+func parseICS(data string) (*icsComponent, error) {
+	data = strings.ReplaceAll(strings.TrimPrefix(data, "\ufeff"), "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+	}
+
+	root := &icsComponent{}
+	stack := []*icsComponent{root}
+	for _, line := range lines {
+		p, err := parseICSLine(line)
+		if err != nil {
+			return nil, err
+		}
+		cur := stack[len(stack)-1]
+		switch p.Name {
+		case "BEGIN":
+			child := &icsComponent{Name: strings.ToUpper(p.Value)}
+			cur.Children = append(cur.Children, child)
+			stack = append(stack, child)
+		case "END":
+			if len(stack) == 1 || cur.Name != strings.ToUpper(p.Value) {
+				return nil, fmt.Errorf("unexpected END:%s", p.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			cur.Props = append(cur.Props, p)
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("unterminated %s", stack[len(stack)-1].Name)
+	}
+	if len(root.Children) == 0 {
+		return nil, errors.New("no iCalendar data")
+	}
+	return root, nil
+}
+
+func parseICSLine(line string) (icsProp, error) {
+	p := icsProp{Params: map[string]string{}, Raw: line}
+	inQuote := false
+	start := 0
+	var parts []string
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuote = !inQuote
+		case ';':
+			if !inQuote {
+				parts = append(parts, line[start:i])
+				start = i + 1
+			}
+		case ':':
+			if !inQuote {
+				parts = append(parts, line[start:i])
+				p.Value = line[i+1:]
+				p.Name = strings.ToUpper(parts[0])
+				for _, param := range parts[1:] {
+					k, v, _ := strings.Cut(param, "=")
+					p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+				}
+				return p, nil
+			}
+		}
+	}
+	return p, fmt.Errorf("invalid iCalendar line %q", line)
+}
+
+// icsEvent is a VEVENT mapped onto a Calendar API event.
+type icsEvent struct {
+	Event    *calendar.Event
+	Modified time.Time
+	Skip     string
+}
+
+// icsEvents maps every VEVENT of a calendar. Events that cannot be imported
+// carry a Skip reason.
+func icsEvents(root *icsComponent, defaultTZ string) []*icsEvent {
+	var out []*icsEvent
+	for _, cal := range root.Children {
+		tz := defaultTZ
+		if wr := strings.TrimSpace(cal.value("X-WR-TIMEZONE")); wr != "" {
+			if _, err := time.LoadLocation(wr); err == nil {
+				tz = wr
+			}
+		}
+		for _, comp := range cal.Children {
+			if comp.Name == "VEVENT" {
+				out = append(out, icsToEvent(comp, tz))
+			}
+		}
+	}
+	return out
+}
+
+func icsToEvent(comp *icsComponent, defaultTZ string) *icsEvent {
+	e := &calendar.Event{
+		ICalUID:     strings.TrimSpace(icsUnescapeText(comp.value("UID"))),
+		Summary:     icsUnescapeText(comp.value("SUMMARY")),
+		Description: icsUnescapeText(comp.value("DESCRIPTION")),
+		Location:    icsUnescapeText(comp.value("LOCATION")),
+	}
+	out := &icsEvent{Event: e}
+	if e.ICalUID == "" {
+		out.Skip = "missing UID"
+		return out
+	}
+	start := comp.prop("DTSTART")
+	if start == nil {
+		out.Skip = "missing DTSTART"
+		return out
+	}
+	var err error
+	var startAt time.Time
+	if e.Start, startAt, err = icsToDateTime(start, defaultTZ); err != nil {
+		out.Skip = err.Error()
+		return out
+	}
+	switch end, dur := comp.prop("DTEND"), comp.value("DURATION"); {
+	case end != nil:
+		if e.End, _, err = icsToDateTime(end, defaultTZ); err != nil {
+			out.Skip = err.Error()
+			return out
+		}
+	case dur != "":
+		d, err := icsParseDuration(dur)
+		if err != nil {
+			out.Skip = err.Error()
+			return out
+		}
+		e.End = icsShiftDateTime(e.Start, startAt, d)
+	case e.Start.Date != "":
+		e.End = icsShiftDateTime(e.Start, startAt, 24*time.Hour)
+	default:
+		e.End = icsShiftDateTime(e.Start, startAt, 0)
+	}
+	if rid := comp.prop("RECURRENCE-ID"); rid != nil {
+		if e.OriginalStartTime, _, err = icsToDateTime(rid, defaultTZ); err != nil {
+			out.Skip = err.Error()
+			return out
+		}
+	}
+
+	for _, p := range comp.Props {
+		switch p.Name {
+		case "RRULE", "EXRULE", "RDATE", "EXDATE":
+			e.Recurrence = append(e.Recurrence, p.Raw)
+		case "ATTENDEE":
+			email := icsMailto(p.Value)
+			if email == "" {
+				continue
+			}
+			e.Attendees = append(e.Attendees, &calendar.EventAttendee{
+				Email:          email,
+				DisplayName:    p.Params["CN"],
+				Optional:       strings.EqualFold(p.Params["ROLE"], "OPT-PARTICIPANT"),
+				Resource:       strings.EqualFold(p.Params["CUTYPE"], "RESOURCE") || strings.EqualFold(p.Params["CUTYPE"], "ROOM"),
+				ResponseStatus: icsResponseStatus(p.Params["PARTSTAT"]),
+			})
+		case "CONFERENCE", "X-GOOGLE-CONFERENCE":
+			icsAddConference(e, p)
+		}
+	}
+	if len(e.Recurrence) > 0 && e.Start.Date == "" && e.Start.TimeZone == "" {
+		// Recurring events need a zone to expand in.
+		e.Start.TimeZone, e.End.TimeZone = "UTC", "UTC"
+	}
+	if o := comp.prop("ORGANIZER"); o != nil {
+		if email := icsMailto(o.Value); email != "" {
+			e.Organizer = &calendar.EventOrganizer{Email: email, DisplayName: o.Params["CN"]}
+		}
+	}
+	switch strings.ToUpper(comp.value("STATUS")) {
+	case "TENTATIVE":
+		e.Status = "tentative"
+	case "CANCELLED":
+		e.Status = "cancelled"
+	case "CONFIRMED":
+		e.Status = "confirmed"
+	}
+	if strings.EqualFold(comp.value("TRANSP"), "TRANSPARENT") {
+		e.Transparency = "transparent"
+	}
+	switch class := strings.ToUpper(comp.value("CLASS")); class {
+	case "PRIVATE", "PUBLIC", "CONFIDENTIAL":
+		e.Visibility = strings.ToLower(class)
+	}
+	if seq, err := strconv.ParseInt(comp.value("SEQUENCE"), 10, 64); err == nil {
+		e.Sequence = seq
+	}
+	e.Reminders = icsReminders(comp)
+
+	for _, name := range []string{"LAST-MODIFIED", "DTSTAMP"} {
+		if t, err := time.Parse(icsUTCLayout, comp.value(name)); err == nil {
+			out.Modified = t
+			break
+		}
+	}
+	return out
+}
+
+// icsToDateTime converts a DATE or DATE-TIME property. Floating times and
+// unknown TZIDs are read in defaultTZ.
+func icsToDateTime(p *icsProp, defaultTZ string) (*calendar.EventDateTime, time.Time, error) {
+	value := strings.TrimSpace(p.Value)
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(value) == len(icsDateLayout) {
+		d, err := time.Parse(icsDateLayout, value)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid %s %q", p.Name, value)
+		}
+		return &calendar.EventDateTime{Date: d.Format("2006-01-02")}, d, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icsUTCLayout, value)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("invalid %s %q", p.Name, value)
+		}
+		return &calendar.EventDateTime{DateTime: t.Format(time.RFC3339)}, t, nil
+	}
+	tz := strings.TrimPrefix(p.Params["TZID"], "/")
+	loc, err := time.LoadLocation(tz)
+	if tz == "" || err != nil {
+		tz = defaultTZ
+		if loc, err = time.LoadLocation(tz); err != nil {
+			tz, loc = "UTC", time.UTC
+		}
+	}
+	t, err := time.ParseInLocation(icsLocalLayout, value, loc)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid %s %q", p.Name, value)
+	}
+	return &calendar.EventDateTime{DateTime: t.Format(time.RFC3339), TimeZone: tz}, t, nil
+}
+
+func icsShiftDateTime(start *calendar.EventDateTime, at time.Time, d time.Duration) *calendar.EventDateTime {
+	if start.Date != "" {
+		days := int(d / (24 * time.Hour))
+		if days < 1 {
+			days = 1
+		}
+		return &calendar.EventDateTime{Date: at.AddDate(0, 0, days).Format("2006-01-02")}
+	}
+	return &calendar.EventDateTime{DateTime: at.Add(d).Format(time.RFC3339), TimeZone: start.TimeZone}
+}
+
+func icsMailto(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > 7 && strings.EqualFold(value[:7], "mailto:") {
+		value = value[7:]
+	}
+	if !strings.Contains(value, "@") {
+		return ""
+	}
+	return value
+}
+
+func icsAddConference(e *calendar.Event, p icsProp) {
+	uri := strings.TrimSpace(p.Value)
+	if uri == "" {
+		return
+	}
+	if e.ConferenceData == nil {
+		e.ConferenceData = &calendar.ConferenceData{}
+	}
+	for _, ep := range e.ConferenceData.EntryPoints {
+		if ep.Uri == uri {
+			return
+		}
+	}
+	kind := "more"
+	switch feature := strings.ToUpper(p.Params["FEATURE"]); {
+	case p.Name == "X-GOOGLE-CONFERENCE" || strings.Contains(feature, "VIDEO"):
+		kind = "video"
+	case strings.HasPrefix(strings.ToLower(uri), "tel:"):
+		kind = "phone"
+	case strings.HasPrefix(strings.ToLower(uri), "sip:"):
+		kind = "sip"
+	}
+	e.ConferenceData.EntryPoints = append(e.ConferenceData.EntryPoints, &calendar.EntryPoint{EntryPointType: kind, Uri: uri})
+	if kind == "video" && strings.Contains(uri, "meet.google.com/") {
+		e.ConferenceData.ConferenceSolution = &calendar.ConferenceSolution{Key: &calendar.ConferenceSolutionKey{Type: "hangoutsMeet"}}
+		e.ConferenceData.ConferenceId = uri[strings.LastIndex(uri, "/")+1:]
+	}
+}
+
+// icsReminders maps VALARMs that fire before the start onto reminder
+// overrides; Google keeps at most five.
+func icsReminders(comp *icsComponent) *calendar.EventReminders {
+	var overrides []*calendar.EventReminder
+	for _, alarm := range comp.Children {
+		if alarm.Name != "VALARM" || len(overrides) == icsMaxGoogleAlarm {
+			continue
+		}
+		trigger := alarm.prop("TRIGGER")
+		if trigger == nil || strings.EqualFold(trigger.Params["RELATED"], "END") {
+			continue
+		}
+		d, err := icsParseDuration(trigger.Value)
+		if err != nil || d > 0 {
+			continue
+		}
+		method := "popup"
+		if strings.EqualFold(alarm.value("ACTION"), "EMAIL") {
+			method = "email"
+		}
+		overrides = append(overrides, &calendar.EventReminder{Method: method, Minutes: int64(-d / time.Minute), ForceSendFields: []string{"Minutes"}})
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	return &calendar.EventReminders{Overrides: overrides, ForceSendFields: []string{"UseDefault"}}
+}
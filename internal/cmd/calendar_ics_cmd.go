@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type CalendarExportCmd struct {
+	CalendarID string `arg:"" stdin:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+	TimeRangeFlags
+	Out string `name:"out" aliases:"output" help:"Output file path (default: stdout)"`
+}
+
+func (c *CalendarExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	outPath := strings.TrimSpace(c.Out)
+	if outPath == "-" {
+		outPath = ""
+	}
+	if outPath != "" {
+		outPath, err = config.ExpandPath(outPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.CalendarID)
+	if calendarID == "" {
+		calendarID = primaryCalendarID
+	}
+	calendarID, err = resolveCalendarID(ctx, svc, calendarID)
+	if err != nil {
+		return err
+	}
+	cal, err := svc.Calendars.Get(calendarID).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	// Without a range the whole calendar is exported.
+	var from, to string
+	if c.TimeRangeFlags != (TimeRangeFlags{}) {
+		timeRange, err := ResolveTimeRange(ctx, svc, c.TimeRangeFlags)
+		if err != nil {
+			return err
+		}
+		from, to = timeRange.FormatRFC3339()
+	}
+
+	// Series stay unexpanded so RRULEs survive; deleted instances are needed
+	// to write EXDATEs.
+	events, err := collectAllPages("", func(pageToken string) ([]*calendar.Event, string, error) {
+		call := svc.Events.List(calendarID).SingleEvents(false).ShowDeleted(true).MaxResults(2500).Context(ctx)
+		if from != "" {
+			call = call.TimeMin(from).TimeMax(to)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	content := writeICS(events, cal.Summary, cal.TimeZone)
+	if outPath == "" {
+		_, err := io.WriteString(os.Stdout, content)
+		return err
+	}
+	if err := writeFileAtomic(outPath, []byte(content)); err != nil {
+		return err
+	}
+	exported := 0
+	for _, e := range events {
+		if e.Status != "cancelled" {
+			exported++
+		}
+	}
+	return writeResult(ctx, u,
+		kv("calendarId", calendarID),
+		kv("path", outPath),
+		kv("events", exported),
+		kv("bytes", len(content)),
+	)
+}
+
+type CalendarImportCmd struct {
+	CalendarID string `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
+	File       string `arg:"" name:"file" help:"iCalendar (.ics) file ('-' for stdin)"`
+	Overwrite  bool   `name:"overwrite" help:"Import events even when the calendar copy is unchanged or newer"`
+}
+
+const (
+	icsImportCreated = "created"
+	icsImportUpdated = "updated"
+	icsImportSkipped = "skipped"
+	icsImportFailed  = "failed"
+)
+
+type icsImportResult struct {
+	UID     string `json:"uid"`
+	Summary string `json:"summary,omitempty"`
+	Start   string `json:"start,omitempty"`
+	Status  string `json:"status"`
+	ID      string `json:"id,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+func (c *CalendarImportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.CalendarID)
+	if calendarID == "" {
+		return usage("empty calendarId")
+	}
+	data, err := readICSInput(strings.TrimSpace(c.File))
+	if err != nil {
+		return err
+	}
+	root, err := parseICS(string(data))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", c.File, err)
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID, err = resolveCalendarID(ctx, svc, calendarID)
+	if err != nil {
+		return err
+	}
+	cal, err := svc.Calendars.Get(calendarID).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	items := icsEvents(root, cal.TimeZone)
+	results := make([]*icsImportResult, len(items))
+	for i, item := range items {
+		e := item.Event
+		r := &icsImportResult{UID: e.ICalUID, Summary: e.Summary, Start: eventStart(e), Status: icsImportSkipped, Reason: item.Skip}
+		results[i] = r
+		if r.Reason == "" && e.Status == "cancelled" {
+			r.Reason = "cancelled"
+		}
+		if r.Reason != "" {
+			continue
+		}
+		existing, err := findICalEvent(ctx, svc, calendarID, e)
+		if err != nil {
+			return err
+		}
+		switch {
+		case existing == nil || existing.Status == "cancelled":
+			r.Status = icsImportCreated
+		case !c.Overwrite && icsUnchanged(item, existing):
+			r.ID, r.Reason = existing.Id, "unchanged"
+		default:
+			r.Status, r.ID = icsImportUpdated, existing.Id
+		}
+	}
+
+	if err := dryRunExit(ctx, flags, "calendar.import", map[string]any{
+		"calendar_id": calendarID,
+		"results":     results,
+	}); err != nil {
+		return err
+	}
+
+	failed := 0
+	for i, r := range results {
+		if r.Status == icsImportSkipped {
+			continue
+		}
+		imported, err := importICSEvent(ctx, svc, calendarID, items[i].Event)
+		if err != nil {
+			r.Status, r.Reason = icsImportFailed, err.Error()
+			failed++
+			continue
+		}
+		r.ID = imported.Id
+	}
+
+	if err := writeICSImportResults(ctx, u, calendarID, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d events failed to import", failed, len(results))
+	}
+	return nil
+}
+
+func readICSInput(path string) ([]byte, error) {
+	if path == "" {
+		return nil, usage("missing file")
+	}
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path) //nolint:gosec // user-provided path
+}
+
+// findICalEvent returns the calendar's copy of e (the series or, for a
+// RECURRENCE-ID override, that instance), including deleted copies.
+func findICalEvent(ctx context.Context, svc *calendar.Service, calendarID string, e *calendar.Event) (*calendar.Event, error) {
+	matches, err := collectAllPages("", func(pageToken string) ([]*calendar.Event, string, error) {
+		call := svc.Events.List(calendarID).ICalUID(e.ICalUID).ShowDeleted(true).Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Items, resp.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		if e.OriginalStartTime == nil && m.RecurringEventId == "" {
+			return m, nil
+		}
+		if e.OriginalStartTime != nil && m.OriginalStartTime != nil && sameEventTime(e.OriginalStartTime, m.OriginalStartTime) {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func sameEventTime(a, b *calendar.EventDateTime) bool {
+	if a.Date != "" || b.Date != "" {
+		return a.Date == b.Date
+	}
+	ta, errA := time.Parse(time.RFC3339, a.DateTime)
+	tb, errB := time.Parse(time.RFC3339, b.DateTime)
+	return errA == nil && errB == nil && ta.Equal(tb)
+}
+
+// icsUnchanged reports whether the calendar copy is at least as new as the
+// file's event (by SEQUENCE and LAST-MODIFIED/DTSTAMP).
+func icsUnchanged(item *icsEvent, existing *calendar.Event) bool {
+	if item.Event.Sequence > existing.Sequence || item.Modified.IsZero() {
+		return false
+	}
+	updated, err := time.Parse(time.RFC3339, existing.Updated)
+	return err == nil && !item.Modified.After(updated)
+}
+
+// importICSEvent upserts by iCalUID. When Google rejects the conference data
+// (a 400 about conferenceData), the links are kept in the description instead;
+// every other error is returned as is.
+func importICSEvent(ctx context.Context, svc *calendar.Service, calendarID string, e *calendar.Event) (*calendar.Event, error) {
+	call := svc.Events.Import(calendarID, e).Context(ctx)
+	if e.ConferenceData == nil {
+		return call.Do()
+	}
+	imported, err := call.ConferenceDataVersion(1).Do()
+	if !isConferenceDataRejected(err) {
+		return imported, err
+	}
+	fallback := *e
+	fallback.ConferenceData = nil
+	for _, ep := range e.ConferenceData.EntryPoints {
+		if !strings.Contains(fallback.Description, ep.Uri) {
+			fallback.Description = strings.TrimSpace(fallback.Description + "\n\n" + ep.Uri)
+		}
+	}
+	return svc.Events.Import(calendarID, &fallback).Context(ctx).Do()
+}
+
+func isConferenceDataRejected(err error) bool {
+	var gerr *gapi.Error
+	if !errors.As(err, &gerr) || gerr.Code != http.StatusBadRequest {
+		return false
+	}
+	if strings.Contains(strings.ToLower(gerr.Message), "conference") {
+		return true
+	}
+	for _, item := range gerr.Errors {
+		if strings.Contains(strings.ToLower(item.Message+" "+item.Reason), "conference") {
+			return true
+		}
+	}
+	return false
+}
+
+func writeICSImportResults(ctx context.Context, u *ui.UI, calendarID string, results []*icsImportResult) error {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"calendarId": calendarID,
+			"created":    counts[icsImportCreated],
+			"updated":    counts[icsImportUpdated],
+			"skipped":    counts[icsImportSkipped],
+			"failed":     counts[icsImportFailed],
+			"results":    results,
+		})
+	}
+	if len(results) == 0 {
+		u.Err().Println("No events found")
+		return nil
+	}
+	w, flush := tableWriter(ctx)
+	fmt.Fprintln(w, "STATUS\tSTART\tSUMMARY\tID\tREASON")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Status, r.Start, sanitizeTab(r.Summary), r.ID, sanitizeTab(r.Reason))
+	}
+	flush()
+	u.Err().Printf("%d created, %d updated, %d skipped, %d failed", counts[icsImportCreated], counts[icsImportUpdated], counts[icsImportSkipped], counts[icsImportFailed])
+	return nil
+}
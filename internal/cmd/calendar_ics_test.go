@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestICSRoundTrip(t *testing.T) {
+	long := strings.Repeat("Agenda: növel, items; and more\n", 4)
+	events := []*calendar.Event{
+		{
+			Id:          "series1",
+			ICalUID:     "series1@google.com",
+			Summary:     "Standup",
+			Description: long,
+			Updated:     "2026-01-02T10:00:00Z",
+			Sequence:    2,
+			Start:       &calendar.EventDateTime{DateTime: "2026-01-05T09:00:00+01:00", TimeZone: "Europe/Berlin"},
+			End:         &calendar.EventDateTime{DateTime: "2026-01-05T09:15:00+01:00", TimeZone: "Europe/Berlin"},
+			Recurrence:  []string{"RRULE:FREQ=WEEKLY;BYDAY=MO,WE"},
+			Organizer:   &calendar.EventOrganizer{Email: "boss@example.com", DisplayName: "Boss, The"},
+			Attendees: []*calendar.EventAttendee{
+				{Email: "a@example.com", ResponseStatus: "accepted"},
+				{Email: "b@example.com", Optional: true, ResponseStatus: "tentative"},
+				{Email: "room@resource.calendar.google.com", Resource: true},
+			},
+			Reminders: &calendar.EventReminders{Overrides: []*calendar.EventReminder{{Method: "popup", Minutes: 10}, {Method: "email", Minutes: 1440}}},
+			ConferenceData: &calendar.ConferenceData{
+				ConferenceSolution: &calendar.ConferenceSolution{Name: "Google Meet"},
+				EntryPoints:        []*calendar.EntryPoint{{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"}},
+			},
+			Visibility:   "private",
+			Transparency: "transparent",
+		},
+		{
+			Id:                "series1_20260107T080000Z",
+			RecurringEventId:  "series1",
+			Status:            "cancelled",
+			OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-01-07T09:00:00+01:00", TimeZone: "Europe/Berlin"},
+		},
+		{
+			Id:      "allday",
+			ICalUID: "allday@google.com",
+			Summary: "Offsite",
+			Start:   &calendar.EventDateTime{Date: "2026-02-10"},
+			End:     &calendar.EventDateTime{Date: "2026-02-12"},
+		},
+	}
+
+	out := writeICS(events, "Team", "Europe/Berlin")
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > icsMaxLineOctets {
+			t.Fatalf("line not folded (%d octets): %q", len(line), line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		"X-WR-CALNAME:Team",
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"DTSTART;TZID=Europe/Berlin:20260105T090000",
+		"EXDATE;TZID=Europe/Berlin:20260107T090000",
+		`ORGANIZER;CN="Boss, The":mailto:boss@example.com`,
+		"CONFERENCE;VALUE=URI;FEATURE=VIDEO;LABEL=Google Meet:https://meet.google.com/abc-defg-hij",
+		"TRIGGER:-P1D",
+		"DTSTART;VALUE=DATE:20260210",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "series1_20260107") {
+		t.Fatalf("cancelled instance must only appear as EXDATE")
+	}
+
+	root, err := parseICS(out)
+	if err != nil {
+		t.Fatalf("parseICS: %v", err)
+	}
+	items := icsEvents(root, "UTC")
+	if len(items) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(items))
+	}
+	got := items[0].Event
+	if items[0].Skip != "" || got.ICalUID != "series1@google.com" || got.Description != long || got.Sequence != 2 {
+		t.Fatalf("unexpected event: skip=%q %+v", items[0].Skip, got)
+	}
+	if got.Start.DateTime != "2026-01-05T09:00:00+01:00" || got.Start.TimeZone != "Europe/Berlin" || got.End.DateTime != "2026-01-05T09:15:00+01:00" {
+		t.Fatalf("unexpected times: %+v %+v", got.Start, got.End)
+	}
+	if strings.Join(got.Recurrence, "|") != "RRULE:FREQ=WEEKLY;BYDAY=MO,WE|EXDATE;TZID=Europe/Berlin:20260107T090000" {
+		t.Fatalf("unexpected recurrence: %v", got.Recurrence)
+	}
+	if got.Organizer.DisplayName != "Boss, The" || len(got.Attendees) != 3 || !got.Attendees[1].Optional || got.Attendees[1].ResponseStatus != "tentative" || !got.Attendees[2].Resource {
+		t.Fatalf("unexpected people: %+v %+v", got.Organizer, got.Attendees)
+	}
+	if len(got.Reminders.Overrides) != 2 || got.Reminders.Overrides[1].Method != "email" || got.Reminders.Overrides[1].Minutes != 1440 {
+		t.Fatalf("unexpected reminders: %+v", got.Reminders.Overrides)
+	}
+	if got.ConferenceData == nil || got.ConferenceData.ConferenceId != "abc-defg-hij" || len(got.ConferenceData.EntryPoints) != 1 {
+		t.Fatalf("unexpected conference: %+v", got.ConferenceData)
+	}
+	if got.Visibility != "private" || got.Transparency != "transparent" || !items[0].Modified.Equal(time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected flags: %+v modified %v", got, items[0].Modified)
+	}
+	if d := items[1].Event; d.Start.Date != "2026-02-10" || d.End.Date != "2026-02-12" {
+		t.Fatalf("unexpected all-day event: %+v %+v", d.Start, d.End)
+	}
+}
+
+func TestICSWriterFoldsAt75Octets(t *testing.T) {
+	for _, value := range []string{strings.Repeat("a", 400), strings.Repeat("ü", 200), strings.Repeat("x", 74)} {
+		var w icsWriter
+		w.prop("DESCRIPTION", value)
+		out := w.String()
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			if len(line) > icsMaxLineOctets {
+				t.Fatalf("line has %d octets: %q", len(line), line)
+			}
+			if !utf8.ValidString(strings.TrimPrefix(line, " ")) {
+				t.Fatalf("line splits a UTF-8 sequence: %q", line)
+			}
+		}
+		if got := strings.ReplaceAll(out, "\r\n ", ""); got != "DESCRIPTION:"+value+"\r\n" {
+			t.Fatalf("unfolding changed the value: %q", got)
+		}
+	}
+}
+
+func TestParseICSExternal(t *testing.T) {
+	data := "BEGIN:VCALENDAR\nVERSION:2.0\nX-WR-TIMEZONE:America/New_York\n" +
+		"BEGIN:VEVENT\nUID:ext-1\nDTSTAMP:20260101T000000Z\nDTSTART:20260301T140000\nDURATION:PT1H30M\n" +
+		"SUMMARY:Floating\\, long\n  title\n" +
+		"BEGIN:VALARM\nACTION:DISPLAY\nTRIGGER:-PT15M\nEND:VALARM\n" +
+		"BEGIN:VALARM\nACTION:DISPLAY\nTRIGGER;RELATED=END:PT0M\nEND:VALARM\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nUID:ext-2\nDTSTART;TZID=W. Europe Standard Time:20260302T090000\nEND:VEVENT\n" +
+		"BEGIN:VEVENT\nDTSTART:20260303T090000Z\nEND:VEVENT\nEND:VCALENDAR\n"
+	root, err := parseICS(data)
+	if err != nil {
+		t.Fatalf("parseICS: %v", err)
+	}
+	items := icsEvents(root, "UTC")
+	if len(items) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(items))
+	}
+	e := items[0].Event
+	if e.Summary != "Floating, long title" || e.Start.DateTime != "2026-03-01T14:00:00-05:00" || e.Start.TimeZone != "America/New_York" || e.End.DateTime != "2026-03-01T15:30:00-05:00" {
+		t.Fatalf("unexpected floating event: %q %+v %+v", e.Summary, e.Start, e.End)
+	}
+	if len(e.Reminders.Overrides) != 1 || e.Reminders.Overrides[0].Minutes != 15 {
+		t.Fatalf("unexpected reminders: %+v", e.Reminders.Overrides)
+	}
+	if e2 := items[1].Event; e2.Start.TimeZone != "America/New_York" || e2.End.DateTime != e2.Start.DateTime {
+		t.Fatalf("unknown TZID should fall back to the calendar zone: %+v %+v", e2.Start, e2.End)
+	}
+	if items[2].Skip != "missing UID" {
+		t.Fatalf("expected the UID-less event to be skipped: %+v", items[2])
+	}
+	if _, err := parseICS("BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n"); err == nil {
+		t.Fatalf("expected an error for mismatched END")
+	}
+}
+
+func TestCalendarImportExportCmds(t *testing.T) {
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+
+	existing := map[string]map[string]any{
+		"same@x":  {"id": "ev-same", "iCalUID": "same@x", "updated": "2026-01-10T00:00:00Z"},
+		"older@x": {"id": "ev-older", "iCalUID": "older@x", "updated": "2025-12-01T00:00:00Z"},
+	}
+	var imported []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/calendar/v3")
+		switch {
+		case path == "/calendars/cal@example.com" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "cal@example.com", "summary": "Team", "timeZone": "UTC"})
+		case path == "/calendars/cal@example.com/events" && r.Method == http.MethodGet:
+			if uid := r.URL.Query().Get("iCalUID"); uid != "" {
+				items := []map[string]any{}
+				if ev := existing[uid]; ev != nil {
+					items = append(items, ev)
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
+				return
+			}
+			if r.URL.Query().Get("singleEvents") != "false" || r.URL.Query().Get("showDeleted") != "true" {
+				t.Errorf("unexpected export query: %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"items": []map[string]any{{
+				"id": "e1", "iCalUID": "e1@google.com", "summary": "Review",
+				"start": map[string]any{"dateTime": "2026-01-05T10:00:00Z"},
+				"end":   map[string]any{"dateTime": "2026-01-05T11:00:00Z"},
+			}}})
+		case path == "/calendars/cal@example.com/events/import" && r.Method == http.MethodPost:
+			var ev calendar.Event
+			_ = json.NewDecoder(r.Body).Decode(&ev)
+			imported = append(imported, ev.ICalUID)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "new-" + ev.ICalUID})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	svc := newCalendarServiceFromServer(t, srv)
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+	ctx := newCalendarJSONContext(t)
+
+	out := captureStdout(t, func() {
+		if err := runKong(t, &CalendarExportCmd{}, []string{"cal@example.com"}, ctx, &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+	})
+	if !strings.Contains(out, "UID:e1@google.com") || !strings.Contains(out, "DTSTART:20260105T100000Z") || !strings.Contains(out, "X-WR-CALNAME:Team") {
+		t.Fatalf("unexpected export:\n%s", out)
+	}
+
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nUID:same@x\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260105T100000Z\r\nSUMMARY:Same\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:older@x\r\nDTSTAMP:20260101T000000Z\r\nDTSTART:20260106T100000Z\r\nSUMMARY:Changed\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:new@x\r\nDTSTART;VALUE=DATE:20260107\r\nSUMMARY:New\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:gone@x\r\nSTATUS:CANCELLED\r\nDTSTART:20260108T100000Z\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	file := filepath.Join(t.TempDir(), "in.ics")
+	if err := os.WriteFile(file, []byte(ics), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runKong(t, &CalendarImportCmd{}, []string{"cal@example.com", file}, ctx, &RootFlags{Account: "a@b.com", DryRun: true}); ExitCode(err) != 0 {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if len(imported) != 0 || !strings.Contains(out, `"status": "created"`) {
+		t.Fatalf("dry run must only plan: %v\n%s", imported, out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runKong(t, &CalendarImportCmd{}, []string{"cal@example.com", file}, ctx, &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("import: %v", err)
+		}
+	})
+	var parsed struct {
+		Created, Updated, Skipped int
+		Results                   []icsImportResult
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if parsed.Created != 1 || parsed.Updated != 1 || parsed.Skipped != 2 {
+		t.Fatalf("unexpected counts: %+v", parsed)
+	}
+	sort.Strings(imported)
+	if strings.Join(imported, ",") != "new@x,older@x" {
+		t.Fatalf("unexpected imports: %v", imported)
+	}
+	if parsed.Results[0].Reason != "unchanged" || parsed.Results[3].Reason != "cancelled" {
+		t.Fatalf("unexpected skip reasons: %+v", parsed.Results)
+	}
+}
+
+func TestImportICSEventConferenceFallback(t *testing.T) {
+	var status int
+	var message string
+	var calls []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev calendar.Event
+		_ = json.NewDecoder(r.Body).Decode(&ev)
+		calls = append(calls, ev.ConferenceData != nil)
+		w.Header().Set("Content-Type", "application/json")
+		if ev.ConferenceData != nil {
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": status, "message": message}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ev)
+	}))
+	defer srv.Close()
+	svc := newCalendarServiceFromServer(t, srv)
+
+	event := func() *calendar.Event {
+		return &calendar.Event{
+			ICalUID: "c@x",
+			ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+				{EntryPointType: "video", Uri: "https://meet.example.com/abc"},
+			}},
+		}
+	}
+
+	status, message = http.StatusBadRequest, "Invalid conference type value."
+	got, err := importICSEvent(context.Background(), svc, "primary", event())
+	if err != nil || len(calls) != 2 || !strings.Contains(got.Description, "https://meet.example.com/abc") {
+		t.Fatalf("expected a fallback keeping the link, got %v calls=%v %+v", err, calls, got)
+	}
+
+	for _, tc := range []struct {
+		status  int
+		message string
+	}{
+		{http.StatusForbidden, "Rate Limit Exceeded"},
+		{http.StatusBadRequest, "The specified time range is empty."},
+	} {
+		calls = nil
+		status, message = tc.status, tc.message
+		if _, err := importICSEvent(context.Background(), svc, "primary", event()); err == nil || len(calls) != 1 {
+			t.Fatalf("%d %q: expected the error without a retry, got %v calls=%v", tc.status, tc.message, err, calls)
+		}
+	}
+}
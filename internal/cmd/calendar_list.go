@@ -48,6 +48,12 @@ func listCalendarEvents(ctx context.Context, svc *calendar.Service, calendarID,
 		return resp.Items, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, allPages, page, failEmpty, fetch, func(items []*calendar.Event) ([]*eventWithDays, error) {
+		return wrapEventsWithDays(items), nil
+	}); handled {
+		return err
+	}
+
 	var items []*calendar.Event
 	nextPageToken := ""
 	if allPages {
@@ -12,7 +12,7 @@ import (
 )
 
 type CalendarOOOCmd struct {
-	CalendarID     string `arg:"" name:"calendarId" help:"Calendar ID (default: primary)" default:"primary"`
+	CalendarID     string `arg:"" stdin:"" name:"calendarId" help:"Calendar ID (default: primary)" default:"primary"`
 	Summary        string `name:"summary" help:"Out of office title" default:"Out of office"`
 	From           string `name:"from" required:"" help:"Start date or datetime (RFC3339 or YYYY-MM-DD)"`
 	To             string `name:"to" required:"" help:"End date or datetime (RFC3339 or YYYY-MM-DD)"`
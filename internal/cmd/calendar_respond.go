@@ -14,8 +14,8 @@ import (
 )
 
 type CalendarRespondCmd struct {
-	CalendarID string `arg:"" name:"calendarId" help:"Calendar ID"`
-	EventID    string `arg:"" name:"eventId" help:"Event ID"`
+	CalendarID string `arg:"" stdin:"" name:"calendarId" help:"Calendar ID"`
+	EventID    string `arg:"" stdin:"" name:"eventId" help:"Event ID"`
 	Status     string `name:"status" help:"Response status (accepted, declined, tentative, needsAction)"`
 	Comment    string `name:"comment" help:"Optional comment/note to include with response"`
 }
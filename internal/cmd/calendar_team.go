@@ -3,14 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"google.golang.org/api/calendar/v3"
 
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -164,90 +167,67 @@ func (c *CalendarTeamCmd) runFreeBusy(ctx context.Context, svc *calendar.Service
 
 func (c *CalendarTeamCmd) runEvents(ctx context.Context, svc *calendar.Service, u *ui.UI, emails []string, tr *TimeRange) error {
 	var (
-		mu     sync.Mutex
 		events []teamEvent
 		errors []string
-		wg     sync.WaitGroup
-		sem    = make(chan struct{}, 10) // max 10 concurrent requests
 	)
 
 	queryLower := strings.ToLower(c.Query)
 
-	for _, email := range emails {
-		wg.Add(1)
-		go func(email string) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			call := svc.Events.List(email).
-				SingleEvents(true).
-				TimeMin(tr.From.Format(time.RFC3339)).
-				TimeMax(tr.To.Format(time.RFC3339)).
-				MaxResults(c.Max).
-				OrderBy("startTime").
-				Context(ctx)
-
-			resp, err := call.Do()
-			if err != nil {
-				mu.Lock()
-				errors = append(errors, fmt.Sprintf("%s: %v", email, err))
-				mu.Unlock()
-				return
+	if b := batcherFor(svc); b != nil {
+		paths := make([]string, len(emails))
+		for i, email := range emails {
+			paths[i] = teamEventsBatchPath(email, tr, c.Max)
+		}
+		lists, errs, err := googleapi.BatchGet[calendar.Events](ctx, b, paths)
+		if err != nil {
+			return err
+		}
+		for i, email := range emails {
+			if errs[i] != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", email, errs[i]))
+				continue
 			}
+			events = append(events, c.teamEventsFor(email, lists[i].Items, tr, queryLower)...)
+		}
+	} else {
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, 10) // max 10 concurrent requests
+		)
 
-			for _, ev := range resp.Items {
-				if ev == nil {
-					continue
-				}
-
-				// Skip declined events
-				declined := false
-				for _, att := range ev.Attendees {
-					if att.Self && att.ResponseStatus == "declined" {
-						declined = true
-						break
-					}
-				}
-				if declined {
-					continue
-				}
-
-				summary := ev.Summary
-				// Hide private events
-				if ev.Visibility == "private" || ev.Visibility == "confidential" {
-					summary = "(busy)"
-				}
-
-				// Apply query filter
-				if queryLower != "" && !strings.Contains(strings.ToLower(summary), queryLower) {
-					continue
+		for _, email := range emails {
+			wg.Add(1)
+			go func(email string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				call := svc.Events.List(email).
+					SingleEvents(true).
+					TimeMin(tr.From.Format(time.RFC3339)).
+					TimeMax(tr.To.Format(time.RFC3339)).
+					MaxResults(c.Max).
+					OrderBy("startTime").
+					Context(ctx)
+
+				resp, err := call.Do()
+				if err != nil {
+					mu.Lock()
+					errors = append(errors, fmt.Sprintf("%s: %v", email, err))
+					mu.Unlock()
+					return
 				}
 
-				start, end := formatEventTime(ev, tr.Location)
-				startDay, endDay := eventDaysOfWeek(ev)
-				startTime := parseEventStart(ev, tr.Location)
-				dedupeKey := eventDedupeKey(ev, startTime)
-
+				found := c.teamEventsFor(email, resp.Items, tr, queryLower)
 				mu.Lock()
-				events = append(events, teamEvent{
-					Who:            email,
-					ID:             ev.Id,
-					Start:          start,
-					End:            end,
-					Summary:        summary,
-					Status:         ev.Status,
-					StartDayOfWeek: startDay,
-					EndDayOfWeek:   endDay,
-					dedupeKey:      dedupeKey,
-					sortKey:        startTime,
-				})
+				events = append(events, found...)
 				mu.Unlock()
-			}
-		}(email)
-	}
+			}(email)
+		}
 
-	wg.Wait()
+		wg.Wait()
+	}
 
 	// Print warnings for errors
 	for _, e := range errors {
@@ -293,6 +273,70 @@ func (c *CalendarTeamCmd) runEvents(ctx context.Context, svc *calendar.Service,
 	return nil
 }
 
+// teamEventsFor converts one member's events, skipping declined ones and hiding private titles.
+func (c *CalendarTeamCmd) teamEventsFor(email string, items []*calendar.Event, tr *TimeRange, queryLower string) []teamEvent {
+	var out []teamEvent
+	for _, ev := range items {
+		if ev == nil {
+			continue
+		}
+
+		// Skip declined events
+		declined := false
+		for _, att := range ev.Attendees {
+			if att.Self && att.ResponseStatus == "declined" {
+				declined = true
+				break
+			}
+		}
+		if declined {
+			continue
+		}
+
+		summary := ev.Summary
+		// Hide private events
+		if ev.Visibility == "private" || ev.Visibility == "confidential" {
+			summary = "(busy)"
+		}
+
+		// Apply query filter
+		if queryLower != "" && !strings.Contains(strings.ToLower(summary), queryLower) {
+			continue
+		}
+
+		start, end := formatEventTime(ev, tr.Location)
+		startDay, endDay := eventDaysOfWeek(ev)
+		startTime := parseEventStart(ev, tr.Location)
+		dedupeKey := eventDedupeKey(ev, startTime)
+
+		out = append(out, teamEvent{
+			Who:            email,
+			ID:             ev.Id,
+			Start:          start,
+			End:            end,
+			Summary:        summary,
+			Status:         ev.Status,
+			StartDayOfWeek: startDay,
+			EndDayOfWeek:   endDay,
+			dedupeKey:      dedupeKey,
+			sortKey:        startTime,
+		})
+	}
+	return out
+}
+
+// teamEventsBatchPath mirrors the Events.List call in runEvents as a batch sub-request.
+func teamEventsBatchPath(email string, tr *TimeRange, maxResults int64) string {
+	q := url.Values{
+		"singleEvents": {"true"},
+		"timeMin":      {tr.From.Format(time.RFC3339)},
+		"timeMax":      {tr.To.Format(time.RFC3339)},
+		"maxResults":   {strconv.FormatInt(maxResults, 10)},
+		"orderBy":      {"startTime"},
+	}
+	return "calendars/" + url.PathEscape(email) + "/events?" + q.Encode()
+}
+
 func formatEventTime(ev *calendar.Event, loc *time.Location) (start, end string) {
 	if ev.Start == nil {
 		return "", ""
@@ -61,6 +61,12 @@ func (c *CalendarUsersCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.People, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*people.Person) ([]calendarUserItem, error) {
+		return calendarUserItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var peopleList []*people.Person
 	nextPageToken := ""
 	if c.All {
@@ -78,24 +84,7 @@ func (c *CalendarUsersCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Email string `json:"email"`
-			Name  string `json:"name,omitempty"`
-		}
-		items := make([]item, 0, len(peopleList))
-		for _, p := range peopleList {
-			if p == nil {
-				continue
-			}
-			email := primaryEmail(p)
-			if email == "" {
-				continue
-			}
-			items = append(items, item{
-				Email: email,
-				Name:  primaryName(p),
-			})
-		}
+		items := calendarUserItems(peopleList)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"users":         items,
 			"nextPageToken": nextPageToken,
@@ -142,3 +131,26 @@ func (c *CalendarUsersCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	return nil
 }
+
+type calendarUserItem struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+func calendarUserItems(peopleList []*people.Person) []calendarUserItem {
+	items := make([]calendarUserItem, 0, len(peopleList))
+	for _, p := range peopleList {
+		if p == nil {
+			continue
+		}
+		email := primaryEmail(p)
+		if email == "" {
+			continue
+		}
+		items = append(items, calendarUserItem{
+			Email: email,
+			Name:  primaryName(p),
+		})
+	}
+	return items
+}
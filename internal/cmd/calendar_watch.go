@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"google.golang.org/api/calendar/v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type calendarWatchHook struct {
+	URL   string `json:"url"`
+	Token string `json:"token,omitempty"`
+}
+
+// calendarWatchState is the push channel registered for one account and
+// calendar, plus the sync token used to fetch changes when Google notifies.
+// It is kept apart from `calendar changes` so both can advance independently.
+type calendarWatchState struct {
+	Account                string             `json:"account"`
+	CalendarID             string             `json:"calendarId"`
+	ChannelID              string             `json:"channelId"`
+	ResourceID             string             `json:"resourceId"`
+	ChannelToken           string             `json:"channelToken"`
+	Address                string             `json:"address"`
+	TTLSec                 int64              `json:"ttlSec,omitempty"`
+	ExpirationMs           int64              `json:"expirationMs,omitempty"`
+	SyncToken              string             `json:"syncToken"`
+	SyncedAtMs             int64              `json:"syncedAtMs,omitempty"`
+	UpdatedAtMs            int64              `json:"updatedAtMs,omitempty"`
+	Hook                   *calendarWatchHook `json:"hook,omitempty"`
+	LastDeliveryStatus     string             `json:"lastDeliveryStatus,omitempty"`
+	LastDeliveryAtMs       int64              `json:"lastDeliveryAtMs,omitempty"`
+	LastDeliveryStatusNote string             `json:"lastDeliveryStatusNote,omitempty"`
+}
+
+type calendarWatchStore struct {
+	path  string
+	mu    sync.Mutex
+	state calendarWatchState
+}
+
+func calendarWatchStatePath(account, calendarID string) (string, error) {
+	dir, err := config.EnsureCalendarWatchDir()
+	if err != nil {
+		return "", err
+	}
+	name := sanitizeAccountForPath(account) + "--" + sanitizeAccountForPath(calendarID)
+	return filepath.Join(dir, name+".json"), nil
+}
+
+func newCalendarWatchStore(account, calendarID string) (*calendarWatchStore, error) {
+	path, err := calendarWatchStatePath(account, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	return &calendarWatchStore{path: path}, nil
+}
+
+func loadCalendarWatchStore(account, calendarID string) (*calendarWatchStore, error) {
+	store, err := newCalendarWatchStore(account, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("calendar watch state not found; run calendar watch start")
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.state); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *calendarWatchStore) Get() calendarWatchState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Reload replaces the in-memory state with the file on disk.
+func (s *calendarWatchStore) Reload() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var state calendarWatchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	return nil
+}
+
+func (s *calendarWatchStore) Update(fn func(*calendarWatchState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := fn(&s.state); err != nil {
+		return err
+	}
+	return s.Save()
+}
+
+func (s *calendarWatchStore) Save() error {
+	if s.path == "" {
+		return errors.New("missing calendar watch state path")
+	}
+	payload, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, append(payload, '\n'))
+}
+
+type CalendarWatchCmd struct {
+	Start CalendarWatchStartCmd `cmd:"" name:"start" aliases:"begin" help:"Register a push channel for calendar changes"`
+	Stop  CalendarWatchStopCmd  `cmd:"" name:"stop" aliases:"rm,delete" help:"Stop the push channel and clear stored state"`
+	Serve CalendarWatchServeCmd `cmd:"" name:"serve" help:"Receive push notifications and forward changed events to a hook"`
+}
+
+type CalendarWatchStartCmd struct {
+	CalendarID string `arg:"" stdin:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+	Address    string `name:"address" required:"" help:"Public HTTPS URL Google notifies (where watch serve is reachable)"`
+	TTL        string `name:"ttl" help:"Requested channel lifetime (seconds or Go duration; Google may shorten it)"`
+	Token      string `name:"token" help:"Channel token Google echoes in X-Goog-Channel-Token (default: random)"`
+	HookURL    string `name:"hook-url" help:"Webhook URL to forward changed events"`
+	HookToken  string `name:"hook-token" help:"Webhook bearer token"`
+}
+
+func (c *CalendarWatchStartCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	address := strings.TrimSpace(c.Address)
+	if parsed, err := url.Parse(address); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return usage("--address must be an https:// URL")
+	}
+	ttl, err := parseDurationSeconds(c.TTL)
+	if err != nil {
+		return err
+	}
+	hook, err := calendarWatchHookFromFlags(c.HookURL, c.HookToken)
+	if err != nil && !errors.Is(err, errNoHookConfigured) {
+		return err
+	}
+
+	if dryRunErr := dryRunExit(ctx, flags, "calendar.watch.start", map[string]any{
+		"calendarId": strings.TrimSpace(c.CalendarID),
+		"address":    address,
+		"ttl":        ttl.String(),
+		"hook":       hook,
+	}); dryRunErr != nil {
+		return dryRunErr
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.CalendarID)
+	if calendarID == "" {
+		calendarID = primaryCalendarID
+	}
+	calendarID, err = resolveCalendarID(ctx, svc, calendarID)
+	if err != nil {
+		return err
+	}
+
+	token := strings.TrimSpace(c.Token)
+	if token == "" {
+		if token, err = randomCalendarWatchToken(); err != nil {
+			return err
+		}
+	}
+
+	var previous calendarWatchState
+	if existing, loadErr := loadCalendarWatchStore(account, calendarID); loadErr == nil {
+		previous = existing.Get()
+	}
+
+	// Take the sync token before registering so no change falls in between.
+	startedAt := time.Now()
+	_, syncToken, _, err := syncCalendarEvents(ctx, svc, calendarID, "")
+	if err != nil {
+		return err
+	}
+	ch, err := watchCalendarEvents(ctx, svc, calendarID, address, token, ttl)
+	if err != nil {
+		return err
+	}
+
+	state := calendarWatchState{
+		Account:      account,
+		CalendarID:   calendarID,
+		ChannelID:    ch.Id,
+		ResourceID:   ch.ResourceId,
+		ChannelToken: token,
+		Address:      address,
+		TTLSec:       int64(ttl / time.Second),
+		ExpirationMs: ch.Expiration,
+		SyncToken:    syncToken,
+		SyncedAtMs:   startedAt.UnixMilli(),
+		UpdatedAtMs:  time.Now().UnixMilli(),
+		Hook:         hook,
+	}
+	store, err := newCalendarWatchStore(account, calendarID)
+	if err != nil {
+		return err
+	}
+	if err := store.Update(func(s *calendarWatchState) error {
+		*s = state
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if previous.ChannelID != "" && previous.ChannelID != ch.Id {
+		if stopErr := stopCalendarChannel(ctx, svc, previous.ChannelID, previous.ResourceID); stopErr != nil {
+			u.Err().Printf("calendar watch: stop previous channel %s: %v", previous.ChannelID, stopErr)
+		}
+	}
+
+	return writeCalendarWatchState(ctx, state)
+}
+
+type CalendarWatchStopCmd struct {
+	CalendarID string `arg:"" stdin:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+}
+
+func (c *CalendarWatchStopCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	if confirmErr := confirmDestructive(ctx, flags, "stop calendar watch and clear stored state"); confirmErr != nil {
+		return confirmErr
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.CalendarID)
+	if calendarID == "" {
+		calendarID = primaryCalendarID
+	}
+	calendarID, err = resolveCalendarID(ctx, svc, calendarID)
+	if err != nil {
+		return err
+	}
+	store, err := loadCalendarWatchStore(account, calendarID)
+	if err != nil {
+		return err
+	}
+	state := store.Get()
+	if err := stopCalendarChannel(ctx, svc, state.ChannelID, state.ResourceID); err != nil {
+		return err
+	}
+	if err := os.Remove(store.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"stopped": true, "channelId": state.ChannelID})
+	}
+	u.Out().Printf("stopped\ttrue")
+	u.Out().Printf("channel_id\t%s", state.ChannelID)
+	return nil
+}
+
+type CalendarWatchServeCmd struct {
+	CalendarID  string `arg:"" stdin:"" name:"calendarId" optional:"" help:"Calendar ID (default: primary)"`
+	Bind        string `name:"bind" help:"Bind address" default:"127.0.0.1"`
+	Port        int    `name:"port" help:"Listen port" default:"8789"`
+	Path        string `name:"path" help:"Notification handler path" default:"/calendar-push"`
+	SharedToken string `name:"token" help:"Shared token for x-gog-token or ?token= (accepted besides the channel token)"`
+	HookURL     string `name:"hook-url" help:"Webhook URL to forward changed events"`
+	HookToken   string `name:"hook-token" help:"Webhook bearer token"`
+	SaveHook    bool   `name:"save-hook" help:"Persist hook settings to watch state"`
+	RenewBefore string `name:"renew-before" help:"Renew the channel this long before it expires (seconds or Go duration)" default:"1h"`
+	Echo        bool   `name:"echo" help:"Without a hook, return changed events in the response to Google (for debugging)"`
+}
+
+func (c *CalendarWatchServeCmd) Run(ctx context.Context, kctx *kong.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return usage("--path must start with '/'")
+	}
+	if c.Port <= 0 {
+		return usage("--port must be > 0")
+	}
+	renewBefore, err := parseDurationSeconds(c.RenewBefore)
+	if err != nil {
+		return err
+	}
+	if renewBefore <= 0 {
+		return usage("--renew-before must be > 0")
+	}
+
+	svc, err := newCalendarService(ctx, account)
+	if err != nil {
+		return err
+	}
+	calendarID := strings.TrimSpace(c.CalendarID)
+	if calendarID == "" {
+		calendarID = primaryCalendarID
+	}
+	calendarID, err = resolveCalendarID(ctx, svc, calendarID)
+	if err != nil {
+		return err
+	}
+	store, err := loadCalendarWatchStore(account, calendarID)
+	if err != nil {
+		return err
+	}
+	state := store.Get()
+
+	hookURL := c.HookURL
+	hookToken := c.HookToken
+	if hookURL == "" && state.Hook != nil {
+		hookURL = state.Hook.URL
+		if !flagProvided(kctx, "hook-token") {
+			hookToken = state.Hook.Token
+		}
+	}
+	hook, err := calendarWatchHookFromFlags(hookURL, hookToken)
+	if err != nil && !errors.Is(err, errNoHookConfigured) {
+		return err
+	}
+	if hook == nil && !c.Echo {
+		return usage("no hook configured; pass --hook-url (or --echo to return changes in the response)")
+	}
+	if c.SaveHook && hook != nil {
+		if updateErr := store.Update(func(s *calendarWatchState) error {
+			s.Hook = hook
+			s.UpdatedAtMs = time.Now().UnixMilli()
+			return nil
+		}); updateErr != nil {
+			return updateErr
+		}
+	}
+
+	cfg := calendarWatchServeConfig{
+		Account:     account,
+		CalendarID:  calendarID,
+		Path:        c.Path,
+		SharedToken: c.SharedToken,
+		HookTimeout: defaultHookRequestTimeoutSec * time.Second,
+		RenewBefore: renewBefore,
+		Echo:        c.Echo,
+	}
+	if hook != nil {
+		cfg.HookURL = hook.URL
+		cfg.HookToken = hook.Token
+	}
+
+	server := &calendarWatchServer{
+		cfg:        cfg,
+		store:      store,
+		svc:        svc,
+		hookClient: &http.Client{Timeout: cfg.HookTimeout},
+		logf:       u.Err().Printf,
+		warnf:      u.Err().Printf,
+	}
+
+	renewCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go server.renewLoop(renewCtx)
+
+	addr := net.JoinHostPort(c.Bind, strconv.Itoa(c.Port))
+	u.Err().Printf("calendar watch: listening on %s%s", addr, c.Path)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           server,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return listenAndServe(httpServer)
+}
+
+func writeCalendarWatchState(ctx context.Context, state calendarWatchState) error {
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"watch": state})
+	}
+	u := ui.FromContext(ctx)
+	u.Out().Printf("account\t%s", state.Account)
+	u.Out().Printf("calendar_id\t%s", state.CalendarID)
+	u.Out().Printf("channel_id\t%s", state.ChannelID)
+	u.Out().Printf("resource_id\t%s", state.ResourceID)
+	u.Out().Printf("address\t%s", state.Address)
+	if state.ExpirationMs > 0 {
+		u.Out().Printf("expiration\t%s", formatUnixMillis(state.ExpirationMs))
+	}
+	if state.UpdatedAtMs > 0 {
+		u.Out().Printf("updated_at\t%s", formatUnixMillis(state.UpdatedAtMs))
+	}
+	if state.Hook != nil {
+		u.Out().Printf("hook_url\t%s", state.Hook.URL)
+	}
+	return nil
+}
+
+func calendarWatchHookFromFlags(hookURL, hookToken string) (*calendarWatchHook, error) {
+	if strings.TrimSpace(hookURL) == "" {
+		if hookToken != "" {
+			return nil, usage("--hook-url required when using --hook-token")
+		}
+		return nil, errNoHookConfigured
+	}
+	return &calendarWatchHook{URL: strings.TrimSpace(hookURL), Token: hookToken}, nil
+}
+
+func watchCalendarEvents(ctx context.Context, svc *calendar.Service, calendarID, address, token string, ttl time.Duration) (*calendar.Channel, error) {
+	ch := &calendar.Channel{
+		Id:      newCalendarChannelID(),
+		Type:    "web_hook",
+		Address: address,
+		Token:   token,
+	}
+	if ttl > 0 {
+		ch.Params = map[string]string{"ttl": strconv.FormatInt(int64(ttl/time.Second), 10)}
+	}
+	return svc.Events.Watch(calendarID, ch).Context(ctx).Do()
+}
+
+// stopCalendarChannel stops a push channel; channels Google no longer knows
+// about (expired or already stopped) count as stopped.
+func stopCalendarChannel(ctx context.Context, svc *calendar.Service, channelID, resourceID string) error {
+	if channelID == "" {
+		return nil
+	}
+	err := svc.Channels.Stop(&calendar.Channel{Id: channelID, ResourceId: resourceID}).Context(ctx).Do()
+	if err != nil && !isNotFoundAPIError(err) {
+		return err
+	}
+	return nil
+}
+
+func newCalendarChannelID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "gog-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return "gog-" + hex.EncodeToString(b)
+}
+
+func randomCalendarWatchToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
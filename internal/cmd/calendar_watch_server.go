@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+var errNoCalendarChanges = errors.New("no calendar changes")
+
+const calendarWatchRenewRetry = time.Minute
+
+type calendarWatchServeConfig struct {
+	Account     string
+	CalendarID  string
+	Path        string
+	SharedToken string
+	HookURL     string
+	HookToken   string
+	HookTimeout time.Duration
+	RenewBefore time.Duration
+	// Echo returns the changes in the response body when no hook is configured.
+	Echo bool
+}
+
+type calendarHookPayload struct {
+	Source     string           `json:"source"`
+	Account    string           `json:"account"`
+	CalendarID string           `json:"calendarId"`
+	Resync     bool             `json:"resync,omitempty"`
+	Changes    []calendarChange `json:"changes"`
+}
+
+type calendarWatchServer struct {
+	cfg        calendarWatchServeConfig
+	store      *calendarWatchStore
+	svc        *calendar.Service
+	hookClient *http.Client
+	syncMu     sync.Mutex
+	logf       func(string, ...any)
+	warnf      func(string, ...any)
+}
+
+func (s *calendarWatchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !pathMatches(s.cfg.Path, r.URL.Path) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	state := s.store.Get()
+	// A concurrent `calendar watch start` (or a renewal by another serve) replaces
+	// the channel on disk; pick it up before judging the notification.
+	if channelID := r.Header.Get("X-Goog-Channel-ID"); channelID != state.ChannelID {
+		if err := s.store.Reload(); err != nil {
+			s.warnf("calendar watch: reload state: %v", err)
+		}
+		state = s.store.Get()
+	}
+	if ok := s.authorize(r, state); !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// Notifications from a channel replaced by renewal are dropped; the next
+	// sync on the current channel picks up whatever they announced.
+	if channelID := r.Header.Get("X-Goog-Channel-ID"); channelID != state.ChannelID {
+		s.logf("calendar watch: ignoring notification for channel %s", channelID)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if r.Header.Get("X-Goog-Resource-State") == "sync" {
+		s.logf("calendar watch: channel %s is active", state.ChannelID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err := s.handleNotification(r.Context(), func(payload *calendarHookPayload) error {
+		if s.cfg.HookURL != "" {
+			return s.sendHook(r.Context(), payload)
+		}
+		if !s.cfg.Echo {
+			return errNoHookConfigured
+		}
+		return json.NewEncoder(w).Encode(payload)
+	})
+	switch {
+	case errors.Is(err, errNoCalendarChanges):
+		w.WriteHeader(http.StatusAccepted)
+	case err != nil:
+		// A 5xx makes Google retry the notification; the sync token was not
+		// advanced, so the retry fetches the same changes again.
+		s.warnf("calendar watch: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+	case s.cfg.HookURL != "":
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// authorize accepts the channel token Google echoes back, or the shared
+// token when one is configured (e.g. for a proxy in front of serve).
+func (s *calendarWatchServer) authorize(r *http.Request, state calendarWatchState) bool {
+	if s.cfg.SharedToken != "" && sharedTokenMatches(r, s.cfg.SharedToken) {
+		return true
+	}
+	token := r.Header.Get("X-Goog-Channel-Token")
+	if token == "" || state.ChannelToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(state.ChannelToken)) == 1
+}
+
+// handleNotification fetches the changes since the stored sync token and hands
+// them to deliver. The new token is stored only after deliver succeeds, so a
+// failed delivery is retried from the same token.
+func (s *calendarWatchServer) handleNotification(ctx context.Context, deliver func(*calendarHookPayload) error) error {
+	// Notifications can arrive in bursts; each sync must start from the token
+	// the previous one stored.
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	state := s.store.Get()
+	since := time.Time{}
+	if state.SyncedAtMs > 0 {
+		since = time.UnixMilli(state.SyncedAtMs)
+	}
+	startedAt := time.Now()
+	events, nextToken, resync, err := syncCalendarEvents(ctx, s.svc, s.cfg.CalendarID, state.SyncToken)
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
+	if resync {
+		s.warnf("calendar watch: sync token expired; forwarding all events")
+	}
+
+	if len(events) > 0 {
+		changes := make([]calendarChange, 0, len(events))
+		for _, e := range events {
+			changes = append(changes, calendarChange{Change: classifyCalendarChange(e, since), Event: e})
+		}
+		if err := deliver(&calendarHookPayload{
+			Source:     "calendar",
+			Account:    s.cfg.Account,
+			CalendarID: s.cfg.CalendarID,
+			Resync:     resync,
+			Changes:    changes,
+		}); err != nil {
+			return fmt.Errorf("hook failed: %w", err)
+		}
+	}
+
+	if err := s.store.Update(func(st *calendarWatchState) error {
+		st.SyncToken = nextToken
+		st.SyncedAtMs = startedAt.UnixMilli()
+		st.UpdatedAtMs = time.Now().UnixMilli()
+		return nil
+	}); err != nil {
+		s.warnf("calendar watch: failed to update state: %v", err)
+	}
+	if len(events) == 0 {
+		return errNoCalendarChanges
+	}
+	return nil
+}
+
+func (s *calendarWatchServer) sendHook(ctx context.Context, payload *calendarHookPayload) error {
+	status, note, err := postHook(ctx, s.hookClient, s.cfg.HookURL, s.cfg.HookToken, payload)
+	_ = s.store.Update(func(state *calendarWatchState) error {
+		state.LastDeliveryStatus = status
+		state.LastDeliveryAtMs = time.Now().UnixMilli()
+		state.LastDeliveryStatusNote = note
+		return nil
+	})
+	return err
+}
+
+// renewLoop replaces the channel RenewBefore ahead of its expiration until
+// ctx is cancelled. Failed renewals are retried every calendarWatchRenewRetry.
+func (s *calendarWatchServer) renewLoop(ctx context.Context) {
+	var retry time.Duration
+	for {
+		wait, ok := s.renewDelay(time.Now())
+		if !ok {
+			s.logf("calendar watch: channel has no expiration; not renewing")
+			return
+		}
+		wait = max(wait, retry)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		retry = 0
+		if err := s.renew(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.warnf("calendar watch: renew failed: %v", err)
+			retry = calendarWatchRenewRetry
+		}
+	}
+}
+
+func (s *calendarWatchServer) renewDelay(now time.Time) (time.Duration, bool) {
+	state := s.store.Get()
+	if state.ExpirationMs <= 0 {
+		return 0, false
+	}
+	renewAt := time.UnixMilli(state.ExpirationMs).Add(-s.cfg.RenewBefore)
+	return max(renewAt.Sub(now), 0), true
+}
+
+// renew registers a new channel with the stored address and token, then
+// stops the old one so Google never has a gap without an active channel.
+func (s *calendarWatchServer) renew(ctx context.Context) error {
+	old := s.store.Get()
+	ch, err := watchCalendarEvents(ctx, s.svc, s.cfg.CalendarID, old.Address, old.ChannelToken, time.Duration(old.TTLSec)*time.Second)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Update(func(state *calendarWatchState) error {
+		state.ChannelID = ch.Id
+		state.ResourceID = ch.ResourceId
+		state.ExpirationMs = ch.Expiration
+		state.UpdatedAtMs = time.Now().UnixMilli()
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := stopCalendarChannel(ctx, s.svc, old.ChannelID, old.ResourceID); err != nil {
+		s.warnf("calendar watch: stop previous channel %s: %v", old.ChannelID, err)
+	}
+	s.logf("calendar watch: renewed channel %s (expires %s)", ch.Id, formatUnixMillis(ch.Expiration))
+	return nil
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// newCalendarWatchAPI serves the events list, watch and channel stop calls
+// used by calendar watch and records watched and stopped channels.
+func newCalendarWatchAPI(t *testing.T) (*calendar.Service, *[]calendar.Channel, *[]string) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		watched []calendar.Channel
+		stopped []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch path := strings.TrimPrefix(r.URL.Path, "/calendar/v3"); {
+		case r.Method == http.MethodGet && path == "/calendars/primary/events":
+			if r.URL.Query().Get("syncToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"items": []any{}, "nextSyncToken": "s1"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"items":         []map[string]any{{"id": "e1", "status": "confirmed", "summary": "Lunch", "created": "2099-01-01T00:00:00Z"}},
+				"nextSyncToken": "s2",
+			})
+		case r.Method == http.MethodPost && path == "/calendars/primary/events/watch":
+			var ch calendar.Channel
+			_ = json.NewDecoder(r.Body).Decode(&ch)
+			watched = append(watched, ch)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":         ch.Id,
+				"resourceId": fmt.Sprintf("r%d", len(watched)),
+				"expiration": "4102444800000",
+			})
+		case r.Method == http.MethodPost && path == "/channels/stop":
+			var ch calendar.Channel
+			_ = json.NewDecoder(r.Body).Decode(&ch)
+			stopped = append(stopped, ch.Id+"/"+ch.ResourceId)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return newCalendarServiceFromServer(t, srv), &watched, &stopped
+}
+
+func TestCalendarWatchStartStop(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	origNew := newCalendarService
+	t.Cleanup(func() { newCalendarService = origNew })
+	svc, watched, stopped := newCalendarWatchAPI(t)
+	newCalendarService = func(context.Context, string) (*calendar.Service, error) { return svc, nil }
+
+	ctx := newCalendarJSONContext(t)
+	flags := &RootFlags{Account: "a@b.com", Force: true}
+	args := []string{"--address", "https://example.com/calendar-push", "--ttl", "2h", "--hook-url", "http://127.0.0.1:9/hook"}
+	start := func() calendarWatchState {
+		t.Helper()
+		out := captureStdout(t, func() {
+			if err := runKong(t, &CalendarWatchStartCmd{}, args, ctx, flags); err != nil {
+				t.Fatalf("start: %v", err)
+			}
+		})
+		var parsed struct {
+			Watch calendarWatchState `json:"watch"`
+		}
+		if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+			t.Fatalf("json: %v\n%s", err, out)
+		}
+		return parsed.Watch
+	}
+
+	first := start()
+	if first.ChannelToken == "" || first.SyncToken != "s1" || first.ResourceID != "r1" || first.Hook == nil || first.ExpirationMs != 4102444800000 {
+		t.Fatalf("unexpected state: %+v", first)
+	}
+	if ch := (*watched)[0]; ch.Type != "web_hook" || ch.Address != "https://example.com/calendar-push" || ch.Token != first.ChannelToken || ch.Params["ttl"] != "7200" {
+		t.Fatalf("unexpected channel: %+v", ch)
+	}
+
+	// Starting again replaces the channel and stops the previous one.
+	second := start()
+	if second.ChannelID == first.ChannelID || strings.Join(*stopped, ",") != first.ChannelID+"/r1" {
+		t.Fatalf("expected the first channel to be stopped: %v", *stopped)
+	}
+
+	path, err := calendarWatchStatePath("a@b.com", "primary")
+	if err != nil {
+		t.Fatalf("state path: %v", err)
+	}
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &CalendarWatchStopCmd{}, nil, ctx, flags); err != nil {
+			t.Fatalf("stop: %v", err)
+		}
+	})
+	if len(*stopped) != 2 || (*stopped)[1] != second.ChannelID+"/r2" {
+		t.Fatalf("expected the second channel to be stopped: %v", *stopped)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected state to be removed, got %v", err)
+	}
+
+	if err := runKong(t, &CalendarWatchStartCmd{}, []string{"--address", "http://example.com/x"}, ctx, flags); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for a non-https address, got %v", err)
+	}
+}
+
+func TestCalendarWatchServer(t *testing.T) {
+	svc, watched, stopped := newCalendarWatchAPI(t)
+
+	var hookAuth string
+	var hookPayload calendarHookPayload
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hookAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&hookPayload)
+	}))
+	defer hook.Close()
+
+	now := time.Now()
+	store := &calendarWatchStore{
+		path: filepath.Join(t.TempDir(), "watch.json"),
+		state: calendarWatchState{
+			Account:      "a@b.com",
+			CalendarID:   "primary",
+			ChannelID:    "c1",
+			ResourceID:   "r0",
+			ChannelToken: "tok",
+			Address:      "https://example.com/calendar-push",
+			ExpirationMs: now.Add(3 * time.Hour).UnixMilli(),
+			SyncToken:    "s1",
+			SyncedAtMs:   now.Add(-time.Hour).UnixMilli(),
+		},
+	}
+	server := &calendarWatchServer{
+		cfg: calendarWatchServeConfig{
+			Account:     "a@b.com",
+			CalendarID:  "primary",
+			Path:        "/calendar-push",
+			HookURL:     hook.URL,
+			HookToken:   "hook-tok",
+			RenewBefore: time.Hour,
+		},
+		store:      store,
+		svc:        svc,
+		hookClient: hook.Client(),
+		logf:       func(string, ...any) {},
+		warnf:      func(string, ...any) {},
+	}
+	notify := func(channelID, token, state string) int {
+		req := httptest.NewRequest(http.MethodPost, "/calendar-push", nil)
+		req.Header.Set("X-Goog-Channel-ID", channelID)
+		req.Header.Set("X-Goog-Channel-Token", token)
+		req.Header.Set("X-Goog-Resource-State", state)
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := notify("c1", "wrong", "exists"); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a bad token, got %d", code)
+	}
+	if code := notify("other", "tok", "exists"); code != http.StatusAccepted {
+		t.Fatalf("expected 202 for an unknown channel, got %d", code)
+	}
+	if code := notify("c1", "tok", "sync"); code != http.StatusOK || hookPayload.Source != "" {
+		t.Fatalf("expected the sync message to be acknowledged only, got %d", code)
+	}
+
+	if code := notify("c1", "tok", "exists"); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if hookAuth != "Bearer hook-tok" || hookPayload.Source != "calendar" || len(hookPayload.Changes) != 1 {
+		t.Fatalf("unexpected hook delivery: %q %+v", hookAuth, hookPayload)
+	}
+	if ch := hookPayload.Changes[0]; ch.Change != calendarChangeCreated || ch.Event.Summary != "Lunch" {
+		t.Fatalf("unexpected change: %+v", ch)
+	}
+	if state := store.Get(); state.SyncToken != "s2" || state.LastDeliveryStatus != "ok" {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+
+	wait, ok := server.renewDelay(now)
+	if !ok || wait < 119*time.Minute || wait > 2*time.Hour {
+		t.Fatalf("unexpected renew delay: %s %v", wait, ok)
+	}
+	if err := server.renew(context.Background()); err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	state := store.Get()
+	if state.ChannelID == "c1" || state.ResourceID != "r1" || (*watched)[0].Token != "tok" {
+		t.Fatalf("unexpected renewed state: %+v", state)
+	}
+	if strings.Join(*stopped, ",") != "c1/r0" {
+		t.Fatalf("expected the old channel to be stopped: %v", *stopped)
+	}
+}
+
+func TestCalendarWatchServer_DeliveryFailureKeepsToken(t *testing.T) {
+	svc, _, _ := newCalendarWatchAPI(t)
+
+	var hookCalls int
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hookCalls++
+		if hookCalls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer hook.Close()
+
+	store := &calendarWatchStore{
+		path: filepath.Join(t.TempDir(), "watch.json"),
+		state: calendarWatchState{
+			Account:      "a@b.com",
+			CalendarID:   "primary",
+			ChannelID:    "c1",
+			ChannelToken: "tok",
+			SyncToken:    "s1",
+		},
+	}
+	server := &calendarWatchServer{
+		cfg:        calendarWatchServeConfig{Account: "a@b.com", CalendarID: "primary", Path: "/calendar-push", HookURL: hook.URL},
+		store:      store,
+		svc:        svc,
+		hookClient: hook.Client(),
+		logf:       func(string, ...any) {},
+		warnf:      func(string, ...any) {},
+	}
+	notify := func(channelID, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/calendar-push", nil)
+		req.Header.Set("X-Goog-Channel-ID", channelID)
+		req.Header.Set("X-Goog-Channel-Token", token)
+		req.Header.Set("X-Goog-Resource-State", "exists")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := notify("c1", "tok"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 when the hook fails, got %d", rec.Code)
+	}
+	if state := store.Get(); state.SyncToken != "s1" || state.LastDeliveryStatus == "ok" {
+		t.Fatalf("expected the sync token to stay put after a failed hook: %+v", state)
+	}
+	if rec := notify("c1", "tok"); rec.Code != http.StatusOK || hookCalls != 2 {
+		t.Fatalf("expected the retry to deliver, got %d after %d hook calls", rec.Code, hookCalls)
+	}
+	if state := store.Get(); state.SyncToken != "s2" {
+		t.Fatalf("expected the sync token to advance after delivery: %+v", state)
+	}
+
+	// Without a hook, changes are only echoed back when --echo is set.
+	server.cfg.HookURL = ""
+	store.state.SyncToken = "s1"
+	if rec := notify("c1", "tok"); rec.Code != http.StatusInternalServerError || store.Get().SyncToken != "s1" {
+		t.Fatalf("expected 500 without hook or echo, got %d", rec.Code)
+	}
+	server.cfg.Echo = true
+	rec := notify("c1", "tok")
+	var echoed calendarHookPayload
+	if err := json.Unmarshal(rec.Body.Bytes(), &echoed); err != nil || rec.Code != http.StatusOK || len(echoed.Changes) != 1 {
+		t.Fatalf("expected echoed changes, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// A channel replaced on disk by another process is picked up on demand.
+	onDisk := &calendarWatchStore{path: store.path, state: store.Get()}
+	onDisk.state.ChannelID = "c2"
+	onDisk.state.ChannelToken = "tok2"
+	if err := onDisk.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if rec := notify("c2", "tok2"); rec.Code != http.StatusOK {
+		t.Fatalf("expected the renewed channel to be accepted, got %d", rec.Code)
+	}
+	if state := store.Get(); state.ChannelID != "c2" {
+		t.Fatalf("expected state to be reloaded: %+v", state)
+	}
+}
@@ -88,6 +88,12 @@ func (c *ChatMessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Messages, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*chat.Message) ([]chatMessageItem, error) {
+		return chatMessageItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var messages []*chat.Message
 	nextPageToken := ""
 	if c.All {
@@ -105,26 +111,7 @@ func (c *ChatMessagesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource   string `json:"resource"`
-			Sender     string `json:"sender,omitempty"`
-			Text       string `json:"text,omitempty"`
-			CreateTime string `json:"createTime,omitempty"`
-			Thread     string `json:"thread,omitempty"`
-		}
-		items := make([]item, 0, len(messages))
-		for _, msg := range messages {
-			if msg == nil {
-				continue
-			}
-			items = append(items, item{
-				Resource:   msg.Name,
-				Sender:     chatMessageSender(msg),
-				Text:       chatMessageText(msg),
-				CreateTime: msg.CreateTime,
-				Thread:     chatMessageThread(msg),
-			})
-		}
+		items := chatMessageItems(messages)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"messages":      items,
 			"nextPageToken": nextPageToken,
@@ -239,3 +226,28 @@ func (c *ChatMessagesSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	return nil
 }
+
+type chatMessageItem struct {
+	Resource   string `json:"resource"`
+	Sender     string `json:"sender,omitempty"`
+	Text       string `json:"text,omitempty"`
+	CreateTime string `json:"createTime,omitempty"`
+	Thread     string `json:"thread,omitempty"`
+}
+
+func chatMessageItems(messages []*chat.Message) []chatMessageItem {
+	items := make([]chatMessageItem, 0, len(messages))
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+		items = append(items, chatMessageItem{
+			Resource:   msg.Name,
+			Sender:     chatMessageSender(msg),
+			Text:       chatMessageText(msg),
+			CreateTime: msg.CreateTime,
+			Thread:     chatMessageThread(msg),
+		})
+	}
+	return items
+}
@@ -52,6 +52,12 @@ func (c *ChatSpacesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Spaces, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*chat.Space) ([]chatSpaceItem, error) {
+		return chatSpaceItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var spaces []*chat.Space
 	nextPageToken := ""
 	if c.All {
@@ -69,26 +75,7 @@ func (c *ChatSpacesListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource    string `json:"resource"`
-			Name        string `json:"name,omitempty"`
-			SpaceType   string `json:"type,omitempty"`
-			SpaceURI    string `json:"uri,omitempty"`
-			ThreadState string `json:"threading,omitempty"`
-		}
-		items := make([]item, 0, len(spaces))
-		for _, space := range spaces {
-			if space == nil {
-				continue
-			}
-			items = append(items, item{
-				Resource:    space.Name,
-				Name:        space.DisplayName,
-				SpaceType:   chatSpaceType(space),
-				SpaceURI:    space.SpaceUri,
-				ThreadState: space.SpaceThreadingState,
-			})
-		}
+		items := chatSpaceItems(spaces)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"spaces":        items,
 			"nextPageToken": nextPageToken,
@@ -297,3 +284,28 @@ func (c *ChatSpacesCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 	return nil
 }
+
+type chatSpaceItem struct {
+	Resource    string `json:"resource"`
+	Name        string `json:"name,omitempty"`
+	SpaceType   string `json:"type,omitempty"`
+	SpaceURI    string `json:"uri,omitempty"`
+	ThreadState string `json:"threading,omitempty"`
+}
+
+func chatSpaceItems(spaces []*chat.Space) []chatSpaceItem {
+	items := make([]chatSpaceItem, 0, len(spaces))
+	for _, space := range spaces {
+		if space == nil {
+			continue
+		}
+		items = append(items, chatSpaceItem{
+			Resource:    space.Name,
+			Name:        space.DisplayName,
+			SpaceType:   chatSpaceType(space),
+			SpaceURI:    space.SpaceUri,
+			ThreadState: space.SpaceThreadingState,
+		})
+	}
+	return items
+}
@@ -22,7 +22,7 @@ type ClassroomAnnouncementsCmd struct {
 }
 
 type ClassroomAnnouncementsListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	States    string `name:"state" help:"Announcement states filter (comma-separated: DRAFT,PUBLISHED,DELETED)"`
 	OrderBy   string `name:"order-by" help:"Order by (e.g., updateTime desc)"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
@@ -69,6 +69,10 @@ func (c *ClassroomAnnouncementsListCmd) Run(ctx context.Context, flags *RootFlag
 		return resp.Announcements, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var announcements []*classroom.Announcement
 	nextPageToken := ""
 	if c.All {
@@ -123,8 +127,8 @@ func (c *ClassroomAnnouncementsListCmd) Run(ctx context.Context, flags *RootFlag
 }
 
 type ClassroomAnnouncementsGetCmd struct {
-	CourseID       string `arg:"" name:"courseId" help:"Course ID or alias"`
-	AnnouncementID string `arg:"" name:"announcementId" help:"Announcement ID"`
+	CourseID       string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	AnnouncementID string `arg:"" stdin:"" name:"announcementId" help:"Announcement ID"`
 }
 
 func (c *ClassroomAnnouncementsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -171,7 +175,7 @@ func (c *ClassroomAnnouncementsGetCmd) Run(ctx context.Context, flags *RootFlags
 }
 
 type ClassroomAnnouncementsCreateCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Text      string `name:"text" help:"Announcement text" required:""`
 	State     string `name:"state" help:"State: PUBLISHED, DRAFT"`
 	Scheduled string `name:"scheduled" help:"Scheduled publish time (RFC3339)"`
@@ -226,8 +230,8 @@ func (c *ClassroomAnnouncementsCreateCmd) Run(ctx context.Context, flags *RootFl
 }
 
 type ClassroomAnnouncementsUpdateCmd struct {
-	CourseID       string `arg:"" name:"courseId" help:"Course ID or alias"`
-	AnnouncementID string `arg:"" name:"announcementId" help:"Announcement ID"`
+	CourseID       string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	AnnouncementID string `arg:"" stdin:"" name:"announcementId" help:"Announcement ID"`
 	Text           string `name:"text" help:"Announcement text"`
 	State          string `name:"state" help:"State: PUBLISHED, DRAFT"`
 	Scheduled      string `name:"scheduled" help:"Scheduled publish time (RFC3339)"`
@@ -296,8 +300,8 @@ func (c *ClassroomAnnouncementsUpdateCmd) Run(ctx context.Context, flags *RootFl
 }
 
 type ClassroomAnnouncementsDeleteCmd struct {
-	CourseID       string `arg:"" name:"courseId" help:"Course ID or alias"`
-	AnnouncementID string `arg:"" name:"announcementId" help:"Announcement ID"`
+	CourseID       string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	AnnouncementID string `arg:"" stdin:"" name:"announcementId" help:"Announcement ID"`
 }
 
 func (c *ClassroomAnnouncementsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -337,8 +341,8 @@ func (c *ClassroomAnnouncementsDeleteCmd) Run(ctx context.Context, flags *RootFl
 }
 
 type ClassroomAnnouncementsAssigneesCmd struct {
-	CourseID       string   `arg:"" name:"courseId" help:"Course ID or alias"`
-	AnnouncementID string   `arg:"" name:"announcementId" help:"Announcement ID"`
+	CourseID       string   `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	AnnouncementID string   `arg:"" stdin:"" name:"announcementId" help:"Announcement ID"`
 	Mode           string   `name:"mode" help:"Assignee mode: ALL_STUDENTS, INDIVIDUAL_STUDENTS"`
 	AddStudents    []string `name:"add-student" help:"Student IDs to add" sep:","`
 	RemoveStudents []string `name:"remove-student" help:"Student IDs to remove" sep:","`
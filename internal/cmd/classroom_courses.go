@@ -72,6 +72,10 @@ func (c *ClassroomCoursesListCmd) Run(ctx context.Context, flags *RootFlags) err
 		return resp.Courses, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var courses []*classroom.Course
 	nextPageToken := ""
 	if c.All {
@@ -126,7 +130,7 @@ func (c *ClassroomCoursesListCmd) Run(ctx context.Context, flags *RootFlags) err
 }
 
 type ClassroomCoursesGetCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 }
 
 func (c *ClassroomCoursesGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -254,7 +258,7 @@ func (c *ClassroomCoursesCreateCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type ClassroomCoursesUpdateCmd struct {
-	CourseID           string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID           string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Name               string `name:"name" help:"Course name"`
 	OwnerID            string `name:"owner" help:"Owner user ID or email"`
 	Section            string `name:"section" help:"Section"`
@@ -341,7 +345,7 @@ func (c *ClassroomCoursesUpdateCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type ClassroomCoursesDeleteCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 }
 
 func (c *ClassroomCoursesDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -376,7 +380,7 @@ func (c *ClassroomCoursesDeleteCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type ClassroomCoursesArchiveCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 }
 
 func (c *ClassroomCoursesArchiveCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -384,7 +388,7 @@ func (c *ClassroomCoursesArchiveCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomCoursesUnarchiveCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 }
 
 func (c *ClassroomCoursesUnarchiveCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -438,7 +442,7 @@ func updateCourseState(ctx context.Context, flags *RootFlags, courseID, state st
 }
 
 type ClassroomCoursesJoinCmd struct {
-	CourseID       string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID       string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Role           string `name:"role" help:"Role to join as: student|teacher" default:"student"`
 	UserID         string `name:"user" help:"User ID or email to join" default:"me"`
 	EnrollmentCode string `name:"enrollment-code" help:"Enrollment code (student joins only)"`
@@ -512,7 +516,7 @@ func (c *ClassroomCoursesJoinCmd) Run(ctx context.Context, flags *RootFlags) err
 }
 
 type ClassroomCoursesLeaveCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Role     string `name:"role" help:"Role to remove: student|teacher" default:"student"`
 	UserID   string `name:"user" help:"User ID or email to remove" default:"me"`
 }
@@ -565,7 +569,7 @@ func (c *ClassroomCoursesLeaveCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomCoursesURLCmd struct {
-	CourseIDs []string `arg:"" name:"courseId" help:"Course IDs or aliases"`
+	CourseIDs []string `arg:"" stdin:"" name:"courseId" help:"Course IDs or aliases"`
 }
 
 func (c *ClassroomCoursesURLCmd) Run(ctx context.Context, flags *RootFlags) error {
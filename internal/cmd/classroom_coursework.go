@@ -22,7 +22,7 @@ type ClassroomCourseworkCmd struct {
 }
 
 type ClassroomCourseworkListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	States    string `name:"state" help:"Coursework states filter (comma-separated: DRAFT,PUBLISHED,DELETED)"`
 	Topic     string `name:"topic" help:"Filter by topic ID"`
 	OrderBy   string `name:"order-by" help:"Order by (e.g., updateTime desc, dueDate desc)"`
@@ -72,6 +72,19 @@ func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags)
 		return resp.CourseWork, resp.NextPageToken, nil
 	}
 
+	topic := strings.TrimSpace(c.Topic)
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*classroom.CourseWork) ([]*classroom.CourseWork, error) {
+		out := items[:0]
+		for _, work := range items {
+			if work != nil && (topic == "" || work.TopicId == topic) {
+				out = append(out, work)
+			}
+		}
+		return out, nil
+	}); handled {
+		return wrapClassroomError(err)
+	}
+
 	var coursework []*classroom.CourseWork
 	var nextPageToken string
 	if c.All {
@@ -80,7 +93,7 @@ func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags)
 			return wrapClassroomError(err)
 		}
 		coursework = all
-		if topic := strings.TrimSpace(c.Topic); topic != "" {
+		if topic != "" {
 			filtered := coursework[:0]
 			for _, work := range coursework {
 				if work == nil {
@@ -151,8 +164,8 @@ func (c *ClassroomCourseworkListCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomCourseworkGetCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
 }
 
 func (c *ClassroomCourseworkGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -210,7 +223,7 @@ func (c *ClassroomCourseworkGetCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type ClassroomCourseworkCreateCmd struct {
-	CourseID    string  `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID    string  `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Title       string  `name:"title" help:"Title" required:""`
 	Description string  `name:"description" help:"Description"`
 	WorkType    string  `name:"type" help:"Work type: ASSIGNMENT, SHORT_ANSWER_QUESTION, MULTIPLE_CHOICE_QUESTION" default:"ASSIGNMENT"`
@@ -315,8 +328,8 @@ func (c *ClassroomCourseworkCreateCmd) Run(ctx context.Context, flags *RootFlags
 }
 
 type ClassroomCourseworkUpdateCmd struct {
-	CourseID     string  `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string  `arg:"" name:"courseworkId" help:"Coursework ID"`
+	CourseID     string  `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string  `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
 	Title        string  `name:"title" help:"Title"`
 	Description  string  `name:"description" help:"Description"`
 	State        string  `name:"state" help:"State: PUBLISHED, DRAFT"`
@@ -440,8 +453,8 @@ func (c *ClassroomCourseworkUpdateCmd) Run(ctx context.Context, flags *RootFlags
 }
 
 type ClassroomCourseworkDeleteCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
 }
 
 func (c *ClassroomCourseworkDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -481,8 +494,8 @@ func (c *ClassroomCourseworkDeleteCmd) Run(ctx context.Context, flags *RootFlags
 }
 
 type ClassroomCourseworkAssigneesCmd struct {
-	CourseID       string   `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID   string   `arg:"" name:"courseworkId" help:"Coursework ID"`
+	CourseID       string   `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID   string   `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
 	Mode           string   `name:"mode" help:"Assignee mode: ALL_STUDENTS, INDIVIDUAL_STUDENTS"`
 	AddStudents    []string `name:"add-student" help:"Student IDs to add" sep:","`
 	RemoveStudents []string `name:"remove-student" help:"Student IDs to remove" sep:","`
@@ -19,7 +19,7 @@ type ClassroomGuardiansCmd struct {
 }
 
 type ClassroomGuardiansListCmd struct {
-	StudentID string `arg:"" name:"studentId" help:"Student ID"`
+	StudentID string `arg:"" stdin:"" name:"studentId" help:"Student ID"`
 	Email     string `name:"email" help:"Filter by invited email address"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page      string `name:"page" aliases:"cursor" help:"Page token"`
@@ -58,6 +58,10 @@ func (c *ClassroomGuardiansListCmd) Run(ctx context.Context, flags *RootFlags) e
 		return resp.Guardians, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var guardians []*classroom.Guardian
 	nextPageToken := ""
 	if c.All {
@@ -110,8 +114,8 @@ func (c *ClassroomGuardiansListCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type ClassroomGuardiansGetCmd struct {
-	StudentID  string `arg:"" name:"studentId" help:"Student ID"`
-	GuardianID string `arg:"" name:"guardianId" help:"Guardian ID"`
+	StudentID  string `arg:"" stdin:"" name:"studentId" help:"Student ID"`
+	GuardianID string `arg:"" stdin:"" name:"guardianId" help:"Guardian ID"`
 }
 
 func (c *ClassroomGuardiansGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -151,8 +155,8 @@ func (c *ClassroomGuardiansGetCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomGuardiansDeleteCmd struct {
-	StudentID  string `arg:"" name:"studentId" help:"Student ID"`
-	GuardianID string `arg:"" name:"guardianId" help:"Guardian ID"`
+	StudentID  string `arg:"" stdin:"" name:"studentId" help:"Student ID"`
+	GuardianID string `arg:"" stdin:"" name:"guardianId" help:"Guardian ID"`
 }
 
 func (c *ClassroomGuardiansDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -198,7 +202,7 @@ type ClassroomGuardianInvitesCmd struct {
 }
 
 type ClassroomGuardianInvitesListCmd struct {
-	StudentID string `arg:"" name:"studentId" help:"Student ID"`
+	StudentID string `arg:"" stdin:"" name:"studentId" help:"Student ID"`
 	Email     string `name:"email" help:"Filter by invited email address"`
 	States    string `name:"state" help:"Invitation states filter (comma-separated: PENDING,COMPLETE)"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
@@ -245,6 +249,10 @@ func (c *ClassroomGuardianInvitesListCmd) Run(ctx context.Context, flags *RootFl
 		return resp.GuardianInvitations, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var invitations []*classroom.GuardianInvitation
 	nextPageToken := ""
 	if c.All {
@@ -298,8 +306,8 @@ func (c *ClassroomGuardianInvitesListCmd) Run(ctx context.Context, flags *RootFl
 }
 
 type ClassroomGuardianInvitesGetCmd struct {
-	StudentID    string `arg:"" name:"studentId" help:"Student ID"`
-	InvitationID string `arg:"" name:"invitationId" help:"Invitation ID"`
+	StudentID    string `arg:"" stdin:"" name:"studentId" help:"Student ID"`
+	InvitationID string `arg:"" stdin:"" name:"invitationId" help:"Invitation ID"`
 }
 
 func (c *ClassroomGuardianInvitesGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -342,7 +350,7 @@ func (c *ClassroomGuardianInvitesGetCmd) Run(ctx context.Context, flags *RootFla
 }
 
 type ClassroomGuardianInvitesCreateCmd struct {
-	StudentID string `arg:"" name:"studentId" help:"Student ID"`
+	StudentID string `arg:"" stdin:"" name:"studentId" help:"Student ID"`
 	Email     string `name:"email" help:"Guardian email address" required:""`
 }
 
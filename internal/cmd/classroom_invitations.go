@@ -60,6 +60,10 @@ func (c *ClassroomInvitationsListCmd) Run(ctx context.Context, flags *RootFlags)
 		return resp.Invitations, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var invitations []*classroom.Invitation
 	nextPageToken := ""
 	if c.All {
@@ -113,7 +117,7 @@ func (c *ClassroomInvitationsListCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomInvitationsGetCmd struct {
-	InvitationID string `arg:"" name:"invitationId" help:"Invitation ID"`
+	InvitationID string `arg:"" stdin:"" name:"invitationId" help:"Invitation ID"`
 }
 
 func (c *ClassroomInvitationsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -149,8 +153,8 @@ func (c *ClassroomInvitationsGetCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomInvitationsCreateCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID   string `arg:"" name:"userId" help:"User ID or email"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID   string `arg:"" stdin:"" name:"userId" help:"User ID or email"`
 	Role     string `name:"role" help:"Role: STUDENT, TEACHER, OWNER" required:""`
 }
 
@@ -202,7 +206,7 @@ func (c *ClassroomInvitationsCreateCmd) Run(ctx context.Context, flags *RootFlag
 }
 
 type ClassroomInvitationsAcceptCmd struct {
-	InvitationID string `arg:"" name:"invitationId" help:"Invitation ID"`
+	InvitationID string `arg:"" stdin:"" name:"invitationId" help:"Invitation ID"`
 }
 
 func (c *ClassroomInvitationsAcceptCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -244,7 +248,7 @@ func (c *ClassroomInvitationsAcceptCmd) Run(ctx context.Context, flags *RootFlag
 }
 
 type ClassroomInvitationsDeleteCmd struct {
-	InvitationID string `arg:"" name:"invitationId" help:"Invitation ID"`
+	InvitationID string `arg:"" stdin:"" name:"invitationId" help:"Invitation ID"`
 }
 
 func (c *ClassroomInvitationsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
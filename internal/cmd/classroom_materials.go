@@ -21,7 +21,7 @@ type ClassroomMaterialsCmd struct {
 }
 
 type ClassroomMaterialsListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	States    string `name:"state" help:"Material states filter (comma-separated: PUBLISHED,DRAFT,DELETED)"`
 	Topic     string `name:"topic" help:"Filter by topic ID"`
 	OrderBy   string `name:"order-by" help:"Order by (e.g., updateTime desc)"`
@@ -71,6 +71,19 @@ func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) e
 		return resp.CourseWorkMaterial, resp.NextPageToken, nil
 	}
 
+	topic := strings.TrimSpace(c.Topic)
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*classroom.CourseWorkMaterial) ([]*classroom.CourseWorkMaterial, error) {
+		out := items[:0]
+		for _, material := range items {
+			if material != nil && (topic == "" || material.TopicId == topic) {
+				out = append(out, material)
+			}
+		}
+		return out, nil
+	}); handled {
+		return wrapClassroomError(err)
+	}
+
 	var materials []*classroom.CourseWorkMaterial
 	var nextPageToken string
 	if c.All {
@@ -79,7 +92,7 @@ func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) e
 			return wrapClassroomError(err)
 		}
 		materials = all
-		if topic := strings.TrimSpace(c.Topic); topic != "" {
+		if topic != "" {
 			filtered := materials[:0]
 			for _, material := range materials {
 				if material == nil {
@@ -148,8 +161,8 @@ func (c *ClassroomMaterialsListCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type ClassroomMaterialsGetCmd struct {
-	CourseID   string `arg:"" name:"courseId" help:"Course ID or alias"`
-	MaterialID string `arg:"" name:"materialId" help:"Material ID"`
+	CourseID   string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	MaterialID string `arg:"" stdin:"" name:"materialId" help:"Material ID"`
 }
 
 func (c *ClassroomMaterialsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -197,7 +210,7 @@ func (c *ClassroomMaterialsGetCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomMaterialsCreateCmd struct {
-	CourseID    string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID    string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Title       string `name:"title" help:"Title" required:""`
 	Description string `name:"description" help:"Description"`
 	State       string `name:"state" help:"State: PUBLISHED, DRAFT"`
@@ -261,8 +274,8 @@ func (c *ClassroomMaterialsCreateCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomMaterialsUpdateCmd struct {
-	CourseID    string `arg:"" name:"courseId" help:"Course ID or alias"`
-	MaterialID  string `arg:"" name:"materialId" help:"Material ID"`
+	CourseID    string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	MaterialID  string `arg:"" stdin:"" name:"materialId" help:"Material ID"`
 	Title       string `name:"title" help:"Title"`
 	Description string `name:"description" help:"Description"`
 	State       string `name:"state" help:"State: PUBLISHED, DRAFT"`
@@ -342,8 +355,8 @@ func (c *ClassroomMaterialsUpdateCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomMaterialsDeleteCmd struct {
-	CourseID   string `arg:"" name:"courseId" help:"Course ID or alias"`
-	MaterialID string `arg:"" name:"materialId" help:"Material ID"`
+	CourseID   string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	MaterialID string `arg:"" stdin:"" name:"materialId" help:"Material ID"`
 }
 
 func (c *ClassroomMaterialsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
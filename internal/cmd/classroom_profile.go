@@ -14,7 +14,7 @@ type ClassroomProfileCmd struct {
 }
 
 type ClassroomProfileGetCmd struct {
-	UserID string `arg:"" name:"userId" optional:"" help:"User ID or email (default: me)"`
+	UserID string `arg:"" stdin:"" name:"userId" optional:"" help:"User ID or email (default: me)"`
 }
 
 func (c *ClassroomProfileGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -20,7 +20,7 @@ type ClassroomStudentsCmd struct {
 }
 
 type ClassroomStudentsListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page      string `name:"page" aliases:"cursor" help:"Page token"`
 	All       bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
@@ -55,6 +55,10 @@ func (c *ClassroomStudentsListCmd) Run(ctx context.Context, flags *RootFlags) er
 		return resp.Students, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var students []*classroom.Student
 	nextPageToken := ""
 	if c.All {
@@ -107,8 +111,8 @@ func (c *ClassroomStudentsListCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomStudentsGetCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID   string `arg:"" name:"userId" help:"Student user ID or email"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID   string `arg:"" stdin:"" name:"userId" help:"Student user ID or email"`
 }
 
 func (c *ClassroomStudentsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -150,8 +154,8 @@ func (c *ClassroomStudentsGetCmd) Run(ctx context.Context, flags *RootFlags) err
 }
 
 type ClassroomStudentsAddCmd struct {
-	CourseID       string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID         string `arg:"" name:"userId" help:"Student user ID or email"`
+	CourseID       string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID         string `arg:"" stdin:"" name:"userId" help:"Student user ID or email"`
 	EnrollmentCode string `name:"enrollment-code" help:"Enrollment code"`
 }
 
@@ -204,8 +208,8 @@ func (c *ClassroomStudentsAddCmd) Run(ctx context.Context, flags *RootFlags) err
 }
 
 type ClassroomStudentsRemoveCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID   string `arg:"" name:"userId" help:"Student user ID or email"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID   string `arg:"" stdin:"" name:"userId" help:"Student user ID or email"`
 }
 
 func (c *ClassroomStudentsRemoveCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -252,7 +256,7 @@ type ClassroomTeachersCmd struct {
 }
 
 type ClassroomTeachersListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page      string `name:"page" aliases:"cursor" help:"Page token"`
 	All       bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
@@ -287,6 +291,10 @@ func (c *ClassroomTeachersListCmd) Run(ctx context.Context, flags *RootFlags) er
 		return resp.Teachers, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var teachers []*classroom.Teacher
 	nextPageToken := ""
 	if c.All {
@@ -339,8 +347,8 @@ func (c *ClassroomTeachersListCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomTeachersGetCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID   string `arg:"" name:"userId" help:"Teacher user ID or email"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID   string `arg:"" stdin:"" name:"userId" help:"Teacher user ID or email"`
 }
 
 func (c *ClassroomTeachersGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -379,8 +387,8 @@ func (c *ClassroomTeachersGetCmd) Run(ctx context.Context, flags *RootFlags) err
 }
 
 type ClassroomTeachersAddCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID   string `arg:"" name:"userId" help:"Teacher user ID or email"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID   string `arg:"" stdin:"" name:"userId" help:"Teacher user ID or email"`
 }
 
 func (c *ClassroomTeachersAddCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -427,8 +435,8 @@ func (c *ClassroomTeachersAddCmd) Run(ctx context.Context, flags *RootFlags) err
 }
 
 type ClassroomTeachersRemoveCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	UserID   string `arg:"" name:"userId" help:"Teacher user ID or email"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	UserID   string `arg:"" stdin:"" name:"userId" help:"Teacher user ID or email"`
 }
 
 func (c *ClassroomTeachersRemoveCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -468,7 +476,7 @@ func (c *ClassroomTeachersRemoveCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomRosterCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Students  bool   `name:"students" help:"Include students"`
 	Teachers  bool   `name:"teachers" help:"Include teachers"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results (per role)" default:"100"`
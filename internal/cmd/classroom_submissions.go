@@ -22,8 +22,8 @@ type ClassroomSubmissionsCmd struct {
 }
 
 type ClassroomSubmissionsListCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
 	States       string `name:"state" help:"Submission states filter (comma-separated: NEW,CREATED,TURNED_IN,RETURNED,RECLAIMED_BY_STUDENT)"`
 	Late         string `name:"late" help:"Late filter: late|not-late"`
 	UserID       string `name:"user" help:"Filter by user ID or email"`
@@ -86,6 +86,10 @@ func (c *ClassroomSubmissionsListCmd) Run(ctx context.Context, flags *RootFlags)
 		return resp.StudentSubmissions, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var submissions []*classroom.StudentSubmission
 	nextPageToken := ""
 	if c.All {
@@ -142,9 +146,9 @@ func (c *ClassroomSubmissionsListCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomSubmissionsGetCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
-	SubmissionID string `arg:"" name:"submissionId" help:"Submission ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
+	SubmissionID string `arg:"" stdin:"" name:"submissionId" help:"Submission ID"`
 }
 
 func (c *ClassroomSubmissionsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -196,9 +200,9 @@ func (c *ClassroomSubmissionsGetCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type ClassroomSubmissionsTurnInCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
-	SubmissionID string `arg:"" name:"submissionId" help:"Submission ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
+	SubmissionID string `arg:"" stdin:"" name:"submissionId" help:"Submission ID"`
 }
 
 func (c *ClassroomSubmissionsTurnInCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -206,9 +210,9 @@ func (c *ClassroomSubmissionsTurnInCmd) Run(ctx context.Context, flags *RootFlag
 }
 
 type ClassroomSubmissionsReclaimCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
-	SubmissionID string `arg:"" name:"submissionId" help:"Submission ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
+	SubmissionID string `arg:"" stdin:"" name:"submissionId" help:"Submission ID"`
 }
 
 func (c *ClassroomSubmissionsReclaimCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -216,9 +220,9 @@ func (c *ClassroomSubmissionsReclaimCmd) Run(ctx context.Context, flags *RootFla
 }
 
 type ClassroomSubmissionsReturnCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
-	SubmissionID string `arg:"" name:"submissionId" help:"Submission ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
+	SubmissionID string `arg:"" stdin:"" name:"submissionId" help:"Submission ID"`
 }
 
 func (c *ClassroomSubmissionsReturnCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -294,9 +298,9 @@ func submissionAction(ctx context.Context, flags *RootFlags, courseID, coursewor
 }
 
 type ClassroomSubmissionsGradeCmd struct {
-	CourseID     string `arg:"" name:"courseId" help:"Course ID or alias"`
-	CourseworkID string `arg:"" name:"courseworkId" help:"Coursework ID"`
-	SubmissionID string `arg:"" name:"submissionId" help:"Submission ID"`
+	CourseID     string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	CourseworkID string `arg:"" stdin:"" name:"courseworkId" help:"Coursework ID"`
+	SubmissionID string `arg:"" stdin:"" name:"submissionId" help:"Submission ID"`
 	Draft        string `name:"draft" help:"Draft grade"`
 	Assigned     string `name:"assigned" help:"Assigned grade"`
 }
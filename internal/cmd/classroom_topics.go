@@ -21,7 +21,7 @@ type ClassroomTopicsCmd struct {
 }
 
 type ClassroomTopicsListCmd struct {
-	CourseID  string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID  string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page      string `name:"page" aliases:"cursor" help:"Page token"`
 	All       bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
@@ -56,6 +56,10 @@ func (c *ClassroomTopicsListCmd) Run(ctx context.Context, flags *RootFlags) erro
 		return resp.Topic, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var topics []*classroom.Topic
 	nextPageToken := ""
 	if c.All {
@@ -108,8 +112,8 @@ func (c *ClassroomTopicsListCmd) Run(ctx context.Context, flags *RootFlags) erro
 }
 
 type ClassroomTopicsGetCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	TopicID  string `arg:"" name:"topicId" help:"Topic ID"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	TopicID  string `arg:"" stdin:"" name:"topicId" help:"Topic ID"`
 }
 
 func (c *ClassroomTopicsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -150,7 +154,7 @@ func (c *ClassroomTopicsGetCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type ClassroomTopicsCreateCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
 	Name     string `name:"name" help:"Topic name" required:""`
 }
 
@@ -197,8 +201,8 @@ func (c *ClassroomTopicsCreateCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomTopicsUpdateCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	TopicID  string `arg:"" name:"topicId" help:"Topic ID"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	TopicID  string `arg:"" stdin:"" name:"topicId" help:"Topic ID"`
 	Name     string `name:"name" help:"Topic name" required:""`
 }
 
@@ -251,8 +255,8 @@ func (c *ClassroomTopicsUpdateCmd) Run(ctx context.Context, flags *RootFlags) er
 }
 
 type ClassroomTopicsDeleteCmd struct {
-	CourseID string `arg:"" name:"courseId" help:"Course ID or alias"`
-	TopicID  string `arg:"" name:"topicId" help:"Topic ID"`
+	CourseID string `arg:"" stdin:"" name:"courseId" help:"Course ID or alias"`
+	TopicID  string `arg:"" stdin:"" name:"topicId" help:"Topic ID"`
 }
 
 func (c *ClassroomTopicsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
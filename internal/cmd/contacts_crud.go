@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
@@ -94,8 +95,11 @@ func (c *ContactsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
+// contactsBatchGetSize is the People API limit for resourceNames per people:batchGet call.
+const contactsBatchGetSize = 200
+
 type ContactsGetCmd struct {
-	Identifier string `arg:"" name:"resourceName" help:"Resource name (people/...) or email"`
+	Identifiers []string `arg:"" stdin:"" name:"resourceName" help:"Resource names (people/...) or emails"`
 }
 
 func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -104,8 +108,15 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if err != nil {
 		return err
 	}
-	identifier := strings.TrimSpace(c.Identifier)
-	if identifier == "" {
+	identifiers := make([]string, 0, len(c.Identifiers))
+	for _, id := range c.Identifiers {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return usage("empty identifier")
+		}
+		identifiers = append(identifiers, id)
+	}
+	if len(identifiers) == 0 {
 		return usage("empty identifier")
 	}
 
@@ -114,6 +125,11 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if len(identifiers) > 1 {
+		return c.runMany(ctx, u, svc, identifiers)
+	}
+
+	identifier := identifiers[0]
 	var p *people.Person
 	if strings.HasPrefix(identifier, "people/") {
 		p, err = svc.People.Get(identifier).PersonFields(contactsGetReadMask).Do()
@@ -121,26 +137,10 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 			return err
 		}
 	} else {
-		resp, err := svc.People.SearchContacts().
-			Query(identifier).
-			PageSize(10).
-			ReadMask(contactsGetReadMask).
-			Do()
+		p, err = findContactByEmail(svc, identifier)
 		if err != nil {
 			return err
 		}
-		for _, r := range resp.Results {
-			if r.Person == nil {
-				continue
-			}
-			if strings.EqualFold(primaryEmail(r.Person), identifier) {
-				p = r.Person
-				break
-			}
-			if p == nil {
-				p = r.Person
-			}
-		}
 		if p == nil {
 			if outfmt.IsJSON(ctx) {
 				return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"found": false})
@@ -153,7 +153,113 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"contact": p})
 	}
+	printContact(u, p)
+	return nil
+}
+
+// runMany fetches resource names through people:batchGet and looks up emails one
+// search at a time, keeping the order identifiers were given in.
+func (c *ContactsGetCmd) runMany(ctx context.Context, u *ui.UI, svc *people.Service, identifiers []string) error {
+	var names []string
+	for _, id := range identifiers {
+		if strings.HasPrefix(id, "people/") {
+			names = append(names, id)
+		}
+	}
+	byName, err := batchGetContacts(ctx, svc, names)
+	if err != nil {
+		return err
+	}
 
+	contacts := make([]*people.Person, 0, len(identifiers))
+	var notFound []string
+	for _, id := range identifiers {
+		var p *people.Person
+		if strings.HasPrefix(id, "people/") {
+			p = byName[id]
+		} else if p, err = findContactByEmail(svc, id); err != nil {
+			return err
+		}
+		if p == nil {
+			notFound = append(notFound, id)
+			continue
+		}
+		contacts = append(contacts, p)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		out := map[string]any{"contacts": contacts}
+		if len(notFound) > 0 {
+			out["notFound"] = notFound
+		}
+		return outfmt.WriteJSON(ctx, os.Stdout, out)
+	}
+	for i, p := range contacts {
+		if i > 0 {
+			u.Out().Println("")
+		}
+		printContact(u, p)
+	}
+	for _, id := range notFound {
+		u.Err().Printf("Not found: %s", id)
+	}
+	return nil
+}
+
+// batchGetContacts resolves resource names with people:batchGet, contactsBatchGetSize
+// at a time. Names the API reports as missing are left out of the result.
+func batchGetContacts(ctx context.Context, svc *people.Service, names []string) (map[string]*people.Person, error) {
+	out := make(map[string]*people.Person, len(names))
+	for start := 0; start < len(names); start += contactsBatchGetSize {
+		end := min(start+contactsBatchGetSize, len(names))
+		resp, err := svc.People.GetBatchGet().
+			ResourceNames(names[start:end]...).
+			PersonFields(contactsGetReadMask).
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resp.Responses {
+			if r == nil || r.Person == nil {
+				if r != nil && r.Status != nil && r.HttpStatusCode != http.StatusNotFound {
+					return nil, fmt.Errorf("get %s: %s", r.RequestedResourceName, r.Status.Message)
+				}
+				continue
+			}
+			out[r.RequestedResourceName] = r.Person
+		}
+	}
+	return out, nil
+}
+
+// findContactByEmail searches contacts for email, preferring an exact primary
+// email match over the first result. It returns nil when nothing matches.
+func findContactByEmail(svc *people.Service, email string) (*people.Person, error) {
+	resp, err := svc.People.SearchContacts().
+		Query(email).
+		PageSize(10).
+		ReadMask(contactsGetReadMask).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	var p *people.Person
+	for _, r := range resp.Results {
+		if r.Person == nil {
+			continue
+		}
+		if strings.EqualFold(primaryEmail(r.Person), email) {
+			return r.Person, nil
+		}
+		if p == nil {
+			p = r.Person
+		}
+	}
+	return p, nil
+}
+
+func printContact(u *ui.UI, p *people.Person) {
 	u.Out().Printf("resource\t%s", p.ResourceName)
 	u.Out().Printf("name\t%s", primaryName(p))
 	if e := primaryEmail(p); e != "" {
@@ -191,7 +297,6 @@ func (c *ContactsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 			u.Out().Printf("custom:%s\t%s", k, customFields[k])
 		}
 	}
-	return nil
 }
 
 type ContactsCreateCmd struct {
@@ -247,7 +352,7 @@ func contactsURLs(values []string) []*people.Url {
 	return out
 }
 
-func contactsApplyPersonName(person *people.Person, givenSet bool, given, familySet bool, family string) {
+func contactsApplyPersonName(person *people.Person, givenSet bool, given string, familySet bool, family string) {
 	curGiven := ""
 	curFamily := ""
 	if len(person.Names) > 0 && person.Names[0] != nil {
@@ -263,7 +368,7 @@ func contactsApplyPersonName(person *people.Person, givenSet bool, given, family
 	person.Names = []*people.Name{{GivenName: curGiven, FamilyName: curFamily}}
 }
 
-func contactsApplyPersonOrganization(person *people.Person, orgSet bool, org, titleSet bool, title string) {
+func contactsApplyPersonOrganization(person *people.Person, orgSet bool, org string, titleSet bool, title string) {
 	curOrg := ""
 	curTitle := ""
 	if len(person.Organizations) > 0 && person.Organizations[0] != nil {
@@ -346,7 +451,7 @@ func (c *ContactsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type ContactsUpdateCmd struct {
-	ResourceName string   `arg:"" name:"resourceName" help:"Resource name (people/...)"`
+	ResourceName string   `arg:"" stdin:"" name:"resourceName" help:"Resource name (people/...)"`
 	Given        string   `name:"given" help:"Given name"`
 	Family       string   `name:"family" help:"Family name"`
 	Email        string   `name:"email" help:"Email address (empty clears)"`
@@ -512,7 +617,7 @@ func (c *ContactsUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *
 }
 
 type ContactsDeleteCmd struct {
-	ResourceName string `arg:"" name:"resourceName" help:"Resource name (people/...)"`
+	ResourceName string `arg:"" stdin:"" name:"resourceName" help:"Resource name (people/...)"`
 }
 
 func parseYYYYMMDD(s string) (*people.Date, error) {
@@ -62,6 +62,12 @@ func (c *ContactsDirectoryListCmd) Run(ctx context.Context, flags *RootFlags) er
 		return resp.People, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*people.Person) ([]directoryPersonItem, error) {
+		return directoryPersonItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var peopleList []*people.Person
 	nextPageToken := ""
 	if c.All {
@@ -78,22 +84,7 @@ func (c *ContactsDirectoryListCmd) Run(ctx context.Context, flags *RootFlags) er
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource string `json:"resource"`
-			Name     string `json:"name,omitempty"`
-			Email    string `json:"email,omitempty"`
-		}
-		items := make([]item, 0, len(peopleList))
-		for _, p := range peopleList {
-			if p == nil {
-				continue
-			}
-			items = append(items, item{
-				Resource: p.ResourceName,
-				Name:     primaryName(p),
-				Email:    primaryEmail(p),
-			})
-		}
+		items := directoryPersonItems(peopleList)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"people":        items,
 			"nextPageToken": nextPageToken,
@@ -169,6 +160,12 @@ func (c *ContactsDirectorySearchCmd) Run(ctx context.Context, flags *RootFlags)
 		return resp.People, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*people.Person) ([]directoryPersonItem, error) {
+		return directoryPersonItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var peopleList []*people.Person
 	nextPageToken := ""
 	if c.All {
@@ -185,22 +182,7 @@ func (c *ContactsDirectorySearchCmd) Run(ctx context.Context, flags *RootFlags)
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource string `json:"resource"`
-			Name     string `json:"name,omitempty"`
-			Email    string `json:"email,omitempty"`
-		}
-		items := make([]item, 0, len(peopleList))
-		for _, p := range peopleList {
-			if p == nil {
-				continue
-			}
-			items = append(items, item{
-				Resource: p.ResourceName,
-				Name:     primaryName(p),
-				Email:    primaryEmail(p),
-			})
-		}
+		items := directoryPersonItems(peopleList)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"people":        items,
 			"nextPageToken": nextPageToken,
@@ -275,6 +257,12 @@ func (c *ContactsOtherListCmd) Run(ctx context.Context, flags *RootFlags) error
 		return resp.OtherContacts, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*people.Person) ([]otherContactItem, error) {
+		return otherContactItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var contacts []*people.Person
 	nextPageToken := ""
 	if c.All {
@@ -291,24 +279,7 @@ func (c *ContactsOtherListCmd) Run(ctx context.Context, flags *RootFlags) error
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource string `json:"resource"`
-			Name     string `json:"name,omitempty"`
-			Email    string `json:"email,omitempty"`
-			Phone    string `json:"phone,omitempty"`
-		}
-		items := make([]item, 0, len(contacts))
-		for _, p := range contacts {
-			if p == nil {
-				continue
-			}
-			items = append(items, item{
-				Resource: p.ResourceName,
-				Name:     primaryName(p),
-				Email:    primaryEmail(p),
-				Phone:    primaryPhone(p),
-			})
-		}
+		items := otherContactItems(contacts)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"contacts":      items,
 			"nextPageToken": nextPageToken,
@@ -417,7 +388,7 @@ func (c *ContactsOtherSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 }
 
 type ContactsOtherDeleteCmd struct {
-	ResourceName string `arg:"" name:"resourceName" help:"Resource name (otherContacts/...)"`
+	ResourceName string `arg:"" stdin:"" name:"resourceName" help:"Resource name (otherContacts/...)"`
 }
 
 func (c *ContactsOtherDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -471,3 +442,47 @@ func deleteOtherContact(ctx context.Context, account, resourceName string) error
 	}
 	return nil
 }
+
+type directoryPersonItem struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+func directoryPersonItems(peopleList []*people.Person) []directoryPersonItem {
+	items := make([]directoryPersonItem, 0, len(peopleList))
+	for _, p := range peopleList {
+		if p == nil {
+			continue
+		}
+		items = append(items, directoryPersonItem{
+			Resource: p.ResourceName,
+			Name:     primaryName(p),
+			Email:    primaryEmail(p),
+		})
+	}
+	return items
+}
+
+type otherContactItem struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+}
+
+func otherContactItems(contacts []*people.Person) []otherContactItem {
+	items := make([]otherContactItem, 0, len(contacts))
+	for _, p := range contacts {
+		if p == nil {
+			continue
+		}
+		items = append(items, otherContactItem{
+			Resource: p.ResourceName,
+			Name:     primaryName(p),
+			Email:    primaryEmail(p),
+			Phone:    primaryPhone(p),
+		})
+	}
+	return items
+}
@@ -36,7 +36,7 @@ type DocsCmd struct {
 	Update      DocsUpdateCmd      `cmd:"" name:"update" help:"Update content in a Google Doc"`
 }
 type DocsExportCmd struct {
-	DocID  string         `arg:"" name:"docId" help:"Doc ID"`
+	DocID  string         `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Output OutputPathFlag `embed:""`
 	Format string         `name:"format" help:"Export format: pdf|docx|txt" default:"pdf"`
 }
@@ -51,7 +51,7 @@ func (c *DocsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DocsInfoCmd struct {
-	DocID string `arg:"" name:"docId" help:"Doc ID"`
+	DocID string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 }
 
 func (c *DocsInfoCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -253,7 +253,7 @@ func (c *DocsCreateCmd) insertImages(ctx context.Context, account string, driveS
 }
 
 type DocsCopyCmd struct {
-	DocID  string `arg:"" name:"docId" help:"Doc ID"`
+	DocID  string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Title  string `arg:"" name:"title" help:"New title"`
 	Parent string `name:"parent" help:"Destination folder ID"`
 }
@@ -267,7 +267,7 @@ func (c *DocsCopyCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DocsCatCmd struct {
-	DocID    string `arg:"" name:"docId" help:"Doc ID"`
+	DocID    string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	MaxBytes int64  `name:"max-bytes" help:"Max bytes to read (0 = unlimited)" default:"2000000"`
 	Tab      string `name:"tab" help:"Tab title or ID to read (omit for default behavior)"`
 	AllTabs  bool   `name:"all-tabs" help:"Show all tabs with headers"`
@@ -318,7 +318,7 @@ func (c *DocsCatCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DocsUpdateCmd struct {
-	DocID       string `arg:"" name:"docId" help:"Doc ID"`
+	DocID       string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Content     string `name:"content" help:"Text content to insert (mutually exclusive with --content-file)"`
 	ContentFile string `name:"content-file" help:"File containing text content to insert"`
 	Format      string `name:"format" help:"Content format: plain|markdown" default:"plain"`
@@ -557,7 +557,7 @@ func (c *DocsCatCmd) runWithTabs(ctx context.Context, svc *docs.Service, id stri
 }
 
 type DocsListTabsCmd struct {
-	DocID string `arg:"" name:"docId" help:"Doc ID"`
+	DocID string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 }
 
 func (c *DocsListTabsCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -617,7 +617,7 @@ func (c *DocsListTabsCmd) Run(ctx context.Context, flags *RootFlags) error {
 // --- Write / Insert / Delete / Find-Replace commands ---
 
 type DocsWriteCmd struct {
-	DocID    string `arg:"" name:"docId" help:"Doc ID"`
+	DocID    string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Content  string `arg:"" optional:"" name:"content" help:"Content to write (or use --file / stdin)"`
 	File     string `name:"file" short:"f" help:"Read content from file (use - for stdin)"`
 	Replace  bool   `name:"replace" help:"Replace all content (default: append)"`
@@ -764,7 +764,7 @@ func (c *DocsWriteCmd) writePlainText(ctx context.Context, account, docID, conte
 }
 
 type DocsInsertCmd struct {
-	DocID   string `arg:"" name:"docId" help:"Doc ID"`
+	DocID   string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Content string `arg:"" optional:"" name:"content" help:"Text to insert (or use --file / stdin)"`
 	Index   int64  `name:"index" help:"Character index to insert at (1 = beginning)" default:"1"`
 	File    string `name:"file" short:"f" help:"Read content from file (use - for stdin)"`
@@ -828,7 +828,7 @@ func (c *DocsInsertCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DocsDeleteCmd struct {
-	DocID string `arg:"" name:"docId" help:"Doc ID"`
+	DocID string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Start int64  `name:"start" required:"" help:"Start index (>= 1)"`
 	End   int64  `name:"end" required:"" help:"End index (> start)"`
 }
@@ -887,7 +887,7 @@ func (c *DocsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DocsFindReplaceCmd struct {
-	DocID       string `arg:"" name:"docId" help:"Doc ID"`
+	DocID       string `arg:"" stdin:"" name:"docId" help:"Doc ID"`
 	Find        string `arg:"" name:"find" help:"Text to find"`
 	ReplaceText string `arg:"" name:"replace" help:"Replacement text"`
 	MatchCase   bool   `name:"match-case" help:"Case-sensitive matching"`
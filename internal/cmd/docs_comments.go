@@ -24,7 +24,7 @@ type DocsCommentsCmd struct {
 
 // DocsCommentsListCmd lists comments on a Google Doc.
 type DocsCommentsListCmd struct {
-	DocID           string `arg:"" name:"docId" help:"Google Doc ID or URL"`
+	DocID           string `arg:"" stdin:"" name:"docId" help:"Google Doc ID or URL"`
 	IncludeResolved bool   `name:"include-resolved" aliases:"resolved" help:"Include resolved comments (default: open only)"`
 	Max             int64  `name:"max" aliases:"limit" help:"Max results per page" default:"100"`
 	Page            string `name:"page" aliases:"cursor" help:"Page token for pagination"`
@@ -67,6 +67,15 @@ func (c *DocsCommentsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Comments, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*drive.Comment) ([]*drive.Comment, error) {
+		if c.IncludeResolved {
+			return items, nil
+		}
+		return filterOpenComments(items), nil
+	}); handled {
+		return err
+	}
+
 	var comments []*drive.Comment
 	nextPageToken := ""
 	if c.All {
@@ -181,8 +190,8 @@ func (c *DocsCommentsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 // DocsCommentsGetCmd retrieves a single comment by ID.
 type DocsCommentsGetCmd struct {
-	DocID     string `arg:"" name:"docId" help:"Google Doc ID or URL"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	DocID     string `arg:"" stdin:"" name:"docId" help:"Google Doc ID or URL"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 }
 
 func (c *DocsCommentsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -250,7 +259,7 @@ func (c *DocsCommentsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 // DocsCommentsAddCmd creates a comment on a Google Doc.
 type DocsCommentsAddCmd struct {
-	DocID   string `arg:"" name:"docId" help:"Google Doc ID or URL"`
+	DocID   string `arg:"" stdin:"" name:"docId" help:"Google Doc ID or URL"`
 	Content string `arg:"" name:"content" help:"Comment text"`
 	Quoted  string `name:"quoted" help:"Quoted text to attach to the comment (shown in UIs when available)"`
 	Anchor  string `name:"anchor" help:"Anchor JSON string (advanced; editor UIs may still treat as unanchored)"`
@@ -316,8 +325,8 @@ func (c *DocsCommentsAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 // DocsCommentsReplyCmd replies to a comment on a Google Doc.
 type DocsCommentsReplyCmd struct {
-	DocID     string `arg:"" name:"docId" help:"Google Doc ID or URL"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	DocID     string `arg:"" stdin:"" name:"docId" help:"Google Doc ID or URL"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 	Content   string `arg:"" name:"content" help:"Reply text"`
 }
 
@@ -375,8 +384,8 @@ func (c *DocsCommentsReplyCmd) Run(ctx context.Context, flags *RootFlags) error
 // DocsCommentsResolveCmd resolves a comment by posting an empty reply with action "resolve".
 // The Drive API resolves a comment when a reply is created with action="resolve".
 type DocsCommentsResolveCmd struct {
-	DocID     string `arg:"" name:"docId" help:"Google Doc ID or URL"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	DocID     string `arg:"" stdin:"" name:"docId" help:"Google Doc ID or URL"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 	Message   string `name:"message" short:"m" help:"Optional message to include when resolving"`
 }
 
@@ -440,8 +449,8 @@ func (c *DocsCommentsResolveCmd) Run(ctx context.Context, flags *RootFlags) erro
 
 // DocsCommentsDeleteCmd deletes a comment on a Google Doc.
 type DocsCommentsDeleteCmd struct {
-	DocID     string `arg:"" name:"docId" help:"Google Doc ID or URL"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	DocID     string `arg:"" stdin:"" name:"docId" help:"Google Doc ID or URL"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 }
 
 func (c *DocsCommentsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -82,13 +83,13 @@ type DriveCmd struct {
 type DriveLsCmd struct {
 	Max       int64  `name:"max" aliases:"limit" help:"Max results" default:"20"`
 	Page      string `name:"page" aliases:"cursor" help:"Page token"`
+	All       bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
 	Query     string `name:"query" help:"Drive query filter"`
 	Parent    string `name:"parent" help:"Folder ID to list (default: root)"`
 	AllDrives bool   `name:"all-drives" help:"Include shared drives (default: true; use --no-all-drives for My Drive only)" default:"true" negatable:"_"`
 }
 
 func (c *DriveLsCmd) Run(ctx context.Context, flags *RootFlags) error {
-	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
 		return err
@@ -104,51 +105,8 @@ func (c *DriveLsCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	q := buildDriveListQuery(folderID, c.Query)
-
-	call := svc.Files.List().
-		Q(q).
-		PageSize(c.Max).
-		PageToken(c.Page).
-		OrderBy("modifiedTime desc")
-	call = driveFilesListCallWithDriveSupport(call, c.AllDrives)
-
-	resp, err := call.
-		Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
-		Context(ctx).
-		Do()
-	if err != nil {
-		return err
-	}
-
-	if outfmt.IsJSON(ctx) {
-		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
-			"files":         resp.Files,
-			"nextPageToken": resp.NextPageToken,
-		})
-	}
-
-	if len(resp.Files) == 0 {
-		u.Err().Println("No files")
-		return nil
-	}
-
-	w, flush := tableWriter(ctx)
-	defer flush()
-	fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
-	for _, f := range resp.Files {
-		fmt.Fprintf(
-			w,
-			"%s\t%s\t%s\t%s\t%s\n",
-			f.Id,
-			f.Name,
-			driveType(f.MimeType),
-			formatDriveSize(f.Size),
-			formatDateTime(f.ModifiedTime),
-		)
-	}
-	printNextPageHint(u, resp.NextPageToken)
-	return nil
+	fetch := driveFilesListFetcher(ctx, svc, buildDriveListQuery(folderID, c.Query), c.Max, c.AllDrives)
+	return writeDriveFileList(ctx, fetch, c.Page, c.All, "No files")
 }
 
 type DriveSearchCmd struct {
@@ -156,11 +114,11 @@ type DriveSearchCmd struct {
 	RawQuery  bool     `name:"raw-query" aliases:"raw" help:"Treat query as Drive query language (pass through; may error if invalid)"`
 	Max       int64    `name:"max" aliases:"limit" help:"Max results" default:"20"`
 	Page      string   `name:"page" aliases:"cursor" help:"Page token"`
+	All       bool     `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
 	AllDrives bool     `name:"all-drives" help:"Include shared drives (default: true; use --no-all-drives for My Drive only)" default:"true" negatable:"_"`
 }
 
 func (c *DriveSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
-	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
 	if err != nil {
 		return err
@@ -175,37 +133,76 @@ func (c *DriveSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	call := svc.Files.List().
-		Q(buildDriveSearchQuery(query, c.RawQuery)).
-		PageSize(c.Max).
-		PageToken(c.Page).
-		OrderBy("modifiedTime desc")
-	call = driveFilesListCallWithDriveSupport(call, c.AllDrives)
+	fetch := driveFilesListFetcher(ctx, svc, buildDriveSearchQuery(query, c.RawQuery), c.Max, c.AllDrives)
+	return writeDriveFileList(ctx, fetch, c.Page, c.All, "No results")
+}
 
-	resp, err := call.
-		Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
-		Context(ctx).
-		Do()
-	if err != nil {
-		return err
+func driveFilesListFetcher(ctx context.Context, svc *drive.Service, q string, pageSize int64, allDrives bool) func(string) ([]*drive.File, string, error) {
+	return func(pageToken string) ([]*drive.File, string, error) {
+		call := svc.Files.List().
+			Q(q).
+			PageSize(pageSize).
+			PageToken(pageToken).
+			OrderBy("modifiedTime desc")
+		call = driveFilesListCallWithDriveSupport(call, allDrives)
+
+		resp, err := call.
+			Fields("nextPageToken, files(id, name, mimeType, size, modifiedTime, parents, webViewLink)").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Files, resp.NextPageToken, nil
+	}
+}
+
+func writeDriveFileList(ctx context.Context, fetch func(string) ([]*drive.File, string, error), page string, allPages bool, emptyMsg string) error {
+	u := ui.FromContext(ctx)
+
+	if allPages && outfmt.IsNDJSON(ctx) {
+		count, err := streamAllPagesNDJSON(ctx, page, fetch)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			u.Err().Println(emptyMsg)
+		}
+		return nil
+	}
+
+	var files []*drive.File
+	nextPageToken := ""
+	if allPages {
+		all, err := collectAllPages(page, fetch)
+		if err != nil {
+			return err
+		}
+		files = all
+	} else {
+		var err error
+		files, nextPageToken, err = fetch(page)
+		if err != nil {
+			return err
+		}
 	}
 
 	if outfmt.IsJSON(ctx) {
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
-			"files":         resp.Files,
-			"nextPageToken": resp.NextPageToken,
+			"files":         files,
+			"nextPageToken": nextPageToken,
 		})
 	}
 
-	if len(resp.Files) == 0 {
-		u.Err().Println("No results")
+	if len(files) == 0 {
+		u.Err().Println(emptyMsg)
 		return nil
 	}
 
 	w, flush := tableWriter(ctx)
 	defer flush()
 	fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
-	for _, f := range resp.Files {
+	for _, f := range files {
 		fmt.Fprintf(
 			w,
 			"%s\t%s\t%s\t%s\t%s\n",
@@ -216,14 +213,17 @@ func (c *DriveSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 			formatDateTime(f.ModifiedTime),
 		)
 	}
-	printNextPageHint(u, resp.NextPageToken)
+	printNextPageHint(u, nextPageToken)
 	return nil
 }
 
 type DriveGetCmd struct {
-	FileID string `arg:"" name:"fileId" help:"File ID"`
+	FileID  string   `arg:"" stdin:"" name:"fileId" help:"File ID"`
+	FileIDs []string `arg:"" stdin:"" optional:"" name:"moreFileIds" help:"More file IDs (fetched in one batch request)"`
 }
 
+const driveGetFields = "id, name, mimeType, size, modifiedTime, createdTime, parents, webViewLink, description, starred"
+
 func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
 	account, err := requireAccount(flags)
@@ -240,9 +240,13 @@ func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	if len(c.FileIDs) > 0 {
+		return c.runMany(ctx, u, svc, append([]string{fileID}, c.FileIDs...))
+	}
+
 	f, err := svc.Files.Get(fileID).
 		SupportsAllDrives(true).
-		Fields("id, name, mimeType, size, modifiedTime, createdTime, parents, webViewLink, description, starred").
+		Fields(driveGetFields).
 		Context(ctx).
 		Do()
 	if err != nil {
@@ -269,8 +273,75 @@ func (c *DriveGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
+// runMany fetches metadata for several files; failures are reported per file.
+func (c *DriveGetCmd) runMany(ctx context.Context, u *ui.UI, svc *drive.Service, ids []string) error {
+	files := make([]*drive.File, len(ids))
+	errs := make([]error, len(ids))
+
+	if b := batcherFor(svc); b != nil {
+		q := url.Values{"supportsAllDrives": {"true"}, "fields": {driveGetFields}}
+		paths := make([]string, 0, len(ids))
+		for _, id := range ids {
+			paths = append(paths, "files/"+url.PathEscape(strings.TrimSpace(id))+"?"+q.Encode())
+		}
+		var err error
+		if files, errs, err = googleapi.BatchGet[drive.File](ctx, b, paths); err != nil {
+			return err
+		}
+	} else {
+		for i, id := range ids {
+			files[i], errs[i] = svc.Files.Get(strings.TrimSpace(id)).
+				SupportsAllDrives(true).
+				Fields(driveGetFields).
+				Context(ctx).
+				Do()
+		}
+	}
+
+	found := make([]*drive.File, 0, len(ids))
+	failures := make([]map[string]string, 0)
+	for i, id := range ids {
+		if errs[i] != nil {
+			failures = append(failures, map[string]string{"id": id, "error": errs[i].Error()})
+			continue
+		}
+		found = append(found, files[i])
+	}
+
+	if outfmt.IsJSON(ctx) {
+		payload := map[string]any{"files": found}
+		if len(failures) > 0 {
+			payload["errors"] = failures
+		}
+		if err := outfmt.WriteJSON(ctx, os.Stdout, payload); err != nil {
+			return err
+		}
+	} else {
+		w, flush := tableWriter(ctx)
+		fmt.Fprintln(w, "ID\tNAME\tTYPE\tSIZE\tMODIFIED")
+		for _, f := range found {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				f.Id,
+				sanitizeTab(f.Name),
+				driveType(f.MimeType),
+				formatDriveSize(f.Size),
+				formatDateTime(f.ModifiedTime),
+			)
+		}
+		flush()
+		for _, failure := range failures {
+			u.Err().Printf("%s: %s", failure["id"], failure["error"])
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d files failed", len(failures), len(ids))
+	}
+	return nil
+}
+
 type DriveDownloadCmd struct {
-	FileID string         `arg:"" name:"fileId" help:"File ID"`
+	FileID string         `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Output OutputPathFlag `embed:""`
 	Format string         `name:"format" help:"Export format for Google Docs files: pdf|csv|xlsx|pptx|txt|png|docx (default: inferred)"`
 }
@@ -333,7 +404,7 @@ func (c *DriveDownloadCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveCopyCmd struct {
-	FileID string `arg:"" name:"fileId" help:"File ID"`
+	FileID string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Name   string `arg:"" name:"name" help:"New file name"`
 	Parent string `name:"parent" help:"Destination folder ID"`
 }
@@ -553,7 +624,7 @@ func (c *DriveMkdirCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveDeleteCmd struct {
-	FileID    string `arg:"" name:"fileId" help:"File ID"`
+	FileID    string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Permanent bool   `name:"permanent" help:"Permanently delete instead of moving to trash" default:"false"`
 }
 
@@ -606,7 +677,7 @@ func (c *DriveDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveMoveCmd struct {
-	FileID string `arg:"" name:"fileId" help:"File ID"`
+	FileID string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Parent string `name:"parent" help:"New parent folder ID (required)"`
 }
 
@@ -662,7 +733,7 @@ func (c *DriveMoveCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveRenameCmd struct {
-	FileID  string `arg:"" name:"fileId" help:"File ID"`
+	FileID  string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	NewName string `arg:"" name:"newName" help:"New name"`
 }
 
@@ -705,7 +776,7 @@ func (c *DriveRenameCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveShareCmd struct {
-	FileID       string `arg:"" name:"fileId" help:"File ID"`
+	FileID       string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	To           string `name:"to" help:"Share target: anyone|user|domain"`
 	Anyone       bool   `name:"anyone" hidden:"" help:"(deprecated) Use --to=anyone"`
 	Email        string `name:"email" help:"User email (for --to=user)"`
@@ -828,8 +899,8 @@ func (c *DriveShareCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveUnshareCmd struct {
-	FileID       string `arg:"" name:"fileId" help:"File ID"`
-	PermissionID string `arg:"" name:"permissionId" help:"Permission ID"`
+	FileID       string `arg:"" stdin:"" name:"fileId" help:"File ID"`
+	PermissionID string `arg:"" stdin:"" name:"permissionId" help:"Permission ID"`
 }
 
 func (c *DriveUnshareCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -868,7 +939,7 @@ func (c *DriveUnshareCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DrivePermissionsCmd struct {
-	FileID string `arg:"" name:"fileId" help:"File ID"`
+	FileID string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Max    int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page   string `name:"page" aliases:"cursor" help:"Page token"`
 }
@@ -935,7 +1006,7 @@ func (c *DrivePermissionsCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveURLCmd struct {
-	FileIDs []string `arg:"" name:"fileId" help:"File IDs"`
+	FileIDs []string `arg:"" stdin:"" name:"fileId" help:"File IDs"`
 }
 
 func (c *DriveURLCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -23,7 +23,7 @@ type DriveCommentsCmd struct {
 }
 
 type DriveCommentsListCmd struct {
-	FileID        string `arg:"" name:"fileId" help:"File ID"`
+	FileID        string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Max           int64  `name:"max" aliases:"limit" help:"Max results" default:"100"`
 	Page          string `name:"page" aliases:"cursor" help:"Page token"`
 	All           bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
@@ -73,6 +73,10 @@ func (c *DriveCommentsListCmd) Run(ctx context.Context, flags *RootFlags) error
 		return resp.Comments, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var comments []*drive.Comment
 	nextPageToken := ""
 	if c.All {
@@ -152,8 +156,8 @@ func (c *DriveCommentsListCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type DriveCommentsGetCmd struct {
-	FileID    string `arg:"" name:"fileId" help:"File ID"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	FileID    string `arg:"" stdin:"" name:"fileId" help:"File ID"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 }
 
 func (c *DriveCommentsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -206,7 +210,7 @@ func (c *DriveCommentsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type DriveCommentsCreateCmd struct {
-	FileID  string `arg:"" name:"fileId" help:"File ID"`
+	FileID  string `arg:"" stdin:"" name:"fileId" help:"File ID"`
 	Content string `arg:"" name:"content" help:"Comment text"`
 	Quoted  string `name:"quoted" help:"Text to anchor the comment to (for Google Docs)"`
 }
@@ -271,8 +275,8 @@ func (c *DriveCommentsCreateCmd) Run(ctx context.Context, flags *RootFlags) erro
 }
 
 type DriveCommentsUpdateCmd struct {
-	FileID    string `arg:"" name:"fileId" help:"File ID"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	FileID    string `arg:"" stdin:"" name:"fileId" help:"File ID"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 	Content   string `arg:"" name:"content" help:"New comment text"`
 }
 
@@ -332,8 +336,8 @@ func (c *DriveCommentsUpdateCmd) Run(ctx context.Context, flags *RootFlags) erro
 }
 
 type DriveCommentsDeleteCmd struct {
-	FileID    string `arg:"" name:"fileId" help:"File ID"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	FileID    string `arg:"" stdin:"" name:"fileId" help:"File ID"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 }
 
 func (c *DriveCommentsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -373,8 +377,8 @@ func (c *DriveCommentsDeleteCmd) Run(ctx context.Context, flags *RootFlags) erro
 }
 
 type DriveCommentReplyCmd struct {
-	FileID    string `arg:"" name:"fileId" help:"File ID"`
-	CommentID string `arg:"" name:"commentId" help:"Comment ID"`
+	FileID    string `arg:"" stdin:"" name:"fileId" help:"File ID"`
+	CommentID string `arg:"" stdin:"" name:"commentId" help:"Comment ID"`
 	Content   string `arg:"" name:"content" help:"Reply text"`
 }
 
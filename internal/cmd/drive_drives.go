@@ -51,6 +51,10 @@ func (c *DriveDrivesCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Drives, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var drives []*drive.Drive
 	nextPageToken := ""
 	if c.All {
@@ -130,3 +130,80 @@ func TestExecute_ContactsGet_ByEmail_JSON(t *testing.T) {
 		t.Fatalf("unexpected contact: %#v", parsed.Contact)
 	}
 }
+
+func TestExecute_ContactsGet_Many_JSON(t *testing.T) {
+	origNew := newPeopleContactsService
+	t.Cleanup(func() { newPeopleContactsService = origNew })
+
+	var batchGets, searches int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "people:batchGet"):
+			batchGets++
+			names := r.URL.Query()["resourceNames"]
+			if strings.Join(names, ",") != "people/c1,people/c2,people/missing" {
+				t.Errorf("unexpected resourceNames: %v", names)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"responses": []map[string]any{
+					{"requestedResourceName": "people/c1", "httpStatusCode": 200, "person": map[string]any{"resourceName": "people/c1"}},
+					{"requestedResourceName": "people/c2", "httpStatusCode": 200, "person": map[string]any{"resourceName": "people/c2"}},
+					{"requestedResourceName": "people/missing", "httpStatusCode": 404, "status": map[string]any{"code": 5, "message": "not found"}},
+				},
+			})
+		case strings.Contains(r.URL.Path, "people:searchContacts"):
+			searches++
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"results": []map[string]any{
+					{"person": map[string]any{
+						"resourceName":   "people/c3",
+						"emailAddresses": []map[string]any{{"value": "ada@example.com"}},
+					}},
+				},
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := people.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	newPeopleContactsService = func(context.Context, string) (*people.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "contacts", "get", "people/c1", "ada@example.com", "people/c2", "people/missing"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		Contacts []struct {
+			ResourceName string `json:"resourceName"`
+		} `json:"contacts"`
+		NotFound []string `json:"notFound"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if batchGets != 1 || searches != 1 {
+		t.Fatalf("expected one batchGet and one search, got %d and %d", batchGets, searches)
+	}
+	var got []string
+	for _, c := range parsed.Contacts {
+		got = append(got, c.ResourceName)
+	}
+	if strings.Join(got, ",") != "people/c1,people/c3,people/c2" || strings.Join(parsed.NotFound, ",") != "people/missing" {
+		t.Fatalf("unexpected contacts: %v notFound=%v", got, parsed.NotFound)
+	}
+}
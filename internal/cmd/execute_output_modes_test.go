@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExecute_DriveLs_AllNDJSONStreamsPages(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	pages := 0
+	svc, closeSrv := newDriveTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || (r.URL.Path != "/drive/v3/files" && r.URL.Path != "/files") {
+			http.NotFound(w, r)
+			return
+		}
+		pages++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"files": []map[string]any{
+					{"id": "f1", "name": "One", "mimeType": "text/plain"},
+					{"id": "f2", "name": "Two", "mimeType": "text/plain"},
+				},
+				"nextPageToken": "p2",
+			})
+			return
+		}
+		requireQuery(t, r, "pageToken", "p2")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files": []map[string]any{
+				{"id": "f3", "name": "Three", "mimeType": "text/plain"},
+			},
+		})
+	}))
+	defer closeSrv()
+	newDriveService = stubDriveService(svc)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--ndjson", "--select", "id,name", "--account", "a@b.com", "drive", "ls", "--all"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	if pages != 2 {
+		t.Fatalf("expected 2 page requests, got %d", pages)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	want := []string{
+		`{"id":"f1","name":"One"}`,
+		`{"id":"f2","name":"Two"}`,
+		`{"id":"f3","name":"Three"}`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %q", len(want), out)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("line %d: got %q want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestExecute_DriveLs_AllNDJSONEmpty(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	svc, closeSrv := newDriveTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"files": []map[string]any{}})
+	}))
+	defer closeSrv()
+	newDriveService = stubDriveService(svc)
+
+	var errOut string
+	out := captureStdout(t, func() {
+		errOut = captureStderr(t, func() {
+			if err := Execute([]string{"--ndjson", "--account", "a@b.com", "drive", "ls", "--all"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+	if strings.TrimSpace(out) != "" {
+		t.Fatalf("expected no output, got %q", out)
+	}
+	if !strings.Contains(errOut, "No files") {
+		t.Fatalf("expected empty message on stderr, got %q", errOut)
+	}
+}
+
+func TestExecute_DriveLs_NDJSONSinglePageUnwrapsEnvelope(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	svc, closeSrv := newDriveTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files": []map[string]any{
+				{"id": "f1", "name": "One"},
+				{"id": "f2", "name": "Two"},
+			},
+			"nextPageToken": "next",
+		})
+	}))
+	defer closeSrv()
+	newDriveService = stubDriveService(svc)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--ndjson", "--account", "a@b.com", "drive", "ls"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", out)
+	}
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("json parse: %v (line=%q)", err, lines[0])
+	}
+	if first["id"] != "f1" {
+		t.Fatalf("unexpected first item: %#v", first)
+	}
+}
+
+func TestExecute_NDJSONRejectsPlain(t *testing.T) {
+	err := Execute([]string{"--ndjson", "--plain", "drive", "ls"})
+	if err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func TestExecute_DriveLs_OutputCSV(t *testing.T) {
+	origNew := newDriveService
+	t.Cleanup(func() { newDriveService = origNew })
+
+	svc, closeSrv := newDriveTestService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"files": []map[string]any{
+				{"id": "f1", "name": "One", "mimeType": "text/plain"},
+				{"id": "f2", "name": "Two, Too", "mimeType": "text/plain"},
+			},
+		})
+	}))
+	defer closeSrv()
+	newDriveService = stubDriveService(svc)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--account", "a@b.com", "drive", "ls", "--output", "csv", "--select", "name,id"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	want := "name,id\nOne,f1\n\"Two, Too\",f2\n"
+	if out != want {
+		t.Fatalf("unexpected csv output: %q", out)
+	}
+}
+
+func TestExecute_OutputFormatInvalid(t *testing.T) {
+	err := Execute([]string{"--output-format", "xml", "drive", "ls"})
+	if err == nil || ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecute_GmailLabelsFromReplayCassette(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	cassette := `{
+  "request": {"method": "GET", "url": "https://gmail.googleapis.com/gmail/v1/users/me/labels"},
+  "response": {
+    "status": 200,
+    "header": {"Content-Type": ["application/json"]},
+    "body": "{\"labels\":[{\"id\":\"INBOX\",\"name\":\"INBOX\",\"type\":\"system\"},{\"id\":\"Label_1\",\"name\":\"Receipts\",\"type\":\"user\"}]}"
+  }
+}`
+	if err := os.WriteFile(filepath.Join(dir, "0001-GET-gmail_v1_users_me_labels.json"), []byte(cassette), 0o600); err != nil {
+		t.Fatalf("write cassette: %v", err)
+	}
+	t.Setenv("GOG_REPLAY", dir)
+
+	out := captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "gmail", "labels", "list"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	var parsed struct {
+		Labels []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("json parse: %v\nout=%q", err, out)
+	}
+	if len(parsed.Labels) != 2 || parsed.Labels[1].Name != "Receipts" {
+		t.Fatalf("unexpected labels: %#v", parsed.Labels)
+	}
+}
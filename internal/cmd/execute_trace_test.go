@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeLabelsCassette(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	cassette := `{
+  "request": {"method": "GET", "url": "https://gmail.googleapis.com/gmail/v1/users/me/labels"},
+  "response": {"status": 200, "header": {"Content-Type": ["application/json"]}, "body": "{\"labels\":[]}"}
+}`
+	if err := os.WriteFile(filepath.Join(dir, "0001-GET-gmail_v1_users_me_labels.json"), []byte(cassette), 0o600); err != nil {
+		t.Fatalf("write cassette: %v", err)
+	}
+	return dir
+}
+
+func TestExecute_TraceOTLP(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_REPLAY", writeLabelsCassette(t))
+
+	tracePath := filepath.Join(t.TempDir(), "trace.json")
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "--trace", tracePath, "gmail", "labels", "list"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	b, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("read trace: %v", err)
+	}
+
+	type kv struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+	var doc struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Attributes   []kv   `json:"attributes"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("parse trace: %v\n%s", err, b)
+	}
+
+	spans := doc.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected command + API span, got %d:\n%s", len(spans), b)
+	}
+	root, call := spans[0], spans[1]
+	if root.Name != "gog gmail labels list" || root.ParentSpanID != "" {
+		t.Fatalf("unexpected root span: %#v", root)
+	}
+	if call.ParentSpanID != root.SpanID || call.TraceID != root.TraceID || call.Name != "GET /gmail/v1/users/me/labels" {
+		t.Fatalf("unexpected API span: %#v", call)
+	}
+
+	found := false
+	for _, a := range call.Attributes {
+		if a.Key == "http.status_code" && a.Value["intValue"] == "200" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("missing http.status_code on API span: %#v", call.Attributes)
+	}
+}
+
+func TestExecute_TraceChrome(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GOG_REPLAY", writeLabelsCassette(t))
+
+	tracePath := filepath.Join(t.TempDir(), "trace.json")
+	_ = captureStdout(t, func() {
+		_ = captureStderr(t, func() {
+			if err := Execute([]string{"--json", "--account", "a@b.com", "--trace", tracePath, "--trace-format", "chrome", "gmail", "labels", "list"}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+		})
+	})
+
+	b, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("read trace: %v", err)
+	}
+	var doc struct {
+		TraceEvents []struct {
+			Name  string `json:"name"`
+			Phase string `json:"ph"`
+			Cat   string `json:"cat"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatalf("parse trace: %v\n%s", err, b)
+	}
+	if len(doc.TraceEvents) != 2 || doc.TraceEvents[0].Cat != "command" || doc.TraceEvents[1].Cat != "http" || doc.TraceEvents[1].Phase != "X" {
+		t.Fatalf("unexpected chrome events: %s", b)
+	}
+}
+
+func TestExecute_InvalidTraceFormat(t *testing.T) {
+	_ = captureStderr(t, func() {
+		err := Execute([]string{"--trace", filepath.Join(t.TempDir(), "t.json"), "--trace-format", "xml", "version"})
+		if ExitCode(err) != 2 {
+			t.Fatalf("expected usage exit 2, got %v", err)
+		}
+	})
+}
@@ -27,7 +27,7 @@ type FormsResponsesCmd struct {
 }
 
 type FormsGetCmd struct {
-	FormID string `arg:"" name:"formId" help:"Form ID"`
+	FormID string `arg:"" stdin:"" name:"formId" help:"Form ID"`
 }
 
 func (c *FormsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -115,7 +115,7 @@ func (c *FormsCreateCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type FormsResponsesListCmd struct {
-	FormID string `arg:"" name:"formId" help:"Form ID"`
+	FormID string `arg:"" stdin:"" name:"formId" help:"Form ID"`
 	Max    int    `name:"max" help:"Maximum responses" default:"20"`
 	Page   string `name:"page" help:"Page token"`
 	Filter string `name:"filter" help:"Filter expression"`
@@ -175,8 +175,8 @@ func (c *FormsResponsesListCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type FormsResponseGetCmd struct {
-	FormID     string `arg:"" name:"formId" help:"Form ID"`
-	ResponseID string `arg:"" name:"responseId" help:"Response ID"`
+	FormID     string `arg:"" stdin:"" name:"formId" help:"Form ID"`
+	ResponseID string `arg:"" stdin:"" name:"responseId" help:"Response ID"`
 }
 
 func (c *FormsResponseGetCmd) Run(ctx context.Context, flags *RootFlags) error {
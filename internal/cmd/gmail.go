@@ -26,11 +26,16 @@ type GmailCmd struct {
 	Attachment GmailAttachmentCmd `cmd:"" name:"attachment" group:"Read" help:"Download a single attachment"`
 	URL        GmailURLCmd        `cmd:"" name:"url" group:"Read" help:"Print Gmail web URLs for threads"`
 	History    GmailHistoryCmd    `cmd:"" name:"history" group:"Read" help:"Gmail history"`
+	Sync       GmailSyncCmd       `cmd:"" name:"sync" aliases:"mirror,archive" group:"Read" help:"Mirror the mailbox into a local Maildir (incremental via history)"`
+	Stats      GmailStatsCmd      `cmd:"" name:"stats" aliases:"analytics,report" group:"Read" help:"Mailbox analytics: top senders/domains, volume, largest messages, labels"`
 
-	Labels GmailLabelsCmd `cmd:"" name:"labels" aliases:"label" group:"Organize" help:"Label operations"`
-	Batch  GmailBatchCmd  `cmd:"" name:"batch" group:"Organize" help:"Batch operations"`
+	Labels      GmailLabelsCmd      `cmd:"" name:"labels" aliases:"label" group:"Organize" help:"Label operations"`
+	Batch       GmailBatchCmd       `cmd:"" name:"batch" group:"Organize" help:"Batch operations"`
+	Unsubscribe GmailUnsubscribeCmd `cmd:"" name:"unsubscribe" aliases:"unsub" group:"Organize" help:"Unsubscribe from mailing lists (RFC 8058 one-click or mailto), optionally archiving"`
 
 	Send   GmailSendCmd   `cmd:"" name:"send" group:"Write" help:"Send an email"`
+	Merge  GmailMergeCmd  `cmd:"" name:"merge" aliases:"mailmerge" group:"Write" help:"Mail merge: send one templated message per CSV/JSON row (resumable)"`
+	Queue  GmailQueueCmd  `cmd:"" name:"queue" group:"Write" help:"Scheduled sends (send --at): run, list, cancel"`
 	Track  GmailTrackCmd  `cmd:"" name:"track" group:"Write" help:"Email open tracking"`
 	Drafts GmailDraftsCmd `cmd:"" name:"drafts" aliases:"draft" group:"Write" help:"Draft operations"`
 
@@ -52,6 +57,7 @@ type GmailSettingsCmd struct {
 	Forwarding  GmailForwardingCmd  `cmd:"" name:"forwarding" group:"Admin" help:"Forwarding addresses"`
 	AutoForward GmailAutoForwardCmd `cmd:"" name:"autoforward" group:"Admin" help:"Auto-forwarding settings"`
 	SendAs      GmailSendAsCmd      `cmd:"" name:"sendas" group:"Admin" help:"Send-as settings"`
+	Smime       GmailSmimeCmd       `cmd:"" name:"smime" group:"Admin" help:"S/MIME certificates for send-as aliases"`
 	Vacation    GmailVacationCmd    `cmd:"" name:"vacation" group:"Admin" help:"Vacation responder"`
 	Watch       GmailWatchCmd       `cmd:"" name:"watch" group:"Admin" help:"Manage Gmail watch"`
 }
@@ -65,15 +71,24 @@ type GmailSearchCmd struct {
 	Oldest    bool     `name:"oldest" help:"Show first message date instead of last"`
 	Timezone  string   `name:"timezone" short:"z" help:"Output timezone (IANA name, e.g. America/New_York, UTC). Default: local"`
 	Local     bool     `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
+	Offline   bool     `name:"offline" help:"Search a local gmail sync archive instead of Gmail (regex, field operators, ranked results)"`
+	Archive   string   `name:"archive" help:"Archive directory for --offline (default: $GOG_GMAIL_ARCHIVE); implies --offline"`
 }
 
 func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	u := ui.FromContext(ctx)
+	query := strings.TrimSpace(strings.Join(c.Query, " "))
+	if c.Offline || strings.TrimSpace(c.Archive) != "" {
+		if query == "" {
+			return usage("missing query")
+		}
+		return c.runOffline(ctx, query)
+	}
+
 	account, err := requireAccount(flags)
 	if err != nil {
 		return err
 	}
-	query := strings.TrimSpace(strings.Join(c.Query, " "))
 	if query == "" {
 		return usage("missing query")
 	}
@@ -98,6 +113,10 @@ func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Threads, resp.NextPageToken, nil
 	}
 
+	if c.All && outfmt.IsNDJSON(ctx) {
+		return c.streamNDJSON(ctx, svc, fetch)
+	}
+
 	var threads []*gmail.Thread
 	nextPageToken := ""
 	if c.All {
@@ -145,6 +164,10 @@ func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
+	return c.writeThreads(ctx, u, items, nextPageToken)
+}
+
+func (c *GmailSearchCmd) writeThreads(ctx context.Context, u *ui.UI, items []threadItem, nextPageToken string) error {
 	if outfmt.IsJSON(ctx) {
 		if writeErr := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"threads":       items,
@@ -178,6 +201,25 @@ func (c *GmailSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	return nil
 }
 
+// streamNDJSON emits thread summaries page by page so --all never buffers the whole mailbox.
+func (c *GmailSearchCmd) streamNDJSON(ctx context.Context, svc *gmail.Service, fetch func(string) ([]*gmail.Thread, string, error)) error {
+	idToName, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+	loc, err := resolveOutputLocation(c.Timezone, c.Local)
+	if err != nil {
+		return err
+	}
+	count, err := streamAllPagesNDJSONMapped(ctx, c.Page, fetch, func(threads []*gmail.Thread) ([]threadItem, error) {
+		return fetchThreadDetails(ctx, svc, threads, idToName, c.Oldest, loc)
+	})
+	if err != nil {
+		return err
+	}
+	return streamedResultExit(count, c.FailEmpty)
+}
+
 func firstMessage(t *gmail.Thread) *gmail.Message {
 	if t == nil || len(t.Messages) == 0 {
 		return nil
@@ -382,6 +424,10 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 		return nil, nil
 	}
 
+	if b := batcherFor(svc); b != nil {
+		return fetchThreadDetailsBatch(ctx, b, threads, idToName, oldest, loc)
+	}
+
 	const maxConcurrency = 10 // Limit parallel requests to avoid rate limiting
 	sem := make(chan struct{}, maxConcurrency)
 
@@ -422,32 +468,7 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 				return
 			}
 
-			item := threadItem{ID: threadID, MessageCount: len(thread.Messages)}
-			if first := firstMessage(thread); first != nil {
-				item.From = sanitizeTab(headerValue(first.Payload, "From"))
-				item.Subject = sanitizeTab(headerValue(first.Payload, "Subject"))
-				if len(first.LabelIds) > 0 {
-					names := make([]string, 0, len(first.LabelIds))
-					for _, lid := range first.LabelIds {
-						if n, ok := idToName[lid]; ok {
-							names = append(names, n)
-						} else {
-							names = append(names, lid)
-						}
-					}
-					item.Labels = names
-				}
-			}
-			// Date from newest message by default, oldest if --oldest
-			dateMsg := newestMessageByDate(thread)
-			if oldest {
-				dateMsg = oldestMessageByDate(thread)
-			}
-			if dateMsg != nil {
-				item.Date = formatGmailDateInLocation(headerValue(dateMsg.Payload, "Date"), loc)
-			}
-
-			results <- result{index: idx, item: item}
+			results <- result{index: idx, item: threadItemFromThread(threadID, thread, idToName, oldest, loc)}
 		}(i, t.Id)
 	}
 
@@ -494,3 +515,62 @@ func fetchThreadDetails(ctx context.Context, svc *gmail.Service, threads []*gmai
 	}
 	return items, nil
 }
+
+// fetchThreadDetailsBatch fetches thread metadata through the batch endpoint,
+// 100 threads per HTTP call. The first per-thread error (in list order) is returned.
+func fetchThreadDetailsBatch(ctx context.Context, b *googleapi.Batcher, threads []*gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) ([]threadItem, error) {
+	ids := make([]string, 0, len(threads))
+	for _, t := range threads {
+		if t != nil && t.Id != "" {
+			ids = append(ids, t.Id)
+		}
+	}
+
+	fetched, errs, err := batchGetGmailThreads(ctx, b, ids, gmailMetadataQuery("From", "Subject", "Date"))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]threadItem, 0, len(ids))
+	for i, id := range ids {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		items = append(items, threadItemFromThread(id, fetched[i], idToName, oldest, loc))
+	}
+	return items, nil
+}
+
+func threadItemFromThread(threadID string, thread *gmail.Thread, idToName map[string]string, oldest bool, loc *time.Location) threadItem {
+	item := threadItem{ID: threadID, MessageCount: len(thread.Messages)}
+	if first := firstMessage(thread); first != nil {
+		item.From = sanitizeTab(headerValue(first.Payload, "From"))
+		item.Subject = sanitizeTab(headerValue(first.Payload, "Subject"))
+		item.Labels = labelNamesFor(first.LabelIds, idToName)
+	}
+	// Date from newest message by default, oldest if --oldest
+	dateMsg := newestMessageByDate(thread)
+	if oldest {
+		dateMsg = oldestMessageByDate(thread)
+	}
+	if dateMsg != nil {
+		item.Date = formatGmailDateInLocation(headerValue(dateMsg.Payload, "Date"), loc)
+	}
+	return item
+}
+
+// labelNamesFor maps label IDs to display names, keeping unknown IDs as-is.
+func labelNamesFor(labelIDs []string, idToName map[string]string) []string {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(labelIDs))
+	for _, lid := range labelIDs {
+		if n, ok := idToName[lid]; ok {
+			names = append(names, n)
+		} else {
+			names = append(names, lid)
+		}
+	}
+	return names
+}
@@ -17,8 +17,8 @@ import (
 )
 
 type GmailAttachmentCmd struct {
-	MessageID    string         `arg:"" name:"messageId" help:"Message ID"`
-	AttachmentID string         `arg:"" name:"attachmentId" help:"Attachment ID"`
+	MessageID    string         `arg:"" stdin:"" name:"messageId" help:"Message ID"`
+	AttachmentID string         `arg:"" stdin:"" name:"attachmentId" help:"Attachment ID"`
 	Output       OutputPathFlag `embed:""`
 	Name         string         `name:"name" help:"Filename (used when --out is empty or points to a directory)"`
 }
@@ -252,7 +252,7 @@ func writeFileAtomic(outPath string, data []byte) error {
 		return err
 	}
 
-	f, err := os.CreateTemp(dir, ".gog-attachment-*")
+	f, err := os.CreateTemp(dir, "."+filepath.Base(outPath)+".tmp-*")
 	if err != nil {
 		return err
 	}
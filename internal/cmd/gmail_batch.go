@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strings"
 
 	"google.golang.org/api/gmail/v1"
 
@@ -17,7 +18,7 @@ type GmailBatchCmd struct {
 }
 
 type GmailBatchDeleteCmd struct {
-	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
+	MessageIDs []string `arg:"" stdin:"" name:"messageId" help:"Message IDs"`
 }
 
 func (c *GmailBatchDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -67,9 +68,11 @@ func (c *GmailBatchDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type GmailBatchModifyCmd struct {
-	MessageIDs []string `arg:"" name:"messageId" help:"Message IDs"`
+	MessageIDs []string `arg:"" stdin:"" optional:"" name:"messageId" help:"Message IDs (or use --query)"`
+	Query      string   `name:"query" help:"Modify every message matching this Gmail search query instead of listed IDs"`
 	Add        string   `name:"add" help:"Labels to add (comma-separated, name or ID)"`
 	Remove     string   `name:"remove" help:"Labels to remove (comma-separated, name or ID)"`
+	Restart    bool     `name:"restart" help:"With --query: discard a saved checkpoint and start over"`
 }
 
 func (c *GmailBatchModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -82,14 +85,21 @@ func (c *GmailBatchModifyCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 		ids = append(ids, id)
 	}
-	if len(ids) == 0 {
-		return usage("missing messageId")
+	query := strings.TrimSpace(c.Query)
+	if query != "" && len(ids) > 0 {
+		return usage("use either message IDs or --query, not both")
+	}
+	if query == "" && len(ids) == 0 {
+		return usage("missing messageId (or --query)")
 	}
 	addLabels := splitCSV(c.Add)
 	removeLabels := splitCSV(c.Remove)
 	if len(addLabels) == 0 && len(removeLabels) == 0 {
 		return errors.New("must specify --add and/or --remove")
 	}
+	if query != "" {
+		return c.runQuery(ctx, flags, query, addLabels, removeLabels)
+	}
 
 	if err := dryRunExit(ctx, flags, "gmail.batch.modify", map[string]any{
 		"message_ids": ids,
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+// gmailGetConcurrency bounds the per-message fallback when no batcher is available.
+const gmailGetConcurrency = 10
+
+// batcherFor returns the batch helper for services built by googleapi.New*. Services
+// created elsewhere (e.g. in tests) get nil and keep the per-item request paths.
+var batcherFor = googleapi.BatcherFor
+
+// gmailMetadataQuery builds format=metadata with the given metadataHeaders.
+func gmailMetadataQuery(headers ...string) url.Values {
+	return url.Values{
+		"format":          {"metadata"},
+		"metadataHeaders": headers,
+	}
+}
+
+func batchGetGmailThreads(ctx context.Context, b *googleapi.Batcher, ids []string, query url.Values) ([]*gmail.Thread, []error, error) {
+	return googleapi.BatchGet[gmail.Thread](ctx, b, gmailBatchPaths("users/me/threads/", ids, query))
+}
+
+func batchGetGmailMessages(ctx context.Context, b *googleapi.Batcher, ids []string, query url.Values) ([]*gmail.Message, []error, error) {
+	return googleapi.BatchGet[gmail.Message](ctx, b, gmailBatchPaths("users/me/messages/", ids, query))
+}
+
+func gmailBatchPaths(prefix string, ids []string, query url.Values) []string {
+	suffix := ""
+	if enc := query.Encode(); enc != "" {
+		suffix = "?" + enc
+	}
+
+	paths := make([]string, len(ids))
+	for i, id := range ids {
+		paths[i] = prefix + url.PathEscape(id) + suffix
+	}
+	return paths
+}
+
+// fetchGmailMessages gets messages with the given users.messages.get query
+// parameters and fails on the first message that could not be fetched.
+func fetchGmailMessages(ctx context.Context, svc *gmail.Service, ids []string, query url.Values) ([]*gmail.Message, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	msgs, errs, err := getGmailMessages(ctx, svc, ids, query)
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range ids {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("message %s: %w", id, errs[i])
+		}
+		if msgs[i] == nil {
+			return nil, fmt.Errorf("message %s: empty response", id)
+		}
+	}
+	return msgs, nil
+}
+
+// fetchExistingGmailMessages is fetchGmailMessages for scans over a listing:
+// messages deleted since they were listed (404) are skipped instead of failing
+// the whole call. The result keeps request order minus the skipped messages.
+func fetchExistingGmailMessages(ctx context.Context, svc *gmail.Service, ids []string, query url.Values) ([]*gmail.Message, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	msgs, errs, err := getGmailMessages(ctx, svc, ids, query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*gmail.Message, 0, len(msgs))
+	for i, id := range ids {
+		switch {
+		case errs[i] != nil && isNotFoundAPIError(errs[i]):
+			continue
+		case errs[i] != nil:
+			return nil, fmt.Errorf("message %s: %w", id, errs[i])
+		case msgs[i] == nil:
+			return nil, fmt.Errorf("message %s: empty response", id)
+		}
+		out = append(out, msgs[i])
+	}
+	return out, nil
+}
+
+// getGmailMessages gets messages over the batch endpoint when available and
+// with bounded parallel requests otherwise. errs holds per-message failures
+// (e.g. 404 for deleted messages); err is set only when the whole call failed.
+func getGmailMessages(ctx context.Context, svc *gmail.Service, ids []string, query url.Values) ([]*gmail.Message, []error, error) {
+	if b := batcherFor(svc); b != nil {
+		return batchGetGmailMessages(ctx, b, ids, query)
+	}
+
+	msgs := make([]*gmail.Message, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, gmailGetConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			call := svc.Users.Messages.Get("me", id).Format(query.Get("format"))
+			if headers := query["metadataHeaders"]; len(headers) > 0 {
+				call = call.MetadataHeaders(headers...)
+			}
+			if fields := query.Get("fields"); fields != "" {
+				call = call.Fields(gapi.Field(fields))
+			}
+			msgs[i], errs[i] = call.Context(ctx).Do()
+		}(i, id)
+	}
+	wg.Wait()
+	return msgs, errs, ctx.Err()
+}
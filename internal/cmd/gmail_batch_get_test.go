@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/googleapi"
+)
+
+func TestFetchMessageDetails_UsesBatchEndpoint(t *testing.T) {
+	var batches atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/batch/gmail/v1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		batches.Add(1)
+
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		var ids, paths []string
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Errorf("next part: %v", err)
+				return
+			}
+			inner, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				t.Errorf("read inner: %v", err)
+				return
+			}
+			ids = append(ids, strings.Trim(part.Header.Get("Content-ID"), "<>"))
+			paths = append(paths, inner.URL.RequestURI())
+		}
+
+		mw := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+		for i, p := range paths {
+			if !strings.Contains(p, "format=metadata") || !strings.Contains(p, "metadataHeaders=Subject") {
+				t.Errorf("unexpected sub-request %q", p)
+			}
+			id := strings.TrimPrefix(p[:strings.Index(p, "?")], "/gmail/v1/users/me/messages/")
+			body := fmt.Sprintf(`{"id":%q,"threadId":"t-%s","labelIds":["INBOX"],"payload":{"headers":[{"name":"Subject","value":"hello %s"}]}}`, id, id, id)
+
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", "application/http")
+			header.Set("Content-ID", "<response-"+ids[i]+">")
+			pw, _ := mw.CreatePart(header)
+			fmt.Fprintf(pw, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n%s", body)
+		}
+		_ = mw.Close()
+	}))
+	defer srv.Close()
+
+	b, err := googleapi.NewBatcher(srv.Client(), srv.URL+"/", "gmail/v1")
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+	origBatcher := batcherFor
+	t.Cleanup(func() { batcherFor = origBatcher })
+	batcherFor = func(any) *googleapi.Batcher { return b }
+
+	messages := []*gmail.Message{{Id: "m1"}, {Id: "m2"}, {Id: "m3"}}
+	items, err := fetchMessageDetails(context.Background(), &gmail.Service{}, messages, map[string]string{"INBOX": "Inbox"}, time.UTC, false)
+	if err != nil {
+		t.Fatalf("fetchMessageDetails: %v", err)
+	}
+
+	if batches.Load() != 1 {
+		t.Fatalf("expected a single batch call, got %d", batches.Load())
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	for i, item := range items {
+		want := fmt.Sprintf("m%d", i+1)
+		if item.ID != want || item.Subject != "hello "+want || item.ThreadID != "t-"+want {
+			t.Fatalf("unexpected item %d: %#v", i, item)
+		}
+		if len(item.Labels) != 1 || item.Labels[0] != "Inbox" {
+			t.Fatalf("expected label names, got %#v", item.Labels)
+		}
+	}
+}
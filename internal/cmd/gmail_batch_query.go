@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// gmailBatchModifyMaxIDs is the per-request limit of users.messages.batchModify.
+const gmailBatchModifyMaxIDs = 1000
+
+// gmailBatchCheckpoint records the matched IDs of a --query run and how many
+// of them are already modified, so an interrupted run resumes where it
+// stopped instead of re-listing a query whose matches the run itself changes.
+type gmailBatchCheckpoint struct {
+	Account   string   `json:"account"`
+	Query     string   `json:"query"`
+	Add       []string `json:"add,omitempty"`
+	Remove    []string `json:"remove,omitempty"`
+	IDs       []string `json:"ids"`
+	Done      int      `json:"done"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt,omitempty"`
+}
+
+func gmailBatchCheckpointPath(account, query string, addIDs, removeIDs []string) (string, error) {
+	dir, err := config.EnsureGmailBatchDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		strings.ToLower(account),
+		query,
+		strings.Join(addIDs, ","),
+		strings.Join(removeIDs, ","),
+	}, "\x00")))
+	name := fmt.Sprintf("%s-%s.json", sanitizeAccountForPath(account), hex.EncodeToString(sum[:8]))
+	return filepath.Join(dir, name), nil
+}
+
+func loadGmailBatchCheckpoint(path string) (*gmailBatchCheckpoint, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from the config dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read batch checkpoint: %w", err)
+	}
+	var cp gmailBatchCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("decode batch checkpoint %s: %w", path, err)
+	}
+	if cp.Done < 0 || cp.Done > len(cp.IDs) {
+		return nil, fmt.Errorf("batch checkpoint %s is corrupt; rerun with --restart", path)
+	}
+	return &cp, nil
+}
+
+func saveGmailBatchCheckpoint(path string, cp *gmailBatchCheckpoint) error {
+	cp.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	payload, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", payload, 0o600); err != nil {
+		return fmt.Errorf("write batch checkpoint: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("write batch checkpoint: %w", err)
+	}
+	return nil
+}
+
+// listGmailQueryMessageIDs lists the IDs matching query, newest first. With
+// limit > 0 it stops paging once limit IDs are collected and reports whether
+// more messages matched.
+func listGmailQueryMessageIDs(ctx context.Context, svc *gmail.Service, u *ui.UI, query string, limit int) ([]string, bool, error) {
+	found := 0
+	truncated := false
+	fetch := func(pageToken string) ([]string, string, error) {
+		pageSize := int64(500)
+		if limit > 0 {
+			pageSize = min(pageSize, int64(limit-found))
+		}
+		call := svc.Users.Messages.List("me").Q(query).MaxResults(pageSize).Fields("messages(id),nextPageToken").Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, "", err
+		}
+		ids := make([]string, 0, len(resp.Messages))
+		for _, m := range resp.Messages {
+			if m != nil && m.Id != "" {
+				ids = append(ids, m.Id)
+			}
+		}
+		found += len(ids)
+		if limit > 0 && found >= limit {
+			// Stop paging; the list is newest first, so the rest is older mail.
+			truncated = found > limit || resp.NextPageToken != ""
+			return ids, "", nil
+		}
+		if u != nil && resp.NextPageToken != "" {
+			u.Err().Printf("Found %d messages so far...", found)
+		}
+		return ids, resp.NextPageToken, nil
+	}
+	ids, err := collectAllPages("", fetch)
+	if err != nil {
+		return nil, false, err
+	}
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, truncated, nil
+}
+
+func (c *GmailBatchModifyCmd) runQuery(ctx context.Context, flags *RootFlags, query string, addLabels, removeLabels []string) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	idMap, err := fetchLabelNameToID(svc)
+	if err != nil {
+		return err
+	}
+	addIDs := resolveLabelIDs(addLabels, idMap)
+	removeIDs := resolveLabelIDs(removeLabels, idMap)
+
+	path, err := gmailBatchCheckpointPath(account, query, addIDs, removeIDs)
+	if err != nil {
+		return err
+	}
+	var cp *gmailBatchCheckpoint
+	if !c.Restart {
+		if cp, err = loadGmailBatchCheckpoint(path); err != nil {
+			return err
+		}
+	}
+
+	if flags != nil && flags.DryRun {
+		payload := map[string]any{
+			"query":  query,
+			"add":    addLabels,
+			"remove": removeLabels,
+		}
+		if cp != nil {
+			payload["count"] = len(cp.IDs)
+			payload["remaining"] = len(cp.IDs) - cp.Done
+			payload["checkpoint"] = path
+		} else {
+			ids, _, listErr := listGmailQueryMessageIDs(ctx, svc, u, query, 0)
+			if listErr != nil {
+				return listErr
+			}
+			payload["count"] = len(ids)
+		}
+		return dryRunExit(ctx, flags, "gmail.batch.modify", payload)
+	}
+
+	resumed := cp != nil
+	if cp == nil {
+		ids, _, listErr := listGmailQueryMessageIDs(ctx, svc, u, query, 0)
+		if listErr != nil {
+			return listErr
+		}
+		cp = &gmailBatchCheckpoint{
+			Account:   account,
+			Query:     query,
+			Add:       addIDs,
+			Remove:    removeIDs,
+			IDs:       ids,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+		if len(ids) > gmailBatchModifyMaxIDs {
+			if err := saveGmailBatchCheckpoint(path, cp); err != nil {
+				return err
+			}
+		}
+	} else {
+		u.Err().Printf("Resuming: %d of %d messages already modified", cp.Done, len(cp.IDs))
+	}
+
+	startedAt := cp.Done
+	for cp.Done < len(cp.IDs) {
+		end := min(cp.Done+gmailBatchModifyMaxIDs, len(cp.IDs))
+		err := svc.Users.Messages.BatchModify("me", &gmail.BatchModifyMessagesRequest{
+			Ids:            cp.IDs[cp.Done:end],
+			AddLabelIds:    addIDs,
+			RemoveLabelIds: removeIDs,
+		}).Context(ctx).Do()
+		if err != nil {
+			if saveErr := saveGmailBatchCheckpoint(path, cp); saveErr != nil {
+				return errors.Join(err, saveErr)
+			}
+			u.Err().Printf("Stopped after %d of %d messages; rerun the same command to resume", cp.Done, len(cp.IDs))
+			return err
+		}
+		cp.Done = end
+		if end < len(cp.IDs) {
+			if err := saveGmailBatchCheckpoint(path, cp); err != nil {
+				return err
+			}
+		}
+		u.Err().Printf("Modified %d/%d messages", cp.Done, len(cp.IDs))
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove batch checkpoint: %w", err)
+	}
+
+	return writeResult(ctx, u,
+		kv("query", query),
+		kv("count", len(cp.IDs)),
+		kv("modified", len(cp.IDs)-startedAt),
+		kv("resumed", resumed),
+		kv("addedLabels", addIDs),
+		kv("removedLabels", removeIDs),
+	)
+}
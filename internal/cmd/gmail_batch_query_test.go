@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestGmailBatchModifyCmd_QueryResume(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	const total = 2500
+	var (
+		mu        sync.Mutex
+		lists     int
+		batches   []int
+		modified  = map[string]bool{}
+		failBatch = 2
+	)
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/")
+		switch {
+		case path == "labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{
+				{"id": "INBOX", "name": "INBOX"},
+				{"id": "Label_9", "name": "Archive"},
+			}})
+		case path == "messages" && r.Method == http.MethodGet:
+			if r.URL.Query().Get("q") != "older_than:1y label:newsletters" {
+				t.Errorf("unexpected query %q", r.URL.Query().Get("q"))
+			}
+			lists++
+			start := 0
+			if tok := r.URL.Query().Get("pageToken"); tok != "" {
+				_, _ = fmt.Sscanf(tok, "p%d", &start)
+			}
+			end := min(start+1200, total)
+			msgs := make([]map[string]any, 0, end-start)
+			for i := start; i < end; i++ {
+				msgs = append(msgs, map[string]any{"id": fmt.Sprintf("m%04d", i)})
+			}
+			resp := map[string]any{"messages": msgs}
+			if end < total {
+				resp["nextPageToken"] = fmt.Sprintf("p%d", end)
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case path == "messages/batchModify" && r.Method == http.MethodPost:
+			var req gmail.BatchModifyMessagesRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			if strings.Join(req.AddLabelIds, ",") != "Label_9" || strings.Join(req.RemoveLabelIds, ",") != "INBOX" {
+				t.Errorf("unexpected labels: %+v", req)
+			}
+			if len(batches)+1 == failBatch {
+				failBatch = 0
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 400, "message": "boom"}})
+				return
+			}
+			batches = append(batches, len(req.Ids))
+			for _, id := range req.Ids {
+				if modified[id] {
+					t.Errorf("message %s modified twice", id)
+				}
+				modified[id] = true
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := outfmt.WithMode(ui.WithUI(context.Background(), u), outfmt.Mode{JSON: true})
+	args := []string{"--query", "older_than:1y label:newsletters", "--add", "Archive", "--remove", "INBOX"}
+
+	out := captureStdout(t, func() {
+		if err := runKong(t, &GmailBatchModifyCmd{}, args, ctx, &RootFlags{Account: "a@b.com", DryRun: true}); ExitCode(err) != 0 {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"count": 2500`) || len(batches) != 0 {
+		t.Fatalf("unexpected dry run: %s (batches %v)", out, batches)
+	}
+
+	flags := &RootFlags{Account: "a@b.com"}
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &GmailBatchModifyCmd{}, args, ctx, flags); err == nil {
+			t.Fatalf("expected the second batch to fail")
+		}
+	})
+	if len(batches) != 1 || len(modified) != 1000 {
+		t.Fatalf("unexpected first run: batches %v", batches)
+	}
+	dir, err := config.GmailBatchDir()
+	if err != nil {
+		t.Fatalf("GmailBatchDir: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected a checkpoint, got %v", entries)
+	}
+
+	listsBefore := lists
+	out = captureStdout(t, func() {
+		if err := runKong(t, &GmailBatchModifyCmd{}, args, ctx, flags); err != nil {
+			t.Fatalf("resume: %v", err)
+		}
+	})
+	if lists != listsBefore {
+		t.Fatalf("resume must not list the query again")
+	}
+	if fmt.Sprint(batches) != "[1000 1000 500]" || len(modified) != total {
+		t.Fatalf("unexpected batches %v (%d modified)", batches, len(modified))
+	}
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if parsed["count"] != float64(total) || parsed["modified"] != float64(1500) || parsed["resumed"] != true {
+		t.Fatalf("unexpected result: %v", parsed)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected the checkpoint to be removed, got %v", entries)
+	}
+
+	if err := runKong(t, &GmailBatchModifyCmd{}, append([]string{"m1"}, args...), ctx, flags); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for IDs plus --query, got %v", err)
+	}
+}
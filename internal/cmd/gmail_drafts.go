@@ -54,6 +54,12 @@ func (c *GmailDraftsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Drafts, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*gmail.Draft) ([]gmailDraftItem, error) {
+		return gmailDraftItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var drafts []*gmail.Draft
 	nextPageToken := ""
 	if c.All {
@@ -70,23 +76,7 @@ func (c *GmailDraftsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		}
 	}
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			ID        string `json:"id"`
-			MessageID string `json:"messageId,omitempty"`
-			ThreadID  string `json:"threadId,omitempty"`
-		}
-		items := make([]item, 0, len(drafts))
-		for _, d := range drafts {
-			if d == nil {
-				continue
-			}
-			var msgID, threadID string
-			if d.Message != nil {
-				msgID = d.Message.Id
-				threadID = d.Message.ThreadId
-			}
-			items = append(items, item{ID: d.Id, MessageID: msgID, ThreadID: threadID})
-		}
+		items := gmailDraftItems(drafts)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"drafts":        items,
 			"nextPageToken": nextPageToken,
@@ -118,7 +108,7 @@ func (c *GmailDraftsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type GmailDraftsGetCmd struct {
-	DraftID  string `arg:"" name:"draftId" help:"Draft ID"`
+	DraftID  string `arg:"" stdin:"" name:"draftId" help:"Draft ID"`
 	Download bool   `name:"download" help:"Download draft attachments"`
 }
 
@@ -206,7 +196,7 @@ func (c *GmailDraftsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type GmailDraftsDeleteCmd struct {
-	DraftID string `arg:"" name:"draftId" help:"Draft ID"`
+	DraftID string `arg:"" stdin:"" name:"draftId" help:"Draft ID"`
 }
 
 func (c *GmailDraftsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -240,7 +230,7 @@ func (c *GmailDraftsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type GmailDraftsSendCmd struct {
-	DraftID string `arg:"" name:"draftId" help:"Draft ID"`
+	DraftID string `arg:"" stdin:"" name:"draftId" help:"Draft ID"`
 }
 
 func (c *GmailDraftsSendCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -266,7 +256,7 @@ func (c *GmailDraftsSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	msg, err := svc.Users.Drafts.Send("me", &gmail.Draft{Id: draftID}).Do()
+	msg, err := sendGmailDraft(ctx, svc, draftID)
 	if err != nil {
 		return err
 	}
@@ -475,7 +465,7 @@ func (c *GmailDraftsCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type GmailDraftsUpdateCmd struct {
-	DraftID          string   `arg:"" name:"draftId" help:"Draft ID"`
+	DraftID          string   `arg:"" stdin:"" name:"draftId" help:"Draft ID"`
 	To               *string  `name:"to" help:"Recipients (comma-separated; omit to keep existing)"`
 	Cc               string   `name:"cc" help:"CC recipients (comma-separated)"`
 	Bcc              string   `name:"bcc" help:"BCC recipients (comma-separated)"`
@@ -599,3 +589,25 @@ func (c *GmailDraftsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error
 	}
 	return writeDraftResult(ctx, u, draft, threadID)
 }
+
+type gmailDraftItem struct {
+	ID        string `json:"id"`
+	MessageID string `json:"messageId,omitempty"`
+	ThreadID  string `json:"threadId,omitempty"`
+}
+
+func gmailDraftItems(drafts []*gmail.Draft) []gmailDraftItem {
+	items := make([]gmailDraftItem, 0, len(drafts))
+	for _, d := range drafts {
+		if d == nil {
+			continue
+		}
+		var msgID, threadID string
+		if d.Message != nil {
+			msgID = d.Message.Id
+			threadID = d.Message.ThreadId
+		}
+		items = append(items, gmailDraftItem{ID: d.Id, MessageID: msgID, ThreadID: threadID})
+	}
+	return items
+}
@@ -19,6 +19,8 @@ type GmailFiltersCmd struct {
 	Get    GmailFiltersGetCmd    `cmd:"" name:"get" aliases:"info,show" help:"Get a specific filter"`
 	Create GmailFiltersCreateCmd `cmd:"" name:"create" aliases:"add,new" help:"Create a new email filter"`
 	Delete GmailFiltersDeleteCmd `cmd:"" name:"delete" aliases:"rm,del,remove" help:"Delete a filter"`
+	Export GmailFiltersExportCmd `cmd:"" name:"export" help:"Export all filters as YAML (label names, not IDs)"`
+	Apply  GmailFiltersApplyCmd  `cmd:"" name:"apply" aliases:"import,sync" help:"Converge filters to a YAML or mailFilters.xml file (creates/deletes; --dry-run shows the diff)"`
 }
 
 type GmailFiltersListCmd struct{}
@@ -75,7 +77,7 @@ func (c *GmailFiltersListCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type GmailFiltersGetCmd struct {
-	FilterID string `arg:"" name:"filterId" help:"Filter ID"`
+	FilterID string `arg:"" stdin:"" name:"filterId" help:"Filter ID"`
 }
 
 func (c *GmailFiltersGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -341,7 +343,7 @@ func (c *GmailFiltersCreateCmd) Run(ctx context.Context, flags *RootFlags) error
 }
 
 type GmailFiltersDeleteCmd struct {
-	FilterID string `arg:"" name:"filterId" help:"Filter ID"`
+	FilterID string `arg:"" stdin:"" name:"filterId" help:"Filter ID"`
 }
 
 func (c *GmailFiltersDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -0,0 +1,572 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+	"gopkg.in/yaml.v3"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Filters as declarative config: `export` writes the account's filters as YAML with
+// label names, `apply` converges the account to a file. Gmail filters cannot be
+// edited, so a changed filter is a delete plus a create.
+
+type gmailFiltersFile struct {
+	Filters []gmailFilterSpec `yaml:"filters" json:"filters"`
+}
+
+type gmailFilterSpec struct {
+	Criteria gmailFilterCriteriaSpec `yaml:"criteria" json:"criteria"`
+	Action   gmailFilterActionSpec   `yaml:"action" json:"action"`
+}
+
+type gmailFilterCriteriaSpec struct {
+	From           string `yaml:"from,omitempty" json:"from,omitempty"`
+	To             string `yaml:"to,omitempty" json:"to,omitempty"`
+	Subject        string `yaml:"subject,omitempty" json:"subject,omitempty"`
+	Query          string `yaml:"query,omitempty" json:"query,omitempty"`
+	NegatedQuery   string `yaml:"negatedQuery,omitempty" json:"negatedQuery,omitempty"`
+	HasAttachment  bool   `yaml:"hasAttachment,omitempty" json:"hasAttachment,omitempty"`
+	ExcludeChats   bool   `yaml:"excludeChats,omitempty" json:"excludeChats,omitempty"`
+	Size           int64  `yaml:"size,omitempty" json:"size,omitempty"`
+	SizeComparison string `yaml:"sizeComparison,omitempty" json:"sizeComparison,omitempty"`
+}
+
+type gmailFilterActionSpec struct {
+	AddLabels    []string `yaml:"addLabels,omitempty" json:"addLabels,omitempty"`
+	RemoveLabels []string `yaml:"removeLabels,omitempty" json:"removeLabels,omitempty"`
+	Forward      string   `yaml:"forward,omitempty" json:"forward,omitempty"`
+}
+
+type GmailFiltersExportCmd struct{}
+
+func (c *GmailFiltersExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	resp, err := svc.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	idToName, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+
+	file := gmailFiltersFile{Filters: make([]gmailFilterSpec, 0, len(resp.Filter))}
+	for _, f := range resp.Filter {
+		file.Filters = append(file.Filters, filterSpecFromAPI(f, idToName))
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, file)
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+	return enc.Close()
+}
+
+type GmailFiltersApplyCmd struct {
+	File      string `arg:"" name:"file" help:"Filters file: YAML from 'filters export', or Gmail's mailFilters.xml ('-' for stdin)"`
+	KeepExtra bool   `name:"keep-extra" help:"Do not delete filters that are missing from the file"`
+}
+
+type gmailFilterPlan struct {
+	Create    []gmailFilterSpec
+	Delete    []*gmail.Filter
+	Unchanged int
+	NewLabels []string
+}
+
+func (c *GmailFiltersApplyCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	desired, err := readGmailFiltersFile(c.File)
+	if err != nil {
+		return err
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	resp, err := svc.Users.Settings.Filters.List("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	nameToID, err := fetchLabelNameToID(svc)
+	if err != nil {
+		return err
+	}
+	idToName, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+
+	plan := planGmailFilters(desired, resp.Filter, nameToID)
+	if c.KeepExtra {
+		plan.Delete = nil
+	}
+
+	// Dry runs print the plan as a diff; JSON mode gets it as the request payload.
+	var planPayload any
+	if outfmt.IsJSON(ctx) {
+		planPayload = map[string]any{
+			"create":    plan.Create,
+			"delete":    plan.Delete,
+			"unchanged": plan.Unchanged,
+			"newLabels": plan.NewLabels,
+		}
+	} else if flags != nil && flags.DryRun {
+		printGmailFilterPlan(u, plan, idToName)
+	}
+	if dryRunErr := dryRunExit(ctx, flags, "gmail.filters.apply", planPayload); dryRunErr != nil {
+		return dryRunErr
+	}
+	if len(plan.Delete) > 0 {
+		if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("delete %d gmail filter(s) not in %s", len(plan.Delete), c.File)); confirmErr != nil {
+			return confirmErr
+		}
+	}
+
+	for _, name := range plan.NewLabels {
+		label, createErr := svc.Users.Labels.Create("me", &gmail.Label{
+			Name:                  name,
+			LabelListVisibility:   "labelShow",
+			MessageListVisibility: "show",
+		}).Context(ctx).Do()
+		if createErr != nil {
+			return fmt.Errorf("create label %q: %w", name, createErr)
+		}
+		nameToID[strings.ToLower(name)] = label.Id
+		idToName[label.Id] = name
+	}
+
+	created := make([]*gmail.Filter, 0, len(plan.Create))
+	for _, spec := range plan.Create {
+		filter, createErr := svc.Users.Settings.Filters.Create("me", spec.toAPI(nameToID)).Context(ctx).Do()
+		if createErr != nil {
+			return fmt.Errorf("create filter (%s): %w", describeFilterSpec(spec), createErr)
+		}
+		created = append(created, filter)
+		if !outfmt.IsJSON(ctx) {
+			u.Out().Printf("+ %s\t%s", filter.Id, describeFilterSpec(spec))
+		}
+	}
+
+	deleted := make([]string, 0, len(plan.Delete))
+	for _, f := range plan.Delete {
+		if delErr := svc.Users.Settings.Filters.Delete("me", f.Id).Context(ctx).Do(); delErr != nil && !isNotFoundAPIError(delErr) {
+			return fmt.Errorf("delete filter %s: %w", f.Id, delErr)
+		}
+		deleted = append(deleted, f.Id)
+		if !outfmt.IsJSON(ctx) {
+			u.Out().Printf("- %s\t%s", f.Id, describeFilterSpec(filterSpecFromAPI(f, idToName)))
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"created":       created,
+			"deleted":       deleted,
+			"unchanged":     plan.Unchanged,
+			"labelsCreated": plan.NewLabels,
+		})
+	}
+	u.Err().Printf("%d created, %d deleted, %d unchanged", len(created), len(deleted), plan.Unchanged)
+	return nil
+}
+
+// planGmailFilters matches desired filters against existing ones by their criteria
+// and actions (label order ignored). Labels the account does not have yet are
+// collected in NewLabels.
+func planGmailFilters(desired []gmailFilterSpec, existing []*gmail.Filter, nameToID map[string]string) gmailFilterPlan {
+	var plan gmailFilterPlan
+
+	newLabels := map[string]bool{}
+	for _, spec := range desired {
+		for _, name := range append(append([]string{}, spec.Action.AddLabels...), spec.Action.RemoveLabels...) {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if _, ok := nameToID[key]; !ok && !newLabels[key] {
+				newLabels[key] = true
+				plan.NewLabels = append(plan.NewLabels, strings.TrimSpace(name))
+			}
+		}
+	}
+
+	current := make(map[string][]*gmail.Filter, len(existing))
+	for _, f := range existing {
+		key := gmailFilterKey(f)
+		current[key] = append(current[key], f)
+	}
+
+	seen := map[string]bool{}
+	for _, spec := range desired {
+		key := gmailFilterKey(spec.toAPI(nameToID))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if matches := current[key]; len(matches) > 0 {
+			plan.Unchanged++
+			// Exact duplicates already on the account collapse to one.
+			plan.Delete = append(plan.Delete, matches[1:]...)
+			delete(current, key)
+			continue
+		}
+		plan.Create = append(plan.Create, spec)
+	}
+
+	for _, f := range existing {
+		if _, ok := current[gmailFilterKey(f)]; ok {
+			plan.Delete = append(plan.Delete, f)
+		}
+	}
+	return plan
+}
+
+func gmailFilterKey(f *gmail.Filter) string {
+	type key struct {
+		Criteria gmailFilterCriteriaSpec
+		Add      []string
+		Remove   []string
+		Forward  string
+	}
+	k := key{}
+	if f.Criteria != nil {
+		k.Criteria = gmailFilterCriteriaSpec{
+			From:           strings.TrimSpace(f.Criteria.From),
+			To:             strings.TrimSpace(f.Criteria.To),
+			Subject:        strings.TrimSpace(f.Criteria.Subject),
+			Query:          strings.TrimSpace(f.Criteria.Query),
+			NegatedQuery:   strings.TrimSpace(f.Criteria.NegatedQuery),
+			HasAttachment:  f.Criteria.HasAttachment,
+			ExcludeChats:   f.Criteria.ExcludeChats,
+			Size:           f.Criteria.Size,
+			SizeComparison: strings.ToLower(strings.TrimSpace(f.Criteria.SizeComparison)),
+		}
+		if k.Criteria.Size == 0 {
+			k.Criteria.SizeComparison = ""
+		}
+	}
+	if f.Action != nil {
+		k.Add = sortedLower(f.Action.AddLabelIds)
+		k.Remove = sortedLower(f.Action.RemoveLabelIds)
+		k.Forward = strings.ToLower(strings.TrimSpace(f.Action.Forward))
+	}
+	data, _ := json.Marshal(k)
+	return string(data)
+}
+
+func sortedLower(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (s gmailFilterSpec) toAPI(nameToID map[string]string) *gmail.Filter {
+	c := s.Criteria
+	return &gmail.Filter{
+		Criteria: &gmail.FilterCriteria{
+			From:           strings.TrimSpace(c.From),
+			To:             strings.TrimSpace(c.To),
+			Subject:        strings.TrimSpace(c.Subject),
+			Query:          strings.TrimSpace(c.Query),
+			NegatedQuery:   strings.TrimSpace(c.NegatedQuery),
+			HasAttachment:  c.HasAttachment,
+			ExcludeChats:   c.ExcludeChats,
+			Size:           c.Size,
+			SizeComparison: strings.TrimSpace(c.SizeComparison),
+		},
+		Action: &gmail.FilterAction{
+			AddLabelIds:    resolveLabelIDs(s.Action.AddLabels, nameToID),
+			RemoveLabelIds: resolveLabelIDs(s.Action.RemoveLabels, nameToID),
+			Forward:        strings.TrimSpace(s.Action.Forward),
+		},
+	}
+}
+
+func filterSpecFromAPI(f *gmail.Filter, idToName map[string]string) gmailFilterSpec {
+	var spec gmailFilterSpec
+	if c := f.Criteria; c != nil {
+		spec.Criteria = gmailFilterCriteriaSpec{
+			From:           c.From,
+			To:             c.To,
+			Subject:        c.Subject,
+			Query:          c.Query,
+			NegatedQuery:   c.NegatedQuery,
+			HasAttachment:  c.HasAttachment,
+			ExcludeChats:   c.ExcludeChats,
+			Size:           c.Size,
+			SizeComparison: c.SizeComparison,
+		}
+	}
+	if a := f.Action; a != nil {
+		spec.Action.AddLabels = labelNamesFor(a.AddLabelIds, idToName)
+		spec.Action.RemoveLabels = labelNamesFor(a.RemoveLabelIds, idToName)
+		spec.Action.Forward = a.Forward
+	}
+	return spec
+}
+
+func (s gmailFilterSpec) validate() error {
+	c := s.Criteria
+	if c.From == "" && c.To == "" && c.Subject == "" && c.Query == "" && c.NegatedQuery == "" && !c.HasAttachment && c.Size == 0 {
+		return errors.New("no criteria")
+	}
+	if len(s.Action.AddLabels) == 0 && len(s.Action.RemoveLabels) == 0 && s.Action.Forward == "" {
+		return errors.New("no action")
+	}
+	switch strings.ToLower(c.SizeComparison) {
+	case "", "larger", "smaller":
+	default:
+		return fmt.Errorf("sizeComparison must be larger or smaller, got %q", c.SizeComparison)
+	}
+	return nil
+}
+
+// describeFilterSpec renders a filter on one line for plans and progress output.
+func describeFilterSpec(s gmailFilterSpec) string {
+	var parts []string
+	add := func(k, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			if strings.ContainsAny(v, " \t") {
+				v = strconv.Quote(v)
+			}
+			parts = append(parts, k+":"+v)
+		}
+	}
+	c := s.Criteria
+	add("from", c.From)
+	add("to", c.To)
+	add("subject", c.Subject)
+	add("query", c.Query)
+	add("-query", c.NegatedQuery)
+	if c.HasAttachment {
+		parts = append(parts, "has:attachment")
+	}
+	if c.ExcludeChats {
+		parts = append(parts, "-chats")
+	}
+	if c.Size > 0 {
+		parts = append(parts, fmt.Sprintf("size:%s:%d", c.SizeComparison, c.Size))
+	}
+	parts = append(parts, "=>")
+	for _, l := range s.Action.AddLabels {
+		parts = append(parts, "+"+sanitizeTab(l))
+	}
+	for _, l := range s.Action.RemoveLabels {
+		parts = append(parts, "-"+sanitizeTab(l))
+	}
+	add("forward", s.Action.Forward)
+	return sanitizeTab(strings.Join(parts, " "))
+}
+
+func printGmailFilterPlan(u *ui.UI, plan gmailFilterPlan, idToName map[string]string) {
+	if u == nil {
+		return
+	}
+	for _, name := range plan.NewLabels {
+		u.Out().Printf("+ label\t%s", name)
+	}
+	for _, spec := range plan.Create {
+		u.Out().Printf("+ filter\t%s", describeFilterSpec(spec))
+	}
+	for _, f := range plan.Delete {
+		u.Out().Printf("- filter\t%s\t%s", describeFilterSpec(filterSpecFromAPI(f, idToName)), f.Id)
+	}
+	u.Err().Printf("%d to create, %d to delete, %d unchanged", len(plan.Create), len(plan.Delete), plan.Unchanged)
+}
+
+// readGmailFiltersFile loads filters from our YAML (or JSON) format or from Gmail's
+// own mailFilters.xml export (Settings → Filters → Export).
+func readGmailFiltersFile(path string) ([]gmailFilterSpec, error) {
+	path = strings.TrimSpace(path)
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = config.ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err = os.ReadFile(path) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []gmailFilterSpec
+	if strings.EqualFold(filepath.Ext(path), ".xml") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		specs, err = parseMailFiltersXML(data)
+		if err != nil {
+			return nil, usagef("parse %s as mailFilters.xml: %v", path, err)
+		}
+	} else {
+		var file gmailFiltersFile
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if decErr := dec.Decode(&file); decErr != nil && !errors.Is(decErr, io.EOF) {
+			return nil, usagef("parse %s: %v", path, decErr)
+		}
+		specs = file.Filters
+	}
+
+	for i, spec := range specs {
+		if err := spec.validate(); err != nil {
+			return nil, usagef("filter %d (%s): %v", i+1, describeFilterSpec(spec), err)
+		}
+	}
+	return specs, nil
+}
+
+type mailFiltersFeed struct {
+	Entries []struct {
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"property"`
+	} `xml:"entry"`
+}
+
+var smartLabelCategories = map[string]string{
+	"^smartlabel_personal":     "CATEGORY_PERSONAL",
+	"^smartlabel_social":       "CATEGORY_SOCIAL",
+	"^smartlabel_promo":        "CATEGORY_PROMOTIONS",
+	"^smartlabel_notification": "CATEGORY_UPDATES",
+	"^smartlabel_group":        "CATEGORY_FORUMS",
+}
+
+func parseMailFiltersXML(data []byte) ([]gmailFilterSpec, error) {
+	var feed mailFiltersFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+
+	specs := make([]gmailFilterSpec, 0, len(feed.Entries))
+	for _, entry := range feed.Entries {
+		var (
+			spec gmailFilterSpec
+			size int64
+			unit = int64(1)
+		)
+		for _, p := range entry.Properties {
+			v := p.Value
+			yes := strings.EqualFold(v, "true")
+			switch p.Name {
+			case "from":
+				spec.Criteria.From = v
+			case "to":
+				spec.Criteria.To = v
+			case "subject":
+				spec.Criteria.Subject = v
+			case "hasTheWord":
+				spec.Criteria.Query = v
+			case "doesNotHaveTheWord":
+				spec.Criteria.NegatedQuery = v
+			case "hasAttachment":
+				spec.Criteria.HasAttachment = yes
+			case "excludeChats":
+				spec.Criteria.ExcludeChats = yes
+			case "size":
+				n, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid size %q", v)
+				}
+				size = n
+			case "sizeOperator":
+				switch v {
+				case "s_sl":
+					spec.Criteria.SizeComparison = "larger"
+				case "s_ss":
+					spec.Criteria.SizeComparison = "smaller"
+				}
+			case "sizeUnit":
+				switch v {
+				case "s_skb":
+					unit = 1 << 10
+				case "s_smb":
+					unit = 1 << 20
+				}
+			case "label":
+				spec.Action.AddLabels = append(spec.Action.AddLabels, v)
+			case "smartLabelToApply":
+				if id, ok := smartLabelCategories[v]; ok {
+					spec.Action.AddLabels = append(spec.Action.AddLabels, id)
+				}
+			case "shouldArchive":
+				if yes {
+					spec.Action.RemoveLabels = append(spec.Action.RemoveLabels, "INBOX")
+				}
+			case "shouldMarkAsRead":
+				if yes {
+					spec.Action.RemoveLabels = append(spec.Action.RemoveLabels, "UNREAD")
+				}
+			case "shouldStar":
+				if yes {
+					spec.Action.AddLabels = append(spec.Action.AddLabels, "STARRED")
+				}
+			case "shouldTrash":
+				if yes {
+					spec.Action.AddLabels = append(spec.Action.AddLabels, "TRASH")
+				}
+			case "shouldNeverSpam":
+				if yes {
+					spec.Action.RemoveLabels = append(spec.Action.RemoveLabels, "SPAM")
+				}
+			case "shouldAlwaysMarkAsImportant":
+				if yes {
+					spec.Action.AddLabels = append(spec.Action.AddLabels, "IMPORTANT")
+				}
+			case "shouldNeverMarkAsImportant":
+				if yes {
+					spec.Action.RemoveLabels = append(spec.Action.RemoveLabels, "IMPORTANT")
+				}
+			case "forwardTo":
+				spec.Action.Forward = v
+			}
+		}
+		if size > 0 {
+			spec.Criteria.Size = size * unit
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
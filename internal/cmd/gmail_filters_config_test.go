@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestParseMailFiltersXML(t *testing.T) {
+	data := `<?xml version='1.0' encoding='UTF-8'?><feed xmlns='http://www.w3.org/2005/Atom' xmlns:apps='http://schemas.google.com/apps/2006'>
+<title>Mail Filters</title>
+<entry>
+	<category term='filter'></category>
+	<title>Mail Filter</title>
+	<apps:property name='from' value='noreply@example.com'/>
+	<apps:property name='label' value='Notifications'/>
+	<apps:property name='shouldArchive' value='true'/>
+	<apps:property name='shouldMarkAsRead' value='true'/>
+	<apps:property name='sizeOperator' value='s_sl'/>
+	<apps:property name='sizeUnit' value='s_smb'/>
+</entry>
+<entry>
+	<apps:property name='hasTheWord' value='list:dev.example.com'/>
+	<apps:property name='size' value='2'/>
+	<apps:property name='sizeOperator' value='s_sl'/>
+	<apps:property name='sizeUnit' value='s_smb'/>
+	<apps:property name='smartLabelToApply' value='^smartlabel_group'/>
+	<apps:property name='shouldNeverSpam' value='true'/>
+</entry>
+</feed>`
+
+	specs, err := parseMailFiltersXML([]byte(data))
+	if err != nil {
+		t.Fatalf("parseMailFiltersXML: %v", err)
+	}
+	if len(specs) != 2 {
+		t.Fatalf("expected 2 filters, got %d", len(specs))
+	}
+	first := specs[0]
+	if first.Criteria.From != "noreply@example.com" || first.Criteria.Size != 0 || strings.Join(first.Action.AddLabels, ",") != "Notifications" || strings.Join(first.Action.RemoveLabels, ",") != "INBOX,UNREAD" {
+		t.Fatalf("unexpected first filter: %+v", first)
+	}
+	second := specs[1]
+	if second.Criteria.Query != "list:dev.example.com" || second.Criteria.Size != 2<<20 || second.Criteria.SizeComparison != "larger" {
+		t.Fatalf("unexpected criteria: %+v", second.Criteria)
+	}
+	if strings.Join(second.Action.AddLabels, ",") != "CATEGORY_FORUMS" || strings.Join(second.Action.RemoveLabels, ",") != "SPAM" {
+		t.Fatalf("unexpected actions: %+v", second.Action)
+	}
+}
+
+func TestPlanGmailFilters(t *testing.T) {
+	nameToID := map[string]string{"inbox": "INBOX", "work": "Label_1", "label_1": "Label_1"}
+	existing := []*gmail.Filter{
+		{Id: "keep", Criteria: &gmail.FilterCriteria{From: "boss@example.com"}, Action: &gmail.FilterAction{AddLabelIds: []string{"Label_1"}, RemoveLabelIds: []string{"INBOX"}}},
+		{Id: "dup", Criteria: &gmail.FilterCriteria{From: "boss@example.com"}, Action: &gmail.FilterAction{RemoveLabelIds: []string{"INBOX"}, AddLabelIds: []string{"Label_1"}}},
+		{Id: "stale", Criteria: &gmail.FilterCriteria{Subject: "old"}, Action: &gmail.FilterAction{AddLabelIds: []string{"TRASH"}}},
+	}
+	desired := []gmailFilterSpec{
+		{Criteria: gmailFilterCriteriaSpec{From: "boss@example.com"}, Action: gmailFilterActionSpec{AddLabels: []string{"work"}, RemoveLabels: []string{"INBOX"}}},
+		{Criteria: gmailFilterCriteriaSpec{From: "news@example.com"}, Action: gmailFilterActionSpec{AddLabels: []string{"Newsletters"}}},
+	}
+
+	plan := planGmailFilters(desired, existing, nameToID)
+	if plan.Unchanged != 1 || len(plan.Create) != 1 || plan.Create[0].Criteria.From != "news@example.com" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	ids := make([]string, 0, len(plan.Delete))
+	for _, f := range plan.Delete {
+		ids = append(ids, f.Id)
+	}
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "dup,stale" || strings.Join(plan.NewLabels, ",") != "Newsletters" {
+		t.Fatalf("unexpected deletes %v / new labels %v", ids, plan.NewLabels)
+	}
+}
+
+func TestGmailFiltersExportApply(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		filters = []*gmail.Filter{
+			{Id: "f1", Criteria: &gmail.FilterCriteria{From: "boss@example.com"}, Action: &gmail.FilterAction{AddLabelIds: []string{"Label_1", "STARRED"}}},
+			{Id: "f2", Criteria: &gmail.FilterCriteria{Subject: "old"}, Action: &gmail.FilterAction{RemoveLabelIds: []string{"INBOX"}}},
+		}
+		labels  = []map[string]any{{"id": "INBOX", "name": "INBOX"}, {"id": "STARRED", "name": "STARRED"}, {"id": "Label_1", "name": "Work"}}
+		created []string
+		deleted []string
+	)
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/")
+		switch {
+		case path == "labels" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": labels})
+		case path == "labels" && r.Method == http.MethodPost:
+			var l gmail.Label
+			_ = json.NewDecoder(r.Body).Decode(&l)
+			labels = append(labels, map[string]any{"id": "Label_new", "name": l.Name})
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "Label_new", "name": l.Name})
+		case path == "settings/filters" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"filter": filters})
+		case path == "settings/filters" && r.Method == http.MethodPost:
+			var f gmail.Filter
+			_ = json.NewDecoder(r.Body).Decode(&f)
+			f.Id = "new" + string(rune('0'+len(created)))
+			created = append(created, f.Criteria.From+"->"+strings.Join(f.Action.AddLabelIds, ","))
+			_ = json.NewEncoder(w).Encode(f)
+		case strings.HasPrefix(path, "settings/filters/") && r.Method == http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(path, "settings/filters/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := ui.WithUI(context.Background(), u)
+	flags := &RootFlags{Account: "a@b.com", Force: true}
+
+	exported := captureStdout(t, func() {
+		if err := runKong(t, &GmailFiltersExportCmd{}, nil, ctx, flags); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+	})
+	if !strings.Contains(exported, "from: boss@example.com") || !strings.Contains(exported, "- Work") || strings.Contains(exported, "Label_1") {
+		t.Fatalf("unexpected export:\n%s", exported)
+	}
+
+	// Keep f1 as exported, drop f2, add a filter with a new label.
+	path := filepath.Join(t.TempDir(), "filters.yaml")
+	keep := exported[:strings.Index(exported, "  - criteria:\n      subject: old")]
+	content := keep + "  - criteria:\n      from: news@example.com\n    action:\n      addLabels: [Newsletters]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		jsonCtx := outfmt.WithMode(ctx, outfmt.Mode{JSON: true})
+		if err := runKong(t, &GmailFiltersApplyCmd{}, []string{path}, jsonCtx, &RootFlags{Account: "a@b.com", DryRun: true}); ExitCode(err) != 0 {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"unchanged": 1`) || len(created) != 0 || len(deleted) != 0 {
+		t.Fatalf("dry run must not change anything: %s (created %v, deleted %v)", out, created, deleted)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &GmailFiltersApplyCmd{}, []string{path}, ctx, flags); err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+	})
+	if strings.Join(created, "|") != "news@example.com->Label_new" || strings.Join(deleted, ",") != "f2" {
+		t.Fatalf("unexpected changes: created %v deleted %v", created, deleted)
+	}
+
+	if err := os.WriteFile(path, []byte("filters:\n  - criteria:\n      form: typo@example.com\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := runKong(t, &GmailFiltersApplyCmd{}, []string{path}, ctx, flags); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for an unknown field, got %v", err)
+	}
+}
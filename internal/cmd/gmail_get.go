@@ -12,7 +12,7 @@ import (
 )
 
 type GmailGetCmd struct {
-	MessageID string `arg:"" name:"messageId" help:"Message ID"`
+	MessageID string `arg:"" stdin:"" name:"messageId" help:"Message ID"`
 	Format    string `name:"format" help:"Message format: full|metadata|raw" default:"full"`
 	Headers   string `name:"headers" help:"Metadata headers (comma-separated; only for --format=metadata)"`
 }
@@ -51,6 +51,10 @@ func (c *GmailHistoryCmd) Run(ctx context.Context, flags *RootFlags) error {
 		historyIDs := collectHistoryMessageIDs(resp)
 		return historyIDs.FetchIDs, resp.NextPageToken, nil
 	}
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var ids []string
 	nextPageToken := ""
 	if c.All {
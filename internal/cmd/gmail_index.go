@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The search index sits next to the sync state in a `gmail sync` archive. It stores
+// the parsed headers, a snippet and the distinct body words of every archived
+// message, not the bodies, so it stays small enough to load on every search; only
+// body regexes re-read the Maildir.
+const (
+	gmailIndexFile      = ".gog-index.json"
+	gmailIndexVersion   = 2
+	maxIndexedBodyBytes = 256 << 10
+	maxIndexedMIMEDepth = 10
+	maxIndexedTerms     = 2000
+	maxIndexedTermLen   = 40
+	indexSnippetRunes   = 200
+)
+
+type gmailIndex struct {
+	Version int                       `json:"version"`
+	Docs    map[string]*gmailIndexDoc `json:"docs"`
+}
+
+type gmailIndexDoc struct {
+	ID           string   `json:"id"`
+	ThreadID     string   `json:"threadId,omitempty"`
+	File         string   `json:"file"`
+	InternalDate int64    `json:"internalDate,omitempty"`
+	Date         string   `json:"date,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           string   `json:"to,omitempty"`
+	Cc           string   `json:"cc,omitempty"`
+	Subject      string   `json:"subject,omitempty"`
+	LabelIDs     []string `json:"labelIds,omitempty"`
+	Attachments  []string `json:"attachments,omitempty"`
+	Snippet      string   `json:"snippet,omitempty"`
+	// Terms holds the distinct lowercased body words, space-separated.
+	Terms string `json:"terms,omitempty"`
+}
+
+// loadGmailIndex reads the index of a sync archive and brings it up to date with the
+// archive's sync state. It returns the state alongside for label names.
+func loadGmailIndex(dir string) (*gmailIndex, *gmailSyncState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, gmailSyncStateFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, usagef("%s is not a gmail sync archive; run: gog gmail sync %s", dir, dir)
+		}
+		return nil, nil, fmt.Errorf("read sync state: %w", err)
+	}
+	var state gmailSyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil, fmt.Errorf("parse sync state: %w", err)
+	}
+
+	idx := &gmailIndex{}
+	if data, err := os.ReadFile(filepath.Join(dir, gmailIndexFile)); err == nil {
+		// A corrupt or outdated index is rebuilt.
+		if json.Unmarshal(data, idx) != nil || idx.Version != gmailIndexVersion {
+			idx = &gmailIndex{}
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("read search index: %w", err)
+	}
+
+	changed, err := idx.refresh(dir, &state)
+	if err != nil {
+		return nil, nil, err
+	}
+	if changed {
+		if err := idx.save(dir); err != nil {
+			return nil, nil, err
+		}
+	}
+	return idx, &state, nil
+}
+
+// refresh parses messages new to the archive, updates labels of renamed ones and drops
+// messages that left it.
+func (idx *gmailIndex) refresh(dir string, state *gmailSyncState) (bool, error) {
+	if idx.Docs == nil {
+		idx.Docs = map[string]*gmailIndexDoc{}
+	}
+	idx.Version = gmailIndexVersion
+	changed := false
+
+	for id := range idx.Docs {
+		if e, ok := state.Messages[id]; !ok || e.Deleted {
+			delete(idx.Docs, id)
+			changed = true
+		}
+	}
+
+	for id, e := range state.Messages {
+		if e.Deleted {
+			continue
+		}
+		if doc, ok := idx.Docs[id]; ok {
+			if doc.File != e.File || !equalStringSets(doc.LabelIDs, e.LabelIDs) {
+				doc.File = e.File
+				doc.LabelIDs = e.LabelIDs
+				changed = true
+			}
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(e.File)))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return changed, fmt.Errorf("read message %s: %w", id, err)
+		}
+		doc := parseIndexedMessage(raw)
+		doc.ID = id
+		doc.ThreadID = e.ThreadID
+		doc.File = e.File
+		doc.InternalDate = e.InternalDate
+		doc.LabelIDs = e.LabelIDs
+		idx.Docs[id] = doc
+		changed = true
+	}
+	return changed, nil
+}
+
+func (idx *gmailIndex) save(dir string) error {
+	payload, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, gmailIndexFile), payload); err != nil {
+		return fmt.Errorf("write search index: %w", err)
+	}
+	return nil
+}
+
+// parseIndexedMessage extracts headers, attachment names, a snippet and the body
+// words. Unparseable messages are indexed by whatever headers could be read.
+func parseIndexedMessage(raw []byte) *gmailIndexDoc {
+	doc, body := parseIndexedMessageText(raw)
+	doc.Snippet = indexSnippet(body)
+	doc.Terms = strings.Join(indexTerms(body), " ")
+	return doc
+}
+
+// readIndexedBody re-reads the body text of doc from the Maildir.
+func readIndexedBody(dir string, doc *gmailIndexDoc) string {
+	raw, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(doc.File)))
+	if err != nil {
+		return doc.Snippet
+	}
+	_, body := parseIndexedMessageText(raw)
+	return body
+}
+
+// parseIndexedMessageText returns the headers and attachment names, plus the body
+// text (text/plain, or tag-stripped text/html when there is no plain part).
+func parseIndexedMessageText(raw []byte) (*gmailIndexDoc, string) {
+	doc := &gmailIndexDoc{}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return doc, ""
+	}
+
+	doc.Date = msg.Header.Get("Date")
+	doc.From = decodeMIMEHeader(msg.Header.Get("From"))
+	doc.To = decodeMIMEHeader(msg.Header.Get("To"))
+	doc.Cc = decodeMIMEHeader(msg.Header.Get("Cc"))
+	doc.Subject = decodeMIMEHeader(msg.Header.Get("Subject"))
+
+	var plain, html strings.Builder
+	walkIndexedPart(textproto.MIMEHeader(msg.Header), msg.Body, 0, doc, &plain, &html)
+	body := plain.String()
+	if strings.TrimSpace(body) == "" {
+		body = stripHTMLTags(html.String())
+	}
+	if len(body) > maxIndexedBodyBytes {
+		body = body[:maxIndexedBodyBytes]
+	}
+	return doc, strings.TrimSpace(body)
+}
+
+func indexSnippet(body string) string {
+	snippet := strings.Join(strings.Fields(body), " ")
+	if runes := []rune(snippet); len(runes) > indexSnippetRunes {
+		snippet = string(runes[:indexSnippetRunes])
+	}
+	return snippet
+}
+
+// indexTerms splits s into distinct lowercased words in order of appearance.
+// Overlong tokens (URLs, encoded blobs) are skipped.
+func indexTerms(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	seen := make(map[string]struct{}, len(words))
+	terms := make([]string, 0, min(len(words), maxIndexedTerms))
+	for _, w := range words {
+		if len(w) > maxIndexedTermLen {
+			continue
+		}
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		terms = append(terms, w)
+		if len(terms) == maxIndexedTerms {
+			break
+		}
+	}
+	return terms
+}
+
+func walkIndexedPart(header textproto.MIMEHeader, body io.Reader, depth int, doc *gmailIndexDoc, plain *strings.Builder, html *strings.Builder) {
+	if depth > maxIndexedMIMEDepth {
+		return
+	}
+
+	contentType := header.Get("Content-Type")
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err != nil {
+				return
+			}
+			walkIndexedPart(part.Header, part, depth+1, doc, plain, html)
+		}
+	}
+
+	if name := attachmentName(header, params); name != "" {
+		doc.Attachments = append(doc.Attachments, name)
+		return
+	}
+
+	switch mediaType {
+	case "text/plain", "text/html":
+	default:
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxIndexedBodyBytes*4))
+	if err != nil {
+		return
+	}
+	data = decodeBodyCharset(decodeTransferEncoding(data, header.Get("Content-Transfer-Encoding")), contentType)
+	if mediaType == "text/html" {
+		html.Write(data)
+		html.WriteString("\n")
+		return
+	}
+	plain.Write(data)
+	plain.WriteString("\n")
+}
+
+func attachmentName(header textproto.MIMEHeader, ctParams map[string]string) string {
+	disposition, params, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := params["filename"]
+	if name == "" {
+		name = ctParams["name"]
+	}
+	if name == "" && disposition != "attachment" {
+		return ""
+	}
+	if name == "" {
+		name = "attachment"
+	}
+	return decodeMIMEHeader(name)
+}
+
+var indexHeaderDecoder = &mime.WordDecoder{
+	CharsetReader: func(label string, input io.Reader) (io.Reader, error) {
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		if decoded, ok := decodeWithCharsetLabel(data, label); ok {
+			return bytes.NewReader(decoded), nil
+		}
+		return bytes.NewReader(data), nil
+	},
+}
+
+func decodeMIMEHeader(v string) string {
+	if decoded, err := indexHeaderDecoder.DecodeHeader(v); err == nil {
+		return strings.TrimSpace(decoded)
+	}
+	return strings.TrimSpace(v)
+}
+
+// messageTime is Gmail's internal (received) date, falling back to the Date header.
+func (d *gmailIndexDoc) messageTime() time.Time {
+	if d.InternalDate > 0 {
+		return time.UnixMilli(d.InternalDate)
+	}
+	if t, err := mailParseDate(d.Date); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -21,7 +21,7 @@ type GmailLabelsCmd struct {
 }
 
 type GmailLabelsGetCmd struct {
-	Label string `arg:"" name:"labelIdOrName" help:"Label ID or name"`
+	Label string `arg:"" stdin:"" name:"labelIdOrName" help:"Label ID or name"`
 }
 
 func (c *GmailLabelsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -148,7 +148,7 @@ func (c *GmailLabelsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type GmailLabelsModifyCmd struct {
-	ThreadIDs []string `arg:"" name:"threadId" help:"Thread IDs"`
+	ThreadIDs []string `arg:"" stdin:"" name:"threadId" help:"Thread IDs"`
 	Add       string   `name:"add" help:"Labels to add (comma-separated, name or ID)"`
 	Remove    string   `name:"remove" help:"Labels to remove (comma-separated, name or ID)"`
 }
@@ -243,7 +243,7 @@ func fetchLabelNameOnlyToID(svc *gmail.Service) (map[string]string, error) {
 }
 
 type GmailLabelsDeleteCmd struct {
-	Label string `arg:"" name:"labelIdOrName" help:"Label ID or name"`
+	Label string `arg:"" stdin:"" name:"labelIdOrName" help:"Label ID or name"`
 }
 
 func (c *GmailLabelsDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
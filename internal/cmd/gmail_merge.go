@@ -0,0 +1,535 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// GmailMergeCmd sends one templated message per data row. Every attempt is appended
+// to a JSON-lines results log keyed by the rendered recipients and subject; messages
+// already logged as sent are skipped on the next run, so an interrupted merge is
+// resumed by running the same command again, even after rows were reordered.
+type GmailMergeCmd struct {
+	Template     string        `name:"template" help:"Plain-text body template file (Go text/template; '-' for stdin)"`
+	HTMLTemplate string        `name:"html-template" help:"HTML body template file (Go html/template)"`
+	Subject      string        `name:"subject" help:"Subject template (required)"`
+	Data         string        `name:"data" help:"Rows: CSV with a header row, or a JSON array of objects (required)"`
+	Cc           string        `name:"cc" help:"CC recipients for every message (comma-separated; templated)"`
+	Bcc          string        `name:"bcc" help:"BCC recipients for every message (comma-separated; templated)"`
+	Attach       []string      `name:"attach" help:"Attachment for every message (repeatable)"`
+	From         string        `name:"from" help:"Send from this email address (must be a verified send-as alias)"`
+	ReplyTo      string        `name:"reply-to" help:"Reply-To header address"`
+	Delay        time.Duration `name:"delay" help:"Pause between messages" default:"1s"`
+	Limit        int           `name:"limit" help:"Send at most N pending rows this run (0 = all)" default:"0"`
+	Log          string        `name:"log" help:"Results log (JSON lines; default: <data>.merge.jsonl). Sent messages are skipped by recipients + subject"`
+}
+
+// Columns with a meaning beyond template data. Lookups are case-insensitive.
+var (
+	mergeToColumns     = []string{"to", "email"}
+	mergeCcColumns     = []string{"cc"}
+	mergeBccColumns    = []string{"bcc"}
+	mergeAttachColumns = []string{"attachments", "attachment", "attach"}
+)
+
+const (
+	mergeStatusSent   = "sent"
+	mergeStatusFailed = "failed"
+)
+
+// mergeTemplate is satisfied by both text/template and html/template.
+type mergeTemplate interface {
+	Execute(w io.Writer, data any) error
+}
+
+type gmailMergeMessage struct {
+	Row         int
+	To          []string
+	Cc          []string
+	Bcc         []string
+	Subject     string
+	Body        string
+	BodyHTML    string
+	Attachments []string
+}
+
+type gmailMergeLogEntry struct {
+	Row       int    `json:"row"`
+	To        string `json:"to"`
+	Key       string `json:"key"`
+	Status    string `json:"status"`
+	MessageID string `json:"messageId,omitempty"`
+	ThreadID  string `json:"threadId,omitempty"`
+	Error     string `json:"error,omitempty"`
+	At        string `json:"at"`
+}
+
+func (c *GmailMergeCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	dataPath := strings.TrimSpace(c.Data)
+	if dataPath == "" {
+		return usage("required: --data")
+	}
+	if strings.TrimSpace(c.Subject) == "" {
+		return usage("required: --subject")
+	}
+	if strings.TrimSpace(c.Template) == "" && strings.TrimSpace(c.HTMLTemplate) == "" {
+		return usage("required: --template or --html-template")
+	}
+	if c.Delay < 0 {
+		return usage("--delay must be >= 0")
+	}
+	if c.Limit < 0 {
+		return usage("--limit must be >= 0")
+	}
+
+	logPath := strings.TrimSpace(c.Log)
+	if logPath == "" {
+		if dataPath == "-" {
+			return usage("--log is required when --data is read from stdin")
+		}
+		logPath = dataPath + ".merge.jsonl"
+	}
+	logPath, err := config.ExpandPath(logPath)
+	if err != nil {
+		return err
+	}
+
+	rows, baseDir, err := readMergeData(dataPath)
+	if err != nil {
+		return err
+	}
+	messages, err := c.render(rows, baseDir)
+	if err != nil {
+		return err
+	}
+
+	sent, err := readMergeLog(logPath)
+	if err != nil {
+		return err
+	}
+	pending := make([]gmailMergeMessage, 0, len(messages))
+	for _, m := range messages {
+		if _, ok := sent[m.logKey()]; ok {
+			continue
+		}
+		pending = append(pending, m)
+	}
+	skipped := len(messages) - len(pending)
+	if c.Limit > 0 && len(pending) > c.Limit {
+		pending = pending[:c.Limit]
+	}
+
+	preview := make([]map[string]any, 0, len(pending))
+	for _, m := range pending {
+		preview = append(preview, map[string]any{
+			"row":           m.Row,
+			"to":            m.To,
+			"cc":            m.Cc,
+			"bcc":           m.Bcc,
+			"subject":       m.Subject,
+			"attachments":   m.Attachments,
+			"body_len":      len(m.Body),
+			"body_html_len": len(m.BodyHTML),
+		})
+	}
+	if dryRunErr := dryRunExit(ctx, flags, "gmail.merge", map[string]any{
+		"rows":     len(messages),
+		"skipped":  skipped,
+		"log":      logPath,
+		"from":     strings.TrimSpace(c.From),
+		"delay":    c.Delay.String(),
+		"messages": preview,
+	}); dryRunErr != nil {
+		return dryRunErr
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	fromAddr, _, err := resolveSendFrom(ctx, svc, account, c.From)
+	if err != nil {
+		return err
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gosec // user-provided path
+	if err != nil {
+		return fmt.Errorf("open merge log: %w", err)
+	}
+	defer logFile.Close()
+
+	results := make([]gmailMergeLogEntry, 0, len(pending))
+	failed := 0
+	for i, m := range pending {
+		if i > 0 && c.Delay > 0 {
+			if err := googleapi.SleepContext(ctx, c.Delay); err != nil {
+				return err
+			}
+		}
+
+		atts := make([]mailAttachment, 0, len(m.Attachments))
+		for _, p := range m.Attachments {
+			atts = append(atts, mailAttachment{Path: p})
+		}
+		entry := gmailMergeLogEntry{Row: m.Row, To: m.To[0], Key: m.logKey()}
+		sendResults, sendErr := sendGmailBatches(ctx, svc, sendMessageOptions{
+			FromAddr:    fromAddr,
+			ReplyTo:     c.ReplyTo,
+			Subject:     m.Subject,
+			Body:        m.Body,
+			BodyHTML:    m.BodyHTML,
+			Attachments: atts,
+		}, buildSendBatches(m.To, m.Cc, m.Bcc, false, false))
+		if sendErr != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed++
+			entry.Status = mergeStatusFailed
+			entry.Error = sendErr.Error()
+			u.Err().Printf("row %d (%s): %v", m.Row, entry.To, sendErr)
+		} else {
+			entry.Status = mergeStatusSent
+			entry.MessageID = sendResults[0].MessageID
+			entry.ThreadID = sendResults[0].ThreadID
+		}
+		entry.At = time.Now().UTC().Format(time.RFC3339)
+		if err := appendMergeLog(logFile, entry); err != nil {
+			return err
+		}
+		results = append(results, entry)
+	}
+
+	if err := writeMergeResults(ctx, logPath, skipped, failed, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d messages failed; run again to retry them (log: %s)", failed, len(results), logPath)
+	}
+	return nil
+}
+
+// render executes the templates for every row up front so template and data mistakes
+// surface before anything is sent.
+func (c *GmailMergeCmd) render(rows []map[string]any, baseDir string) ([]gmailMergeMessage, error) {
+	subjectTmpl, err := texttemplate.New("subject").Option("missingkey=error").Parse(c.Subject)
+	if err != nil {
+		return nil, usagef("parse --subject: %v", err)
+	}
+	ccTmpl, err := texttemplate.New("cc").Option("missingkey=error").Parse(c.Cc)
+	if err != nil {
+		return nil, usagef("parse --cc: %v", err)
+	}
+	bccTmpl, err := texttemplate.New("bcc").Option("missingkey=error").Parse(c.Bcc)
+	if err != nil {
+		return nil, usagef("parse --bcc: %v", err)
+	}
+
+	var textTmpl *texttemplate.Template
+	if path := strings.TrimSpace(c.Template); path != "" {
+		src, readErr := resolveBodyInput("", path)
+		if readErr != nil {
+			return nil, readErr
+		}
+		textTmpl, err = texttemplate.New(filepath.Base(path)).Option("missingkey=error").Parse(src)
+		if err != nil {
+			return nil, usagef("parse --template: %v", err)
+		}
+	}
+	var htmlTmpl *htmltemplate.Template
+	if path := strings.TrimSpace(c.HTMLTemplate); path != "" {
+		src, readErr := resolveBodyInput("", path)
+		if readErr != nil {
+			return nil, readErr
+		}
+		htmlTmpl, err = htmltemplate.New(filepath.Base(path)).Option("missingkey=error").Parse(src)
+		if err != nil {
+			return nil, usagef("parse --html-template: %v", err)
+		}
+	}
+
+	commonAttach := make([]string, 0, len(c.Attach))
+	for _, p := range c.Attach {
+		expanded, expandErr := config.ExpandPath(p)
+		if expandErr != nil {
+			return nil, expandErr
+		}
+		commonAttach = append(commonAttach, expanded)
+	}
+
+	messages := make([]gmailMergeMessage, 0, len(rows))
+	for i, row := range rows {
+		m := gmailMergeMessage{Row: i + 1}
+		execText := func(t mergeTemplate) (string, error) {
+			var buf bytes.Buffer
+			if err := t.Execute(&buf, row); err != nil {
+				return "", usagef("row %d: %v", m.Row, err)
+			}
+			return buf.String(), nil
+		}
+
+		m.To = splitCSV(mergeField(row, mergeToColumns))
+		if len(m.To) == 0 {
+			return nil, usagef("row %d: missing recipient (column %q or %q)", m.Row, mergeToColumns[0], mergeToColumns[1])
+		}
+
+		subject, err := execText(subjectTmpl)
+		if err != nil {
+			return nil, err
+		}
+		m.Subject = strings.TrimSpace(strings.ReplaceAll(subject, "\n", " "))
+		if m.Subject == "" {
+			return nil, usagef("row %d: subject renders empty", m.Row)
+		}
+
+		cc, err := execText(ccTmpl)
+		if err != nil {
+			return nil, err
+		}
+		bcc, err := execText(bccTmpl)
+		if err != nil {
+			return nil, err
+		}
+		m.Cc = append(splitCSV(cc), splitCSV(mergeField(row, mergeCcColumns))...)
+		m.Bcc = append(splitCSV(bcc), splitCSV(mergeField(row, mergeBccColumns))...)
+
+		if textTmpl != nil {
+			if m.Body, err = execText(textTmpl); err != nil {
+				return nil, err
+			}
+		}
+		if htmlTmpl != nil {
+			if m.BodyHTML, err = execText(htmlTmpl); err != nil {
+				return nil, err
+			}
+		}
+
+		m.Attachments = append([]string{}, commonAttach...)
+		for _, p := range strings.FieldsFunc(mergeField(row, mergeAttachColumns), func(r rune) bool { return r == ';' || r == ',' }) {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			expanded, expandErr := config.ExpandPath(p)
+			if expandErr != nil {
+				return nil, expandErr
+			}
+			if !filepath.IsAbs(expanded) {
+				expanded = filepath.Join(baseDir, expanded)
+			}
+			m.Attachments = append(m.Attachments, expanded)
+		}
+		for _, p := range m.Attachments {
+			if _, statErr := os.Stat(p); statErr != nil {
+				return nil, usagef("row %d: attachment %s: %v", m.Row, p, statErr)
+			}
+		}
+
+		messages = append(messages, m)
+	}
+	return messages, nil
+}
+
+// readMergeData loads the rows of a CSV or JSON data file. Relative attachment paths
+// in the rows are resolved against the returned directory.
+func readMergeData(path string) ([]map[string]any, string, error) {
+	var (
+		data    []byte
+		err     error
+		baseDir = "."
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		path, err = config.ExpandPath(path)
+		if err != nil {
+			return nil, "", err
+		}
+		baseDir = filepath.Dir(path)
+		data, err = os.ReadFile(path) //nolint:gosec // user-provided path
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var rows []map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") || bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, "", usagef("parse --data as a JSON array of objects: %v", err)
+		}
+	} else {
+		rows, err = parseMergeCSV(data)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if len(rows) == 0 {
+		return nil, "", usage("--data has no rows")
+	}
+	return rows, baseDir, nil
+}
+
+func parseMergeCSV(data []byte) ([]map[string]any, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, usagef("parse --data as CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, usagef("CSV header column %d is empty", i+1)
+		}
+		if seen[name] {
+			return nil, usagef("CSV header has duplicate column %q", name)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+
+	rows := make([]map[string]any, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, name := range header {
+			row[name] = rec[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func mergeField(row map[string]any, names []string) string {
+	for _, name := range names {
+		for k, v := range row {
+			if !strings.EqualFold(k, name) || v == nil {
+				continue
+			}
+			switch val := v.(type) {
+			case string:
+				return val
+			case []any:
+				parts := make([]string, 0, len(val))
+				for _, item := range val {
+					parts = append(parts, fmt.Sprint(item))
+				}
+				return strings.Join(parts, ",")
+			default:
+				return fmt.Sprint(val)
+			}
+		}
+	}
+	return ""
+}
+
+// logKey identifies a message by what it is sent to and its subject, not by its
+// row, so reordering or inserting rows in the data does not re-send or skip
+// anyone. Editing a row's recipients or subject makes it a new message.
+func (m gmailMergeMessage) logKey() string {
+	h := sha256.New()
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		addrs := make([]string, 0, len(list))
+		for _, a := range list {
+			addrs = append(addrs, strings.ToLower(strings.TrimSpace(a)))
+		}
+		sort.Strings(addrs)
+		fmt.Fprintf(h, "%s\n", strings.Join(addrs, ","))
+	}
+	h.Write([]byte(m.Subject))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// readMergeLog returns the messages a previous run already sent, by logKey. A truncated last line
+// (the process died mid-write) is ignored.
+func readMergeLog(path string) (map[string]gmailMergeLogEntry, error) {
+	sent := map[string]gmailMergeLogEntry{}
+	f, err := os.Open(path) //nolint:gosec // user-provided path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return sent, nil
+		}
+		return nil, fmt.Errorf("read merge log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry gmailMergeLogEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if entry.Status == mergeStatusSent && entry.Key != "" {
+			sent[entry.Key] = entry
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read merge log: %w", err)
+	}
+	return sent, nil
+}
+
+func appendMergeLog(f *os.File, entry gmailMergeLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write merge log: %w", err)
+	}
+	return f.Sync()
+}
+
+func writeMergeResults(ctx context.Context, logPath string, skipped, failed int, results []gmailMergeLogEntry) error {
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"sent":     len(results) - failed,
+			"failed":   failed,
+			"skipped":  skipped,
+			"log":      logPath,
+			"messages": results,
+		})
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "ROW\tTO\tSTATUS\tMESSAGE_ID\tTHREAD_ID")
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", r.Row, sanitizeTab(r.To), r.Status, r.MessageID, r.ThreadID)
+	}
+	if u := ui.FromContext(ctx); u != nil {
+		u.Err().Printf("sent %d, failed %d, skipped %d (already sent); log: %s", len(results)-failed, failed, skipped, logPath)
+	}
+	return nil
+}
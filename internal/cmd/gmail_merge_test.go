@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func writeMergeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestGmailMergeCmd_Render(t *testing.T) {
+	dir := t.TempDir()
+	writeMergeFile(t, dir, "ada.pdf", "%PDF")
+	data := writeMergeFile(t, dir, "people.csv", "\xef\xbb\xbfname,Email,cc,attachments\nAda,ada@example.com,boss@example.com,ada.pdf\n<Bob>,bob@example.com,,\n")
+	tmpl := writeMergeFile(t, dir, "body.tmpl", "Hi {{.name}},\nsee you.\n")
+	htmlTmpl := writeMergeFile(t, dir, "body.html", "<p>Hi {{.name}}</p>")
+
+	rows, baseDir, err := readMergeData(data)
+	if err != nil {
+		t.Fatalf("readMergeData: %v", err)
+	}
+	cmd := &GmailMergeCmd{Subject: "Hello {{.name}}", Template: tmpl, HTMLTemplate: htmlTmpl, Cc: "team@example.com"}
+	messages, err := cmd.render(rows, baseDir)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(messages))
+	}
+	ada, bob := messages[0], messages[1]
+	if ada.Row != 1 || ada.To[0] != "ada@example.com" || ada.Subject != "Hello Ada" || ada.Body != "Hi Ada,\nsee you.\n" {
+		t.Fatalf("unexpected first message: %+v", ada)
+	}
+	if strings.Join(ada.Cc, ",") != "team@example.com,boss@example.com" {
+		t.Fatalf("unexpected cc: %v", ada.Cc)
+	}
+	if len(ada.Attachments) != 1 || ada.Attachments[0] != filepath.Join(dir, "ada.pdf") {
+		t.Fatalf("unexpected attachments: %v", ada.Attachments)
+	}
+	if bob.BodyHTML != "<p>Hi &lt;Bob&gt;</p>" || len(bob.Attachments) != 0 {
+		t.Fatalf("expected escaped html and no attachments: %+v", bob)
+	}
+
+	bad := &GmailMergeCmd{Subject: "Hello {{.nmae}}", Template: tmpl}
+	if _, err := bad.render(rows, baseDir); ExitCode(err) != 2 || !strings.Contains(err.Error(), "row 1") {
+		t.Fatalf("expected usage error for a missing key, got %v", err)
+	}
+
+	jsonRows, _, err := readMergeData(writeMergeFile(t, dir, "people.json", `[{"email":"x@example.com","attachments":["a","b"],"n":3}]`))
+	if err != nil {
+		t.Fatalf("readMergeData json: %v", err)
+	}
+	if mergeField(jsonRows[0], mergeAttachColumns) != "a,b" || mergeField(jsonRows[0], []string{"n"}) != "3" {
+		t.Fatalf("unexpected json fields: %v", jsonRows[0])
+	}
+}
+
+func TestGmailMergeCmd_SendAndResume(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []string
+		fail = true
+	)
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/users/me/messages/send") {
+			http.NotFound(w, r)
+			return
+		}
+		var msg gmail.Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		raw, _ := base64.RawURLEncoding.DecodeString(msg.Raw)
+
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if fail && strings.Contains(string(raw), "To: bob@example.com") {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 400, "message": "Invalid To header"}})
+			return
+		}
+		sent = append(sent, string(raw))
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "m" + string(rune('0'+len(sent))), "threadId": "t1"})
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	dir := t.TempDir()
+	data := writeMergeFile(t, dir, "people.csv", "name,email\nAda,ada@example.com\nBob,bob@example.com\nCy,cy@example.com\n")
+	tmpl := writeMergeFile(t, dir, "body.tmpl", "Hi {{.name}}")
+	args := []string{"--data", data, "--template", tmpl, "--subject", "Hi {{.name}}", "--delay", "0"}
+
+	run := func() (map[string]any, error) {
+		var runErr error
+		out := captureStdout(t, func() {
+			u, uiErr := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+			if uiErr != nil {
+				t.Fatalf("ui.New: %v", uiErr)
+			}
+			ctx := outfmt.WithMode(ui.WithUI(context.Background(), u), outfmt.Mode{JSON: true})
+			runErr = runKong(t, &GmailMergeCmd{}, args, ctx, &RootFlags{Account: "a@b.com"})
+		})
+		var result map[string]any
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("decode %q: %v", out, err)
+		}
+		return result, runErr
+	}
+
+	result, err := run()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 messages failed") {
+		t.Fatalf("expected a failure for bob, got %v", err)
+	}
+	if result["sent"] != float64(2) || result["failed"] != float64(1) || result["skipped"] != float64(0) {
+		t.Fatalf("unexpected first run: %v", result)
+	}
+	if len(sent) != 2 || !strings.Contains(sent[0], "Subject: Hi Ada") || !strings.Contains(sent[1], "To: cy@example.com") {
+		t.Fatalf("unexpected sent messages: %q", sent)
+	}
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	result, err = run()
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if result["sent"] != float64(1) || result["skipped"] != float64(2) || len(sent) != 3 || !strings.Contains(sent[2], "To: bob@example.com") {
+		t.Fatalf("unexpected resumed run: %v (sent %d)", result, len(sent))
+	}
+
+	logData, err := os.ReadFile(data + ".merge.jsonl")
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[1], `"status":"failed"`) || !strings.Contains(lines[3], `"messageId":"m3"`) {
+		t.Fatalf("unexpected log: %s", logData)
+	}
+
+	// Reordering rows and inserting one only sends the new recipient.
+	_ = writeMergeFile(t, dir, "people.csv", "name,email\nDee,dee@example.com\nCy,cy@example.com\nAda,ada@example.com\nBob,bob@example.com\n")
+	result, err = run()
+	if err != nil {
+		t.Fatalf("reordered run: %v", err)
+	}
+	if result["sent"] != float64(1) || result["skipped"] != float64(3) || len(sent) != 4 || !strings.Contains(sent[3], "To: dee@example.com") {
+		t.Fatalf("unexpected reordered run: %v (sent %d)", result, len(sent))
+	}
+}
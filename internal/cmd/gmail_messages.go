@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
@@ -10,6 +11,7 @@ import (
 
 	"google.golang.org/api/gmail/v1"
 
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -61,6 +63,10 @@ func (c *GmailMessagesSearchCmd) Run(ctx context.Context, flags *RootFlags) erro
 		return resp.Messages, resp.NextPageToken, nil
 	}
 
+	if c.All && outfmt.IsNDJSON(ctx) {
+		return c.streamNDJSON(ctx, svc, fetch)
+	}
+
 	var messages []*gmail.Message
 	nextPageToken := ""
 	if c.All {
@@ -158,11 +164,34 @@ type messageItem struct {
 	Body     string   `json:"body,omitempty"`
 }
 
+// streamNDJSON emits message summaries page by page so --all never buffers the whole mailbox.
+func (c *GmailMessagesSearchCmd) streamNDJSON(ctx context.Context, svc *gmail.Service, fetch func(string) ([]*gmail.Message, string, error)) error {
+	idToName, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+	loc, err := resolveOutputLocation(c.Timezone, c.Local)
+	if err != nil {
+		return err
+	}
+	count, err := streamAllPagesNDJSONMapped(ctx, c.Page, fetch, func(messages []*gmail.Message) ([]messageItem, error) {
+		return fetchMessageDetails(ctx, svc, messages, idToName, loc, c.IncludeBody)
+	})
+	if err != nil {
+		return err
+	}
+	return streamedResultExit(count, c.FailEmpty)
+}
+
 func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) ([]messageItem, error) {
 	if len(messages) == 0 {
 		return nil, nil
 	}
 
+	if b := batcherFor(svc); b != nil {
+		return fetchMessageDetailsBatch(ctx, b, messages, idToName, loc, includeBody)
+	}
+
 	const maxConcurrency = 10
 	sem := make(chan struct{}, maxConcurrency)
 
@@ -206,31 +235,7 @@ func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gm
 				return
 			}
 
-			item := messageItem{
-				ID:       messageID,
-				ThreadID: msg.ThreadId,
-			}
-
-			item.From = sanitizeTab(headerValue(msg.Payload, "From"))
-			item.Subject = sanitizeTab(headerValue(msg.Payload, "Subject"))
-			item.Date = formatGmailDateInLocation(headerValue(msg.Payload, "Date"), loc)
-			if includeBody {
-				item.Body = bestBodyText(msg.Payload)
-			}
-
-			if len(msg.LabelIds) > 0 {
-				names := make([]string, 0, len(msg.LabelIds))
-				for _, lid := range msg.LabelIds {
-					if n, ok := idToName[lid]; ok {
-						names = append(names, n)
-					} else {
-						names = append(names, lid)
-					}
-				}
-				item.Labels = names
-			}
-
-			results <- result{index: idx, messageID: messageID, item: item}
+			results <- result{index: idx, messageID: messageID, item: messageItemFromMessage(messageID, msg, idToName, loc, includeBody)}
 		}(i, m.Id)
 	}
 
@@ -264,6 +269,51 @@ func fetchMessageDetails(ctx context.Context, svc *gmail.Service, messages []*gm
 	return items, nil
 }
 
+// fetchMessageDetailsBatch is fetchMessageDetails over the batch endpoint.
+func fetchMessageDetailsBatch(ctx context.Context, b *googleapi.Batcher, messages []*gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) ([]messageItem, error) {
+	ids := make([]string, 0, len(messages))
+	for _, m := range messages {
+		if m != nil && m.Id != "" {
+			ids = append(ids, m.Id)
+		}
+	}
+
+	query := url.Values{"format": {gmailFormatFull}}
+	if !includeBody {
+		query = gmailMetadataQuery("From", "Subject", "Date")
+		query.Set("fields", "id,threadId,labelIds,payload(headers)")
+	}
+
+	fetched, errs, err := batchGetGmailMessages(ctx, b, ids, query)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]messageItem, 0, len(ids))
+	for i, id := range ids {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("message %s: %w", id, errs[i])
+		}
+		items = append(items, messageItemFromMessage(id, fetched[i], idToName, loc, includeBody))
+	}
+	return items, nil
+}
+
+func messageItemFromMessage(messageID string, msg *gmail.Message, idToName map[string]string, loc *time.Location, includeBody bool) messageItem {
+	item := messageItem{
+		ID:       messageID,
+		ThreadID: msg.ThreadId,
+		From:     sanitizeTab(headerValue(msg.Payload, "From")),
+		Subject:  sanitizeTab(headerValue(msg.Payload, "Subject")),
+		Date:     formatGmailDateInLocation(headerValue(msg.Payload, "Date"), loc),
+		Labels:   labelNamesFor(msg.LabelIds, idToName),
+	}
+	if includeBody {
+		item.Body = bestBodyText(msg.Payload)
+	}
+	return item
+}
+
 func sanitizeMessageBody(body string) string {
 	if body == "" {
 		return ""
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"mime"
+	"mime/quotedprintable"
 	"net/mail"
 	"net/url"
 	"os"
@@ -24,6 +25,8 @@ type mailAttachment struct {
 
 type rfc822Config struct {
 	allowMissingTo bool
+	// smime, when set, wraps the finished message in an S/MIME signature.
+	smime *smimeSigner
 }
 
 type mailOptions struct {
@@ -42,6 +45,16 @@ type mailOptions struct {
 }
 
 func buildRFC822(opts mailOptions, cfg *rfc822Config) ([]byte, error) {
+	if cfg != nil && cfg.smime != nil {
+		unsigned := *cfg
+		unsigned.smime = nil
+		raw, err := buildRFC822(opts, &unsigned)
+		if err != nil {
+			return nil, err
+		}
+		return cfg.smime.sign(raw)
+	}
+
 	allowMissingTo := cfg != nil && cfg.allowMissingTo
 
 	if strings.TrimSpace(opts.From) == "" {
@@ -136,15 +149,11 @@ func buildRFC822(opts mailOptions, cfg *rfc822Config) ([]byte, error) {
 			return b.Bytes(), nil
 		case hasHTML && !hasPlain:
 			writeHeader(&b, "Content-Type", "text/html; charset=\"utf-8\"")
-			writeHeader(&b, "Content-Transfer-Encoding", "7bit")
-			b.WriteString("\r\n")
-			writeBodyWithTrailingCRLF(&b, htmlBody)
+			writeTextBody(&b, htmlBody)
 			return b.Bytes(), nil
 		default:
 			writeHeader(&b, "Content-Type", "text/plain; charset=\"utf-8\"")
-			writeHeader(&b, "Content-Transfer-Encoding", "7bit")
-			b.WriteString("\r\n")
-			writeBodyWithTrailingCRLF(&b, plainBody)
+			writeTextBody(&b, plainBody)
 			return b.Bytes(), nil
 		}
 	}
@@ -171,12 +180,10 @@ func buildRFC822(opts mailOptions, cfg *rfc822Config) ([]byte, error) {
 		b.WriteString(fmt.Sprintf("--%s--\r\n", altBoundary))
 	case hasHTML && !hasPlain:
 		b.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
-		b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
-		writeBodyWithTrailingCRLF(&b, htmlBody)
+		writeTextBody(&b, htmlBody)
 	default:
 		b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
-		b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
-		writeBodyWithTrailingCRLF(&b, plainBody)
+		writeTextBody(&b, plainBody)
 	}
 
 	// Attachments
@@ -292,8 +299,40 @@ func writeBodyWithTrailingCRLF(b *bytes.Buffer, body string) {
 func writeTextPart(b *bytes.Buffer, boundary string, contentType string, body string) {
 	_, _ = fmt.Fprintf(b, "--%s\r\n", boundary)
 	_, _ = fmt.Fprintf(b, "Content-Type: %s\r\n", contentType)
-	b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
-	writeBodyWithTrailingCRLF(b, body)
+	writeTextBody(b, body)
+}
+
+// writeTextBody writes the Content-Transfer-Encoding header, the blank line and
+// the body. Bodies that are not 7-bit safe (non-ASCII or lines over 998 octets)
+// are quoted-printable encoded: the 7bit label would be wrong for them, and
+// S/MIME signatures (RFC 8551 section 3.1) only survive relays on 7-bit content.
+func writeTextBody(b *bytes.Buffer, body string) {
+	body = normalizeCRLF(body)
+	if is7bitSafe(body) {
+		b.WriteString("Content-Transfer-Encoding: 7bit\r\n\r\n")
+		writeBodyWithTrailingCRLF(b, body)
+		return
+	}
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	var encoded bytes.Buffer
+	qp := quotedprintable.NewWriter(&encoded)
+	_, _ = qp.Write([]byte(body))
+	_ = qp.Close()
+	writeBodyWithTrailingCRLF(b, encoded.String())
+}
+
+func is7bitSafe(body string) bool {
+	if !isASCII(body) {
+		return false
+	}
+	// Bodies may use "\n" or "\r\n"; the 998-octet limit is per line either way.
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(line) > 998 || strings.ContainsRune(line, 0) {
+			return false
+		}
+	}
+	return true
 }
 
 func randomBoundary() (string, error) {
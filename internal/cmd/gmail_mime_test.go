@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"bytes"
+	"io"
+	"mime/quotedprintable"
 	"regexp"
 	"strings"
 	"testing"
@@ -28,6 +31,64 @@ func TestBuildRFC822Plain(t *testing.T) {
 	}
 }
 
+func TestBuildRFC822NonASCIIBodyIsQuotedPrintable(t *testing.T) {
+	body := "Grüße aus Köln\n" + strings.Repeat("x", 1200)
+	raw, err := buildRFC822(mailOptions{
+		From:    "a@b.com",
+		To:      []string{"c@d.com"},
+		Subject: "Hi",
+		Body:    body,
+	}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !isASCII(string(raw)) {
+		t.Fatalf("expected a 7-bit message: %q", raw)
+	}
+	head, encoded, _ := strings.Cut(string(raw), "\r\n\r\n")
+	if !strings.Contains(head, "Content-Transfer-Encoding: quoted-printable") {
+		t.Fatalf("missing quoted-printable header: %q", head)
+	}
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(encoded)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.TrimSuffix(string(decoded), "\r\n") != normalizeCRLF(body) {
+		t.Fatalf("unexpected decoded body: %q", decoded)
+	}
+}
+
+func TestBuildRFC822LongASCIIBodyStays7bit(t *testing.T) {
+	line := strings.Repeat("x", 80)
+	body := strings.Repeat(line+"\n", 40)
+	raw, err := buildRFC822(mailOptions{
+		From:    "a@b.com",
+		To:      []string{"c@d.com"},
+		Subject: "Hi",
+		Body:    body,
+	}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	head, rest, _ := strings.Cut(string(raw), "\r\n\r\n")
+	if !strings.Contains(head, "Content-Transfer-Encoding: 7bit") {
+		t.Fatalf("expected 7bit for a multi-line ASCII body: %q", head)
+	}
+	if !strings.Contains(rest, line+"\r\n"+line) {
+		t.Fatalf("expected the body unencoded: %q", rest)
+	}
+
+	// Callers may hand over "\n" line endings; the limit is still per line.
+	var b bytes.Buffer
+	writeTextBody(&b, body)
+	if !strings.HasPrefix(b.String(), "Content-Transfer-Encoding: 7bit\r\n\r\n"+line+"\r\n") {
+		t.Fatalf("expected a 7bit CRLF body: %q", b.String()[:120])
+	}
+	if is7bitSafe(strings.Repeat("x", 999)) {
+		t.Fatalf("expected a single 999-octet line to need encoding")
+	}
+}
+
 func TestBuildRFC822HTMLOnly(t *testing.T) {
 	raw, err := buildRFC822(mailOptions{
 		From:     "a@b.com",
@@ -0,0 +1,419 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/googleapi"
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Gmail has no scheduled send in its API. `send --at` stores the message as a draft
+// and records it here; `gmail queue run` (from cron or a systemd timer) sends the
+// drafts that are due.
+const (
+	gmailQueueFile = "queue.json"
+	// gmailQueueLockFile guards read-modify-write cycles of queue.json; it is a
+	// separate file because each write renames a new queue.json into place.
+	gmailQueueLockFile = "queue.lock"
+	// gmailQueueRunLockFile serializes `queue run` so overlapping runs cannot
+	// send the same draft twice.
+	gmailQueueRunLockFile = "run.lock"
+)
+
+type GmailQueueCmd struct {
+	Run    GmailQueueRunCmd    `cmd:"" name:"run" help:"Send queued drafts that are due (run from cron/systemd)"`
+	List   GmailQueueListCmd   `cmd:"" name:"list" aliases:"ls" help:"List scheduled messages"`
+	Cancel GmailQueueCancelCmd `cmd:"" name:"cancel" aliases:"rm,remove" help:"Unschedule messages (the drafts are kept unless --delete-draft)"`
+}
+
+type gmailQueueEntry struct {
+	DraftID   string   `json:"draftId"`
+	Account   string   `json:"account"`
+	SendAt    string   `json:"sendAt"`
+	To        []string `json:"to,omitempty"`
+	Subject   string   `json:"subject,omitempty"`
+	CreatedAt string   `json:"createdAt"`
+	Attempts  int      `json:"attempts,omitempty"`
+	LastError string   `json:"lastError,omitempty"`
+}
+
+type gmailQueue struct {
+	Entries []*gmailQueueEntry `json:"entries"`
+}
+
+func (e *gmailQueueEntry) sendTime() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.SendAt)
+	return t
+}
+
+func gmailQueuePath() (string, error) {
+	dir, err := config.EnsureGmailQueueDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, gmailQueueFile), nil
+}
+
+func loadGmailQueue() (*gmailQueue, error) {
+	path, err := gmailQueuePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // config-dir path
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &gmailQueue{}, nil
+		}
+		return nil, fmt.Errorf("read send queue: %w", err)
+	}
+	var q gmailQueue
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, fmt.Errorf("parse send queue %s: %w", path, err)
+	}
+	return &q, nil
+}
+
+// lockGmailQueue takes an exclusive cross-process lock on name in the queue
+// dir and returns the function that releases it.
+func lockGmailQueue(name string) (func(), error) {
+	dir, err := config.EnsureGmailQueueDir()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_RDWR|os.O_CREATE, 0o600) //nolint:gosec // config-dir path
+	if err != nil {
+		return nil, fmt.Errorf("open send queue lock: %w", err)
+	}
+	if err := googleapi.LockFile(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("lock send queue: %w", err)
+	}
+	return func() {
+		_ = googleapi.UnlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// updateGmailQueue re-reads the queue, applies fn and writes it back atomically
+// while holding the queue lock, so a `send --at` running next to `queue run`
+// does not lose entries.
+func updateGmailQueue(fn func(q *gmailQueue)) error {
+	unlock, err := lockGmailQueue(gmailQueueLockFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	q, err := loadGmailQueue()
+	if err != nil {
+		return err
+	}
+	fn(q)
+	sort.SliceStable(q.Entries, func(i, j int) bool { return q.Entries[i].sendTime().Before(q.Entries[j].sendTime()) })
+
+	path, err := gmailQueuePath()
+	if err != nil {
+		return err
+	}
+	payload, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, payload); err != nil {
+		return fmt.Errorf("write send queue: %w", err)
+	}
+	return nil
+}
+
+func (q *gmailQueue) remove(draftID string) {
+	kept := q.Entries[:0]
+	for _, e := range q.Entries {
+		if e.DraftID != draftID {
+			kept = append(kept, e)
+		}
+	}
+	q.Entries = kept
+}
+
+// queueAccountFilter limits queue commands to --account when one was given;
+// otherwise they cover every account's scheduled messages.
+func queueAccountFilter(flags *RootFlags) (string, error) {
+	if flags == nil || strings.TrimSpace(flags.Account) == "" {
+		return "", nil
+	}
+	return requireAccount(flags)
+}
+
+// scheduleGmailDraft saves the message as a draft (as `gmail drafts create` does)
+// and queues it for sending at sendAt.
+func scheduleGmailDraft(ctx context.Context, svc *gmail.Service, account string, sendAt time.Time, input draftComposeInput) (*gmailQueueEntry, error) {
+	msg, _, err := buildDraftMessage(ctx, svc, account, input)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := svc.Users.Drafts.Create("me", &gmail.Draft{Message: msg}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &gmailQueueEntry{
+		DraftID:   draft.Id,
+		Account:   account,
+		SendAt:    sendAt.UTC().Format(time.RFC3339),
+		To:        splitCSV(input.To),
+		Subject:   strings.TrimSpace(input.Subject),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := updateGmailQueue(func(q *gmailQueue) {
+		q.remove(entry.DraftID)
+		q.Entries = append(q.Entries, entry)
+	}); err != nil {
+		return nil, fmt.Errorf("draft %s was created but not queued: %w", draft.Id, err)
+	}
+	return entry, nil
+}
+
+func sendGmailDraft(ctx context.Context, svc *gmail.Service, draftID string) (*gmail.Message, error) {
+	return svc.Users.Drafts.Send("me", &gmail.Draft{Id: draftID}).Context(ctx).Do()
+}
+
+type GmailQueueRunCmd struct{}
+
+type gmailQueueRunResult struct {
+	DraftID   string `json:"draftId"`
+	Account   string `json:"account"`
+	Status    string `json:"status"`
+	MessageID string `json:"messageId,omitempty"`
+	ThreadID  string `json:"threadId,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (c *GmailQueueRunCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	account, err := queueAccountFilter(flags)
+	if err != nil {
+		return err
+	}
+	// Held for the whole run: a second run waits and then only sees the
+	// entries this one left in the queue.
+	unlock, err := lockGmailQueue(gmailQueueRunLockFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	q, err := loadGmailQueue()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due := make([]*gmailQueueEntry, 0, len(q.Entries))
+	for _, e := range q.Entries {
+		if account != "" && !strings.EqualFold(e.Account, account) {
+			continue
+		}
+		if !e.sendTime().After(now) {
+			due = append(due, e)
+		}
+	}
+
+	if dryRunErr := dryRunExit(ctx, flags, "gmail.queue.run", map[string]any{
+		"due": due,
+	}); dryRunErr != nil {
+		return dryRunErr
+	}
+
+	services := map[string]*gmail.Service{}
+	results := make([]gmailQueueRunResult, 0, len(due))
+	failed := 0
+	for _, e := range due {
+		res := gmailQueueRunResult{DraftID: e.DraftID, Account: e.Account}
+
+		svc, ok := services[e.Account]
+		if !ok {
+			svc, err = newGmailService(ctx, e.Account)
+			if err != nil {
+				return err
+			}
+			services[e.Account] = svc
+		}
+
+		msg, sendErr := sendGmailDraft(ctx, svc, e.DraftID)
+		switch {
+		case sendErr == nil:
+			res.Status = "sent"
+			res.MessageID = msg.Id
+			res.ThreadID = msg.ThreadId
+			err = updateGmailQueue(func(q *gmailQueue) { q.remove(e.DraftID) })
+		case isNotFoundAPIError(sendErr):
+			// The draft was deleted or sent by hand; nothing left to retry.
+			failed++
+			res.Status = "missing"
+			res.Error = "draft not found (deleted or already sent); removed from queue"
+			err = updateGmailQueue(func(q *gmailQueue) { q.remove(e.DraftID) })
+		default:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed++
+			res.Status = "failed"
+			res.Error = sendErr.Error()
+			err = updateGmailQueue(func(q *gmailQueue) {
+				for _, qe := range q.Entries {
+					if qe.DraftID == e.DraftID {
+						qe.Attempts++
+						qe.LastError = sendErr.Error()
+					}
+				}
+			})
+		}
+		if err != nil {
+			return err
+		}
+		results = append(results, res)
+	}
+
+	if outfmt.IsJSON(ctx) {
+		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"results": results}); err != nil {
+			return err
+		}
+	} else if len(results) == 0 {
+		u.Err().Println("No messages due")
+	} else {
+		w, flush := tableWriter(ctx)
+		fmt.Fprintln(w, "DRAFT_ID\tACCOUNT\tSTATUS\tMESSAGE_ID\tERROR")
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.DraftID, r.Account, r.Status, r.MessageID, sanitizeTab(r.Error))
+		}
+		flush()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d due messages failed to send", failed, len(results))
+	}
+	return nil
+}
+
+type GmailQueueListCmd struct{}
+
+func (c *GmailQueueListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	account, err := queueAccountFilter(flags)
+	if err != nil {
+		return err
+	}
+	q, err := loadGmailQueue()
+	if err != nil {
+		return err
+	}
+	entries := make([]*gmailQueueEntry, 0, len(q.Entries))
+	for _, e := range q.Entries {
+		if account == "" || strings.EqualFold(e.Account, account) {
+			entries = append(entries, e)
+		}
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"messages": entries})
+	}
+	if len(entries) == 0 {
+		u.Err().Println("No scheduled messages")
+		return nil
+	}
+
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "DRAFT_ID\tACCOUNT\tSEND_AT\tTO\tSUBJECT\tLAST_ERROR")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.DraftID,
+			e.Account,
+			e.sendTime().In(time.Local).Format("2006-01-02 15:04 MST"),
+			sanitizeTab(strings.Join(e.To, ", ")),
+			sanitizeTab(e.Subject),
+			sanitizeTab(e.LastError),
+		)
+	}
+	return nil
+}
+
+type GmailQueueCancelCmd struct {
+	DraftIDs    []string `arg:"" stdin:"" name:"draftId" help:"Queued draft IDs"`
+	DeleteDraft bool     `name:"delete-draft" help:"Also delete the drafts (default: keep them in Drafts)"`
+}
+
+func (c *GmailQueueCancelCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+
+	q, err := loadGmailQueue()
+	if err != nil {
+		return err
+	}
+	queued := make(map[string]*gmailQueueEntry, len(q.Entries))
+	for _, e := range q.Entries {
+		queued[e.DraftID] = e
+	}
+
+	ids := make([]string, 0, len(c.DraftIDs))
+	for _, id := range c.DraftIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := queued[id]; !ok {
+			return usagef("draft %s is not queued (see: gog gmail queue list)", id)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return usage("missing draftId")
+	}
+
+	if c.DeleteDraft {
+		if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("delete %d scheduled gmail draft(s)", len(ids))); confirmErr != nil {
+			return confirmErr
+		}
+	} else if dryRunErr := dryRunExit(ctx, flags, "gmail.queue.cancel", map[string]any{"draft_ids": ids}); dryRunErr != nil {
+		return dryRunErr
+	}
+
+	if c.DeleteDraft {
+		for _, id := range ids {
+			svc, svcErr := newGmailService(ctx, queued[id].Account)
+			if svcErr != nil {
+				return svcErr
+			}
+			if delErr := svc.Users.Drafts.Delete("me", id).Context(ctx).Do(); delErr != nil && !isNotFoundAPIError(delErr) {
+				return fmt.Errorf("delete draft %s: %w", id, delErr)
+			}
+		}
+	}
+
+	if err := updateGmailQueue(func(q *gmailQueue) {
+		for _, id := range ids {
+			q.remove(id)
+		}
+	}); err != nil {
+		return err
+	}
+
+	return writeResult(ctx, u,
+		kv("cancelled", ids),
+		kv("draftsDeleted", c.DeleteDraft),
+	)
+}
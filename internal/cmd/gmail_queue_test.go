@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func setupGmailQueueTest(t *testing.T, h http.HandlerFunc) context.Context {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	svc, cleanup := newGmailServiceForTest(t, h)
+	t.Cleanup(cleanup)
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	return outfmt.WithMode(ui.WithUI(context.Background(), u), outfmt.Mode{JSON: true})
+}
+
+func TestGmailSendCmd_AtQueuesDraft(t *testing.T) {
+	var created gmail.Draft
+	ctx := setupGmailQueueTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/me/drafts") {
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "d1", "message": map[string]any{"id": "m1"}})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/messages/send") {
+			t.Errorf("scheduled send must not send now")
+		}
+		http.NotFound(w, r)
+	})
+
+	out := captureStdout(t, func() {
+		if err := runKong(t, &GmailSendCmd{}, []string{"--to", "x@example.com", "--subject", "Later", "--body", "hi", "--at", "in 2h"}, ctx, &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("send --at: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"draftId": "d1"`) || created.Message == nil || created.Message.Raw == "" {
+		t.Fatalf("unexpected output %q / draft %+v", out, created)
+	}
+
+	q, err := loadGmailQueue()
+	if err != nil {
+		t.Fatalf("loadGmailQueue: %v", err)
+	}
+	if len(q.Entries) != 1 || q.Entries[0].DraftID != "d1" || q.Entries[0].Account != "a@b.com" || q.Entries[0].Subject != "Later" {
+		t.Fatalf("unexpected queue: %+v", q.Entries)
+	}
+	if d := time.Until(q.Entries[0].sendTime()); d < time.Hour || d > 2*time.Hour {
+		t.Fatalf("unexpected send time: %s", q.Entries[0].SendAt)
+	}
+
+	if err := runKong(t, &GmailSendCmd{}, []string{"--to", "x@example.com", "--subject", "S", "--body", "b", "--at", "2001-01-01T00:00:00Z"}, ctx, &RootFlags{Account: "a@b.com"}); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for a past time, got %v", err)
+	}
+}
+
+func TestGmailQueueCmds_RunListCancel(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		sent []string
+	)
+	ctx := setupGmailQueueTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/users/me/drafts/send") {
+			http.NotFound(w, r)
+			return
+		}
+		var d gmail.Draft
+		_ = json.NewDecoder(r.Body).Decode(&d)
+		w.Header().Set("Content-Type", "application/json")
+		if d.Id == "gone" {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "Requested entity was not found."}})
+			return
+		}
+		mu.Lock()
+		sent = append(sent, d.Id)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "m-" + d.Id, "threadId": "t1"})
+	})
+
+	past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	if err := updateGmailQueue(func(q *gmailQueue) {
+		q.Entries = []*gmailQueueEntry{
+			{DraftID: "later", Account: "a@b.com", SendAt: future},
+			{DraftID: "due", Account: "a@b.com", SendAt: past},
+			{DraftID: "gone", Account: "a@b.com", SendAt: past},
+			{DraftID: "other", Account: "c@d.com", SendAt: past},
+		}
+	}); err != nil {
+		t.Fatalf("seed queue: %v", err)
+	}
+
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = runKong(t, &GmailQueueRunCmd{}, nil, ctx, &RootFlags{Account: "a@b.com"})
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "1 of 2 due messages failed") {
+		t.Fatalf("expected the missing draft to fail the run, got %v", runErr)
+	}
+	var parsed struct {
+		Results []gmailQueueRunResult `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(parsed.Results) != 2 || parsed.Results[0].Status != "sent" || parsed.Results[0].MessageID != "m-due" || parsed.Results[1].Status != "missing" {
+		t.Fatalf("unexpected results: %+v", parsed.Results)
+	}
+	if strings.Join(sent, ",") != "due" {
+		t.Fatalf("unexpected sends: %v", sent)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runKong(t, &GmailQueueListCmd{}, nil, ctx, &RootFlags{}); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	var listed struct {
+		Messages []gmailQueueEntry `json:"messages"`
+	}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if len(listed.Messages) != 2 || listed.Messages[0].DraftID != "other" || listed.Messages[1].DraftID != "later" {
+		t.Fatalf("unexpected list: %+v", listed.Messages)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &GmailQueueCancelCmd{}, []string{"later", "other"}, ctx, &RootFlags{}); err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+	})
+	if q, err := loadGmailQueue(); err != nil || len(q.Entries) != 0 {
+		t.Fatalf("expected an empty queue, got %+v (%v)", q, err)
+	}
+	if err := runKong(t, &GmailQueueCancelCmd{}, []string{"later"}, ctx, &RootFlags{}); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for an unknown draft, got %v", err)
+	}
+}
+
+func TestUpdateGmailQueue_ConcurrentWritersKeepEntries(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- updateGmailQueue(func(q *gmailQueue) {
+				q.Entries = append(q.Entries, &gmailQueueEntry{DraftID: fmt.Sprintf("d%d", i), Account: "a@b.com"})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("updateGmailQueue: %v", err)
+		}
+	}
+
+	q, err := loadGmailQueue()
+	if err != nil {
+		t.Fatalf("loadGmailQueue: %v", err)
+	}
+	if len(q.Entries) != writers {
+		t.Fatalf("expected %d entries, got %d", writers, len(q.Entries))
+	}
+}
@@ -0,0 +1,487 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// Offline search over a `gmail sync` archive. The query language is a subset of
+// Gmail's: free text, "quoted phrases", /regular expressions/, field operators
+// (from: to: cc: subject: body: filename: label:/in: is: has:attachment
+// after: before: older_than: newer_than:), and a leading - to negate a term.
+
+// Ranking weights per match location.
+const (
+	localScoreSubject    = 5
+	localScoreAddress    = 3
+	localScoreAttachment = 2
+	localScoreBody       = 1
+	localScoreMaxHits    = 10
+)
+
+type localQueryTerm struct {
+	field  string
+	value  string
+	re     *regexp.Regexp
+	negate bool
+	after  time.Time
+	before time.Time
+}
+
+type localQuery struct {
+	terms []localQueryTerm
+	// readBody loads the full body text for body regexes; without it they
+	// only see the snippet.
+	readBody func(*gmailIndexDoc) string
+}
+
+var localQueryFields = map[string]string{
+	"from":       "from",
+	"to":         "to",
+	"cc":         "cc",
+	"subject":    "subject",
+	"body":       "body",
+	"filename":   "filename",
+	"label":      "label",
+	"in":         "label",
+	"is":         "is",
+	"has":        "has",
+	"after":      "after",
+	"before":     "before",
+	"older":      "before",
+	"newer":      "after",
+	"older_than": "older_than",
+	"newer_than": "newer_than",
+}
+
+func parseLocalQuery(q string, now time.Time) (*localQuery, error) {
+	query := &localQuery{}
+	for i := 0; i < len(q); {
+		for i < len(q) && (q[i] == ' ' || q[i] == '\t') {
+			i++
+		}
+		if i >= len(q) {
+			break
+		}
+
+		term := localQueryTerm{}
+		if q[i] == '-' && i+1 < len(q) && q[i+1] != ' ' {
+			term.negate = true
+			i++
+		}
+		if colon := strings.IndexByte(q[i:], ':'); colon > 0 {
+			if field, ok := localQueryFields[strings.ToLower(q[i:i+colon])]; ok && !strings.ContainsAny(q[i:i+colon], " \t\"/") {
+				term.field = field
+				i += colon + 1
+			}
+		}
+
+		value, isRegex, next, err := readLocalQueryValue(q, i)
+		if err != nil {
+			return nil, err
+		}
+		i = next
+		if value == "" && !isRegex {
+			if term.field != "" {
+				return nil, usagef("missing value for %s:", term.field)
+			}
+			continue
+		}
+
+		if isRegex {
+			re, err := regexp.Compile("(?i)" + value)
+			if err != nil {
+				return nil, usagef("invalid regex /%s/: %v", value, err)
+			}
+			term.re = re
+		} else {
+			term.value = strings.ToLower(value)
+		}
+		if err := term.resolve(now); err != nil {
+			return nil, err
+		}
+		query.terms = append(query.terms, term)
+	}
+	if len(query.terms) == 0 {
+		return nil, usage("missing query")
+	}
+	return query, nil
+}
+
+// readLocalQueryValue reads a "phrase", a /regex/ (\/ escapes a slash) or a bare word.
+func readLocalQueryValue(q string, i int) (string, bool, int, error) {
+	if i >= len(q) {
+		return "", false, i, nil
+	}
+	switch q[i] {
+	case '"':
+		end := strings.IndexByte(q[i+1:], '"')
+		if end < 0 {
+			return "", false, i, usage("unterminated quote in query")
+		}
+		return q[i+1 : i+1+end], false, i + end + 2, nil
+	case '/':
+		var b strings.Builder
+		for j := i + 1; j < len(q); j++ {
+			switch {
+			case q[j] == '\\' && j+1 < len(q) && q[j+1] == '/':
+				b.WriteByte('/')
+				j++
+			case q[j] == '/':
+				return b.String(), true, j + 1, nil
+			default:
+				b.WriteByte(q[j])
+			}
+		}
+		return "", false, i, usage("unterminated /regex/ in query")
+	}
+	end := strings.IndexAny(q[i:], " \t")
+	if end < 0 {
+		return q[i:], false, len(q), nil
+	}
+	return q[i : i+end], false, i + end, nil
+}
+
+// resolve validates operator values and turns relative dates into bounds.
+func (t *localQueryTerm) resolve(now time.Time) error {
+	switch t.field {
+	case "is":
+		switch t.value {
+		case "unread", "read", "starred", "important", "draft", "sent", "trash", "spam":
+		default:
+			return usagef("unsupported is:%s", t.value)
+		}
+	case "has":
+		if t.value != "attachment" {
+			return usagef("unsupported has:%s (only has:attachment)", t.value)
+		}
+	case "after", "before":
+		d, err := parseLocalQueryDate(t.value)
+		if err != nil {
+			return err
+		}
+		if t.field == "after" {
+			t.after = d
+		} else {
+			t.before = d
+		}
+	case "older_than", "newer_than":
+		d, err := parseLocalQueryAge(t.value, now)
+		if err != nil {
+			return err
+		}
+		if t.field == "newer_than" {
+			t.after = d
+		} else {
+			t.before = d
+		}
+	}
+	if t.re != nil {
+		switch t.field {
+		case "is", "has", "after", "before", "older_than", "newer_than":
+			return usagef("%s: does not take a regex", t.field)
+		}
+	}
+	return nil
+}
+
+func parseLocalQueryDate(v string) (time.Time, error) {
+	for _, layout := range []string{"2006/01/02", "2006-01-02", "2006/1/2"} {
+		if d, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return d, nil
+		}
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Time{}, usagef("invalid date %q (expected YYYY/MM/DD)", v)
+}
+
+func parseLocalQueryAge(v string, now time.Time) (time.Time, error) {
+	if len(v) < 2 {
+		return time.Time{}, usagef("invalid age %q (expected e.g. 7d, 2m, 1y)", v)
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, usagef("invalid age %q (expected e.g. 7d, 2m, 1y)", v)
+	}
+	switch v[len(v)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, usagef("invalid age %q (expected e.g. 7d, 2m, 1y)", v)
+	}
+}
+
+// match reports whether doc satisfies every term, and its rank score. Terms that
+// need the body text run last, so it is only read for otherwise matching docs.
+func (q *localQuery) match(doc *gmailIndexDoc, labelNames map[string]string) (bool, int) {
+	var text *string
+	body := func() string {
+		if text == nil {
+			s := doc.Snippet
+			if q.readBody != nil {
+				s = q.readBody(doc)
+			}
+			text = &s
+		}
+		return *text
+	}
+
+	score := 0
+	for _, pass := range []bool{false, true} {
+		for i := range q.terms {
+			t := &q.terms[i]
+			if t.needsBody() != pass {
+				continue
+			}
+			hits := t.hits(doc, labelNames, body)
+			if (hits > 0) == t.negate {
+				return false, 0
+			}
+			if !t.negate {
+				score += hits
+			}
+		}
+	}
+	return true, score
+}
+
+func (t *localQueryTerm) needsBody() bool {
+	return t.re != nil && (t.field == "" || t.field == "body")
+}
+
+// hits returns the weighted number of matches of t in doc (1 for filters).
+func (t *localQueryTerm) hits(doc *gmailIndexDoc, labelNames map[string]string, body func() string) int {
+	switch t.field {
+	case "":
+		return localScoreSubject*t.count(doc.Subject) +
+			localScoreAddress*(t.count(doc.From)+t.count(doc.To)+t.count(doc.Cc)) +
+			localScoreAttachment*t.count(strings.Join(doc.Attachments, "\n")) +
+			localScoreBody*t.bodyHits(doc, body)
+	case "from":
+		return localScoreAddress * t.count(doc.From)
+	case "to":
+		return localScoreAddress * (t.count(doc.To) + t.count(doc.Cc))
+	case "cc":
+		return localScoreAddress * t.count(doc.Cc)
+	case "subject":
+		return localScoreSubject * t.count(doc.Subject)
+	case "body":
+		return localScoreBody * t.bodyHits(doc, body)
+	case "filename":
+		return localScoreAttachment * t.count(strings.Join(doc.Attachments, "\n"))
+	case "label":
+		for _, id := range doc.LabelIDs {
+			if t.matchesLabel(id) || t.matchesLabel(labelNames[id]) {
+				return 1
+			}
+		}
+		return 0
+	case "is":
+		return boolHit(localIsMatch(t.value, doc.LabelIDs))
+	case "has":
+		return boolHit(len(doc.Attachments) > 0)
+	case "after", "newer_than":
+		return boolHit(!doc.messageTime().Before(t.after))
+	case "before", "older_than":
+		return boolHit(doc.messageTime().Before(t.before))
+	}
+	return 0
+}
+
+// bodyHits matches a plain value when each of its words occurs in (a word of)
+// the body, and a regex against the body text.
+func (t *localQueryTerm) bodyHits(doc *gmailIndexDoc, body func() string) int {
+	if t.re != nil {
+		return t.count(body())
+	}
+	words := indexTerms(t.value)
+	if len(words) == 0 {
+		return t.count(doc.Snippet)
+	}
+	for _, w := range words {
+		if !strings.Contains(doc.Terms, w) {
+			return 0
+		}
+	}
+	return 1
+}
+
+func (t *localQueryTerm) count(s string) int {
+	if s == "" {
+		return 0
+	}
+	if t.re != nil {
+		return len(t.re.FindAllStringIndex(s, localScoreMaxHits))
+	}
+	return min(strings.Count(strings.ToLower(s), t.value), localScoreMaxHits)
+}
+
+// matchesLabel compares like Gmail: case-insensitive, with spaces and slashes in
+// label names written as dashes (label:work-projects matches "Work/Projects").
+func (t *localQueryTerm) matchesLabel(name string) bool {
+	if name == "" {
+		return false
+	}
+	if t.re != nil {
+		return t.re.MatchString(name)
+	}
+	lower := strings.ToLower(name)
+	return lower == t.value || strings.NewReplacer(" ", "-", "/", "-").Replace(lower) == t.value
+}
+
+func localIsMatch(value string, labelIDs []string) bool {
+	switch value {
+	case "read":
+		return !hasLabel(labelIDs, "UNREAD")
+	default:
+		return hasLabel(labelIDs, strings.ToUpper(value))
+	}
+}
+
+func boolHit(ok bool) int {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+type localThreadHit struct {
+	threadID string
+	score    int
+	latest   time.Time
+}
+
+// searchLocalThreads ranks threads by their best-matching message (ties: newest first)
+// and builds the same items as the online search.
+func searchLocalThreads(idx *gmailIndex, state *gmailSyncState, query *localQuery, oldest bool, loc *time.Location) []threadItem {
+	threads := map[string][]*gmailIndexDoc{}
+	hits := map[string]*localThreadHit{}
+	for _, doc := range idx.Docs {
+		threadID := doc.ThreadID
+		if threadID == "" {
+			threadID = doc.ID
+		}
+		threads[threadID] = append(threads[threadID], doc)
+
+		ok, score := query.match(doc, state.Labels)
+		if !ok {
+			continue
+		}
+		h := hits[threadID]
+		if h == nil {
+			h = &localThreadHit{threadID: threadID}
+			hits[threadID] = h
+		}
+		h.score = max(h.score, score)
+		if t := doc.messageTime(); t.After(h.latest) {
+			h.latest = t
+		}
+	}
+
+	ranked := make([]*localThreadHit, 0, len(hits))
+	for _, h := range hits {
+		ranked = append(ranked, h)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if !a.latest.Equal(b.latest) {
+			return a.latest.After(b.latest)
+		}
+		return a.threadID < b.threadID
+	})
+
+	items := make([]threadItem, 0, len(ranked))
+	for _, h := range ranked {
+		msgs := threads[h.threadID]
+		sort.Slice(msgs, func(i, j int) bool { return msgs[i].messageTime().Before(msgs[j].messageTime()) })
+
+		first := msgs[0]
+		dateMsg := msgs[len(msgs)-1]
+		if oldest {
+			dateMsg = first
+		}
+		item := threadItem{
+			ID:           h.threadID,
+			From:         sanitizeTab(first.From),
+			Subject:      sanitizeTab(first.Subject),
+			Labels:       labelNamesFor(first.LabelIDs, state.Labels),
+			MessageCount: len(msgs),
+		}
+		if dateMsg.Date != "" {
+			item.Date = formatGmailDateInLocation(dateMsg.Date, loc)
+		} else if t := dateMsg.messageTime(); !t.IsZero() {
+			item.Date = t.In(loc).Format("2006-01-02 15:04")
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// runOffline answers the search from a local `gmail sync` archive.
+func (c *GmailSearchCmd) runOffline(ctx context.Context, query string) error {
+	u := ui.FromContext(ctx)
+
+	dir := strings.TrimSpace(c.Archive)
+	if dir == "" {
+		dir = strings.TrimSpace(os.Getenv("GOG_GMAIL_ARCHIVE"))
+	}
+	if dir == "" {
+		return usage("--offline needs --archive <dir> (or GOG_GMAIL_ARCHIVE) pointing at a gmail sync archive")
+	}
+	dir, err := config.ExpandPath(dir)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := parseLocalQuery(query, time.Now())
+	if err != nil {
+		return err
+	}
+	loc, err := resolveOutputLocation(c.Timezone, c.Local)
+	if err != nil {
+		return err
+	}
+
+	idx, state, err := loadGmailIndex(dir)
+	if err != nil {
+		return err
+	}
+	parsed.readBody = func(doc *gmailIndexDoc) string { return readIndexedBody(dir, doc) }
+	items := searchLocalThreads(idx, state, parsed, c.Oldest, loc)
+
+	// Page tokens are result offsets.
+	offset := 0
+	if page := strings.TrimSpace(c.Page); page != "" {
+		if offset, err = strconv.Atoi(page); err != nil || offset < 0 {
+			return usagef("invalid --page %q for --offline (expected an offset)", page)
+		}
+	}
+	offset = min(offset, len(items))
+	items = items[offset:]
+	nextPageToken := ""
+	if !c.All && c.Max > 0 && int64(len(items)) > c.Max {
+		items = items[:c.Max]
+		nextPageToken = strconv.Itoa(offset + len(items))
+	}
+
+	return c.writeThreads(ctx, u, items, nextPageToken)
+}
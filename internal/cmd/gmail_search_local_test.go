@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestParseIndexedMessage_Multipart(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: =?UTF-8?B?SsO8cmdlbg==?= <j@example.com>",
+		"To: team@example.com",
+		"Subject: =?UTF-8?Q?Rechnung_M=C3=A4rz?=",
+		"Date: Mon, 02 Jan 2006 15:04:05 +0000",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="b1"`,
+		"",
+		"--b1",
+		`Content-Type: multipart/alternative; boundary="b2"`,
+		"",
+		"--b2",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"Invoice total: 42 =E2=82=AC",
+		"--b2",
+		"Content-Type: text/html; charset=utf-8",
+		"",
+		"<p>Invoice total: 42 &euro;</p>",
+		"--b2--",
+		"--b1",
+		`Content-Type: application/pdf; name="invoice-03.pdf"`,
+		"Content-Disposition: attachment; filename=\"invoice-03.pdf\"",
+		"Content-Transfer-Encoding: base64",
+		"",
+		"JVBERi0xLjQK",
+		"--b1--",
+		"",
+	}, "\r\n")
+
+	doc := parseIndexedMessage([]byte(raw))
+	if doc.From != "Jürgen <j@example.com>" || doc.Subject != "Rechnung März" || doc.To != "team@example.com" {
+		t.Fatalf("unexpected headers: %+v", doc)
+	}
+	if doc.Snippet != "Invoice total: 42 €" || doc.Terms != "invoice total 42" {
+		t.Fatalf("unexpected body index: %q %q", doc.Snippet, doc.Terms)
+	}
+	if len(doc.Attachments) != 1 || doc.Attachments[0] != "invoice-03.pdf" {
+		t.Fatalf("unexpected attachments: %v", doc.Attachments)
+	}
+}
+
+func TestParseLocalQuery(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	q, err := parseLocalQuery(`from:alice subject:/inv(oice)?\/\d+/ "quarterly report" -is:unread has:attachment newer_than:2w http://x`, now)
+	if err != nil {
+		t.Fatalf("parseLocalQuery: %v", err)
+	}
+	if len(q.terms) != 7 {
+		t.Fatalf("expected 7 terms, got %+v", q.terms)
+	}
+	if q.terms[0].field != "from" || q.terms[0].value != "alice" {
+		t.Fatalf("unexpected from term: %+v", q.terms[0])
+	}
+	if q.terms[1].re == nil || !q.terms[1].re.MatchString("INVOICE/12") {
+		t.Fatalf("unexpected regex term: %+v", q.terms[1])
+	}
+	if q.terms[2].field != "" || q.terms[2].value != "quarterly report" {
+		t.Fatalf("unexpected phrase term: %+v", q.terms[2])
+	}
+	if !q.terms[3].negate || q.terms[3].field != "is" {
+		t.Fatalf("unexpected negated term: %+v", q.terms[3])
+	}
+	if !q.terms[5].after.Equal(now.AddDate(0, 0, -14)) {
+		t.Fatalf("unexpected newer_than: %v", q.terms[5].after)
+	}
+	if q.terms[6].field != "" || q.terms[6].value != "http://x" {
+		t.Fatalf("unknown operators should be free text: %+v", q.terms[6])
+	}
+
+	for _, bad := range []string{"", "is:bogus", "has:drive", "after:yesterday", "subject:/(/", `"open`, "from:"} {
+		if _, err := parseLocalQuery(bad, now); ExitCode(err) != 2 {
+			t.Fatalf("%q: expected usage error, got %v", bad, err)
+		}
+	}
+}
+
+func writeSyncArchive(t *testing.T, messages map[string]string, entries map[string]*gmailSyncEntry) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "cur"), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for id, raw := range messages {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(entries[id].File)), []byte(raw), 0o600); err != nil {
+			t.Fatalf("write message: %v", err)
+		}
+	}
+	state := gmailSyncState{
+		Account:   "a@b.com",
+		HistoryID: "10",
+		Labels:    map[string]string{"INBOX": "INBOX", "UNREAD": "UNREAD", "Label_1": "Work/Projects"},
+		Messages:  entries,
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, gmailSyncStateFile), data, 0o600); err != nil {
+		t.Fatalf("write state: %v", err)
+	}
+	return dir
+}
+
+func runOfflineSearch(t *testing.T, args ...string) (threads []threadItem, next string) {
+	t.Helper()
+
+	out := captureStdout(t, func() {
+		u, uiErr := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+		if uiErr != nil {
+			t.Fatalf("ui.New: %v", uiErr)
+		}
+		ctx := ui.WithUI(context.Background(), u)
+		ctx = outfmt.WithMode(ctx, outfmt.Mode{JSON: true})
+		if err := runKong(t, &GmailSearchCmd{}, args, ctx, &RootFlags{}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+
+	var parsed struct {
+		Threads       []threadItem `json:"threads"`
+		NextPageToken string       `json:"nextPageToken"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	return parsed.Threads, parsed.NextPageToken
+}
+
+func TestGmailSearchCmd_Offline(t *testing.T) {
+	msg := func(from, subject, body string) string {
+		return "From: " + from + "\r\nSubject: " + subject + "\r\nDate: Mon, 02 Jan 2006 15:04:05 +0000\r\n\r\n" + body + "\r\n"
+	}
+	dir := writeSyncArchive(t,
+		map[string]string{
+			"m1": msg("alice@example.com", "Lunch", "see the invoice attached"),
+			"m2": msg("bob@example.com", "Invoice 2024-17", "invoice invoice"),
+			"m3": msg("bob@example.com", "Re: Invoice 2024-17", "thanks"),
+			"m4": msg("carol@example.com", "Party", "no match here"),
+		},
+		map[string]*gmailSyncEntry{
+			"m1": {ThreadID: "t1", File: "cur/1.m1.gog", InternalDate: 1000, LabelIDs: []string{"INBOX", "UNREAD"}},
+			"m2": {ThreadID: "t2", File: "cur/2.m2.gog", InternalDate: 2000, LabelIDs: []string{"INBOX", "Label_1"}},
+			"m3": {ThreadID: "t2", File: "cur/3.m3.gog", InternalDate: 3000, LabelIDs: []string{"INBOX"}},
+			"m4": {ThreadID: "t4", File: "cur/4.m4.gog", InternalDate: 4000, LabelIDs: []string{"INBOX"}},
+		},
+	)
+
+	threads, _ := runOfflineSearch(t, "--archive", dir, "invoice")
+	if len(threads) != 2 || threads[0].ID != "t2" || threads[1].ID != "t1" {
+		t.Fatalf("expected subject matches ranked first, got %+v", threads)
+	}
+	if threads[0].MessageCount != 2 || threads[0].Subject != "Invoice 2024-17" || strings.Join(threads[0].Labels, ",") != "INBOX,Work/Projects" {
+		t.Fatalf("unexpected thread item: %+v", threads[0])
+	}
+
+	threads, _ = runOfflineSearch(t, "--archive", dir, `subject:/invoice \d{4}-\d+/ -from:alice`)
+	if len(threads) != 1 || threads[0].ID != "t2" {
+		t.Fatalf("unexpected regex results: %+v", threads)
+	}
+
+	// Body regexes read the message from the Maildir; the index only has words.
+	threads, _ = runOfflineSearch(t, "--archive", dir, `body:/see the inv\w+ att/`)
+	if len(threads) != 1 || threads[0].ID != "t1" {
+		t.Fatalf("unexpected body regex results: %+v", threads)
+	}
+	threads, _ = runOfflineSearch(t, "--archive", dir, `body:"attached see"`)
+	if len(threads) != 1 || threads[0].ID != "t1" {
+		t.Fatalf("unexpected body word results: %+v", threads)
+	}
+
+	threads, _ = runOfflineSearch(t, "--archive", dir, "label:work-projects")
+	if len(threads) != 1 || threads[0].ID != "t2" {
+		t.Fatalf("unexpected label results: %+v", threads)
+	}
+
+	t.Setenv("GOG_GMAIL_ARCHIVE", dir)
+	threads, next := runOfflineSearch(t, "--offline", "--max", "1", "in:inbox")
+	if len(threads) != 1 || threads[0].ID != "t4" || next != "1" {
+		t.Fatalf("expected newest first with a next page, got %+v next=%q", threads, next)
+	}
+	threads, next = runOfflineSearch(t, "--offline", "--max", "1", "--page", next, "in:inbox")
+	if len(threads) != 1 || threads[0].ID != "t2" || next != "2" {
+		t.Fatalf("unexpected second page: %+v next=%q", threads, next)
+	}
+
+	threads, _ = runOfflineSearch(t, "--offline", "is:unread")
+	if len(threads) != 1 || threads[0].ID != "t1" {
+		t.Fatalf("unexpected is:unread results: %+v", threads)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, gmailIndexFile))
+	if err != nil {
+		t.Fatalf("expected index file: %v", err)
+	}
+	var idx gmailIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != gmailIndexVersion || idx.Docs["m2"].Terms != "invoice" {
+		t.Fatalf("unexpected index: %s (%v)", data, err)
+	}
+}
@@ -8,11 +8,13 @@ import (
 	"net/mail"
 	"os"
 	"strings"
+	"time"
 
 	"google.golang.org/api/gmail/v1"
 
 	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/timeparse"
 	"github.com/steipete/gogcli/internal/tracking"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -34,6 +36,8 @@ type GmailSendCmd struct {
 	Track            bool     `name:"track" help:"Enable open tracking (requires tracking setup)"`
 	TrackSplit       bool     `name:"track-split" help:"Send tracked messages separately per recipient"`
 	Quote            bool     `name:"quote" help:"Include quoted original message in reply (requires --reply-to-message-id or --thread-id)"`
+	At               string   `name:"at" help:"Schedule instead of sending now: saves a draft and queues it for 'gmail queue run' (e.g. 'tomorrow 9am', 'monday 14:30', 'in 2h', RFC3339)"`
+	SmimeP12         string   `name:"smime-p12" help:"Sign with S/MIME using this PKCS#12 key file (password from GOG_SMIME_PASSWORD); signing only, not encryption"`
 }
 
 type sendBatch struct {
@@ -60,6 +64,7 @@ type sendMessageOptions struct {
 	Attachments []mailAttachment
 	Track       bool
 	TrackingCfg *tracking.Config
+	Smime       *smimeSigner
 }
 
 func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -104,6 +109,37 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return fmt.Errorf("--track requires --body-html (pixel must be in HTML)")
 	}
 
+	var sendAt time.Time
+	if at := strings.TrimSpace(c.At); at != "" {
+		if c.Track {
+			return usage("--at cannot be combined with --track")
+		}
+		if strings.TrimSpace(c.SmimeP12) != "" {
+			return usage("--at cannot be combined with --smime-p12")
+		}
+		now := time.Now()
+		sendAt, err = timeparse.ParseAt(at, now, time.Local)
+		if err != nil {
+			return usagef("invalid --at: %v", err)
+		}
+		if !sendAt.After(now) {
+			return usagef("--at %q is in the past (%s)", at, sendAt.Format(time.RFC3339))
+		}
+	}
+
+	var signer *smimeSigner
+	smimePath := strings.TrimSpace(c.SmimeP12)
+	if smimePath != "" {
+		smimePath, err = config.ExpandPath(smimePath)
+		if err != nil {
+			return err
+		}
+		signer, err = loadSmimeSigner(smimePath, os.Getenv(smimePasswordEnv))
+		if err != nil {
+			return err
+		}
+	}
+
 	attachPaths := make([]string, 0, len(c.Attach))
 	for _, p := range c.Attach {
 		expanded, expandErr := config.ExpandPath(p)
@@ -113,7 +149,7 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		attachPaths = append(attachPaths, expanded)
 	}
 
-	if dryRunErr := dryRunExit(ctx, flags, "gmail.send", map[string]any{
+	payload := map[string]any{
 		"to":                  splitCSV(c.To),
 		"cc":                  splitCSV(c.Cc),
 		"bcc":                 splitCSV(c.Bcc),
@@ -128,7 +164,12 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		"attachments":         attachPaths,
 		"track":               c.Track,
 		"track_split":         c.TrackSplit,
-	}); dryRunErr != nil {
+		"smime_p12":           smimePath,
+	}
+	if !sendAt.IsZero() {
+		payload["send_at"] = sendAt.Format(time.RFC3339)
+	}
+	if dryRunErr := dryRunExit(ctx, flags, "gmail.send", payload); dryRunErr != nil {
 		return dryRunErr
 	}
 
@@ -142,48 +183,12 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return err
 	}
 
-	sendAsList, sendAsListErr := listSendAs(ctx, svc)
-
-	// Determine the From address
-	fromAddr := account
-	sendingEmail := account // The email we're sending from (without display name)
-	if fromEmail := strings.TrimSpace(c.From); fromEmail != "" {
-		// Validate that this is a configured and verified send-as alias.
-		var sa *gmail.SendAs
-		if sendAsListErr == nil {
-			sa = findSendAsByEmail(sendAsList, fromEmail)
-			if sa == nil {
-				return fmt.Errorf("invalid --from address %q: not found in send-as settings", fromEmail)
-			}
-		} else {
-			// Fallback: preserve legacy behavior if we cannot list settings.
-			var getErr error
-			sa, getErr = svc.Users.Settings.SendAs.Get("me", fromEmail).Context(ctx).Do()
-			if getErr != nil {
-				return fmt.Errorf("invalid --from address %q: %w", fromEmail, getErr)
-			}
-		}
-
-		if sa.VerificationStatus != gmailVerificationAccepted {
-			return fmt.Errorf("--from address %q is not verified (status: %s)", fromEmail, sa.VerificationStatus)
-		}
-
-		sendingEmail = fromEmail
-		fromAddr = fromEmail
-
-		if displayName := strings.TrimSpace(sa.DisplayName); displayName != "" {
-			fromAddr = displayName + " <" + fromEmail + ">"
-		}
-	} else {
-		// No --from specified: best-effort look up the primary account's display name.
-		displayName := ""
-		if sendAsListErr == nil {
-			displayName = primaryDisplayNameFromSendAsList(sendAsList, account)
-		}
-		if displayName != "" {
-			fromAddr = displayName + " <" + account + ">"
-		}
-		// If lookup fails, we just use the plain email address (no error)
+	fromAddr, sendingEmail, err := resolveSendFrom(ctx, svc, account, c.From)
+	if err != nil {
+		return err
+	}
+	if signer != nil && !signer.covers(sendingEmail) {
+		u.Err().Printf("Warning: S/MIME certificate is not issued for %s; recipients will see an invalid signature", sendingEmail)
 	}
 
 	// Fetch reply info (includes recipient headers for reply-all, and body for quoting)
@@ -216,6 +221,33 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 
 	bccRecipients := splitCSV(c.Bcc)
 
+	if !sendAt.IsZero() {
+		entry, schedErr := scheduleGmailDraft(ctx, svc, account, sendAt, draftComposeInput{
+			To:               strings.Join(toRecipients, ","),
+			Cc:               strings.Join(ccRecipients, ","),
+			Bcc:              strings.Join(bccRecipients, ","),
+			Subject:          c.Subject,
+			Body:             body,
+			BodyHTML:         htmlBody,
+			ReplyToMessageID: replyToMessageID,
+			ReplyToThreadID:  threadID,
+			ReplyTo:          c.ReplyTo,
+			Attach:           attachPaths,
+			From:             c.From,
+		})
+		if schedErr != nil {
+			return schedErr
+		}
+		if !outfmt.IsJSON(ctx) {
+			u.Err().Printf("Scheduled for %s; sent by 'gog gmail queue run' (run it from cron or a systemd timer)", sendAt.Format("2006-01-02 15:04 MST"))
+		}
+		return writeResult(ctx, u,
+			kv("scheduled", true),
+			kv("draftId", entry.DraftID),
+			kv("sendAt", entry.SendAt),
+		)
+	}
+
 	atts := make([]mailAttachment, 0, len(attachPaths))
 	for _, p := range attachPaths {
 		atts = append(atts, mailAttachment{Path: p})
@@ -240,6 +272,7 @@ func (c *GmailSendCmd) Run(ctx context.Context, flags *RootFlags) error {
 		Attachments: atts,
 		Track:       c.Track,
 		TrackingCfg: trackingCfg,
+		Smime:       signer,
 	}, batches)
 	if err != nil {
 		return err
@@ -269,6 +302,56 @@ func (c *GmailSendCmd) resolveTrackingConfig(account string, toRecipients, ccRec
 	return trackingCfg, nil
 }
 
+// resolveSendFrom returns the From header (with display name when known) and the bare
+// sending address. A --from address must be a verified send-as alias.
+func resolveSendFrom(ctx context.Context, svc *gmail.Service, account, from string) (string, string, error) {
+	sendAsList, sendAsListErr := listSendAs(ctx, svc)
+
+	// Determine the From address
+	fromAddr := account
+	sendingEmail := account // The email we're sending from (without display name)
+	if fromEmail := strings.TrimSpace(from); fromEmail != "" {
+		// Validate that this is a configured and verified send-as alias.
+		var sa *gmail.SendAs
+		if sendAsListErr == nil {
+			sa = findSendAsByEmail(sendAsList, fromEmail)
+			if sa == nil {
+				return "", "", fmt.Errorf("invalid --from address %q: not found in send-as settings", fromEmail)
+			}
+		} else {
+			// Fallback: preserve legacy behavior if we cannot list settings.
+			var getErr error
+			sa, getErr = svc.Users.Settings.SendAs.Get("me", fromEmail).Context(ctx).Do()
+			if getErr != nil {
+				return "", "", fmt.Errorf("invalid --from address %q: %w", fromEmail, getErr)
+			}
+		}
+
+		if sa.VerificationStatus != gmailVerificationAccepted {
+			return "", "", fmt.Errorf("--from address %q is not verified (status: %s)", fromEmail, sa.VerificationStatus)
+		}
+
+		sendingEmail = fromEmail
+		fromAddr = fromEmail
+
+		if displayName := strings.TrimSpace(sa.DisplayName); displayName != "" {
+			fromAddr = displayName + " <" + fromEmail + ">"
+		}
+	} else {
+		// No --from specified: best-effort look up the primary account's display name.
+		displayName := ""
+		if sendAsListErr == nil {
+			displayName = primaryDisplayNameFromSendAsList(sendAsList, account)
+		}
+		if displayName != "" {
+			fromAddr = displayName + " <" + account + ">"
+		}
+		// If lookup fails, we just use the plain email address (no error)
+	}
+
+	return fromAddr, sendingEmail, nil
+}
+
 func listSendAs(ctx context.Context, svc *gmail.Service) ([]*gmail.SendAs, error) {
 	if svc == nil {
 		return nil, nil
@@ -384,7 +467,7 @@ func sendGmailBatches(ctx context.Context, svc *gmail.Service, opts sendMessageO
 			InReplyTo:   reply.InReplyTo,
 			References:  reply.References,
 			Attachments: opts.Attachments,
-		}, nil)
+		}, &rfc822Config{smime: opts.Smime})
 		if err != nil {
 			return nil, err
 		}
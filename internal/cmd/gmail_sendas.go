@@ -2,15 +2,18 @@ package cmd
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"google.golang.org/api/gmail/v1"
 
+	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/ui"
 )
@@ -339,3 +342,218 @@ func (c *GmailSendAsUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flag
 	u.Out().Printf("Updated send-as alias: %s", updated.SendAsEmail)
 	return nil
 }
+
+type GmailSmimeCmd struct {
+	List       GmailSmimeListCmd       `cmd:"" name:"list" aliases:"ls" help:"List S/MIME certificates of a send-as alias"`
+	Insert     GmailSmimeInsertCmd     `cmd:"" name:"insert" aliases:"add,upload" help:"Upload a PKCS#12 S/MIME key for a send-as alias"`
+	Delete     GmailSmimeDeleteCmd     `cmd:"" name:"delete" aliases:"rm,del,remove" help:"Delete an S/MIME certificate"`
+	SetDefault GmailSmimeSetDefaultCmd `cmd:"" name:"set-default" aliases:"default" help:"Make an S/MIME certificate the default for a send-as alias"`
+}
+
+// smimeSendAs returns the send-as address the S/MIME commands act on,
+// defaulting to the account itself.
+func smimeSendAs(account, sendAs string) string {
+	if sendAs = strings.TrimSpace(sendAs); sendAs != "" {
+		return sendAs
+	}
+	return account
+}
+
+type GmailSmimeListCmd struct {
+	SendAs string `name:"send-as" help:"Send-as email (default: the account)"`
+}
+
+func (c *GmailSmimeListCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	resp, err := svc.Users.Settings.SendAs.SmimeInfo.List("me", smimeSendAs(account, c.SendAs)).Do()
+	if err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"smimeInfo": resp.SmimeInfo})
+	}
+
+	if len(resp.SmimeInfo) == 0 {
+		u.Err().Println("No S/MIME certificates")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tISSUER\tEXPIRES\tDEFAULT")
+	for _, info := range resp.SmimeInfo {
+		isDefault := ""
+		if info.IsDefault {
+			isDefault = sendAsYes
+		}
+		expires := ""
+		if info.Expiration > 0 {
+			expires = time.UnixMilli(info.Expiration).Format("2006-01-02")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Id, info.IssuerCn, expires, isDefault)
+	}
+	_ = tw.Flush()
+	return nil
+}
+
+type GmailSmimeInsertCmd struct {
+	File        string `arg:"" name:"file" help:"PKCS#12 (.p12/.pfx) file with the key and certificate chain (password from GOG_SMIME_PASSWORD)"`
+	SendAs      string `name:"send-as" help:"Send-as email (default: the account)"`
+	MakeDefault bool   `name:"make-default" help:"Make this the default certificate for the send-as alias"`
+}
+
+func (c *GmailSmimeInsertCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	path, err := config.ExpandPath(strings.TrimSpace(c.File))
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided path
+	if err != nil {
+		return err
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	sendAsEmail := smimeSendAs(account, c.SendAs)
+
+	if dryRunErr := dryRunExit(ctx, flags, "gmail.smime.insert", map[string]any{
+		"send_as":      sendAsEmail,
+		"file":         path,
+		"make_default": c.MakeDefault,
+	}); dryRunErr != nil {
+		return dryRunErr
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	info, err := svc.Users.Settings.SendAs.SmimeInfo.Insert("me", sendAsEmail, &gmail.SmimeInfo{
+		Pkcs12:               base64.URLEncoding.EncodeToString(data),
+		EncryptedKeyPassword: os.Getenv(smimePasswordEnv),
+	}).Do()
+	if err != nil {
+		return err
+	}
+	if c.MakeDefault {
+		if err := svc.Users.Settings.SendAs.SmimeInfo.SetDefault("me", sendAsEmail, info.Id).Do(); err != nil {
+			return err
+		}
+		info.IsDefault = true
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"smimeInfo": info})
+	}
+
+	u.Out().Printf("id\t%s", info.Id)
+	u.Out().Printf("issuer\t%s", info.IssuerCn)
+	if info.Expiration > 0 {
+		u.Out().Printf("expires\t%s", time.UnixMilli(info.Expiration).Format(time.RFC3339))
+	}
+	u.Out().Printf("is_default\t%t", info.IsDefault)
+	return nil
+}
+
+type GmailSmimeDeleteCmd struct {
+	ID     string `arg:"" stdin:"" name:"id" help:"S/MIME certificate ID"`
+	SendAs string `name:"send-as" help:"Send-as email (default: the account)"`
+}
+
+func (c *GmailSmimeDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	id := strings.TrimSpace(c.ID)
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	sendAsEmail := smimeSendAs(account, c.SendAs)
+
+	if confirmErr := confirmDestructive(ctx, flags, fmt.Sprintf("delete S/MIME certificate %s of %s", id, sendAsEmail)); confirmErr != nil {
+		return confirmErr
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.Users.Settings.SendAs.SmimeInfo.Delete("me", sendAsEmail, id).Do(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"id":      id,
+			"sendAs":  sendAsEmail,
+			"deleted": true,
+		})
+	}
+
+	u.Out().Printf("Deleted S/MIME certificate: %s", id)
+	return nil
+}
+
+type GmailSmimeSetDefaultCmd struct {
+	ID     string `arg:"" stdin:"" name:"id" help:"S/MIME certificate ID"`
+	SendAs string `name:"send-as" help:"Send-as email (default: the account)"`
+}
+
+func (c *GmailSmimeSetDefaultCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	id := strings.TrimSpace(c.ID)
+	if id == "" {
+		return errors.New("id is required")
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	sendAsEmail := smimeSendAs(account, c.SendAs)
+
+	if dryRunErr := dryRunExit(ctx, flags, "gmail.smime.set_default", map[string]any{
+		"send_as": sendAsEmail,
+		"id":      id,
+	}); dryRunErr != nil {
+		return dryRunErr
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.Users.Settings.SendAs.SmimeInfo.SetDefault("me", sendAsEmail, id).Do(); err != nil {
+		return err
+	}
+
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
+			"id":        id,
+			"sendAs":    sendAsEmail,
+			"isDefault": true,
+		})
+	}
+
+	u.Out().Printf("Default S/MIME certificate for %s: %s", sendAsEmail, id)
+	return nil
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+const smimePasswordEnv = "GOG_SMIME_PASSWORD" //nolint:gosec // env var name, not a credential
+
+var (
+	oidCMSData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidCMSSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidAttrContentType  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrDigest       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttrSigningTime  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidSHA256           = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA256  = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	errSmimeKeyMismatch = errors.New("PKCS#12 file has no certificate for its private key")
+)
+
+// smimeSigner signs outgoing messages with a local S/MIME key (RFC 8551).
+type smimeSigner struct {
+	key   crypto.Signer
+	cert  *x509.Certificate
+	chain []*x509.Certificate
+	now   func() time.Time
+}
+
+// loadSmimeSigner reads a PKCS#12 bundle holding the signing key, its
+// certificate and optionally the issuing chain.
+func loadSmimeSigner(path, password string) (*smimeSigner, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided path
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := pkcs12.ToPEM(data, password)
+	if err != nil {
+		return nil, smimeP12Error(path, err)
+	}
+
+	var key crypto.Signer
+	var certs []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "PRIVATE KEY":
+			// pkcs12.ToPEM emits PKCS#1 for RSA and SEC 1 for EC keys.
+			if rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes); rsaErr == nil {
+				key = rsaKey
+			} else if ecKey, ecErr := x509.ParseECPrivateKey(block.Bytes); ecErr == nil {
+				key = ecKey
+			} else {
+				return nil, fmt.Errorf("read %s: unsupported private key", path)
+			}
+		case "CERTIFICATE":
+			cert, certErr := x509.ParseCertificate(block.Bytes)
+			if certErr != nil {
+				return nil, fmt.Errorf("read %s: %w", path, certErr)
+			}
+			certs = append(certs, cert)
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("read %s: no private key", path)
+	}
+	return newSmimeSigner(key, certs)
+}
+
+// newSmimeSigner picks the certificate matching key; the rest are sent as
+// the chain so recipients can build a path to a trusted root.
+// smimeP12Error explains PKCS#12 decode failures. The decoder only handles the
+// legacy 3DES/RC2 + SHA-1 format; OpenSSL 3 exports AES and SHA-256 by default.
+func smimeP12Error(path string, err error) error {
+	var unsupported pkcs12.NotImplementedError
+	switch {
+	case errors.As(err, &unsupported):
+		return fmt.Errorf("read %s: %w; re-export it in the legacy format with `openssl pkcs12 -export -legacy`", path, err)
+	case errors.Is(err, pkcs12.ErrIncorrectPassword), errors.Is(err, pkcs12.ErrDecryption):
+		return fmt.Errorf("read %s: wrong password (set %s)", path, smimePasswordEnv)
+	default:
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+}
+
+func newSmimeSigner(key crypto.Signer, certs []*x509.Certificate) (*smimeSigner, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+	default:
+		return nil, fmt.Errorf("unsupported S/MIME key type %T", key)
+	}
+	s := &smimeSigner{key: key, now: time.Now}
+	for _, cert := range certs {
+		if s.cert == nil {
+			if pub, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); ok && pub.Equal(key.Public()) {
+				s.cert = cert
+				continue
+			}
+		}
+		s.chain = append(s.chain, cert)
+	}
+	if s.cert == nil {
+		return nil, errSmimeKeyMismatch
+	}
+	return s, nil
+}
+
+// covers reports whether the certificate is issued for email.
+func (s *smimeSigner) covers(email string) bool {
+	return slices.ContainsFunc(s.cert.EmailAddresses, func(e string) bool {
+		return strings.EqualFold(e, strings.TrimSpace(email))
+	})
+}
+
+// sign turns a complete RFC822 message into multipart/signed (RFC 1847):
+// the Content-* headers and body become the signed entity, everything else
+// stays on the outer message.
+func (s *smimeSigner) sign(raw []byte) ([]byte, error) {
+	head, body, ok := bytes.Cut(raw, []byte("\r\n\r\n"))
+	if !ok {
+		return nil, errors.New("sign message: missing header separator")
+	}
+
+	var outer, entity bytes.Buffer
+	toEntity := false
+	for _, line := range strings.Split(string(head), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			toEntity = strings.HasPrefix(strings.ToLower(line), "content-")
+		}
+		if toEntity {
+			entity.WriteString(line + "\r\n")
+		} else {
+			outer.WriteString(line + "\r\n")
+		}
+	}
+	entity.WriteString("\r\n")
+	entity.Write(body)
+
+	signature, err := s.detachedSignature(entity.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("sign message: %w", err)
+	}
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	writeHeader(&outer, "Content-Type", fmt.Sprintf("multipart/signed; protocol=\"application/pkcs7-signature\"; micalg=sha-256; boundary=%q", boundary))
+	outer.WriteString("\r\nThis is a cryptographically signed message in MIME format.\r\n\r\n")
+	fmt.Fprintf(&outer, "--%s\r\n", boundary)
+	outer.Write(entity.Bytes())
+	fmt.Fprintf(&outer, "\r\n--%s\r\n", boundary)
+	outer.WriteString("Content-Type: application/pkcs7-signature; name=\"smime.p7s\"\r\n")
+	outer.WriteString("Content-Transfer-Encoding: base64\r\n")
+	outer.WriteString("Content-Disposition: attachment; filename=\"smime.p7s\"\r\n\r\n")
+	outer.WriteString(wrapBase64(signature))
+	fmt.Fprintf(&outer, "\r\n--%s--\r\n", boundary)
+	return outer.Bytes(), nil
+}
+
+type cmsIssuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type cmsSignerInfo struct {
+	Version     int
+	SID         cmsIssuerAndSerial
+	DigestAlg   pkix.AlgorithmIdentifier
+	SignedAttrs asn1.RawValue
+	SigAlg      pkix.AlgorithmIdentifier
+	Signature   []byte
+}
+
+type cmsEncapContent struct {
+	Type asn1.ObjectIdentifier
+}
+
+type cmsSignedData struct {
+	Version     int
+	DigestAlgs  asn1.RawValue
+	Encap       cmsEncapContent
+	Certs       asn1.RawValue
+	SignerInfos asn1.RawValue
+}
+
+type cmsContentInfo struct {
+	Type    asn1.ObjectIdentifier
+	Content asn1.RawValue
+}
+
+type cmsAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+type cmsAttributeValue struct {
+	Type  asn1.ObjectIdentifier
+	Value any
+}
+
+// detachedSignature builds a DER CMS SignedData over content with the
+// contentType, signingTime and messageDigest signed attributes.
+func (s *smimeSigner) detachedSignature(content []byte) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	attrs, err := cmsSignedAttributes(
+		cmsAttributeValue{Type: oidAttrContentType, Value: oidCMSData},
+		cmsAttributeValue{Type: oidAttrSigningTime, Value: s.now().UTC()},
+		cmsAttributeValue{Type: oidAttrDigest, Value: digest[:]},
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The signature covers the attributes re-tagged as a universal SET.
+	toSign, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: attrs})
+	if err != nil {
+		return nil, err
+	}
+	attrsDigest := sha256.Sum256(toSign)
+	signature, err := s.key.Sign(rand.Reader, attrsDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	sha256Alg := pkix.AlgorithmIdentifier{Algorithm: oidSHA256}
+	sigAlg := pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}
+	if _, ok := s.key.(*rsa.PrivateKey); ok {
+		sigAlg = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}
+	}
+	signerInfo, err := asn1.Marshal(cmsSignerInfo{
+		Version:     1,
+		SID:         cmsIssuerAndSerial{Issuer: asn1.RawValue{FullBytes: s.cert.RawIssuer}, Serial: s.cert.SerialNumber},
+		DigestAlg:   sha256Alg,
+		SignedAttrs: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+		SigAlg:      sigAlg,
+		Signature:   signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	digestAlg, err := asn1.Marshal(sha256Alg)
+	if err != nil {
+		return nil, err
+	}
+	var certs []byte
+	for _, cert := range append([]*x509.Certificate{s.cert}, s.chain...) {
+		certs = append(certs, cert.Raw...)
+	}
+
+	signedData, err := asn1.Marshal(cmsSignedData{
+		Version:     1,
+		DigestAlgs:  asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: digestAlg},
+		Encap:       cmsEncapContent{Type: oidCMSData},
+		Certs:       asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: signerInfo},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(cmsContentInfo{
+		Type:    oidCMSSignedData,
+		Content: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// cmsSignedAttributes encodes single-valued attributes, sorted as DER
+// requires for a SET OF.
+func cmsSignedAttributes(attrs ...cmsAttributeValue) ([]byte, error) {
+	encoded := make([][]byte, 0, len(attrs))
+	for _, a := range attrs {
+		inner, err := asn1.Marshal(a.Value)
+		if err != nil {
+			return nil, err
+		}
+		attr, err := asn1.Marshal(cmsAttribute{Type: a.Type, Values: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: inner}})
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, attr)
+	}
+	slices.SortFunc(encoded, bytes.Compare)
+	return bytes.Join(encoded, nil), nil
+}
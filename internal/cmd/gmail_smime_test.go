@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func testSmimeCert(t *testing.T, key crypto.Signer, email string) *x509.Certificate {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(42),
+		Subject:        pkix.Name{CommonName: email},
+		EmailAddresses: []string{email},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	return cert
+}
+
+func TestSmimeSignedMessage(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		key crypto.Signer
+		alg x509.SignatureAlgorithm
+	}{
+		"rsa":   {rsaKey, x509.SHA256WithRSA},
+		"ecdsa": {ecKey, x509.ECDSAWithSHA256},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cert := testSmimeCert(t, tc.key, "a@b.com")
+			other := testSmimeCert(t, rsaKey, "ca@b.com")
+			if name == "rsa" {
+				other = testSmimeCert(t, ecKey, "ca@b.com")
+			}
+			signer, err := newSmimeSigner(tc.key, []*x509.Certificate{other, cert})
+			if err != nil {
+				t.Fatalf("newSmimeSigner: %v", err)
+			}
+			if signer.cert != cert || len(signer.chain) != 1 || !signer.covers("A@B.com") || signer.covers("x@b.com") {
+				t.Fatalf("unexpected signer: %+v", signer)
+			}
+
+			raw, err := buildRFC822(mailOptions{
+				From:    "a@b.com",
+				To:      []string{"c@d.com"},
+				Subject: "Signed",
+				Body:    "Hello",
+			}, &rfc822Config{smime: signer})
+			if err != nil {
+				t.Fatalf("buildRFC822: %v", err)
+			}
+			msg := string(raw)
+			head, _, _ := strings.Cut(msg, "\r\n\r\n")
+			if !strings.Contains(head, "Subject: Signed") || strings.Contains(head, "text/plain") {
+				t.Fatalf("unexpected outer headers:\n%s", head)
+			}
+			_, params, err := mime.ParseMediaType(headerFromRaw(head, "Content-Type"))
+			if err != nil || params["protocol"] != "application/pkcs7-signature" || params["micalg"] != "sha-256" {
+				t.Fatalf("unexpected content type: %v %v", params, err)
+			}
+			delim := "--" + params["boundary"] + "\r\n"
+			_, rest, _ := strings.Cut(msg, delim)
+			entity, sigPart, ok := strings.Cut(rest, "\r\n"+delim)
+			if !ok || !strings.HasPrefix(entity, "Content-Type: text/plain") || !strings.HasSuffix(entity, "Hello\r\n") {
+				t.Fatalf("unexpected signed entity %q", entity)
+			}
+			_, sigB64, _ := strings.Cut(sigPart, "\r\n\r\n")
+			sigB64, _, _ = strings.Cut(sigB64, "\r\n--")
+			der, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(sigB64, "\r\n", ""))
+			if err != nil {
+				t.Fatalf("decode signature: %v", err)
+			}
+
+			var ci cmsContentInfo
+			if _, err := asn1.Unmarshal(der, &ci); err != nil || !ci.Type.Equal(oidCMSSignedData) {
+				t.Fatalf("content info: %v %v", ci.Type, err)
+			}
+			var sd cmsSignedData
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+				t.Fatalf("signed data: %v", err)
+			}
+			if !bytes.Equal(sd.Certs.Bytes, append(append([]byte{}, cert.Raw...), other.Raw...)) {
+				t.Fatalf("expected the signing certificate first, then the chain")
+			}
+			var si cmsSignerInfo
+			if _, err := asn1.Unmarshal(sd.SignerInfos.Bytes, &si); err != nil {
+				t.Fatalf("signer info: %v", err)
+			}
+			if si.SID.Serial.Int64() != 42 || !bytes.Equal(si.SID.Issuer.FullBytes, cert.RawIssuer) {
+				t.Fatalf("unexpected signer id: %+v", si.SID)
+			}
+
+			digest := sha256.Sum256([]byte(entity))
+			rest2 := si.SignedAttrs.Bytes
+			found := false
+			for len(rest2) > 0 {
+				var attr cmsAttribute
+				if rest2, err = asn1.Unmarshal(rest2, &attr); err != nil {
+					t.Fatalf("attribute: %v", err)
+				}
+				if attr.Type.Equal(oidAttrDigest) {
+					var got []byte
+					if _, err := asn1.Unmarshal(attr.Values.Bytes, &got); err != nil || !bytes.Equal(got, digest[:]) {
+						t.Fatalf("message digest mismatch: %x vs %x (%v)", got, digest, err)
+					}
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("missing messageDigest attribute")
+			}
+			signed, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: si.SignedAttrs.Bytes})
+			if err != nil {
+				t.Fatalf("marshal attributes: %v", err)
+			}
+			if err := cert.CheckSignature(tc.alg, signed, si.Signature); err != nil {
+				t.Fatalf("signature does not verify: %v", err)
+			}
+		})
+	}
+
+	if _, err := newSmimeSigner(rsaKey, []*x509.Certificate{testSmimeCert(t, ecKey, "a@b.com")}); err == nil {
+		t.Fatalf("expected an error for a certificate that does not match the key")
+	}
+}
+
+func TestSmimeSignedEntityIs7bit(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	signer, err := newSmimeSigner(key, []*x509.Certificate{testSmimeCert(t, key, "a@b.com")})
+	if err != nil {
+		t.Fatalf("newSmimeSigner: %v", err)
+	}
+	raw, err := buildRFC822(mailOptions{
+		From:     "a@b.com",
+		To:       []string{"c@d.com"},
+		Subject:  "Signed",
+		Body:     "Grüße",
+		BodyHTML: "<p>Grüße</p>",
+	}, &rfc822Config{smime: signer})
+	if err != nil {
+		t.Fatalf("buildRFC822: %v", err)
+	}
+	if !isASCII(string(raw)) || strings.Count(string(raw), "Content-Transfer-Encoding: quoted-printable") != 2 {
+		t.Fatalf("expected quoted-printable text parts in the signed entity:\n%s", raw)
+	}
+}
+
+func headerFromRaw(head, name string) string {
+	for _, line := range strings.Split(head, "\r\n") {
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.EqualFold(k, name) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func TestSmimeP12Error(t *testing.T) {
+	// What golang.org/x/crypto/pkcs12 returns for an OpenSSL 3 default export.
+	modern := pkcs12.NotImplementedError("pkcs12: unknown digest algorithm: 2.16.840.1.101.3.4.2.1")
+	if err := smimeP12Error("me.p12", modern); !strings.Contains(err.Error(), "openssl pkcs12 -export -legacy") {
+		t.Fatalf("expected a re-export hint, got %v", err)
+	}
+	if err := smimeP12Error("me.p12", pkcs12.ErrIncorrectPassword); !strings.Contains(err.Error(), "wrong password") || strings.Contains(err.Error(), "-legacy") {
+		t.Fatalf("expected a password error, got %v", err)
+	}
+}
+
+func TestGmailSmimeCmds(t *testing.T) {
+	t.Setenv(smimePasswordEnv, "s3cret")
+	p12 := filepath.Join(t.TempDir(), "me.p12")
+	if err := os.WriteFile(p12, []byte{0xfb, 0xff, 0x01}, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var calls []string
+	var inserted gmail.SmimeInfo
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/settings/sendAs/")
+		calls = append(calls, r.Method+" "+path)
+		switch {
+		case r.Method == http.MethodGet && path == "alias@b.com/smimeInfo":
+			_ = json.NewEncoder(w).Encode(map[string]any{"smimeInfo": []map[string]any{
+				{"id": "s1", "issuerCn": "Example CA", "expiration": "1767225600000", "isDefault": true},
+			}})
+		case r.Method == http.MethodPost && path == "a@b.com/smimeInfo":
+			_ = json.NewDecoder(r.Body).Decode(&inserted)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s2", "issuerCn": "Example CA"})
+		case r.Method == http.MethodPost && path == "a@b.com/smimeInfo/s2/setDefault":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete && path == "a@b.com/smimeInfo/s1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := outfmt.WithMode(ui.WithUI(context.Background(), u), outfmt.Mode{JSON: true})
+	flags := &RootFlags{Account: "a@b.com", Force: true}
+
+	out := captureStdout(t, func() {
+		if err := runKong(t, &GmailSmimeListCmd{}, []string{"--send-as", "alias@b.com"}, ctx, flags); err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"issuerCn": "Example CA"`) {
+		t.Fatalf("unexpected list output: %s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runKong(t, &GmailSmimeInsertCmd{}, []string{p12, "--make-default"}, ctx, flags); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	})
+	if inserted.Pkcs12 != "-_8B" || inserted.EncryptedKeyPassword != "s3cret" {
+		t.Fatalf("unexpected insert request: %+v", inserted)
+	}
+	if !strings.Contains(out, `"isDefault": true`) {
+		t.Fatalf("unexpected insert output: %s", out)
+	}
+
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &GmailSmimeDeleteCmd{}, []string{"s1"}, ctx, flags); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+	})
+
+	want := "GET alias@b.com/smimeInfo,POST a@b.com/smimeInfo,POST a@b.com/smimeInfo/s2/setDefault,DELETE a@b.com/smimeInfo/s1"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("unexpected calls:\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	gmailStatsPageSize = 500
+	// gmailStatsPartDepth is how many MIME levels the part fetch selects, enough
+	// for attachments inside e.g. multipart/signed > mixed > related > alternative.
+	gmailStatsPartDepth = 6
+)
+
+// gmailStatsPartFields selects filenames and sizes gmailStatsPartDepth levels deep.
+var gmailStatsPartFields = "id,payload(" + strings.Repeat("filename,body/size,parts(", gmailStatsPartDepth-1) +
+	"filename,body/size" + strings.Repeat(")", gmailStatsPartDepth)
+
+type GmailStatsCmd struct {
+	Query           []string `arg:"" optional:"" name:"query" help:"Search query (default: all mail)"`
+	Max             int      `name:"max" aliases:"limit" help:"Scan at most this many messages (0 = all)" default:"5000"`
+	Top             int      `name:"top" help:"Rows per ranking" default:"10"`
+	Period          string   `name:"period" help:"Bucket for volume over time: day|week|month|year" enum:"day,week,month,year" default:"month"`
+	SkipAttachments bool     `name:"skip-attachments" help:"Skip the extra fetch that measures attachment bytes"`
+	Timezone        string   `name:"timezone" short:"z" help:"Timezone for volume buckets (IANA name). Default: local"`
+	Local           bool     `name:"local" help:"Use local timezone (default behavior, useful to override --timezone)"`
+}
+
+type gmailStatsEntry struct {
+	Name            string `json:"name"`
+	Messages        int    `json:"messages"`
+	Bytes           int64  `json:"bytes"`
+	AttachmentBytes int64  `json:"attachmentBytes,omitempty"`
+}
+
+type gmailStatsMessage struct {
+	ID       string `json:"id"`
+	ThreadID string `json:"threadId,omitempty"`
+	Date     string `json:"date,omitempty"`
+	From     string `json:"from,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	Bytes    int64  `json:"bytes"`
+}
+
+type gmailStatsReport struct {
+	Query               string              `json:"query,omitempty"`
+	Messages            int                 `json:"messages"`
+	Truncated           bool                `json:"truncated"`
+	Bytes               int64               `json:"bytes"`
+	AttachmentBytes     int64               `json:"attachmentBytes"`
+	Period              string              `json:"period"`
+	TopSenders          []gmailStatsEntry   `json:"topSenders"`
+	TopDomains          []gmailStatsEntry   `json:"topDomains"`
+	Volume              []gmailStatsEntry   `json:"volume"`
+	Largest             []gmailStatsMessage `json:"largest"`
+	AttachmentsBySender []gmailStatsEntry   `json:"attachmentsBySender"`
+	Labels              []gmailStatsEntry   `json:"labels"`
+}
+
+// gmailStats accumulates per-key totals while pages of messages stream in.
+type gmailStats struct {
+	period   string
+	loc      *time.Location
+	idToName map[string]string
+
+	messages        int
+	bytes           int64
+	attachmentBytes int64
+	senders         map[string]*gmailStatsEntry
+	domains         map[string]*gmailStatsEntry
+	volume          map[string]*gmailStatsEntry
+	labels          map[string]*gmailStatsEntry
+	largest         []gmailStatsMessage
+	top             int
+}
+
+var errGmailStatsLimit = errors.New("gmail stats: message limit reached")
+
+func (c *GmailStatsCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	if c.Max < 0 {
+		return usage("--max must be >= 0")
+	}
+	if c.Top <= 0 {
+		return usage("--top must be > 0")
+	}
+	query := strings.TrimSpace(strings.Join(c.Query, " "))
+	loc, err := resolveOutputLocation(c.Timezone, c.Local)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+	idToName, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+
+	stats := newGmailStats(c.Period, loc, idToName, c.Top)
+	truncated := false
+	nextToken := ""
+	fetch := func(pageToken string) ([]*gmail.Message, string, error) {
+		call := svc.Users.Messages.List("me").
+			MaxResults(gmailStatsPageSize).
+			Fields("messages(id),nextPageToken").
+			Context(ctx)
+		if query != "" {
+			call = call.Q(query)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, callErr := call.Do()
+		if callErr != nil {
+			return nil, "", callErr
+		}
+		nextToken = resp.NextPageToken
+		return resp.Messages, resp.NextPageToken, nil
+	}
+	emit := func(page []*gmail.Message) error {
+		ids := make([]string, 0, len(page))
+		for _, m := range page {
+			if m != nil && m.Id != "" {
+				ids = append(ids, m.Id)
+			}
+		}
+		if c.Max > 0 && stats.messages+len(ids) >= c.Max {
+			truncated = stats.messages+len(ids) > c.Max || nextToken != ""
+			ids = ids[:c.Max-stats.messages]
+		}
+		if err := c.scan(ctx, svc, stats, ids); err != nil {
+			return err
+		}
+		u.Err().Printf("Scanned %d messages", stats.messages)
+		if c.Max > 0 && stats.messages >= c.Max {
+			return errGmailStatsLimit
+		}
+		return nil
+	}
+	if err := streamAllPages("", fetch, emit); err != nil && !errors.Is(err, errGmailStatsLimit) {
+		return err
+	}
+
+	report := stats.report(c.Top)
+	report.Query = query
+	report.Truncated = truncated
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, report)
+	}
+	printGmailStats(ctx, u, report)
+	return nil
+}
+
+func (c *GmailStatsCmd) scan(ctx context.Context, svc *gmail.Service, stats *gmailStats, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	meta := gmailMetadataQuery("From", "Subject", "Date", "Content-Type")
+	meta.Set("fields", "id,threadId,labelIds,sizeEstimate,internalDate,payload(headers)")
+	msgs, err := fetchExistingGmailMessages(ctx, svc, ids, meta)
+	if err != nil {
+		return err
+	}
+
+	attachments := map[string]int64{}
+	if !c.SkipAttachments {
+		// Only single-part text messages cannot carry attachments; every other
+		// structure (mixed, signed, related, a bare application/pdf, …) is walked.
+		var structured []string
+		for _, m := range msgs {
+			if ct := normalizeMimeType(headerValue(m.Payload, "Content-Type")); ct != "" && !strings.HasPrefix(ct, "text/") {
+				structured = append(structured, m.Id)
+			}
+		}
+		parts, err := fetchExistingGmailMessages(ctx, svc, structured, url.Values{"format": {gmailFormatFull}, "fields": {gmailStatsPartFields}})
+		if err != nil {
+			return err
+		}
+		for _, m := range parts {
+			attachments[m.Id] = partAttachmentBytes(m.Payload)
+		}
+	}
+	for _, m := range msgs {
+		stats.add(m, attachments[m.Id])
+	}
+	return nil
+}
+
+func partAttachmentBytes(p *gmail.MessagePart) int64 {
+	if p == nil {
+		return 0
+	}
+	var total int64
+	if strings.TrimSpace(p.Filename) != "" && p.Body != nil {
+		total += p.Body.Size
+	}
+	for _, part := range p.Parts {
+		total += partAttachmentBytes(part)
+	}
+	return total
+}
+
+func newGmailStats(period string, loc *time.Location, idToName map[string]string, top int) *gmailStats {
+	if loc == nil {
+		loc = time.Local
+	}
+	return &gmailStats{
+		period:   period,
+		loc:      loc,
+		idToName: idToName,
+		senders:  map[string]*gmailStatsEntry{},
+		domains:  map[string]*gmailStatsEntry{},
+		volume:   map[string]*gmailStatsEntry{},
+		labels:   map[string]*gmailStatsEntry{},
+		top:      top,
+	}
+}
+
+func (s *gmailStats) add(m *gmail.Message, attachmentBytes int64) {
+	s.messages++
+	s.bytes += m.SizeEstimate
+	s.attachmentBytes += attachmentBytes
+
+	from := headerValue(m.Payload, "From")
+	sender := statsSenderAddress(from)
+	bump(s.senders, sender, m.SizeEstimate, attachmentBytes)
+	domain := "(unknown)"
+	if at := strings.LastIndex(sender, "@"); at >= 0 {
+		domain = sender[at+1:]
+	}
+	bump(s.domains, domain, m.SizeEstimate, attachmentBytes)
+
+	when := time.UnixMilli(m.InternalDate).In(s.loc)
+	bump(s.volume, statsBucket(when, s.period), m.SizeEstimate, attachmentBytes)
+
+	for _, name := range labelNamesFor(m.LabelIds, s.idToName) {
+		bump(s.labels, name, m.SizeEstimate, attachmentBytes)
+	}
+
+	// Keep the largest messages in a small sorted slice.
+	s.largest = append(s.largest, gmailStatsMessage{
+		ID:       m.Id,
+		ThreadID: m.ThreadId,
+		Date:     when.Format("2006-01-02 15:04"),
+		From:     sanitizeTab(from),
+		Subject:  sanitizeTab(headerValue(m.Payload, "Subject")),
+		Bytes:    m.SizeEstimate,
+	})
+	sort.SliceStable(s.largest, func(i, j int) bool { return s.largest[i].Bytes > s.largest[j].Bytes })
+	if len(s.largest) > s.top {
+		s.largest = s.largest[:s.top]
+	}
+}
+
+func bump(m map[string]*gmailStatsEntry, key string, bytes, attachmentBytes int64) {
+	e := m[key]
+	if e == nil {
+		e = &gmailStatsEntry{Name: key}
+		m[key] = e
+	}
+	e.Messages++
+	e.Bytes += bytes
+	e.AttachmentBytes += attachmentBytes
+}
+
+func statsSenderAddress(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil && addr.Address != "" {
+		return strings.ToLower(addr.Address)
+	}
+	from = strings.ToLower(strings.TrimSpace(from))
+	if from == "" {
+		return "(unknown)"
+	}
+	if start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); start >= 0 && end > start {
+		return strings.TrimSpace(from[start+1 : end])
+	}
+	return from
+}
+
+func statsBucket(t time.Time, period string) string {
+	switch period {
+	case "day":
+		return t.Format("2006-01-02")
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case "year":
+		return t.Format("2006")
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+func (s *gmailStats) report(top int) gmailStatsReport {
+	r := gmailStatsReport{
+		Messages:        s.messages,
+		Bytes:           s.bytes,
+		AttachmentBytes: s.attachmentBytes,
+		Period:          s.period,
+		TopSenders:      rankStats(s.senders, top, byMessages),
+		TopDomains:      rankStats(s.domains, top, byMessages),
+		Volume:          rankStats(s.volume, 0, byName),
+		Largest:         s.largest,
+		Labels:          rankStats(s.labels, 0, byMessages),
+	}
+	withAttachments := map[string]*gmailStatsEntry{}
+	for k, e := range s.senders {
+		if e.AttachmentBytes > 0 {
+			withAttachments[k] = e
+		}
+	}
+	r.AttachmentsBySender = rankStats(withAttachments, top, byAttachmentBytes)
+	if r.Largest == nil {
+		r.Largest = []gmailStatsMessage{}
+	}
+	return r
+}
+
+type statsOrder func(a, b gmailStatsEntry) bool
+
+func byMessages(a, b gmailStatsEntry) bool {
+	if a.Messages != b.Messages {
+		return a.Messages > b.Messages
+	}
+	return a.Name < b.Name
+}
+
+func byName(a, b gmailStatsEntry) bool { return a.Name < b.Name }
+
+func byAttachmentBytes(a, b gmailStatsEntry) bool {
+	if a.AttachmentBytes != b.AttachmentBytes {
+		return a.AttachmentBytes > b.AttachmentBytes
+	}
+	return a.Name < b.Name
+}
+
+func rankStats(m map[string]*gmailStatsEntry, limit int, less statsOrder) []gmailStatsEntry {
+	out := make([]gmailStatsEntry, 0, len(m))
+	for _, e := range m {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func printGmailStats(ctx context.Context, u *ui.UI, r gmailStatsReport) {
+	size := formatBytes
+	if outfmt.IsPlain(ctx) {
+		size = func(n int64) string { return fmt.Sprintf("%d", n) }
+	}
+
+	w, flush := tableWriter(ctx)
+	fmt.Fprintf(w, "MESSAGES\t%d\n", r.Messages)
+	fmt.Fprintf(w, "SIZE\t%s\n", size(r.Bytes))
+	fmt.Fprintf(w, "ATTACHMENTS\t%s\n", size(r.AttachmentBytes))
+	flush()
+	if r.Truncated {
+		u.Err().Printf("Stopped at --max %d messages; pass --max 0 to scan everything", r.Messages)
+	}
+
+	section := func(header string, entries []gmailStatsEntry, attachments bool) {
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Fprintln(os.Stdout)
+		w, flush := tableWriter(ctx)
+		defer flush()
+		if attachments {
+			fmt.Fprintf(w, "%s\tMESSAGES\tATTACHMENTS\n", header)
+		} else {
+			fmt.Fprintf(w, "%s\tMESSAGES\tSIZE\n", header)
+		}
+		for _, e := range entries {
+			if attachments {
+				fmt.Fprintf(w, "%s\t%d\t%s\n", sanitizeTab(e.Name), e.Messages, size(e.AttachmentBytes))
+			} else {
+				fmt.Fprintf(w, "%s\t%d\t%s\n", sanitizeTab(e.Name), e.Messages, size(e.Bytes))
+			}
+		}
+	}
+	section("SENDER", r.TopSenders, false)
+	section("DOMAIN", r.TopDomains, false)
+	section(strings.ToUpper(r.Period), r.Volume, false)
+	section("LABEL", r.Labels, false)
+	section("ATTACHMENT SENDER", r.AttachmentsBySender, true)
+
+	if len(r.Largest) > 0 {
+		fmt.Fprintln(os.Stdout)
+		w, flush := tableWriter(ctx)
+		defer flush()
+		fmt.Fprintln(w, "LARGEST\tDATE\tSIZE\tFROM\tSUBJECT")
+		for _, m := range r.Largest {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.ID, m.Date, size(m.Bytes), m.From, m.Subject)
+		}
+	}
+}
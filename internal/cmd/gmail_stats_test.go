@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestStatsHelpers(t *testing.T) {
+	if got := statsSenderAddress(`"Billing" <Billing@Example.com>`); got != "billing@example.com" {
+		t.Fatalf("unexpected sender: %q", got)
+	}
+	if got := statsSenderAddress("broken <a@b"); got != "broken <a@b" {
+		t.Fatalf("unexpected fallback sender: %q", got)
+	}
+	when := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for period, want := range map[string]string{"day": "2026-01-01", "week": "2026-W01", "month": "2026-01", "year": "2026"} {
+		if got := statsBucket(when, period); got != want {
+			t.Fatalf("statsBucket(%s) = %q, want %q", period, got, want)
+		}
+	}
+	part := &gmail.MessagePart{Parts: []*gmail.MessagePart{
+		{MimeType: "text/plain", Body: &gmail.MessagePartBody{Size: 10}},
+		{Filename: "a.pdf", Body: &gmail.MessagePartBody{Size: 100}},
+		{Parts: []*gmail.MessagePart{{Filename: "b.png", Body: &gmail.MessagePartBody{Size: 50}}}},
+	}}
+	if got := partAttachmentBytes(part); got != 150 {
+		t.Fatalf("unexpected attachment bytes: %d", got)
+	}
+}
+
+func TestGmailStatsCmd_JSON(t *testing.T) {
+	jan := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC).UnixMilli()
+	feb := time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC).UnixMilli()
+	messages := map[string]map[string]any{
+		"m1": {"from": "Ada <ada@example.com>", "size": 1000, "date": jan, "labels": []string{"INBOX", "Label_1"}, "type": "text/plain"},
+		"m2": {"from": "ada@example.com", "size": 9000, "date": feb, "labels": []string{"Label_1"}, "type": "multipart/mixed; boundary=x"},
+		"m3": {"from": "news@lists.example.org", "size": 500, "date": feb, "labels": []string{"INBOX"}, "type": "multipart/signed; boundary=y"},
+	}
+	var (
+		mu          sync.Mutex
+		fullFetches []string
+	)
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/")
+		switch {
+		case path == "labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{
+				{"id": "INBOX", "name": "INBOX"},
+				{"id": "Label_1", "name": "Receipts"},
+			}})
+		case path == "messages":
+			if r.URL.Query().Get("q") != "older_than:1y" {
+				t.Errorf("unexpected query %q", r.URL.Query().Get("q"))
+			}
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}}, "nextPageToken": "p2"})
+				return
+			}
+			// "gone" is deleted between the listing and the get.
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m3"}, {"id": "gone"}}})
+		case strings.HasPrefix(path, "messages/"):
+			id := strings.TrimPrefix(path, "messages/")
+			m, ok := messages[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "Requested entity was not found."}})
+				return
+			}
+			if r.URL.Query().Get("format") == "full" {
+				fullFetches = append(fullFetches, id)
+				if id == "m3" {
+					// multipart/signed > mixed > logo.png
+					_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "payload": map[string]any{"parts": []map[string]any{
+						{"parts": []map[string]any{
+							{"mimeType": "text/html", "body": map[string]any{"size": 50}},
+							{"filename": "logo.png", "body": map[string]any{"size": 200}},
+						}},
+						{"filename": "smime.p7s", "body": map[string]any{"size": 0}},
+					}}})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": id, "payload": map[string]any{"parts": []map[string]any{
+					{"mimeType": "text/plain", "body": map[string]any{"size": 100}},
+					{"filename": "invoice.pdf", "body": map[string]any{"size": 8000}},
+				}}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":           id,
+				"threadId":     "t-" + id,
+				"labelIds":     m["labels"],
+				"sizeEstimate": m["size"],
+				"internalDate": fmt.Sprint(m["date"]),
+				"payload": map[string]any{"headers": []map[string]any{
+					{"name": "From", "value": m["from"]},
+					{"name": "Subject", "value": "Subject " + id},
+					{"name": "Content-Type", "value": m["type"]},
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := outfmt.WithMode(ui.WithUI(context.Background(), u), outfmt.Mode{JSON: true})
+
+	out := captureStdout(t, func() {
+		if err := runKong(t, &GmailStatsCmd{}, []string{"older_than:1y", "--top", "1", "-z", "UTC"}, ctx, &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("stats: %v", err)
+		}
+	})
+	var report gmailStatsReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if report.Messages != 3 || report.Bytes != 10500 || report.AttachmentBytes != 8200 || report.Truncated {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	sort.Strings(fullFetches)
+	if strings.Join(fullFetches, ",") != "m2,m3" {
+		t.Fatalf("expected only the multipart messages to be measured, got %v", fullFetches)
+	}
+	if len(report.TopSenders) != 1 || report.TopSenders[0].Name != "ada@example.com" || report.TopSenders[0].Messages != 2 {
+		t.Fatalf("unexpected top senders: %+v", report.TopSenders)
+	}
+	if len(report.TopDomains) != 1 || report.TopDomains[0].Name != "example.com" {
+		t.Fatalf("unexpected top domains: %+v", report.TopDomains)
+	}
+	if len(report.Volume) != 2 || report.Volume[0].Name != "2026-01" || report.Volume[1].Messages != 2 {
+		t.Fatalf("unexpected volume: %+v", report.Volume)
+	}
+	if len(report.Largest) != 1 || report.Largest[0].ID != "m2" {
+		t.Fatalf("unexpected largest: %+v", report.Largest)
+	}
+	if len(report.AttachmentsBySender) != 1 || report.AttachmentsBySender[0].AttachmentBytes != 8000 {
+		t.Fatalf("unexpected attachment senders: %+v", report.AttachmentsBySender)
+	}
+	if len(report.Labels) != 2 || report.Labels[0].Name != "INBOX" || report.Labels[1].Name != "Receipts" {
+		t.Fatalf("unexpected labels: %+v", report.Labels)
+	}
+
+	out = captureStdout(t, func() {
+		if err := runKong(t, &GmailStatsCmd{}, []string{"older_than:1y", "--max", "2", "--skip-attachments"}, ctx, &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("stats --max: %v", err)
+		}
+	})
+	report = gmailStatsReport{}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if report.Messages != 2 || !report.Truncated || report.AttachmentBytes != 0 || len(fullFetches) != 2 {
+		t.Fatalf("unexpected limited report: %+v", report)
+	}
+
+	text := captureStdout(t, func() {
+		if err := runKong(t, &GmailStatsCmd{}, []string{"older_than:1y", "--skip-attachments"}, ui.WithUI(context.Background(), u), &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("stats text: %v", err)
+		}
+	})
+	if !strings.Contains(text, "SENDER") || !strings.Contains(text, "ada@example.com") || !strings.Contains(text, "LARGEST") {
+		t.Fatalf("unexpected text output:\n%s", text)
+	}
+}
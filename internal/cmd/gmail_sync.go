@@ -0,0 +1,648 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+// gmailSyncStateFile lives in the archive root; Maildir readers only look at cur/new/tmp.
+const (
+	gmailSyncStateFile   = ".gog-sync.json"
+	gmailSyncConcurrency = 10
+	// The index grows with the mailbox, so long downloads checkpoint it by
+	// time rather than every N messages.
+	gmailSyncSaveInterval   = 30 * time.Second
+	gmailSyncListPageSize   = 500
+	gmailSyncHistoryMaxPage = 500
+)
+
+type GmailSyncCmd struct {
+	Dir              string `arg:"" name:"dir" help:"Archive directory (Maildir; created if missing)"`
+	Full             bool   `name:"full" help:"Re-list every message and refresh labels instead of applying history"`
+	KeepDeleted      bool   `name:"keep-deleted" help:"Keep local copies of messages deleted in Gmail (marked deleted in the index)"`
+	IncludeSpamTrash bool   `name:"include-spam-trash" help:"Also download messages in Spam and Trash"`
+}
+
+// gmailSyncState is the archive index and history checkpoint. PendingHistoryID is the
+// history ID taken before an initial download started, so an interrupted download
+// resumes and later replays everything that changed meanwhile.
+type gmailSyncState struct {
+	Account          string                     `json:"account"`
+	HistoryID        string                     `json:"historyId,omitempty"`
+	PendingHistoryID string                     `json:"pendingHistoryId,omitempty"`
+	UpdatedAtMs      int64                      `json:"updatedAtMs,omitempty"`
+	Labels           map[string]string          `json:"labels,omitempty"`
+	Messages         map[string]*gmailSyncEntry `json:"messages"`
+}
+
+type gmailSyncEntry struct {
+	ThreadID     string   `json:"threadId,omitempty"`
+	LabelIDs     []string `json:"labelIds,omitempty"`
+	InternalDate int64    `json:"internalDate,omitempty"`
+	Size         int64    `json:"size,omitempty"`
+	File         string   `json:"file"`
+	Deleted      bool     `json:"deleted,omitempty"`
+}
+
+type gmailSyncStats struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
+}
+
+type gmailSyncStore struct {
+	dir     string
+	path    string
+	mu      sync.Mutex
+	state   gmailSyncState
+	savedAt time.Time
+}
+
+func openGmailSyncStore(dir string, account string) (*gmailSyncStore, error) {
+	for _, sub := range []string{"cur", "new", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, fmt.Errorf("create maildir: %w", err)
+		}
+	}
+
+	store := &gmailSyncStore{dir: dir, path: filepath.Join(dir, gmailSyncStateFile), savedAt: time.Now()}
+	data, err := os.ReadFile(store.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		store.state.Account = account
+	case err != nil:
+		return nil, fmt.Errorf("read sync state: %w", err)
+	default:
+		if err := json.Unmarshal(data, &store.state); err != nil {
+			return nil, fmt.Errorf("parse sync state %s: %w", store.path, err)
+		}
+		if !strings.EqualFold(store.state.Account, account) {
+			return nil, usagef("%s is an archive of %s, not %s", dir, store.state.Account, account)
+		}
+	}
+	if store.state.Messages == nil {
+		store.state.Messages = map[string]*gmailSyncEntry{}
+	}
+	return store, nil
+}
+
+// Save writes the index atomically; callers hold s.mu.
+func (s *gmailSyncStore) Save() error {
+	s.state.UpdatedAtMs = time.Now().UnixMilli()
+	payload, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, append(payload, '\n')); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	s.savedAt = time.Now()
+	return nil
+}
+
+func (c *GmailSyncCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	dir := strings.TrimSpace(c.Dir)
+	if dir == "" {
+		return usage("empty dir")
+	}
+	dir, err = config.ExpandPath(dir)
+	if err != nil {
+		return err
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	store, err := openGmailSyncStore(dir, account)
+	if err != nil {
+		return err
+	}
+
+	labels, err := fetchLabelIDToName(svc)
+	if err != nil {
+		return err
+	}
+	store.state.Labels = labels
+
+	s := &gmailSyncer{cmd: c, svc: svc, store: store, u: u}
+	mode := "incremental"
+	if c.Full || store.state.HistoryID == "" {
+		mode = "full"
+		if err = s.full(ctx); err != nil {
+			return err
+		}
+	}
+	// After a full pass this replays changes made while it was downloading.
+	if err = s.incremental(ctx); errors.Is(err, errGmailSyncHistoryExpired) {
+		u.Err().Println("Stored history ID expired; running a full sync")
+		mode = "full"
+		store.state.HistoryID = ""
+		store.state.PendingHistoryID = ""
+		if err = s.full(ctx); err == nil {
+			err = s.incremental(ctx)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep the offline search index (gmail search --offline) current.
+	if _, _, indexErr := loadGmailIndex(dir); indexErr != nil {
+		u.Err().Printf("warning: update search index: %v", indexErr)
+	}
+
+	live := 0
+	for _, e := range store.state.Messages {
+		if !e.Deleted {
+			live++
+		}
+	}
+
+	return writeResult(ctx, u,
+		kv("dir", dir),
+		kv("account", account),
+		kv("mode", mode),
+		kv("added", s.stats.Added),
+		kv("updated", s.stats.Updated),
+		kv("deleted", s.stats.Deleted),
+		kv("messages", live),
+		kv("historyId", store.state.HistoryID),
+	)
+}
+
+var errGmailSyncHistoryExpired = errors.New("gmail history expired")
+
+type gmailSyncer struct {
+	cmd   *GmailSyncCmd
+	svc   *gmail.Service
+	store *gmailSyncStore
+	u     *ui.UI
+	stats gmailSyncStats
+}
+
+// full lists every message, downloads the ones missing locally and reconciles the rest.
+func (s *gmailSyncer) full(ctx context.Context) error {
+	st := &s.store.state
+	if st.PendingHistoryID == "" {
+		profile, err := s.svc.Users.GetProfile("me").Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		st.PendingHistoryID = formatHistoryID(profile.HistoryId)
+		if err := s.store.Save(); err != nil {
+			return err
+		}
+	}
+
+	remote := map[string]struct{}{}
+	var missing []string
+	pageToken := ""
+	for {
+		call := s.svc.Users.Messages.List("me").MaxResults(gmailSyncListPageSize).IncludeSpamTrash(s.cmd.IncludeSpamTrash)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		for _, m := range resp.Messages {
+			if m == nil || m.Id == "" {
+				continue
+			}
+			remote[m.Id] = struct{}{}
+			if e, ok := st.Messages[m.Id]; !ok || e.Deleted {
+				missing = append(missing, m.Id)
+			}
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	if len(missing) > 0 && s.u != nil {
+		s.u.Err().Printf("Downloading %d messages", len(missing))
+	}
+	if err := s.download(ctx, missing); err != nil {
+		return err
+	}
+
+	// Local messages Gmail did not list were deleted or moved to Spam/Trash; with
+	// --full every message gets its labels refreshed.
+	downloaded := make(map[string]struct{}, len(missing))
+	for _, id := range missing {
+		downloaded[id] = struct{}{}
+	}
+	var refresh []string
+	for id, e := range st.Messages {
+		if _, fresh := downloaded[id]; fresh || e.Deleted {
+			continue
+		}
+		if _, listed := remote[id]; !listed || s.cmd.Full {
+			refresh = append(refresh, id)
+		}
+	}
+	sort.Strings(refresh)
+	if err := s.refreshLabels(ctx, refresh); err != nil {
+		return err
+	}
+
+	st.HistoryID = st.PendingHistoryID
+	st.PendingHistoryID = ""
+	return s.store.Save()
+}
+
+// incremental applies History API changes since the stored checkpoint.
+func (s *gmailSyncer) incremental(ctx context.Context) error {
+	st := &s.store.state
+	startID, err := parseHistoryID(st.HistoryID)
+	if err != nil {
+		return err
+	}
+
+	var records []*gmail.History
+	latest := st.HistoryID
+	pageToken := ""
+	for {
+		call := s.svc.Users.History.List("me").StartHistoryId(startID).MaxResults(gmailSyncHistoryMaxPage)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Context(ctx).Do()
+		if err != nil {
+			if isStaleHistoryError(err) {
+				return errGmailSyncHistoryExpired
+			}
+			return err
+		}
+		records = append(records, resp.History...)
+		if resp.HistoryId != 0 {
+			latest = formatHistoryID(resp.HistoryId)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	changes := collectGmailSyncChanges(records, s.cmd.IncludeSpamTrash)
+	for _, id := range changes.deleted {
+		if err := s.remove(id); err != nil {
+			return err
+		}
+	}
+	// Relabeled messages missing locally were in Spam/Trash and may have been
+	// restored; download skips the ones that are still there.
+	relabeled := s.stored(changes.relabeled)
+	if err := s.download(ctx, s.notStored(slices.Concat(changes.added, changes.relabeled))); err != nil {
+		return err
+	}
+	if err := s.refreshLabels(ctx, relabeled); err != nil {
+		return err
+	}
+
+	st.HistoryID = latest
+	return s.store.Save()
+}
+
+type gmailSyncChanges struct {
+	added     []string
+	deleted   []string
+	relabeled []string
+}
+
+// collectGmailSyncChanges folds history records into final per-message actions.
+func collectGmailSyncChanges(records []*gmail.History, includeSpamTrash bool) gmailSyncChanges {
+	var order []string
+	action := map[string]string{}
+	set := func(id string, a string) {
+		if id == "" {
+			return
+		}
+		prev, seen := action[id]
+		if !seen {
+			order = append(order, id)
+		}
+		switch {
+		case prev == "deleted":
+			// Deleted is final.
+		case prev == "added" && a == "relabeled":
+			// A fresh download already has current labels.
+		default:
+			action[id] = a
+		}
+	}
+
+	for _, h := range records {
+		if h == nil {
+			continue
+		}
+		for _, m := range h.MessagesAdded {
+			if m == nil || m.Message == nil {
+				continue
+			}
+			if !includeSpamTrash && isSpamOrTrash(m.Message.LabelIds) {
+				continue
+			}
+			set(m.Message.Id, "added")
+		}
+		for _, m := range h.LabelsAdded {
+			if m != nil && m.Message != nil {
+				set(m.Message.Id, "relabeled")
+			}
+		}
+		for _, m := range h.LabelsRemoved {
+			if m != nil && m.Message != nil {
+				set(m.Message.Id, "relabeled")
+			}
+		}
+		for _, m := range h.MessagesDeleted {
+			if m != nil && m.Message != nil {
+				set(m.Message.Id, "deleted")
+			}
+		}
+	}
+
+	var out gmailSyncChanges
+	for _, id := range order {
+		switch action[id] {
+		case "added":
+			out.added = append(out.added, id)
+		case "deleted":
+			out.deleted = append(out.deleted, id)
+		case "relabeled":
+			out.relabeled = append(out.relabeled, id)
+		}
+	}
+	return out
+}
+
+func hasLabel(labelIDs []string, label string) bool {
+	for _, id := range labelIDs {
+		if id == label {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpamOrTrash(labelIDs []string) bool {
+	return hasLabel(labelIDs, "SPAM") || hasLabel(labelIDs, "TRASH")
+}
+
+func (s *gmailSyncer) stored(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if e, ok := s.store.state.Messages[id]; ok && !e.Deleted {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func (s *gmailSyncer) notStored(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if e, ok := s.store.state.Messages[id]; !ok || e.Deleted {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// download fetches raw RFC 822 messages and writes them into the Maildir.
+// Messages deleted in the meantime (404), or in Spam/Trash without
+// --include-spam-trash, are skipped.
+func (s *gmailSyncer) download(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sem := make(chan struct{}, gmailSyncConcurrency)
+	errCh := make(chan error, len(ids))
+	var wg sync.WaitGroup
+
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errCh <- ctx.Err()
+				return
+			}
+
+			msg, err := s.svc.Users.Messages.Get("me", id).Format(gmailFormatRaw).Context(ctx).Do()
+			if err != nil {
+				if isNotFoundAPIError(err) {
+					return
+				}
+				errCh <- fmt.Errorf("message %s: %w", id, err)
+				return
+			}
+			if !s.cmd.IncludeSpamTrash && isSpamOrTrash(msg.LabelIds) {
+				return
+			}
+
+			s.store.mu.Lock()
+			defer s.store.mu.Unlock()
+			if err := s.writeMessage(msg); err != nil {
+				errCh <- err
+				return
+			}
+			if time.Since(s.store.savedAt) >= gmailSyncSaveInterval {
+				if err := s.store.Save(); err != nil {
+					errCh <- err
+				}
+			}
+		}(id)
+	}
+	wg.Wait()
+	close(errCh)
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	saveErr := s.store.Save()
+	if err := <-errCh; err != nil {
+		return err
+	}
+	return saveErr
+}
+
+// refreshLabels fetches current labels and renames files whose Maildir flags changed.
+// Messages that no longer exist, or moved to Spam/Trash without
+// --include-spam-trash, are removed so the mirror matches the mailbox.
+func (s *gmailSyncer) refreshLabels(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	msgs, errs, err := getGmailMessages(ctx, s.svc, ids, url.Values{"format": {"minimal"}})
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if errs[i] != nil {
+			if isNotFoundAPIError(errs[i]) {
+				if err := s.remove(id); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("message %s: %w", id, errs[i])
+		}
+		if !s.cmd.IncludeSpamTrash && isSpamOrTrash(msgs[i].LabelIds) {
+			if err := s.remove(id); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.relabel(id, msgs[i].LabelIds); err != nil {
+			return err
+		}
+	}
+	return s.store.Save()
+}
+
+// writeMessage stores msg as cur/<date>.<id>.gog:2,<flags>; callers hold s.store.mu.
+func (s *gmailSyncer) writeMessage(msg *gmail.Message) error {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(msg.Raw, "="))
+	if err != nil {
+		return fmt.Errorf("message %s: decode raw: %w", msg.Id, err)
+	}
+
+	base := fmt.Sprintf("%d.%s.gog", msg.InternalDate/1000, msg.Id)
+	tmp := filepath.Join(s.store.dir, "tmp", base)
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("write message %s: %w", msg.Id, err)
+	}
+
+	file := filepath.Join("cur", base+maildirInfo(msg.LabelIds))
+	if prev, ok := s.store.state.Messages[msg.Id]; ok && prev.File != file {
+		_ = os.Remove(filepath.Join(s.store.dir, prev.File))
+	}
+	if err := os.Rename(tmp, filepath.Join(s.store.dir, file)); err != nil {
+		return fmt.Errorf("write message %s: %w", msg.Id, err)
+	}
+
+	s.store.state.Messages[msg.Id] = &gmailSyncEntry{
+		ThreadID:     msg.ThreadId,
+		LabelIDs:     msg.LabelIds,
+		InternalDate: msg.InternalDate,
+		Size:         int64(len(raw)),
+		File:         filepath.ToSlash(file),
+	}
+	s.stats.Added++
+	return nil
+}
+
+func (s *gmailSyncer) relabel(id string, labelIDs []string) error {
+	e, ok := s.store.state.Messages[id]
+	if !ok || e.Deleted {
+		return nil
+	}
+
+	file := strings.TrimSuffix(e.File, maildirInfo(e.LabelIDs)) + maildirInfo(labelIDs)
+	if file != e.File {
+		if err := os.Rename(filepath.Join(s.store.dir, e.File), filepath.Join(s.store.dir, file)); err != nil {
+			return fmt.Errorf("rename message %s: %w", id, err)
+		}
+	}
+	if file != e.File || !equalStringSets(e.LabelIDs, labelIDs) {
+		s.stats.Updated++
+	}
+	e.File = file
+	e.LabelIDs = labelIDs
+	return nil
+}
+
+func (s *gmailSyncer) remove(id string) error {
+	e, ok := s.store.state.Messages[id]
+	if !ok || e.Deleted {
+		return nil
+	}
+
+	s.stats.Deleted++
+	if s.cmd.KeepDeleted {
+		e.Deleted = true
+		return nil
+	}
+	if err := os.Remove(filepath.Join(s.store.dir, e.File)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove message %s: %w", id, err)
+	}
+	delete(s.store.state.Messages, id)
+	return nil
+}
+
+// maildirInfoSep separates the unique name from the flags; ':' is not allowed in
+// Windows file names, where Maildir tools use '!'.
+var maildirInfoSep = func() string {
+	if runtime.GOOS == "windows" {
+		return "!"
+	}
+	return ":"
+}()
+
+// maildirInfo maps Gmail system labels to Maildir flags (D draft, F starred,
+// S read, T trash), in the ASCII order the Maildir spec requires.
+func maildirInfo(labelIDs []string) string {
+	flags := ""
+	if hasLabel(labelIDs, "DRAFT") {
+		flags += "D"
+	}
+	if hasLabel(labelIDs, "STARRED") {
+		flags += "F"
+	}
+	if !hasLabel(labelIDs, "UNREAD") {
+		flags += "S"
+	}
+	if hasLabel(labelIDs, "TRASH") {
+		flags += "T"
+	}
+	return maildirInfoSep + "2," + flags
+}
+
+func equalStringSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, v := range a {
+		seen[v]++
+	}
+	for _, v := range b {
+		if seen[v] == 0 {
+			return false
+		}
+		seen[v]--
+	}
+	return true
+}
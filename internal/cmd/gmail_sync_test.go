@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+type fakeSyncMailbox struct {
+	mu        sync.Mutex
+	historyID string
+	labels    map[string][]string
+	history   []map[string]any
+}
+
+func (f *fakeSyncMailbox) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/")
+		switch {
+		case path == "labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}}})
+		case path == "profile":
+			_ = json.NewEncoder(w).Encode(map[string]any{"emailAddress": "a@b.com", "historyId": f.historyID})
+		case path == "history":
+			_ = json.NewEncoder(w).Encode(map[string]any{"history": f.history, "historyId": f.historyID})
+		case path == "messages":
+			ids := make([]string, 0, len(f.labels))
+			for id := range f.labels {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			msgs := make([]map[string]any, 0, len(ids))
+			for _, id := range ids {
+				msgs = append(msgs, map[string]any{"id": id})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": msgs})
+		case strings.HasPrefix(path, "messages/"):
+			id := strings.TrimPrefix(path, "messages/")
+			labels, ok := f.labels[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": 404, "message": "Requested entity was not found."}})
+				return
+			}
+			msg := map[string]any{"id": id, "threadId": "t-" + id, "labelIds": labels, "internalDate": "1700000000000"}
+			if r.URL.Query().Get("format") == "raw" {
+				msg["raw"] = base64.RawURLEncoding.EncodeToString([]byte("Subject: " + id + "\r\n\r\nbody " + id + "\r\n"))
+			}
+			_ = json.NewEncoder(w).Encode(msg)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.String())
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func runGmailSyncForTest(t *testing.T, mailbox *fakeSyncMailbox, args ...string) map[string]any {
+	t.Helper()
+
+	srv := httptest.NewServer(mailbox.handler(t))
+	t.Cleanup(srv.Close)
+
+	svc, err := gmail.NewService(context.Background(),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(srv.Client()),
+		option.WithEndpoint(srv.URL+"/"),
+	)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	out := captureStdout(t, func() {
+		u, uiErr := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+		if uiErr != nil {
+			t.Fatalf("ui.New: %v", uiErr)
+		}
+		ctx := ui.WithUI(context.Background(), u)
+		ctx = outfmt.WithMode(ctx, outfmt.Mode{JSON: true})
+
+		if err := runKong(t, &GmailSyncCmd{}, args, ctx, &RootFlags{Account: "a@b.com"}); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+	})
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	return result
+}
+
+func maildirFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, "cur"))
+	if err != nil {
+		t.Fatalf("read cur: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGmailSyncCmd_FullThenIncremental(t *testing.T) {
+	dir := t.TempDir()
+	mailbox := &fakeSyncMailbox{
+		historyID: "100",
+		labels: map[string][]string{
+			"m1": {"INBOX", "UNREAD"},
+			"m2": {"INBOX", "STARRED"},
+		},
+	}
+
+	result := runGmailSyncForTest(t, mailbox, dir)
+	if result["mode"] != "full" || result["added"] != float64(2) || result["messages"] != float64(2) || result["historyId"] != "100" {
+		t.Fatalf("unexpected full sync result: %v", result)
+	}
+	want := []string{
+		"1700000000.m1.gog" + maildirInfoSep + "2,",
+		"1700000000.m2.gog" + maildirInfoSep + "2,FS",
+	}
+	if got := maildirFiles(t, dir); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected files: %v", got)
+	}
+	body, err := os.ReadFile(filepath.Join(dir, "cur", want[0]))
+	if err != nil || !strings.Contains(string(body), "Subject: m1") {
+		t.Fatalf("unexpected message file: %q (%v)", body, err)
+	}
+
+	// m1 deleted, m3 added, m2 marked unread.
+	mailbox.mu.Lock()
+	mailbox.historyID = "110"
+	delete(mailbox.labels, "m1")
+	mailbox.labels["m3"] = []string{"INBOX"}
+	mailbox.labels["m2"] = []string{"INBOX", "STARRED", "UNREAD"}
+	mailbox.history = []map[string]any{
+		{"id": "101", "messagesDeleted": []map[string]any{{"message": map[string]any{"id": "m1"}}}},
+		{"id": "102", "messagesAdded": []map[string]any{{"message": map[string]any{"id": "m3", "labelIds": []string{"INBOX"}}}}},
+		{"id": "103", "labelsAdded": []map[string]any{{"message": map[string]any{"id": "m2"}, "labelIds": []string{"UNREAD"}}}},
+	}
+	mailbox.mu.Unlock()
+
+	result = runGmailSyncForTest(t, mailbox, dir)
+	if result["mode"] != "incremental" || result["added"] != float64(1) || result["updated"] != float64(1) || result["deleted"] != float64(1) || result["historyId"] != "110" {
+		t.Fatalf("unexpected incremental result: %v", result)
+	}
+	want = []string{
+		"1700000000.m2.gog" + maildirInfoSep + "2,F",
+		"1700000000.m3.gog" + maildirInfoSep + "2,S",
+	}
+	if got := maildirFiles(t, dir); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected files: %v", got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, gmailSyncStateFile))
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	var state gmailSyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("decode state: %v", err)
+	}
+	if state.Account != "a@b.com" || state.HistoryID != "110" || state.PendingHistoryID != "" || len(state.Messages) != 2 || state.Messages["m3"].ThreadID != "t-m3" {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+}
+
+func TestGmailSyncCmd_KeepDeletedAndAccountMismatch(t *testing.T) {
+	dir := t.TempDir()
+	mailbox := &fakeSyncMailbox{historyID: "5", labels: map[string][]string{"m1": {"INBOX"}}}
+	_ = runGmailSyncForTest(t, mailbox, dir)
+
+	mailbox.mu.Lock()
+	mailbox.historyID = "6"
+	delete(mailbox.labels, "m1")
+	mailbox.history = []map[string]any{{"id": "6", "messagesDeleted": []map[string]any{{"message": map[string]any{"id": "m1"}}}}}
+	mailbox.mu.Unlock()
+
+	result := runGmailSyncForTest(t, mailbox, dir, "--keep-deleted")
+	if result["deleted"] != float64(1) || result["messages"] != float64(0) {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if got := maildirFiles(t, dir); len(got) != 1 {
+		t.Fatalf("expected the deleted message to be kept, got %v", got)
+	}
+
+	if _, err := openGmailSyncStore(dir, "other@b.com"); ExitCode(err) != 2 {
+		t.Fatalf("expected usage error for another account, got %v", err)
+	}
+}
+
+func TestGmailSyncCmd_TrashRemovesAndRestoreDownloads(t *testing.T) {
+	dir := t.TempDir()
+	mailbox := &fakeSyncMailbox{historyID: "5", labels: map[string][]string{"m1": {"INBOX"}, "m2": {"INBOX"}}}
+	_ = runGmailSyncForTest(t, mailbox, dir)
+
+	mailbox.mu.Lock()
+	mailbox.historyID = "6"
+	mailbox.labels["m1"] = []string{"TRASH"}
+	mailbox.history = []map[string]any{{"id": "6", "labelsAdded": []map[string]any{{"message": map[string]any{"id": "m1"}, "labelIds": []string{"TRASH"}}}}}
+	mailbox.mu.Unlock()
+
+	result := runGmailSyncForTest(t, mailbox, dir)
+	if result["deleted"] != float64(1) || result["messages"] != float64(1) {
+		t.Fatalf("unexpected result after trash: %v", result)
+	}
+	if got := maildirFiles(t, dir); len(got) != 1 || !strings.Contains(got[0], ".m2.") {
+		t.Fatalf("expected only m2 to remain, got %v", got)
+	}
+
+	mailbox.mu.Lock()
+	mailbox.historyID = "7"
+	mailbox.labels["m1"] = []string{"INBOX"}
+	mailbox.history = []map[string]any{{"id": "7", "labelsRemoved": []map[string]any{{"message": map[string]any{"id": "m1"}, "labelIds": []string{"TRASH"}}}}}
+	mailbox.mu.Unlock()
+
+	result = runGmailSyncForTest(t, mailbox, dir)
+	if result["added"] != float64(1) || result["messages"] != float64(2) {
+		t.Fatalf("unexpected result after restore: %v", result)
+	}
+}
+
+func TestCollectGmailSyncChanges(t *testing.T) {
+	msg := func(id string, labels ...string) *gmail.Message { return &gmail.Message{Id: id, LabelIds: labels} }
+	records := []*gmail.History{
+		{MessagesAdded: []*gmail.HistoryMessageAdded{{Message: msg("a")}, {Message: msg("spam", "SPAM")}}},
+		{LabelsAdded: []*gmail.HistoryLabelAdded{{Message: msg("a")}, {Message: msg("b")}}},
+		{MessagesDeleted: []*gmail.HistoryMessageDeleted{{Message: msg("b")}}},
+		{LabelsRemoved: []*gmail.HistoryLabelRemoved{{Message: msg("b")}, {Message: msg("c")}}},
+	}
+
+	got := collectGmailSyncChanges(records, false)
+	if strings.Join(got.added, ",") != "a" || strings.Join(got.deleted, ",") != "b" || strings.Join(got.relabeled, ",") != "c" {
+		t.Fatalf("unexpected changes: %+v", got)
+	}
+}
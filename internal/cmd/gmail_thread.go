@@ -9,7 +9,6 @@ import (
 	"io"
 	"mime"
 	"mime/quotedprintable"
-	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -51,10 +50,11 @@ type GmailThreadCmd struct {
 	Get         GmailThreadGetCmd         `cmd:"" name:"get" aliases:"info,show" default:"withargs" help:"Get a thread with all messages (optionally download attachments)"`
 	Modify      GmailThreadModifyCmd      `cmd:"" name:"modify" aliases:"update,edit,set" help:"Modify labels on all messages in a thread"`
 	Attachments GmailThreadAttachmentsCmd `cmd:"" name:"attachments" aliases:"files" help:"List all attachments in a thread"`
+	Export      GmailThreadExportCmd      `cmd:"" name:"export" aliases:"transcript" help:"Export a thread as a self-contained transcript (html, md, eml, pdf-ready-html)"`
 }
 
 type GmailThreadGetCmd struct {
-	ThreadID  string        `arg:"" name:"threadId" help:"Thread ID"`
+	ThreadID  string        `arg:"" stdin:"" name:"threadId" help:"Thread ID"`
 	Download  bool          `name:"download" help:"Download attachments"`
 	Full      bool          `name:"full" help:"Show full message bodies"`
 	OutputDir OutputDirFlag `embed:""`
@@ -175,7 +175,7 @@ func (c *GmailThreadGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type GmailThreadModifyCmd struct {
-	ThreadID string `arg:"" name:"threadId" help:"Thread ID"`
+	ThreadID string `arg:"" stdin:"" name:"threadId" help:"Thread ID"`
 	Add      string `name:"add" help:"Labels to add (comma-separated, name or ID)"`
 	Remove   string `name:"remove" help:"Labels to remove (comma-separated, name or ID)"`
 }
@@ -244,7 +244,7 @@ func (c *GmailThreadModifyCmd) Run(ctx context.Context, flags *RootFlags) error
 
 // GmailThreadAttachmentsCmd lists all attachments in a thread.
 type GmailThreadAttachmentsCmd struct {
-	ThreadID  string        `arg:"" name:"threadId" help:"Thread ID"`
+	ThreadID  string        `arg:"" stdin:"" name:"threadId" help:"Thread ID"`
 	Download  bool          `name:"download" help:"Download all attachments"`
 	OutputDir OutputDirFlag `embed:""`
 }
@@ -340,7 +340,7 @@ func (c *GmailThreadAttachmentsCmd) Run(ctx context.Context, flags *RootFlags) e
 }
 
 type GmailURLCmd struct {
-	ThreadIDs []string `arg:"" name:"threadId" help:"Thread IDs"`
+	ThreadIDs []string `arg:"" stdin:"" name:"threadId" help:"Thread IDs"`
 }
 
 func (c *GmailURLCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -355,15 +355,14 @@ func (c *GmailURLCmd) Run(ctx context.Context, flags *RootFlags) error {
 			id = normalizeGmailThreadID(id)
 			urls = append(urls, map[string]string{
 				"id":  id,
-				"url": fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), id),
+				"url": gmailWebURL(account, id),
 			})
 		}
 		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"urls": urls})
 	}
 	for _, id := range c.ThreadIDs {
 		id = normalizeGmailThreadID(id)
-		threadURL := fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), id)
-		u.Out().Printf("%s\t%s", id, threadURL)
+		u.Out().Printf("%s\t%s", id, gmailWebURL(account, id))
 	}
 	return nil
 }
@@ -0,0 +1,940 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	threadExportHTML         = "html"
+	threadExportMarkdown     = "md"
+	threadExportEML          = "eml"
+	threadExportPDFReadyHTML = "pdf-ready-html"
+)
+
+type GmailThreadExportCmd struct {
+	ThreadID       string `arg:"" stdin:"" name:"threadId" help:"Thread ID"`
+	Format         string `name:"format" help:"Transcript format: html|md|eml|pdf-ready-html" enum:"html,md,eml,pdf-ready-html" default:"html"`
+	Out            string `name:"out" aliases:"output" help:"Output file path (default: stdout)"`
+	AttachmentsDir string `name:"attachments-dir" help:"Download attachments here and link them from the transcript (default: link to Gmail)"`
+	RemoteImages   bool   `name:"remote-images" help:"Keep remote (http/https) images; opening the transcript then contacts their servers"`
+}
+
+// threadExport is the format-independent view of a thread that the html and
+// markdown renderers work from.
+type threadExport struct {
+	ThreadID string
+	Subject  string
+	Account  string
+	URL      string
+	Exported time.Time
+	Messages []threadExportMessage
+	// RemoteImages keeps http(s) image sources, which double as tracking
+	// pixels; by default only embedded data: images are rendered.
+	RemoteImages bool
+}
+
+type threadExportMessage struct {
+	ID      string
+	From    string
+	To      string
+	Cc      string
+	Date    string
+	Subject string
+	// HTML is the sanitized message body with cid: images resolved; empty for
+	// plain-text messages.
+	HTML        string
+	Text        string
+	Images      []*threadExportImage
+	Attachments []threadExportAttachment
+}
+
+type threadExportImage struct {
+	ContentID    string
+	Filename     string
+	MimeType     string
+	AttachmentID string
+	DataURI      string
+	Referenced   bool
+	inline       bool
+	part         *gmail.MessagePart
+}
+
+type threadExportAttachment struct {
+	Filename string
+	MimeType string
+	Size     int64
+	Link     string
+}
+
+func (c *GmailThreadExportCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	threadID := normalizeGmailThreadID(strings.TrimSpace(c.ThreadID))
+	if threadID == "" {
+		return usage("empty threadId")
+	}
+	format := strings.ToLower(strings.TrimSpace(c.Format))
+	if format == "" {
+		format = threadExportHTML
+	}
+	if format == threadExportEML && strings.TrimSpace(c.AttachmentsDir) != "" {
+		return usage("--attachments-dir does not apply to --format eml (attachments stay embedded)")
+	}
+
+	outPath := strings.TrimSpace(c.Out)
+	if outPath == "-" {
+		outPath = ""
+	}
+	if outPath != "" {
+		outPath, err = config.ExpandPath(outPath)
+		if err != nil {
+			return err
+		}
+	}
+	attachDir := strings.TrimSpace(c.AttachmentsDir)
+	if attachDir != "" {
+		attachDir, err = config.ExpandPath(attachDir)
+		if err != nil {
+			return err
+		}
+		attachDir = filepath.Clean(attachDir)
+	}
+
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	var (
+		content  []byte
+		messages int
+		attached int
+	)
+	if format == threadExportEML {
+		content, messages, err = exportThreadEML(ctx, svc, threadID)
+		if err != nil {
+			return err
+		}
+	} else {
+		thread, err := svc.Users.Threads.Get("me", threadID).Format("full").Context(ctx).Do()
+		if err != nil {
+			return err
+		}
+		if thread == nil || len(thread.Messages) == 0 {
+			return usagef("thread %s has no messages", threadID)
+		}
+		export, err := buildThreadExport(ctx, svc, account, thread, attachDir, linkBaseDir(outPath))
+		if err != nil {
+			return err
+		}
+		export.RemoteImages = c.RemoteImages
+		var buf bytes.Buffer
+		switch format {
+		case threadExportMarkdown:
+			renderThreadMarkdown(&buf, export)
+		default:
+			renderThreadHTML(&buf, export, format == threadExportPDFReadyHTML)
+		}
+		content = buf.Bytes()
+		messages = len(export.Messages)
+		for _, m := range export.Messages {
+			attached += len(m.Attachments)
+		}
+	}
+
+	if outPath == "" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+	if err := writeFileAtomic(outPath, content); err != nil {
+		return err
+	}
+	return writeResult(ctx, u,
+		kv("threadId", threadID),
+		kv("format", format),
+		kv("path", outPath),
+		kv("messages", messages),
+		kv("attachments", attached),
+		kv("bytes", len(content)),
+	)
+}
+
+// linkBaseDir is the directory attachment links are made relative to, so a
+// transcript and its attachments directory can be moved together.
+func linkBaseDir(outPath string) string {
+	if outPath == "" {
+		return ""
+	}
+	return filepath.Dir(outPath)
+}
+
+func gmailWebURL(account, id string) string {
+	return fmt.Sprintf("https://mail.google.com/mail/?authuser=%s#all/%s", url.QueryEscape(account), id)
+}
+
+// exportThreadEML bundles the raw RFC 822 messages of a thread into a single
+// multipart/digest message (RFC 2046 §5.1.5), which mail clients open as a
+// message with each original attached intact.
+func exportThreadEML(ctx context.Context, svc *gmail.Service, threadID string) ([]byte, int, error) {
+	thread, err := svc.Users.Threads.Get("me", threadID).Format("minimal").Context(ctx).Do()
+	if err != nil {
+		return nil, 0, err
+	}
+	if thread == nil || len(thread.Messages) == 0 {
+		return nil, 0, usagef("thread %s has no messages", threadID)
+	}
+
+	var (
+		body    bytes.Buffer
+		subject string
+	)
+	mw := multipart.NewWriter(&body)
+	count := 0
+	for _, m := range thread.Messages {
+		if m == nil || m.Id == "" {
+			continue
+		}
+		full, err := svc.Users.Messages.Get("me", m.Id).Format("raw").Context(ctx).Do()
+		if err != nil {
+			return nil, 0, err
+		}
+		raw, err := decodeBase64URLBytes(full.Raw)
+		if err != nil {
+			return nil, 0, fmt.Errorf("decode message %s: %w", m.Id, err)
+		}
+		if subject == "" {
+			subject = rawSubject(raw)
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"message/rfc822"}})
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := part.Write(raw); err != nil {
+			return nil, 0, err
+		}
+		count++
+	}
+	if err := mw.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&out, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&out, "Message-ID: <%s@gogcli.local>\r\n", randomExportID())
+	fmt.Fprintf(&out, "Subject: %s\r\n", encodeHeaderIfNeeded("Thread export: "+strings.TrimSpace(subject)))
+	fmt.Fprintf(&out, "X-Gmail-Thread-Id: %s\r\n", threadID)
+	fmt.Fprintf(&out, "Content-Type: multipart/digest; boundary=%q\r\n\r\n", mw.Boundary())
+	out.Write(body.Bytes())
+	return out.Bytes(), count, nil
+}
+
+// rawSubject returns the decoded Subject header of a raw RFC 822 message.
+func rawSubject(raw []byte) string {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return ""
+	}
+	subject := msg.Header.Get("Subject")
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(subject); err == nil {
+		return decoded
+	}
+	return subject
+}
+
+func randomExportID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+func buildThreadExport(ctx context.Context, svc *gmail.Service, account string, thread *gmail.Thread, attachDir, linkBase string) (*threadExport, error) {
+	export := &threadExport{
+		ThreadID: thread.Id,
+		Account:  account,
+		URL:      gmailWebURL(account, thread.Id),
+		Exported: time.Now(),
+	}
+	for _, msg := range thread.Messages {
+		if msg == nil || msg.Payload == nil {
+			continue
+		}
+		m, err := buildThreadExportMessage(ctx, svc, account, msg, attachDir, linkBase)
+		if err != nil {
+			return nil, err
+		}
+		if export.Subject == "" {
+			export.Subject = m.Subject
+		}
+		export.Messages = append(export.Messages, m)
+	}
+	return export, nil
+}
+
+func buildThreadExportMessage(ctx context.Context, svc *gmail.Service, account string, msg *gmail.Message, attachDir, linkBase string) (threadExportMessage, error) {
+	p := msg.Payload
+	m := threadExportMessage{
+		ID:      msg.Id,
+		From:    headerValue(p, "From"),
+		To:      headerValue(p, "To"),
+		Cc:      headerValue(p, "Cc"),
+		Date:    headerValue(p, "Date"),
+		Subject: headerValue(p, "Subject"),
+		Text:    findPartBody(p, "text/plain"),
+	}
+	if m.Date == "" && msg.InternalDate > 0 {
+		m.Date = time.UnixMilli(msg.InternalDate).Format(time.RFC1123Z)
+	}
+	htmlBody := findPartBody(p, "text/html")
+	if htmlBody == "" && looksLikeHTML(m.Text) {
+		htmlBody, m.Text = m.Text, ""
+	}
+
+	images := map[string]*threadExportImage{}
+	collectExportImages(p, images)
+	for _, ref := range cidRefPattern.FindAllStringSubmatch(htmlBody, -1) {
+		if img := images[normalizeContentID(ref[1])]; img != nil {
+			img.Referenced = true
+		}
+	}
+	embedded := map[string]bool{}
+	for _, img := range orderedExportImages(p, images) {
+		if !img.Referenced && !img.inline {
+			continue
+		}
+		data, err := exportPartBytes(ctx, svc, msg.Id, img.part)
+		if err != nil {
+			return m, fmt.Errorf("inline image %s in message %s: %w", img.Filename, msg.Id, err)
+		}
+		img.DataURI = "data:" + img.MimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		if img.AttachmentID != "" {
+			embedded[img.AttachmentID] = true
+		}
+		m.Images = append(m.Images, img)
+	}
+	if htmlBody != "" {
+		m.HTML = htmlBody
+		if m.Text == "" {
+			m.Text = exportHTMLText(htmlBody)
+		}
+	}
+
+	var attachments []attachmentInfo
+	for _, a := range collectAttachments(p) {
+		if !embedded[a.AttachmentID] {
+			attachments = append(attachments, a)
+		}
+	}
+	if attachDir != "" && len(attachments) > 0 {
+		downloads, err := downloadAttachmentOutputs(ctx, svc, msg.Id, attachments, attachDir)
+		if err != nil {
+			return m, err
+		}
+		for _, d := range downloads {
+			m.Attachments = append(m.Attachments, threadExportAttachment{
+				Filename: d.Filename,
+				MimeType: d.MimeType,
+				Size:     d.Size,
+				Link:     relativeFileLink(linkBase, d.Path),
+			})
+		}
+		return m, nil
+	}
+	for _, a := range attachments {
+		m.Attachments = append(m.Attachments, threadExportAttachment{
+			Filename: a.Filename,
+			MimeType: a.MimeType,
+			Size:     a.Size,
+			Link:     gmailWebURL(account, msg.Id),
+		})
+	}
+	return m, nil
+}
+
+var cidRefPattern = regexp.MustCompile(`(?i)cid:([^"'\s)>]+)`)
+
+func normalizeContentID(id string) string {
+	id = strings.TrimSpace(id)
+	if unescaped, err := url.PathUnescape(id); err == nil {
+		id = unescaped
+	}
+	return strings.ToLower(strings.Trim(id, "<>"))
+}
+
+// collectExportImages indexes image parts carrying a Content-ID. Gmail also
+// stamps Content-IDs on regular attachments, so only parts that are inline (or
+// that the HTML body references) end up embedded in the transcript.
+func collectExportImages(p *gmail.MessagePart, out map[string]*threadExportImage) {
+	if p == nil {
+		return
+	}
+	cid := normalizeContentID(headerValue(p, "Content-ID"))
+	if cid == "" {
+		cid = normalizeContentID(headerValue(p, "X-Attachment-Id"))
+	}
+	mimeType := normalizeMimeType(p.MimeType)
+	if cid != "" && strings.HasPrefix(mimeType, "image/") && p.Body != nil {
+		disposition := strings.ToLower(strings.TrimSpace(headerValue(p, "Content-Disposition")))
+		img := &threadExportImage{
+			ContentID: cid,
+			Filename:  p.Filename,
+			MimeType:  mimeType,
+			inline:    !strings.HasPrefix(disposition, "attachment"),
+			part:      p,
+		}
+		if img.Filename == "" {
+			img.Filename = cid
+		}
+		img.AttachmentID = p.Body.AttachmentId
+		out[cid] = img
+	}
+	for _, part := range p.Parts {
+		collectExportImages(part, out)
+	}
+}
+
+func orderedExportImages(p *gmail.MessagePart, images map[string]*threadExportImage) []*threadExportImage {
+	if p == nil {
+		return nil
+	}
+	var out []*threadExportImage
+	for _, img := range images {
+		if img.part == p {
+			out = append(out, img)
+		}
+	}
+	for _, part := range p.Parts {
+		out = append(out, orderedExportImages(part, images)...)
+	}
+	return out
+}
+
+func exportPartBytes(ctx context.Context, svc *gmail.Service, messageID string, p *gmail.MessagePart) ([]byte, error) {
+	if p.Body.Data != "" {
+		return decodeBase64URLBytes(p.Body.Data)
+	}
+	return fetchAttachmentBytes(ctx, svc, messageID, p.Body.AttachmentId)
+}
+
+func relativeFileLink(base, path string) string {
+	if base != "" {
+		if rel, err := filepath.Rel(base, path); err == nil {
+			path = rel
+		}
+	}
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// sanitizeExportHTML reduces a message body to the contents of its <body>,
+// drops active content, resolves cid: images and wraps quoted text. URLs are
+// checked against a scheme allowlist and remote images are dropped unless
+// remoteImages is set. When collapse is set the quotes become <details>
+// blocks; otherwise they stay expanded for printing.
+func sanitizeExportHTML(src string, images []*threadExportImage, collapse, remoteImages bool) string {
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return "<pre>" + html.EscapeString(src) + "</pre>"
+	}
+	body := findHTMLElement(doc, atom.Body)
+	if body == nil {
+		body = doc
+	}
+	byCID := map[string]string{}
+	for _, img := range images {
+		byCID[img.ContentID] = img.DataURI
+	}
+	sanitizeExportNode(body, exportSanitizer{images: byCID, remoteImages: remoteImages}, collapse, true)
+
+	var b strings.Builder
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		_ = html.Render(&b, c)
+	}
+	return b.String()
+}
+
+func findHTMLElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findHTMLElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+type exportSanitizer struct {
+	images       map[string]string // content ID → data: URI
+	remoteImages bool
+}
+
+func sanitizeExportNode(n *html.Node, sz exportSanitizer, collapse, wrapQuotes bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.CommentNode, html.DoctypeNode:
+			n.RemoveChild(c)
+		case html.ElementNode:
+			switch c.DataAtom {
+			case atom.Script, atom.Style, atom.Iframe, atom.Frame, atom.Frameset, atom.Object, atom.Embed, atom.Applet,
+				atom.Link, atom.Meta, atom.Base, atom.Form, atom.Head, atom.Title, atom.Template, atom.Svg, atom.Math:
+				n.RemoveChild(c)
+				c = next
+				continue
+			}
+			sanitizeExportAttrs(c, sz)
+			quote := wrapQuotes && isQuotedHTML(c)
+			sanitizeExportNode(c, sz, collapse, wrapQuotes && !quote)
+			if quote {
+				wrapQuotedHTML(n, c, collapse)
+			}
+		}
+		c = next
+	}
+}
+
+// exportURLAttrs are attributes that load or link to a URL but have no use in
+// a transcript; they are dropped rather than checked.
+var exportURLAttrs = map[string]bool{
+	"action": true, "formaction": true, "background": true, "poster": true, "srcset": true, "lowsrc": true,
+	"dynsrc": true, "longdesc": true, "cite": true, "ping": true, "data": true, "codebase": true, "manifest": true,
+}
+
+func sanitizeExportAttrs(n *html.Node, sz exportSanitizer) {
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		switch {
+		case strings.HasPrefix(key, "on"), exportURLAttrs[key]:
+			continue
+		case key == "href":
+			val, ok := safeExportHref(a.Val)
+			if !ok {
+				continue
+			}
+			a.Val = val
+		case key == "src":
+			val, ok := sz.safeSrc(a.Val)
+			if !ok {
+				continue
+			}
+			a.Val = val
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+}
+
+// cleanExportURL removes the control characters and whitespace browsers
+// ignore inside URLs (e.g. "java\tscript:"), and returns the cleaned URL with
+// its lower-cased scheme ("" for relative URLs).
+func cleanExportURL(raw string) (string, string) {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+	i := strings.IndexByte(cleaned, ':')
+	if i <= 0 || strings.ContainsAny(cleaned[:i], "/?#") {
+		return cleaned, ""
+	}
+	return cleaned, strings.ToLower(cleaned[:i])
+}
+
+// safeExportHref allows relative links and http, https, mailto and cid URLs.
+func safeExportHref(raw string) (string, bool) {
+	cleaned, scheme := cleanExportURL(raw)
+	switch scheme {
+	case "", "http", "https", "mailto", "cid":
+		return cleaned, true
+	}
+	return "", false
+}
+
+// safeSrc resolves cid: references to embedded images and allows data:image/*
+// sources; http(s) sources only pass when remote images are enabled.
+func (sz exportSanitizer) safeSrc(raw string) (string, bool) {
+	cleaned, scheme := cleanExportURL(raw)
+	switch scheme {
+	case "cid":
+		data, ok := sz.images[normalizeContentID(cleaned[len("cid:"):])]
+		return data, ok
+	case "data":
+		return cleaned, strings.HasPrefix(strings.ToLower(cleaned), "data:image/")
+	case "http", "https":
+		return cleaned, sz.remoteImages
+	}
+	return "", false
+}
+
+// isQuotedHTML recognizes the quote containers Gmail, Apple Mail, Thunderbird
+// and Yahoo wrap around replied-to text.
+func isQuotedHTML(n *html.Node) bool {
+	for _, a := range n.Attr {
+		switch strings.ToLower(a.Key) {
+		case "class":
+			for _, class := range strings.Fields(a.Val) {
+				if class == "gmail_quote" || class == "yahoo_quoted" {
+					return true
+				}
+			}
+		case "type":
+			if n.DataAtom == atom.Blockquote && strings.EqualFold(a.Val, "cite") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func wrapQuotedHTML(parent, n *html.Node, collapse bool) {
+	wrapper := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div, Attr: []html.Attribute{{Key: "class", Val: "quote"}}}
+	if collapse {
+		wrapper.Data, wrapper.DataAtom = "details", atom.Details
+		summary := &html.Node{Type: html.ElementNode, Data: "summary", DataAtom: atom.Summary}
+		summary.AppendChild(&html.Node{Type: html.TextNode, Data: "Show quoted text"})
+		wrapper.AppendChild(summary)
+	}
+	parent.InsertBefore(wrapper, n)
+	parent.RemoveChild(n)
+	wrapper.AppendChild(n)
+}
+
+var blockHTMLAtoms = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Tr: true, atom.Li: true, atom.Ul: true, atom.Ol: true, atom.Table: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true, atom.Hr: true, atom.Pre: true,
+}
+
+// exportHTMLText converts an HTML body to readable text for the markdown
+// transcript, turning blockquotes into "> " lines so they collapse like
+// quoted plain text.
+func exportHTMLText(src string) string {
+	doc, err := html.Parse(strings.NewReader(src))
+	if err != nil {
+		return stripHTMLTags(src)
+	}
+	text := strings.ReplaceAll(htmlNodeText(doc), "\u00a0", " ")
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	blank := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			blank++
+			if blank > 1 || len(out) == 0 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func htmlNodeText(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			b.WriteString(whitespacePattern.ReplaceAllString(c.Data, " "))
+		case html.ElementNode:
+			switch c.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+			case atom.Br:
+				b.WriteString("\n")
+			case atom.Img:
+				if alt := strings.TrimSpace(htmlAttr(c, "alt")); alt != "" {
+					b.WriteString("[image: " + alt + "]")
+				}
+			case atom.Blockquote:
+				b.WriteString("\n")
+				for _, line := range strings.Split(strings.TrimSpace(htmlNodeText(c)), "\n") {
+					line = strings.TrimSpace(line)
+					if strings.HasPrefix(line, ">") {
+						b.WriteString(">" + line + "\n")
+					} else {
+						b.WriteString(strings.TrimSpace("> "+line) + "\n")
+					}
+				}
+			case atom.A:
+				text := strings.TrimSpace(htmlNodeText(c))
+				href := strings.TrimSpace(htmlAttr(c, "href"))
+				if safe, ok := safeExportHref(href); ok {
+					href = safe
+				} else {
+					href = ""
+				}
+				if href == "" || href == text || strings.TrimPrefix(href, "mailto:") == text {
+					b.WriteString(text)
+				} else {
+					fmt.Fprintf(&b, "[%s](%s)", text, href)
+				}
+			default:
+				inner := htmlNodeText(c)
+				if blockHTMLAtoms[c.DataAtom] {
+					b.WriteString("\n" + inner + "\n")
+				} else {
+					b.WriteString(inner)
+				}
+			}
+		default:
+			b.WriteString(htmlNodeText(c))
+		}
+	}
+	return b.String()
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+type textSegment struct {
+	Text   string
+	Quoted bool
+}
+
+var quoteAttributionPattern = regexp.MustCompile(`(?i)^(on .+ wrote:|-+\s*original message\s*-+|.+ schrieb .+:)$`)
+
+// splitQuotedText separates "> " quoted runs from the rest of a plain-text
+// body. The "On … wrote:" line introducing a quote is kept with the quote.
+func splitQuotedText(text string) []textSegment {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var (
+		segments []textSegment
+		current  []string
+		quoted   bool
+	)
+	flush := func() {
+		s := strings.Trim(strings.Join(current, "\n"), "\n")
+		if strings.TrimSpace(s) != "" {
+			segments = append(segments, textSegment{Text: s, Quoted: quoted})
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		isQuote := strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+		blank := strings.TrimSpace(line) == ""
+		if blank || isQuote == quoted {
+			current = append(current, line)
+			continue
+		}
+		if isQuote {
+			// Pull a trailing attribution line into the quote.
+			var attribution []string
+			for i := len(current) - 1; i >= 0; i-- {
+				trimmed := strings.TrimSpace(current[i])
+				if trimmed == "" {
+					continue
+				}
+				if quoteAttributionPattern.MatchString(trimmed) {
+					attribution = append([]string{}, current[i:]...)
+					current = current[:i]
+				}
+				break
+			}
+			flush()
+			quoted = true
+			current = attribution
+			current = append(current, line)
+			continue
+		}
+		flush()
+		quoted = false
+		current = append(current, line)
+	}
+	flush()
+	return segments
+}
+
+const threadExportCSS = `body{font:15px/1.5 -apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,Helvetica,Arial,sans-serif;color:#202124;max-width:900px;margin:24px auto;padding:0 16px}
+h1{font-size:22px;margin:0 0 4px}
+.meta{color:#5f6368;font-size:13px;margin:0 0 24px}
+.message{border:1px solid #dadce0;border-radius:8px;padding:16px;margin:0 0 16px}
+.headers{border-collapse:collapse;font-size:13px;margin:0 0 12px}
+.headers th{text-align:left;color:#5f6368;font-weight:normal;padding:0 12px 2px 0;vertical-align:top;white-space:nowrap}
+.headers td{padding:0 0 2px}
+.body{overflow-wrap:anywhere}
+.body img,.images img{max-width:100%;height:auto}
+.text{white-space:pre-wrap;font-family:inherit;margin:0}
+.quote{color:#5f6368;border-left:3px solid #dadce0;padding-left:12px;margin:8px 0}
+details.quote>summary{cursor:pointer;font-size:13px}
+.images{margin:12px 0 0}
+.attachments{border-top:1px solid #dadce0;margin-top:12px;padding-top:8px;font-size:13px}
+.attachments h2{font-size:13px;margin:0 0 4px}
+.attachments ul{margin:0;padding-left:20px}
+`
+
+const threadExportPrintCSS = `@page{size:A4;margin:18mm}
+@media print{body{margin:0;max-width:none}.message{border:none;border-top:1px solid #999;border-radius:0;padding:12px 0}}
+.headers,.attachments,.images img{break-inside:avoid;page-break-inside:avoid}
+.attachments a::after{content:" (" attr(href) ")";color:#5f6368;word-break:break-all}
+`
+
+func renderThreadHTML(b *bytes.Buffer, e *threadExport, printable bool) {
+	esc := html.EscapeString
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	imgSrc := "data:"
+	if e.RemoteImages {
+		imgSrc += " http: https:"
+	}
+	fmt.Fprintf(b, "<meta http-equiv=\"Content-Security-Policy\" content=\"default-src 'none'; img-src %s; style-src 'unsafe-inline'\">\n", imgSrc)
+	fmt.Fprintf(b, "<title>%s</title>\n<style>\n%s", esc(exportSubject(e.Subject)), threadExportCSS)
+	if printable {
+		b.WriteString(threadExportPrintCSS)
+	}
+	b.WriteString("</style>\n</head>\n<body>\n")
+	fmt.Fprintf(b, "<h1>%s</h1>\n", esc(exportSubject(e.Subject)))
+	fmt.Fprintf(b, "<p class=\"meta\">Thread <a href=\"%s\">%s</a> &middot; %d message(s) &middot; %s &middot; exported %s</p>\n",
+		esc(e.URL), esc(e.ThreadID), len(e.Messages), esc(e.Account), esc(e.Exported.Format(time.RFC1123Z)))
+
+	for _, m := range e.Messages {
+		fmt.Fprintf(b, "<article class=\"message\" id=\"message-%s\">\n<table class=\"headers\">\n", esc(m.ID))
+		for _, h := range exportHeaderRows(m) {
+			fmt.Fprintf(b, "<tr><th>%s</th><td>%s</td></tr>\n", h[0], esc(h[1]))
+		}
+		b.WriteString("</table>\n<div class=\"body\">\n")
+		switch {
+		case m.HTML != "":
+			b.WriteString(sanitizeExportHTML(m.HTML, m.Images, !printable, e.RemoteImages))
+		default:
+			for _, seg := range splitQuotedText(m.Text) {
+				text := "<pre class=\"text\">" + esc(seg.Text) + "</pre>"
+				switch {
+				case !seg.Quoted:
+					b.WriteString(text)
+				case printable:
+					b.WriteString("<div class=\"quote\">" + text + "</div>")
+				default:
+					b.WriteString("<details class=\"quote\"><summary>Show quoted text</summary>" + text + "</details>")
+				}
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString("\n</div>\n")
+
+		var loose []*threadExportImage
+		for _, img := range m.Images {
+			if !img.Referenced || m.HTML == "" {
+				loose = append(loose, img)
+			}
+		}
+		if len(loose) > 0 {
+			b.WriteString("<div class=\"images\">\n")
+			for _, img := range loose {
+				fmt.Fprintf(b, "<figure><img src=\"%s\" alt=\"%s\"><figcaption>%s</figcaption></figure>\n", img.DataURI, esc(img.Filename), esc(img.Filename))
+			}
+			b.WriteString("</div>\n")
+		}
+
+		if len(m.Attachments) > 0 {
+			b.WriteString("<section class=\"attachments\">\n<h2>Attachments</h2>\n<ul>\n")
+			for _, a := range m.Attachments {
+				fmt.Fprintf(b, "<li><a href=\"%s\">%s</a> (%s, %s)</li>\n", esc(a.Link), esc(a.Filename), formatBytes(a.Size), esc(a.MimeType))
+			}
+			b.WriteString("</ul>\n</section>\n")
+		}
+		b.WriteString("</article>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+}
+
+func renderThreadMarkdown(b *bytes.Buffer, e *threadExport) {
+	fmt.Fprintf(b, "# %s\n\n", markdownEscape(exportSubject(e.Subject)))
+	fmt.Fprintf(b, "- Thread: [%s](%s) (%d message(s))\n", e.ThreadID, e.URL, len(e.Messages))
+	fmt.Fprintf(b, "- Account: %s\n", markdownEscape(e.Account))
+	fmt.Fprintf(b, "- Exported: %s\n", e.Exported.Format(time.RFC1123Z))
+
+	for i, m := range e.Messages {
+		fmt.Fprintf(b, "\n---\n\n## %d. %s\n\n", i+1, markdownEscape(m.From))
+		for _, h := range exportHeaderRows(m) {
+			fmt.Fprintf(b, "**%s:** %s  \n", h[0], markdownEscape(h[1]))
+		}
+		b.WriteString("\n")
+		for _, seg := range splitQuotedText(m.Text) {
+			if seg.Quoted {
+				fmt.Fprintf(b, "<details>\n<summary>Show quoted text</summary>\n\n%s\n\n</details>\n\n", markdownEscapeHTML(seg.Text))
+				continue
+			}
+			fmt.Fprintf(b, "%s\n\n", markdownEscapeHTML(seg.Text))
+		}
+		for _, img := range m.Images {
+			fmt.Fprintf(b, "![%s](%s)\n\n", markdownEscape(img.Filename), img.DataURI)
+		}
+		if len(m.Attachments) > 0 {
+			b.WriteString("**Attachments**\n\n")
+			for _, a := range m.Attachments {
+				fmt.Fprintf(b, "- [%s](%s) (%s, %s)\n", markdownEscape(a.Filename), a.Link, formatBytes(a.Size), a.MimeType)
+			}
+			b.WriteString("\n")
+		}
+	}
+}
+
+func exportHeaderRows(m threadExportMessage) [][2]string {
+	rows := [][2]string{{"From", m.From}, {"To", m.To}}
+	if m.Cc != "" {
+		rows = append(rows, [2]string{"Cc", m.Cc})
+	}
+	return append(rows, [2]string{"Date", m.Date}, [2]string{"Subject", m.Subject})
+}
+
+func exportSubject(subject string) string {
+	if strings.TrimSpace(subject) == "" {
+		return "(no subject)"
+	}
+	return subject
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownHTMLEscaper keeps body text from being read as raw HTML while
+// leaving the rest of its Markdown (quotes, lists, emphasis) intact.
+var markdownHTMLEscaper = strings.NewReplacer("<", `\<`)
+
+func markdownEscapeHTML(s string) string {
+	return markdownHTMLEscaper.Replace(s)
+}
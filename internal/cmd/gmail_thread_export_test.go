@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func TestSplitQuotedText(t *testing.T) {
+	text := "Sounds good.\n\nOn Mon, Jan 5, 2026 at 9:00 AM Ada <ada@example.com> wrote:\n> Can we meet?\n>\n> Ada\n\nThanks"
+	segments := splitQuotedText(text)
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %+v", segments)
+	}
+	if segments[0].Quoted || segments[0].Text != "Sounds good." {
+		t.Fatalf("unexpected first segment: %+v", segments[0])
+	}
+	if !segments[1].Quoted || !strings.HasPrefix(segments[1].Text, "On Mon") || !strings.HasSuffix(segments[1].Text, "> Ada") {
+		t.Fatalf("unexpected quote segment: %+v", segments[1])
+	}
+	if segments[2].Quoted || segments[2].Text != "Thanks" {
+		t.Fatalf("unexpected last segment: %+v", segments[2])
+	}
+}
+
+func TestSanitizeExportHTML(t *testing.T) {
+	src := `<html><head><style>body{color:red}</style></head><body onload="x()"><p onclick="y()">Hi <img src="cid:logo%40mail"></p>` +
+		`<script>alert(1)</script><a href="javascript:alert(1)">bad</a>` +
+		`<div class="gmail_quote"><div class="gmail_attr">On Mon Ada wrote:</div><blockquote class="gmail_quote">old</blockquote></div></body></html>`
+	images := []*threadExportImage{{ContentID: "logo@mail", DataURI: "data:image/png;base64,AAAA"}}
+
+	got := sanitizeExportHTML(src, images, true, false)
+	for _, bad := range []string{"<script", "onclick", "onload", "javascript:", "color:red"} {
+		if strings.Contains(got, bad) {
+			t.Fatalf("expected %q to be removed: %s", bad, got)
+		}
+	}
+	if !strings.Contains(got, `<img src="data:image/png;base64,AAAA"/>`) {
+		t.Fatalf("expected cid image to be resolved: %s", got)
+	}
+	if strings.Count(got, "<details") != 1 || !strings.Contains(got, `<details class="quote"><summary>Show quoted text</summary><div class="gmail_quote">`) {
+		t.Fatalf("expected a single collapsed quote: %s", got)
+	}
+
+	printed := sanitizeExportHTML(src, images, false, false)
+	if strings.Contains(printed, "<details") || !strings.Contains(printed, `<div class="quote"><div class="gmail_quote">`) {
+		t.Fatalf("expected an expanded quote for printing: %s", printed)
+	}
+
+	text := exportHTMLText(src)
+	if !strings.Contains(text, "On Mon Ada wrote:\n\n> old") || strings.Contains(text, "alert") {
+		t.Fatalf("unexpected text conversion: %q", text)
+	}
+}
+
+func TestSanitizeExportHTMLURLs(t *testing.T) {
+	src := `<a href="java&#x09;script:alert(1)">tab</a><a href=" JAVASCRIPT:alert(1)">upper</a>` +
+		`<a href="vbscript:msgbox(1)">vb</a><a href="data:text/html,<script>alert(1)</script>">data</a>` +
+		`<a href="https://example.com/x">ok</a><a href="mailto:ada@example.com">mail</a><a href="#top">top</a>` +
+		`<img src="https://tracker.example.com/p.gif"><img src="data:image/gif;base64,R0lG"><img src="data:text/html,x">` +
+		`<table background="https://tracker.example.com/bg.png"><tr><td>cell</td></tr></table>` +
+		`<svg><a xlink:href="javascript:alert(1)"><text>svg</text></a></svg>`
+
+	got := sanitizeExportHTML(src, nil, true, false)
+	for _, bad := range []string{"script", "vbscript", "data:text", "tracker.example.com", "<svg"} {
+		if strings.Contains(strings.ToLower(got), bad) {
+			t.Fatalf("expected %q to be removed: %s", bad, got)
+		}
+	}
+	for _, want := range []string{`href="https://example.com/x"`, `href="mailto:ada@example.com"`, `href="#top"`, `src="data:image/gif;base64,R0lG"`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %s to be kept: %s", want, got)
+		}
+	}
+
+	remote := sanitizeExportHTML(src, nil, true, true)
+	if !strings.Contains(remote, `src="https://tracker.example.com/p.gif"`) || strings.Contains(remote, "bg.png") {
+		t.Fatalf("expected only the remote img src with --remote-images: %s", remote)
+	}
+
+	var md bytes.Buffer
+	renderThreadMarkdown(&md, &threadExport{Messages: []threadExportMessage{{Text: "Hi <img src=x onerror=alert(1)>\n\n> <b>quoted</b>"}}})
+	if unescaped := strings.ReplaceAll(md.String(), `\<`, ""); strings.Contains(unescaped, "<img") || strings.Contains(unescaped, "<b>") {
+		t.Fatalf("expected raw HTML to be escaped in markdown: %s", md.String())
+	}
+
+	var page bytes.Buffer
+	renderThreadHTML(&page, &threadExport{}, false)
+	if !strings.Contains(page.String(), `content="default-src 'none'; img-src data:; style-src 'unsafe-inline'"`) {
+		t.Fatalf("expected a content security policy: %s", page.String())
+	}
+}
+
+func TestGmailThreadExportCmd(t *testing.T) {
+	b64 := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	thread := map[string]any{
+		"id": "t1",
+		"messages": []map[string]any{
+			{
+				"id": "m1",
+				"payload": map[string]any{
+					"mimeType": "multipart/mixed",
+					"headers": []map[string]any{
+						{"name": "From", "value": "Ada <ada@example.com>"},
+						{"name": "To", "value": "bob@example.com"},
+						{"name": "Subject", "value": "Contract"},
+						{"name": "Date", "value": "Mon, 5 Jan 2026 09:00:00 +0000"},
+					},
+					"parts": []map[string]any{
+						{
+							"mimeType": "multipart/related",
+							"parts": []map[string]any{
+								{"mimeType": "text/plain", "body": map[string]any{"data": b64("See the logo.\n\n> earlier note")}},
+								{"mimeType": "text/html", "body": map[string]any{"data": b64(`<p>See the <img src="cid:logo"> logo.</p><blockquote type="cite">earlier note</blockquote>`)}},
+								{
+									"mimeType": "image/png",
+									"filename": "logo.png",
+									"headers":  []map[string]any{{"name": "Content-ID", "value": "<logo>"}, {"name": "Content-Disposition", "value": "inline"}},
+									"body":     map[string]any{"attachmentId": "att-logo", "size": 3},
+								},
+							},
+						},
+						{
+							"mimeType": "application/pdf",
+							"filename": "contract v2.pdf",
+							"headers":  []map[string]any{{"name": "Content-ID", "value": "<f_1>"}, {"name": "Content-Disposition", "value": "attachment"}},
+							"body":     map[string]any{"attachmentId": "att-pdf", "size": 4},
+						},
+					},
+				},
+			},
+		},
+	}
+	rawMessage := "From: ada@example.com\r\nSubject: Contract\r\n\r\nSee the logo.\r\n"
+
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1")
+		switch {
+		case path == "/users/me/threads/t1":
+			_ = json.NewEncoder(w).Encode(thread)
+		case path == "/users/me/messages/m1/attachments/att-logo":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": b64("PNG"), "size": 3})
+		case path == "/users/me/messages/m1/attachments/att-pdf":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": b64("%PDF"), "size": 4})
+		case path == "/users/me/messages/m1" && r.URL.Query().Get("format") == "raw":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "m1", "raw": b64(rawMessage)})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := ui.WithUI(context.Background(), u)
+	flags := &RootFlags{Account: "a@b.com"}
+	dir := t.TempDir()
+	outPath := filepath.Join(dir, "transcript.html")
+
+	_ = captureStdout(t, func() {
+		args := []string{"t1", "--out", outPath, "--attachments-dir", filepath.Join(dir, "files")}
+		if err := runKong(t, &GmailThreadExportCmd{}, args, ctx, flags); err != nil {
+			t.Fatalf("export html: %v", err)
+		}
+	})
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+	got := string(data)
+	if !strings.Contains(got, `<img src="data:image/png;base64,`+base64.StdEncoding.EncodeToString([]byte("PNG"))+`"/>`) {
+		t.Fatalf("expected inline image to be embedded:\n%s", got)
+	}
+	if !strings.Contains(got, `<details class="quote">`) || strings.Contains(got, "logo.png</a>") {
+		t.Fatalf("expected collapsed quote and no logo attachment:\n%s", got)
+	}
+	if !strings.Contains(got, `<a href="files/m1_att-pdf_contract%20v2.pdf">contract v2.pdf</a>`) {
+		t.Fatalf("expected relative attachment link:\n%s", got)
+	}
+	if saved, err := os.ReadFile(filepath.Join(dir, "files", "m1_att-pdf_contract v2.pdf")); err != nil || string(saved) != "%PDF" {
+		t.Fatalf("expected attachment download, got %q (%v)", saved, err)
+	}
+
+	md := captureStdout(t, func() {
+		if err := runKong(t, &GmailThreadExportCmd{}, []string{"t1", "--format", "md"}, ctx, flags); err != nil {
+			t.Fatalf("export md: %v", err)
+		}
+	})
+	if !strings.Contains(md, "# Contract") || !strings.Contains(md, "<summary>Show quoted text</summary>\n\n> earlier note") || !strings.Contains(md, "![logo.png](data:image/png;base64,") {
+		t.Fatalf("unexpected markdown:\n%s", md)
+	}
+	if !strings.Contains(md, "- [contract v2.pdf](https://mail.google.com/mail/?authuser=a%40b.com#all/m1)") {
+		t.Fatalf("expected attachment to link to Gmail:\n%s", md)
+	}
+
+	printable := captureStdout(t, func() {
+		if err := runKong(t, &GmailThreadExportCmd{}, []string{"t1", "--format", "pdf-ready-html"}, ctx, flags); err != nil {
+			t.Fatalf("export pdf-ready-html: %v", err)
+		}
+	})
+	if !strings.Contains(printable, "@page") || strings.Contains(printable, "<details") {
+		t.Fatalf("expected print css and expanded quotes:\n%s", printable)
+	}
+
+	eml := captureStdout(t, func() {
+		if err := runKong(t, &GmailThreadExportCmd{}, []string{"t1", "--format", "eml"}, ctx, flags); err != nil {
+			t.Fatalf("export eml: %v", err)
+		}
+	})
+	if !strings.Contains(eml, "Subject: Thread export: Contract") || !strings.Contains(eml, "Content-Type: multipart/digest") || !strings.Contains(eml, "Content-Type: message/rfc822\r\n\r\n"+rawMessage) {
+		t.Fatalf("unexpected eml:\n%s", eml)
+	}
+}
@@ -20,7 +20,7 @@ import (
 const trackingUnknown = "unknown"
 
 type GmailTrackOpensCmd struct {
-	TrackingID string `arg:"" optional:"" help:"Tracking ID from send command"`
+	TrackingID string `arg:"" stdin:"" optional:"" help:"Tracking ID from send command"`
 	To         string `name:"to" help:"Filter by recipient email"`
 	Since      string `name:"since" help:"Filter by time (e.g., '24h', '2024-01-01')"`
 }
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+const (
+	unsubscribeOneClick = "one-click"
+	unsubscribeMailto   = "mailto"
+	unsubscribeManual   = "manual"
+	unsubscribeNone     = "none"
+)
+
+// unsubscribeHTTPClient performs RFC 8058 one-click POSTs. Redirects are not
+// followed: the RFC requires the POST itself to unsubscribe.
+var unsubscribeHTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+type GmailUnsubscribeCmd struct {
+	MessageIDs []string `arg:"" stdin:"" optional:"" name:"messageId" help:"Message IDs (or use --query)"`
+	Query      string   `name:"query" help:"Unsubscribe from the lists of messages matching this Gmail search query"`
+	Max        int      `name:"max" aliases:"limit" help:"With --query: look at most this many messages" default:"500"`
+	Method     string   `name:"method" help:"Unsubscribe method: auto (one-click, then mailto)|one-click|mailto" enum:"auto,one-click,mailto" default:"auto"`
+	From       string   `name:"from" help:"Send mailto unsubscribes from this verified send-as alias"`
+	Archive    bool     `name:"archive" help:"Archive the matching threads (remove INBOX)"`
+	Label      string   `name:"label" help:"Add this label (name or ID) to the matching threads"`
+}
+
+// unsubscribeTarget is one mailing list to leave, with every thread that
+// pointed at it.
+type unsubscribeTarget struct {
+	Sender    string   `json:"sender"`
+	ListID    string   `json:"listId,omitempty"`
+	Method    string   `json:"method"`
+	URL       string   `json:"url,omitempty"`
+	Mailto    string   `json:"mailto,omitempty"`
+	ThreadIDs []string `json:"threadIds"`
+	Status    string   `json:"status,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func (c *GmailUnsubscribeCmd) Run(ctx context.Context, flags *RootFlags) error {
+	u := ui.FromContext(ctx)
+	ids := make([]string, 0, len(c.MessageIDs))
+	for _, id := range c.MessageIDs {
+		if id = normalizeGmailMessageID(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	query := strings.TrimSpace(c.Query)
+	if query != "" && len(ids) > 0 {
+		return usage("use either message IDs or --query, not both")
+	}
+	if query == "" && len(ids) == 0 {
+		return usage("missing messageId (or --query)")
+	}
+	if c.Max <= 0 {
+		return usage("--max must be > 0")
+	}
+
+	account, err := requireAccount(flags)
+	if err != nil {
+		return err
+	}
+	svc, err := newGmailService(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if query != "" {
+		var truncated bool
+		ids, truncated, err = listGmailQueryMessageIDs(ctx, svc, u, query, c.Max)
+		if err != nil {
+			return err
+		}
+		if truncated {
+			u.Err().Printf("Query matched more than %d messages; looking at the newest %d (--max)", c.Max, c.Max)
+		}
+	}
+	meta := gmailMetadataQuery("From", "List-Id", "List-Unsubscribe", "List-Unsubscribe-Post")
+	meta.Set("fields", "id,threadId,payload(headers)")
+	msgs, err := fetchGmailMessages(ctx, svc, ids, meta)
+	if err != nil {
+		return err
+	}
+	targets := planUnsubscribes(msgs, c.Method)
+	if len(targets) == 0 {
+		if outfmt.IsJSON(ctx) {
+			return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"results": []unsubscribeTarget{}})
+		}
+		u.Err().Println("No messages found")
+		return nil
+	}
+
+	var addLabels, removeLabels []string
+	if strings.TrimSpace(c.Label) != "" {
+		addLabels = []string{strings.TrimSpace(c.Label)}
+	}
+	if c.Archive {
+		removeLabels = []string{"INBOX"}
+	}
+	if err := dryRunExit(ctx, flags, "gmail.unsubscribe", map[string]any{
+		"targets": targets,
+		"add":     addLabels,
+		"remove":  removeLabels,
+	}); err != nil {
+		return err
+	}
+
+	var addIDs, removeIDs []string
+	if len(addLabels) > 0 || len(removeLabels) > 0 {
+		nameToID, err := fetchLabelNameToID(svc)
+		if err != nil {
+			return err
+		}
+		addIDs = resolveLabelIDs(addLabels, nameToID)
+		removeIDs = resolveLabelIDs(removeLabels, nameToID)
+	}
+
+	fromAddr := ""
+	failed := 0
+	for _, t := range targets {
+		switch t.Method {
+		case unsubscribeOneClick:
+			err = postOneClickUnsubscribe(ctx, t.URL)
+		case unsubscribeMailto:
+			if fromAddr == "" {
+				if fromAddr, _, err = resolveSendFrom(ctx, svc, account, c.From); err != nil {
+					return err
+				}
+			}
+			err = sendMailtoUnsubscribe(ctx, svc, fromAddr, t.Mailto)
+		default:
+			err = nil
+		}
+		switch {
+		case err != nil:
+			t.Status, t.Error = "failed", err.Error()
+			failed++
+			continue
+		case t.Method == unsubscribeOneClick || t.Method == unsubscribeMailto:
+			t.Status = "unsubscribed"
+		default:
+			// Nothing we can do automatically; the thread is still cleaned up.
+			t.Status = t.Method
+		}
+		if len(addIDs) > 0 || len(removeIDs) > 0 {
+			for _, threadID := range t.ThreadIDs {
+				if _, err := svc.Users.Threads.Modify("me", threadID, &gmail.ModifyThreadRequest{
+					AddLabelIds:    addIDs,
+					RemoveLabelIds: removeIDs,
+				}).Context(ctx).Do(); err != nil {
+					return fmt.Errorf("modify thread %s: %w", threadID, err)
+				}
+			}
+		}
+	}
+
+	if err := writeUnsubscribeResults(ctx, targets); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d unsubscribes failed", failed, len(targets))
+	}
+	return nil
+}
+
+// planUnsubscribes groups messages by mailing list (List-Id, else the
+// List-Unsubscribe value, else sender) and picks how to leave each one.
+func planUnsubscribes(msgs []*gmail.Message, method string) []*unsubscribeTarget {
+	byKey := map[string]*unsubscribeTarget{}
+	var order []string
+	for _, m := range msgs {
+		if m == nil {
+			continue
+		}
+		sender := statsSenderAddress(headerValue(m.Payload, "From"))
+		listID := strings.TrimSpace(headerValue(m.Payload, "List-Id"))
+		header := headerValue(m.Payload, "List-Unsubscribe")
+		key := strings.ToLower(listID)
+		if key == "" {
+			key = strings.TrimSpace(header)
+		}
+		if key == "" {
+			key = sender
+		}
+		t := byKey[key]
+		if t == nil {
+			t = &unsubscribeTarget{Sender: sender, ListID: listID, Method: unsubscribeNone}
+			httpsURL, mailto := unsubscribeLinks(header)
+			oneClick := strings.Contains(strings.ToLower(headerValue(m.Payload, "List-Unsubscribe-Post")), "list-unsubscribe=one-click")
+			t.URL, t.Mailto = httpsURL, mailto
+			switch {
+			case httpsURL != "" && oneClick && method != unsubscribeMailto:
+				t.Method = unsubscribeOneClick
+			case mailto != "" && method != unsubscribeOneClick:
+				t.Method = unsubscribeMailto
+			case httpsURL != "" || mailto != "":
+				t.Method = unsubscribeManual
+			}
+			if t.Method == unsubscribeNone {
+				if link := bestUnsubscribeLink(m.Payload); link != "" {
+					t.URL, t.Method = link, unsubscribeManual
+				}
+			}
+			byKey[key] = t
+			order = append(order, key)
+		}
+		if m.ThreadId != "" && !slices.Contains(t.ThreadIDs, m.ThreadId) {
+			t.ThreadIDs = append(t.ThreadIDs, m.ThreadId)
+		}
+	}
+	out := make([]*unsubscribeTarget, 0, len(order))
+	for _, k := range order {
+		out = append(out, byKey[k])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Sender < out[j].Sender })
+	return out
+}
+
+// unsubscribeLinks picks the first https and mailto links of a
+// List-Unsubscribe header. Plain http links are never POSTed to.
+func unsubscribeLinks(header string) (httpsURL, mailto string) {
+	for _, link := range parseListUnsubscribe(header) {
+		lower := strings.ToLower(link)
+		switch {
+		case strings.HasPrefix(lower, "https://") && httpsURL == "":
+			httpsURL = link
+		case strings.HasPrefix(lower, "mailto:") && mailto == "":
+			mailto = link
+		}
+	}
+	return httpsURL, mailto
+}
+
+// postOneClickUnsubscribe sends the RFC 8058 one-click request.
+func postOneClickUnsubscribe(ctx context.Context, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader("List-Unsubscribe=One-Click"))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := unsubscribeHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("one-click unsubscribe: %s", resp.Status)
+	}
+	return nil
+}
+
+// sendMailtoUnsubscribe composes the message a mailto: unsubscribe URI
+// describes (RFC 6068), defaulting subject and body to "unsubscribe".
+func sendMailtoUnsubscribe(ctx context.Context, svc *gmail.Service, from, mailto string) error {
+	parsed, err := url.Parse(mailto)
+	if err != nil {
+		return err
+	}
+	to := parsed.Opaque
+	if to == "" {
+		to = parsed.Path
+	}
+	if unescaped, err := url.PathUnescape(to); err == nil {
+		to = unescaped
+	}
+	if strings.TrimSpace(to) == "" {
+		return errors.New("mailto unsubscribe has no address")
+	}
+	query := parsed.Query()
+	subject := strings.TrimSpace(query.Get("subject"))
+	if subject == "" {
+		subject = "unsubscribe"
+	}
+	body := query.Get("body")
+	if strings.TrimSpace(body) == "" {
+		body = "unsubscribe"
+	}
+
+	raw, err := buildRFC822(mailOptions{
+		From:    from,
+		To:      splitCSV(to),
+		Subject: subject,
+		Body:    body,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	_, err = svc.Users.Messages.Send("me", &gmail.Message{Raw: base64.RawURLEncoding.EncodeToString(raw)}).Context(ctx).Do()
+	return err
+}
+
+func writeUnsubscribeResults(ctx context.Context, targets []*unsubscribeTarget) error {
+	if outfmt.IsJSON(ctx) {
+		return outfmt.WriteJSON(ctx, os.Stdout, map[string]any{"results": targets})
+	}
+	w, flush := tableWriter(ctx)
+	defer flush()
+	fmt.Fprintln(w, "SENDER\tMETHOD\tSTATUS\tTHREADS\tTARGET")
+	for _, t := range targets {
+		target := t.URL
+		if t.Method == unsubscribeMailto || target == "" {
+			target = t.Mailto
+		}
+		status := t.Status
+		if t.Error != "" {
+			status += ": " + sanitizeTab(t.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", sanitizeTab(t.Sender), t.Method, status, len(t.ThreadIDs), target)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+	"github.com/steipete/gogcli/internal/ui"
+)
+
+func unsubscribeTestMessage(id, threadID string, headers map[string]string) *gmail.Message {
+	p := &gmail.MessagePart{}
+	for k, v := range headers {
+		p.Headers = append(p.Headers, &gmail.MessagePartHeader{Name: k, Value: v})
+	}
+	return &gmail.Message{Id: id, ThreadId: threadID, Payload: p}
+}
+
+func TestPlanUnsubscribes(t *testing.T) {
+	msgs := []*gmail.Message{
+		unsubscribeTestMessage("m1", "t1", map[string]string{
+			"From":                  "News <news@example.com>",
+			"List-Id":               "<news.example.com>",
+			"List-Unsubscribe":      "<mailto:leave@example.com>, <https://example.com/u/1>",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}),
+		unsubscribeTestMessage("m2", "t2", map[string]string{"From": "news@example.com", "List-Id": "<NEWS.example.com>", "List-Unsubscribe": "<https://example.com/u/2>"}),
+		unsubscribeTestMessage("m3", "t1", map[string]string{"From": "deals@shop.example", "List-Unsubscribe": "<mailto:leave@shop.example?subject=stop>, <https://shop.example/u>"}),
+		unsubscribeTestMessage("m4", "t4", map[string]string{"From": "legacy@old.example", "List-Unsubscribe": "<http://old.example/u>"}),
+		unsubscribeTestMessage("m5", "t5", map[string]string{"From": "friend@example.org"}),
+	}
+
+	targets := planUnsubscribes(msgs, "auto")
+	got := map[string]*unsubscribeTarget{}
+	for _, target := range targets {
+		got[target.Sender] = target
+	}
+	if len(targets) != 4 {
+		t.Fatalf("expected 4 targets, got %+v", targets)
+	}
+	if n := got["news@example.com"]; n.Method != unsubscribeOneClick || n.URL != "https://example.com/u/1" || strings.Join(n.ThreadIDs, ",") != "t1,t2" {
+		t.Fatalf("unexpected list target: %+v", n)
+	}
+	if d := got["deals@shop.example"]; d.Method != unsubscribeMailto || d.Mailto != "mailto:leave@shop.example?subject=stop" {
+		t.Fatalf("expected mailto without one-click: %+v", d)
+	}
+	if l := got["legacy@old.example"]; l.Method != unsubscribeManual || l.URL != "http://old.example/u" {
+		t.Fatalf("expected a manual http link: %+v", l)
+	}
+	if f := got["friend@example.org"]; f.Method != unsubscribeNone {
+		t.Fatalf("expected no method: %+v", f)
+	}
+
+	if n := planUnsubscribes(msgs[:1], "mailto")[0]; n.Method != unsubscribeMailto {
+		t.Fatalf("expected --method mailto to win: %+v", n)
+	}
+}
+
+func TestGmailUnsubscribeCmd(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		posts     []string
+		sent      []string
+		modified  []string
+		listCalls []string
+	)
+	oneClick := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		posts = append(posts, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type")+" "+string(body))
+		mu.Unlock()
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer oneClick.Close()
+	origClient := unsubscribeHTTPClient
+	t.Cleanup(func() { unsubscribeHTTPClient = origClient })
+	client := oneClick.Client()
+	client.CheckRedirect = origClient.CheckRedirect
+	unsubscribeHTTPClient = client
+
+	messages := map[string]*gmail.Message{
+		"m1": unsubscribeTestMessage("m1", "t1", map[string]string{
+			"From": "news@example.com", "List-Unsubscribe": "<" + oneClick.URL + "/u/1>", "List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}),
+		"m2": unsubscribeTestMessage("m2", "t2", map[string]string{"From": "deals@shop.example", "List-Unsubscribe": "<mailto:leave@shop.example?subject=stop%20it>"}),
+		"m3": unsubscribeTestMessage("m3", "t3", map[string]string{
+			"From": "sneaky@example.net", "List-Unsubscribe": "<" + oneClick.URL + "/redirect>", "List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}),
+	}
+	svc, cleanup := newGmailServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/")
+		switch {
+		case path == "labels":
+			_ = json.NewEncoder(w).Encode(map[string]any{"labels": []map[string]any{{"id": "INBOX", "name": "INBOX"}}})
+		case path == "messages":
+			listCalls = append(listCalls, r.URL.Query().Get("maxResults"))
+			if r.URL.Query().Get("pageToken") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m1"}, {"id": "m2"}}, "nextPageToken": "p2"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"messages": []map[string]any{{"id": "m3"}}})
+		case path == "messages/send":
+			var msg gmail.Message
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			raw, _ := base64.RawURLEncoding.DecodeString(msg.Raw)
+			sent = append(sent, string(raw))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "s1"})
+		case strings.HasPrefix(path, "threads/") && strings.HasSuffix(path, "/modify"):
+			var req gmail.ModifyThreadRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			modified = append(modified, strings.TrimSuffix(strings.TrimPrefix(path, "threads/"), "/modify")+"-"+strings.Join(req.RemoveLabelIds, ","))
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "x"})
+		case strings.HasPrefix(path, "messages/"):
+			if m := messages[strings.TrimPrefix(path, "messages/")]; m != nil {
+				_ = json.NewEncoder(w).Encode(m)
+				return
+			}
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer cleanup()
+	origNew := newGmailService
+	t.Cleanup(func() { newGmailService = origNew })
+	newGmailService = func(context.Context, string) (*gmail.Service, error) { return svc, nil }
+
+	u, err := ui.New(ui.Options{Stdout: io.Discard, Stderr: io.Discard, Color: "never"})
+	if err != nil {
+		t.Fatalf("ui.New: %v", err)
+	}
+	ctx := outfmt.WithMode(ui.WithUI(context.Background(), u), outfmt.Mode{JSON: true})
+	args := []string{"m1", "m2", "m3", "--archive"}
+
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &GmailUnsubscribeCmd{}, args, ctx, &RootFlags{Account: "a@b.com", DryRun: true}); ExitCode(err) != 0 {
+			t.Fatalf("dry run: %v", err)
+		}
+	})
+	if len(posts) != 0 || len(sent) != 0 || len(modified) != 0 {
+		t.Fatalf("dry run must not act: posts %v sent %v modified %v", posts, sent, modified)
+	}
+
+	// --query stops listing once --max IDs are collected.
+	_ = captureStdout(t, func() {
+		if err := runKong(t, &GmailUnsubscribeCmd{}, []string{"--query", "category:promotions", "--max", "2"}, ctx, &RootFlags{Account: "a@b.com", DryRun: true}); ExitCode(err) != 0 {
+			t.Fatalf("query dry run: %v", err)
+		}
+	})
+	if strings.Join(listCalls, ",") != "2" {
+		t.Fatalf("expected one list call with maxResults=2, got %v", listCalls)
+	}
+
+	var runErr error
+	out := captureStdout(t, func() {
+		runErr = runKong(t, &GmailUnsubscribeCmd{}, args, ctx, &RootFlags{Account: "a@b.com"})
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "1 of 3 unsubscribes failed") {
+		t.Fatalf("expected the redirecting endpoint to fail, got %v", runErr)
+	}
+	var parsed struct {
+		Results []unsubscribeTarget `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	statuses := map[string]string{}
+	for _, r := range parsed.Results {
+		statuses[r.Sender] = r.Status
+	}
+	if statuses["news@example.com"] != "unsubscribed" || statuses["deals@shop.example"] != "unsubscribed" || statuses["sneaky@example.net"] != "failed" {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if len(posts) != 2 || posts[0] != "POST /u/1 application/x-www-form-urlencoded List-Unsubscribe=One-Click" {
+		t.Fatalf("unexpected one-click requests: %v", posts)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0], "To: leave@shop.example") || !strings.Contains(sent[0], "Subject: stop it") {
+		t.Fatalf("unexpected mailto unsubscribe: %q", sent)
+	}
+	sort.Strings(modified)
+	if strings.Join(modified, " ") != "t1-INBOX t2-INBOX" {
+		t.Fatalf("expected only unsubscribed threads to be archived, got %v", modified)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -274,21 +275,11 @@ func (s *gmailWatchServer) fetchMessages(ctx context.Context, svc *gmail.Service
 	if s.cfg.IncludeBody {
 		format = gmailFormatFull
 	}
-	for _, id := range ids {
-		if strings.TrimSpace(id) == "" {
-			continue
-		}
-		msg, err := svc.Users.Messages.Get("me", id).
-			Format(format).
-			MetadataHeaders("From", "To", "Subject", "Date").
-			Context(ctx).
-			Do()
-		if err != nil {
-			if isNotFoundAPIError(err) {
-				continue
-			}
-			return nil, excluded, err
-		}
+	fetched, err := s.getMessages(ctx, svc, ids, format)
+	if err != nil {
+		return nil, excluded, err
+	}
+	for _, msg := range fetched {
 		if msg == nil {
 			continue
 		}
@@ -318,6 +309,52 @@ func (s *gmailWatchServer) fetchMessages(ctx context.Context, svc *gmail.Service
 	return messages, excluded, nil
 }
 
+// getMessages fetches ids in order, skipping messages deleted in the meantime (404).
+func (s *gmailWatchServer) getMessages(ctx context.Context, svc *gmail.Service, ids []string, format string) ([]*gmail.Message, error) {
+	wanted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if strings.TrimSpace(id) != "" {
+			wanted = append(wanted, id)
+		}
+	}
+
+	if b := batcherFor(svc); b != nil && len(wanted) > 1 {
+		query := url.Values{"format": {format}, "metadataHeaders": {"From", "To", "Subject", "Date"}}
+		fetched, errs, err := batchGetGmailMessages(ctx, b, wanted, query)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]*gmail.Message, 0, len(fetched))
+		for i, msg := range fetched {
+			if errs[i] != nil {
+				if isNotFoundAPIError(errs[i]) {
+					continue
+				}
+				return nil, errs[i]
+			}
+			out = append(out, msg)
+		}
+		return out, nil
+	}
+
+	out := make([]*gmail.Message, 0, len(wanted))
+	for _, id := range wanted {
+		msg, err := svc.Users.Messages.Get("me", id).
+			Format(format).
+			MetadataHeaders("From", "To", "Subject", "Date").
+			Context(ctx).
+			Do()
+		if err != nil {
+			if isNotFoundAPIError(err) {
+				continue
+			}
+			return nil, err
+		}
+		out = append(out, msg)
+	}
+	return out, nil
+}
+
 func (s *gmailWatchServer) isExcludedLabel(labelIDs []string) bool {
 	if len(labelIDs) == 0 || len(s.excludeLabelIDs) == 0 {
 		return false
@@ -335,45 +372,40 @@ func (s *gmailWatchServer) isExcludedLabel(labelIDs []string) bool {
 }
 
 func (s *gmailWatchServer) sendHook(ctx context.Context, payload *gmailHookPayload) error {
+	status, note, err := postHook(ctx, s.hookClient, s.cfg.HookURL, s.cfg.HookToken, payload)
+	_ = s.store.Update(func(state *gmailWatchState) error {
+		state.LastDeliveryStatus = status
+		state.LastDeliveryAtMs = time.Now().UnixMilli()
+		state.LastDeliveryStatusNote = note
+		return nil
+	})
+	return err
+}
+
+// postHook POSTs payload as JSON to hookURL and returns the delivery status
+// ("ok", "error" or "http_error") and note to record in watch state.
+func postHook(ctx context.Context, client *http.Client, hookURL, hookToken string, payload any) (string, string, error) {
 	data, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return "error", err.Error(), err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.HookURL, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hookURL, bytes.NewReader(data))
 	if err != nil {
-		return err
+		return "error", err.Error(), err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	if s.cfg.HookToken != "" {
-		req.Header.Set("Authorization", "Bearer "+s.cfg.HookToken)
+	if hookToken != "" {
+		req.Header.Set("Authorization", "Bearer "+hookToken)
 	}
-	resp, err := s.hookClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		_ = s.store.Update(func(state *gmailWatchState) error {
-			state.LastDeliveryStatus = "error"
-			state.LastDeliveryAtMs = time.Now().UnixMilli()
-			state.LastDeliveryStatusNote = err.Error()
-			return nil
-		})
-		return err
+		return "error", err.Error(), err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		_ = s.store.Update(func(state *gmailWatchState) error {
-			state.LastDeliveryStatus = gmailWatchStatusHTTPError
-			state.LastDeliveryAtMs = time.Now().UnixMilli()
-			state.LastDeliveryStatusNote = fmt.Sprintf("status %d", resp.StatusCode)
-			return nil
-		})
-		return fmt.Errorf("hook status %d", resp.StatusCode)
+		return gmailWatchStatusHTTPError, fmt.Sprintf("status %d", resp.StatusCode), fmt.Errorf("hook status %d", resp.StatusCode)
 	}
-	_ = s.store.Update(func(state *gmailWatchState) error {
-		state.LastDeliveryStatus = "ok"
-		state.LastDeliveryAtMs = time.Now().UnixMilli()
-		state.LastDeliveryStatusNote = ""
-		return nil
-	})
-	return nil
+	return "ok", "", nil
 }
 
 func parsePubSubPush(r *http.Request) (*pubsubPushEnvelope, error) {
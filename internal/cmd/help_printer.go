@@ -85,7 +85,7 @@ func helpColorMode(args []string) string {
 	}
 	for i := 0; i < len(args); i++ {
 		a := args[i]
-		if a == "--plain" || a == "--json" {
+		if a == "--plain" || a == "--json" || a == "--ndjson" {
 			return colorNever
 		}
 		if a == "--color" && i+1 < len(args) {
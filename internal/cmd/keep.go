@@ -59,6 +59,10 @@ func (c *KeepListCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd)
 		return resp.Notes, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var notes []*keepapi.Note
 	nextPageToken := ""
 	if c.All {
@@ -201,7 +205,7 @@ func (c *KeepSearchCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd
 }
 
 type KeepGetCmd struct {
-	NoteID string `arg:"" name:"noteId" help:"Note ID or name (e.g. notes/abc123)"`
+	NoteID string `arg:"" stdin:"" name:"noteId" help:"Note ID or name (e.g. notes/abc123)"`
 }
 
 func (c *KeepGetCmd) Run(ctx context.Context, flags *RootFlags, keep *KeepCmd) error {
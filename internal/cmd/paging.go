@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
+
+	"github.com/steipete/gogcli/internal/outfmt"
 )
 
 const emptyResultsExitCode = 3
@@ -17,27 +21,97 @@ func failEmptyExit(failEmpty bool) error {
 // collectAllPages keeps calling fetch until it returns an empty next page token.
 // It guards against pagination loops by tracking seen page tokens.
 func collectAllPages[T any](startPageToken string, fetch func(pageToken string) ([]T, string, error)) ([]T, error) {
+	var out []T
+	if err := streamAllPages(startPageToken, fetch, func(items []T) error {
+		out = append(out, items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// streamAllPages is the incremental form of collectAllPages: emit receives each
+// page as soon as it is fetched, so callers never hold more than one page.
+func streamAllPages[T any](startPageToken string, fetch func(pageToken string) ([]T, string, error), emit func(items []T) error) error {
 	pageToken := strings.TrimSpace(startPageToken)
 	seen := map[string]bool{}
 
-	var out []T
 	for i := 0; i < 10_000; i++ {
 		if seen[pageToken] {
-			return nil, fmt.Errorf("pagination loop: repeated page token %q", pageToken)
+			return fmt.Errorf("pagination loop: repeated page token %q", pageToken)
 		}
 		seen[pageToken] = true
 
 		items, next, err := fetch(pageToken)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := emit(items); err != nil {
+			return err
 		}
-		out = append(out, items...)
 
 		next = strings.TrimSpace(next)
 		if next == "" {
-			return out, nil
+			return nil
 		}
 		pageToken = next
 	}
-	return nil, fmt.Errorf("pagination exceeded max pages")
+	return fmt.Errorf("pagination exceeded max pages")
+}
+
+// streamAllPagesNDJSON writes every item of every page to stdout as an NDJSON
+// line and returns the number of items written.
+func streamAllPagesNDJSON[T any](ctx context.Context, startPageToken string, fetch func(pageToken string) ([]T, string, error)) (int, error) {
+	return streamAllPagesNDJSONMapped(ctx, startPageToken, fetch, func(items []T) ([]T, error) { return items, nil })
+}
+
+// streamAllPagesNDJSONMapped is like streamAllPagesNDJSON but converts each page
+// (e.g. enriching IDs with details) before it is written.
+func streamAllPagesNDJSONMapped[T, R any](ctx context.Context, startPageToken string, fetch func(pageToken string) ([]T, string, error), convert func(items []T) ([]R, error)) (int, error) {
+	count := 0
+	err := streamAllPages(startPageToken, fetch, func(items []T) error {
+		if len(items) == 0 {
+			return nil
+		}
+		out, err := convert(items)
+		if err != nil {
+			return err
+		}
+		for _, it := range out {
+			if err := outfmt.WriteJSONLine(ctx, os.Stdout, it); err != nil {
+				return err
+			}
+		}
+		count += len(out)
+		return nil
+	})
+	return count, err
+}
+
+// handleAllPagesNDJSON streams every page when --all is combined with NDJSON
+// output. It reports whether it handled the call; when it did, err is the
+// command's result, including the --fail-empty exit for an empty stream.
+func handleAllPagesNDJSON[T any](ctx context.Context, all bool, page string, failEmpty bool, fetch func(pageToken string) ([]T, string, error)) (bool, error) {
+	return handleAllPagesNDJSONMapped(ctx, all, page, failEmpty, fetch, func(items []T) ([]T, error) { return items, nil })
+}
+
+// handleAllPagesNDJSONMapped is handleAllPagesNDJSON with a per-page conversion.
+func handleAllPagesNDJSONMapped[T, R any](ctx context.Context, all bool, page string, failEmpty bool, fetch func(pageToken string) ([]T, string, error), convert func(items []T) ([]R, error)) (bool, error) {
+	if !all || !outfmt.IsNDJSON(ctx) {
+		return false, nil
+	}
+	count, err := streamAllPagesNDJSONMapped(ctx, page, fetch, convert)
+	if err != nil {
+		return true, err
+	}
+	return true, streamedResultExit(count, failEmpty)
+}
+
+// streamedResultExit maps the number of streamed items to the --fail-empty exit code.
+func streamedResultExit(count int, failEmpty bool) error {
+	if count == 0 {
+		return failEmptyExit(failEmpty)
+	}
+	return nil
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+)
+
+func TestStreamAllPages_EmitsEachPage(t *testing.T) {
+	pages := map[string][]int{"": {1, 2}, "b": {3}, "c": {}}
+	next := map[string]string{"": "b", "b": "c"}
+
+	var seen [][]int
+	err := streamAllPages("", func(token string) ([]int, string, error) {
+		return pages[token], next[token], nil
+	}, func(items []int) error {
+		seen = append(seen, items)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("streamAllPages: %v", err)
+	}
+	if len(seen) != 3 || len(seen[0]) != 2 || seen[1][0] != 3 || len(seen[2]) != 0 {
+		t.Fatalf("unexpected pages: %#v", seen)
+	}
+}
+
+func TestCollectAllPages_DetectsLoop(t *testing.T) {
+	_, err := collectAllPages("", func(token string) ([]int, string, error) {
+		return []int{1}, "same", nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "pagination loop") {
+		t.Fatalf("expected pagination loop error, got %v", err)
+	}
+}
+
+func TestHandleAllPagesNDJSON(t *testing.T) {
+	fetch := func(token string) ([]int, string, error) {
+		if token == "" {
+			return []int{1, 2}, "b", nil
+		}
+		return []int{3}, "", nil
+	}
+	ndjson := outfmt.WithMode(context.Background(), outfmt.Mode{NDJSON: true})
+
+	if handled, err := handleAllPagesNDJSON(ndjson, false, "", false, fetch); handled || err != nil {
+		t.Fatalf("expected calls without --all to fall through, got %v %v", handled, err)
+	}
+	if handled, err := handleAllPagesNDJSON(context.Background(), true, "", false, fetch); handled || err != nil {
+		t.Fatalf("expected non-NDJSON output to fall through, got %v %v", handled, err)
+	}
+
+	var handled bool
+	var err error
+	out := captureStdout(t, func() {
+		handled, err = handleAllPagesNDJSON(ndjson, true, "", false, fetch)
+	})
+	if !handled || err != nil || out != "1\n2\n3\n" {
+		t.Fatalf("unexpected stream: %v %v %q", handled, err, out)
+	}
+
+	empty := func(string) ([]int, string, error) { return nil, "", nil }
+	_ = captureStdout(t, func() {
+		handled, err = handleAllPagesNDJSON(ndjson, true, "", true, empty)
+	})
+	if !handled || ExitCode(err) != emptyResultsExitCode {
+		t.Fatalf("expected the --fail-empty exit, got %v %v", handled, err)
+	}
+}
@@ -18,7 +18,7 @@ const (
 )
 
 type PeopleGetCmd struct {
-	UserID string `arg:"" name:"userId" help:"User ID (people/...)"`
+	UserID string `arg:"" stdin:"" name:"userId" help:"User ID (people/...)"`
 }
 
 func (c *PeopleGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -109,6 +109,12 @@ func (c *PeopleSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.People, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSONMapped(ctx, c.All, c.Page, c.FailEmpty, fetch, func(items []*people.Person) ([]directoryPersonItem, error) {
+		return directoryPersonItems(items), nil
+	}); handled {
+		return err
+	}
+
 	var peopleList []*people.Person
 	nextPageToken := ""
 	if c.All {
@@ -126,22 +132,7 @@ func (c *PeopleSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 	}
 
 	if outfmt.IsJSON(ctx) {
-		type item struct {
-			Resource string `json:"resource"`
-			Name     string `json:"name,omitempty"`
-			Email    string `json:"email,omitempty"`
-		}
-		items := make([]item, 0, len(peopleList))
-		for _, p := range peopleList {
-			if p == nil {
-				continue
-			}
-			items = append(items, item{
-				Resource: p.ResourceName,
-				Name:     primaryName(p),
-				Email:    primaryEmail(p),
-			})
-		}
+		items := directoryPersonItems(peopleList)
 		if err := outfmt.WriteJSON(ctx, os.Stdout, map[string]any{
 			"people":        items,
 			"nextPageToken": nextPageToken,
@@ -177,7 +168,7 @@ func (c *PeopleSearchCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type PeopleRelationsCmd struct {
-	UserID string `arg:"" optional:"" name:"userId" help:"User ID (people/...)"`
+	UserID string `arg:"" stdin:"" optional:"" name:"userId" help:"User ID (people/...)"`
 	Type   string `name:"type" help:"Filter relation type"`
 }
 
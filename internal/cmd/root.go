@@ -15,9 +15,11 @@ import (
 	"github.com/steipete/gogcli/internal/authclient"
 	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/errfmt"
+	"github.com/steipete/gogcli/internal/googleapi"
 	"github.com/steipete/gogcli/internal/googleauth"
 	"github.com/steipete/gogcli/internal/outfmt"
 	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/tracing"
 	"github.com/steipete/gogcli/internal/ui"
 )
 
@@ -28,18 +30,28 @@ const (
 )
 
 type RootFlags struct {
-	Color          string `help:"Color output: auto|always|never" default:"${color}"`
-	Account        string `help:"Account email for API commands (gmail/calendar/chat/classroom/drive/docs/slides/contacts/tasks/people/sheets/forms/appscript)" aliases:"acct" short:"a"`
-	Client         string `help:"OAuth client name (selects stored credentials + token bucket)" default:"${client}"`
-	EnableCommands string `help:"Comma-separated list of enabled top-level commands (restricts CLI)" default:"${enabled_commands}"`
-	JSON           bool   `help:"Output JSON to stdout (best for scripting)" default:"${json}" aliases:"machine" short:"j"`
-	Plain          bool   `help:"Output stable, parseable text to stdout (TSV; no colors)" default:"${plain}" aliases:"tsv" short:"p"`
-	ResultsOnly    bool   `name:"results-only" help:"In JSON mode, emit only the primary result (drops envelope fields like nextPageToken)"`
-	Select         string `name:"select" aliases:"pick,project" help:"In JSON mode, select comma-separated fields (best-effort; supports dot paths). Desire path: use --fields for most commands."`
-	DryRun         bool   `help:"Do not make changes; print intended actions and exit successfully" aliases:"noop,preview,dryrun" short:"n"`
-	Force          bool   `help:"Skip confirmations for destructive commands" aliases:"yes,assume-yes" short:"y"`
-	NoInput        bool   `help:"Never prompt; fail instead (useful for CI)" aliases:"non-interactive,noninteractive"`
-	Verbose        bool   `help:"Enable verbose logging" short:"v"`
+	Color            string `help:"Color output: auto|always|never" default:"${color}"`
+	Account          string `help:"Account email for API commands (gmail/calendar/chat/classroom/drive/docs/slides/contacts/tasks/people/sheets/forms/appscript)" aliases:"acct" short:"a"`
+	Client           string `help:"OAuth client name (selects stored credentials + token bucket)" default:"${client}"`
+	EnableCommands   string `help:"Comma-separated list of enabled top-level commands (restricts CLI)" default:"${enabled_commands}"`
+	JSON             bool   `help:"Output JSON to stdout (best for scripting)" default:"${json}" aliases:"machine" short:"j"`
+	Plain            bool   `help:"Output stable, parseable text to stdout (TSV; no colors)" default:"${plain}" aliases:"tsv" short:"p"`
+	OutputFormat     string `name:"output-format" short:"o" help:"Output format: json|ndjson|plain|csv|yaml|markdown|table (overrides --json/--plain/--ndjson; --select picks columns)" default:"${output_format}"`
+	NDJSON           bool   `name:"ndjson" help:"Output newline-delimited JSON (one object per result; --all streams pages as they arrive)" default:"${ndjson}" aliases:"jsonl"`
+	ResultsOnly      bool   `name:"results-only" help:"In JSON mode, emit only the primary result (drops envelope fields like nextPageToken)"`
+	Select           string `name:"select" aliases:"pick,project" help:"In JSON mode, select comma-separated fields: dot paths, [N] indexes, [*] wildcards, [?field=='x'] filters, path:alias renames, $ for the whole document. Desire path: use --fields for most commands."`
+	DryRun           bool   `help:"Do not make changes; print intended actions and exit successfully" aliases:"noop,preview,dryrun" short:"n"`
+	Force            bool   `help:"Skip confirmations for destructive commands" aliases:"yes,assume-yes" short:"y"`
+	NoInput          bool   `help:"Never prompt; fail instead (useful for CI)" aliases:"non-interactive,noninteractive"`
+	Verbose          bool   `help:"Enable verbose logging" short:"v"`
+	NoCache          bool   `name:"no-cache" help:"Bypass the on-disk HTTP response cache"`
+	CacheTTL         string `name:"cache-ttl" help:"Cache Google API GET responses on disk for this long (e.g. 5m; 0 disables; default from config http_cache_ttl)" default:"${cache_ttl}"`
+	MaxRetries       string `name:"max-retries" help:"Retries per API call for 429, 5xx and transient network errors (overrides config retry settings)" default:"${max_retries}"`
+	HTTPTimeout      string `name:"http-timeout" help:"Timeout per API call including retries (e.g. 2m; 0 disables; default 30s). Desire path: --timeout on commands without their own --timeout." default:"${http_timeout}"`
+	Trace            string `name:"trace" help:"Write a span trace of this run (command, API calls, retry attempts) to a JSON file" default:"${trace}"`
+	TraceFormat      string `name:"trace-format" help:"Trace file format: otlp (OTLP-JSON) or chrome (chrome://tracing, Perfetto)" default:"${trace_format}"`
+	StdinIDs         bool   `name:"stdin-ids" help:"Read IDs for the command's ID argument from stdin (one per line; TSV first column or NDJSON \"id\"). Same as passing - as the ID."`
+	StdinConcurrency int    `name:"stdin-concurrency" help:"Parallel runs when reading single IDs from stdin" default:"4"`
 }
 
 type CLI struct {
@@ -78,6 +90,7 @@ type CLI struct {
 	Forms      FormsCmd              `cmd:"" aliases:"form" help:"Google Forms"`
 	AppScript  AppScriptCmd          `cmd:"" name:"appscript" aliases:"script,apps-script" help:"Google Apps Script"`
 	Config     ConfigCmd             `cmd:"" help:"Manage configuration"`
+	Cache      CacheCmd              `cmd:"" help:"Manage the on-disk HTTP response cache"`
 	ExitCodes  AgentExitCodesCmd     `cmd:"" name:"exit-codes" aliases:"exitcodes" help:"Print stable exit codes (alias for 'agent exit-codes')"`
 	Agent      AgentCmd              `cmd:"" help:"Agent-friendly helpers"`
 	Schema     SchemaCmd             `cmd:"" help:"Machine-readable command/flag schema" aliases:"help-json,helpjson"`
@@ -91,10 +104,34 @@ type exitPanic struct{ code int }
 func Execute(args []string) (err error) {
 	args = rewriteDesirePathArgs(args)
 
+	stdinPlan, err := planStdinIDs(args)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(err))
+		return err
+	}
+	if stdinPlan != nil {
+		args = stdinPlan.args
+		if stdinPlan.splice {
+			ids, readErr := readStdinIDs()
+			if readErr != nil {
+				_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(readErr))
+				return readErr
+			}
+			if checkErr := stdinPlan.checkIDs(ids); checkErr != nil {
+				_, _ = fmt.Fprintln(os.Stderr, errfmt.Format(checkErr))
+				return checkErr
+			}
+			args = stdinPlan.expand(ids)
+			stdinPlan = nil
+		}
+	}
+
 	parser, cli, err := newParser(helpDescription())
 	if err != nil {
 		return err
 	}
+	args = rewriteOutputFormatArgs(args, parser.Model)
+	args = rewriteTimeoutArgs(args, parser.Model)
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -132,23 +169,73 @@ func Execute(args []string) (err error) {
 
 	// Opt-in "agent mode": default to JSON when stdout is piped/non-TTY.
 	// We intentionally do this after parsing so `--plain` can override it.
-	if envBool("GOG_AUTO_JSON") && !cli.JSON && !cli.Plain && !term.IsTerminal(int(os.Stdout.Fd())) {
+	if envBool("GOG_AUTO_JSON") && !cli.JSON && !cli.Plain && !cli.NDJSON && cli.OutputFormat == "" && !term.IsTerminal(int(os.Stdout.Fd())) {
 		cli.JSON = true
 	}
 
-	mode, err := outfmt.FromFlags(cli.JSON, cli.Plain)
+	mode, err := outfmt.FromFlags(cli.JSON, cli.Plain, cli.NDJSON)
+	if strings.TrimSpace(cli.OutputFormat) != "" {
+		mode, err = outfmt.FromFormat(cli.OutputFormat)
+	}
 	if err != nil {
 		return newUsageError(err)
 	}
 
+	selectFields := outfmt.SplitSelect(cli.Select)
+	if err = outfmt.ValidateSelect(selectFields); err != nil {
+		return newUsageError(err)
+	}
+
 	ctx := context.Background()
 	ctx = outfmt.WithMode(ctx, mode)
 	ctx = outfmt.WithJSONTransform(ctx, outfmt.JSONTransform{
-		ResultsOnly: cli.ResultsOnly,
-		Select:      splitCommaList(cli.Select),
+		// NDJSON is a stream of results, so envelopes are always unwrapped.
+		ResultsOnly: cli.ResultsOnly || mode.NDJSON,
+		Select:      selectFields,
 	})
 	ctx = authclient.WithClient(ctx, cli.Client)
 
+	cacheTTL, err := config.ParseHTTPCacheTTL(cli.CacheTTL)
+	if err != nil {
+		return newUsageError(err)
+	}
+	retryOverrides, err := retryOverridesFromFlags(cli.MaxRetries, cli.HTTPTimeout)
+	if err != nil {
+		return newUsageError(err)
+	}
+	ctx = googleapi.WithRetryOverrides(ctx, retryOverrides)
+
+	ctx = googleapi.WithCacheOptions(ctx, googleapi.CacheOptions{
+		TTL: cacheTTL,
+		// An explicit --cache-ttl=0 also overrides a TTL from config.
+		Disabled: cli.NoCache || (strings.TrimSpace(cli.CacheTTL) != "" && cacheTTL == 0),
+	})
+
+	traceFormat, err := tracing.ParseFormat(cli.TraceFormat)
+	if err != nil {
+		return newUsageError(err)
+	}
+	if tracePath := strings.TrimSpace(cli.Trace); tracePath != "" {
+		tracer := tracing.New()
+		var span *tracing.Span
+		ctx, span = tracer.Start(ctx, "gog "+kctx.Command())
+		span.SetAttr("gog.command", kctx.Command())
+		span.SetAttr("gog.version", VersionString())
+		if account := strings.TrimSpace(cli.Account); account != "" {
+			span.SetAttr("gog.account", account)
+		}
+		defer func() {
+			if err != nil {
+				span.SetError(err)
+				span.SetAttr("gog.exit_code", ExitCode(err))
+			}
+			span.Finish()
+			if writeErr := tracer.WriteFile(tracePath, traceFormat); writeErr != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", writeErr)
+			}
+		}()
+	}
+
 	uiColor := cli.Color
 	if outfmt.IsJSON(ctx) || outfmt.IsPlain(ctx) {
 		uiColor = colorNever
@@ -167,7 +254,11 @@ func Execute(args []string) (err error) {
 	kctx.BindTo(ctx, (*context.Context)(nil))
 	kctx.Bind(&cli.RootFlags)
 
-	err = kctx.Run()
+	if stdinPlan != nil {
+		err = runStdinIDs(ctx, stdinPlan, cli.StdinConcurrency)
+	} else {
+		err = kctx.Run()
+	}
 	if err == nil {
 		return nil
 	}
@@ -222,9 +313,36 @@ func rewriteDesirePathArgs(args []string) []string {
 	return out
 }
 
-func isCalendarEventsCommand(args []string) bool {
-	cmdTokens := make([]string, 0, 2)
-	for i := 0; i < len(args); i++ {
+// rewriteTimeoutArgs maps `--timeout` to the global `--http-timeout`, except for
+// commands that define their own `--timeout` (e.g. the auth flows).
+func rewriteTimeoutArgs(args []string, app *kong.Application) []string {
+	if commandOwnsFlag(args, app, "timeout") {
+		return args
+	}
+
+	out := make([]string, 0, len(args))
+	for i, a := range args {
+		if a == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		if a == "--timeout" {
+			out = append(out, "--http-timeout")
+			continue
+		}
+		if v, ok := strings.CutPrefix(a, "--timeout="); ok {
+			out = append(out, "--http-timeout="+v)
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// leadingCommandTokens returns up to n lower-cased positional tokens, skipping global flags.
+func leadingCommandTokens(args []string, n int) []string {
+	tokens := make([]string, 0, n)
+	for i := 0; i < len(args) && len(tokens) < n; i++ {
 		a := args[i]
 		if a == "--" {
 			break
@@ -235,26 +353,89 @@ func isCalendarEventsCommand(args []string) bool {
 			}
 			continue
 		}
-		cmdTokens = append(cmdTokens, a)
-		if len(cmdTokens) >= 2 {
+		tokens = append(tokens, strings.TrimSpace(strings.ToLower(a)))
+	}
+	return tokens
+}
+
+// rewriteOutputFormatArgs maps `--output <format>` to the global `--output-format`
+// when the value is a known format name (csv, yaml, table, ...). Commands that
+// define their own `--output` (an alias of `--out <path>`) keep it, so a file
+// named `csv` can still be written.
+func rewriteOutputFormatArgs(args []string, app *kong.Application) []string {
+	if commandOwnsFlag(args, app, "output") {
+		return args
+	}
+
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			out = append(out, args[i:]...)
 			break
 		}
+		if a == "--output" && i+1 < len(args) && outfmt.IsFormatName(args[i+1]) {
+			out = append(out, "--output-format="+args[i+1])
+			i++
+			continue
+		}
+		if v, ok := strings.CutPrefix(a, "--output="); ok && outfmt.IsFormatName(v) {
+			out = append(out, "--output-format="+v)
+			continue
+		}
+		out = append(out, a)
 	}
+	return out
+}
 
+// commandOwnsFlag resolves the command named by args in the CLI model and reports
+// whether it, or a parent command, has a flag named or aliased name.
+func commandOwnsFlag(args []string, app *kong.Application, name string) bool {
+	if app == nil {
+		return false
+	}
+	node := app.Node
+	for _, token := range leadingCommandTokens(args, 8) {
+		child := findChildCommand(node, token)
+		if child == nil {
+			break
+		}
+		node = child
+		if nodeHasFlag(node, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeHasFlag(node *kong.Node, name string) bool {
+	for _, flag := range node.Flags {
+		if flag.Name == name {
+			return true
+		}
+		for _, a := range flag.Aliases {
+			if a == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isCalendarEventsCommand(args []string) bool {
+	cmdTokens := leadingCommandTokens(args, 2)
 	if len(cmdTokens) < 2 {
 		return false
 	}
-	cmd0 := strings.TrimSpace(strings.ToLower(cmdTokens[0]))
-	cmd1 := strings.TrimSpace(strings.ToLower(cmdTokens[1]))
-	if cmd0 != "calendar" && cmd0 != "cal" {
+	if cmdTokens[0] != "calendar" && cmdTokens[0] != "cal" {
 		return false
 	}
-	return cmd1 == "events" || cmd1 == "ls" || cmd1 == "list"
+	return cmdTokens[1] == "events" || cmdTokens[1] == "ls" || cmdTokens[1] == "list"
 }
 
 func globalFlagTakesValue(flag string) bool {
 	switch flag {
-	case "--color", "--account", "--acct", "--client", "--enable-commands", "--select", "--pick", "--project", "-a":
+	case "--color", "--account", "--acct", "--client", "--enable-commands", "--select", "--pick", "--project", "--output-format", "--cache-ttl", "--max-retries", "--http-timeout", "--timeout", "--trace", "--trace-format", "--stdin-concurrency", "-a", "-o":
 		return true
 	default:
 		return false
@@ -298,11 +479,18 @@ func newParser(description string) (*kong.Kong, *CLI, error) {
 	vars := kong.Vars{
 		"auth_services":    googleauth.UserServiceCSV(),
 		"color":            envOr("GOG_COLOR", "auto"),
+		"cache_ttl":        envOr("GOG_CACHE_TTL", ""),
 		"calendar_weekday": envOr("GOG_CALENDAR_WEEKDAY", "false"),
 		"client":           envOr("GOG_CLIENT", ""),
 		"enabled_commands": envOr("GOG_ENABLE_COMMANDS", ""),
+		"http_timeout":     envOr("GOG_TIMEOUT", ""),
+		"max_retries":      envOr("GOG_MAX_RETRIES", ""),
 		"json":             boolString(envMode.JSON),
+		"ndjson":           boolString(envMode.NDJSON),
+		"output_format":    envOr("GOG_OUTPUT", ""),
 		"plain":            boolString(envMode.Plain),
+		"trace":            envOr("GOG_TRACE", ""),
+		"trace_format":     envOr("GOG_TRACE_FORMAT", ""),
 		"version":          VersionString(),
 	}
 
@@ -356,3 +544,26 @@ func newUsageError(err error) error {
 	}
 	return &ExitError{Code: 2, Err: err}
 }
+
+func retryOverridesFromFlags(maxRetries string, timeout string) (config.RetryConfig, error) {
+	var out config.RetryConfig
+
+	if v := strings.TrimSpace(maxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return config.RetryConfig{}, fmt.Errorf("invalid --max-retries %q (expected a non-negative integer)", maxRetries)
+		}
+		out.MaxRetries429 = &n
+		out.MaxRetries5xx = &n
+		out.MaxRetriesNetwork = &n
+	}
+
+	if v := strings.TrimSpace(timeout); v != "" {
+		if _, err := config.ParseRetryDuration(v); err != nil {
+			return config.RetryConfig{}, fmt.Errorf("invalid --timeout: %w", err)
+		}
+		out.Timeout = v
+	}
+
+	return out, nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import "testing"
+
+func TestExecute_InvalidRetryFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--max-retries", "-2", "cache", "stats"},
+		{"--timeout", "forever", "cache", "stats"},
+	} {
+		_ = captureStderr(t, func() {
+			if err := Execute(args); ExitCode(err) != 2 {
+				t.Fatalf("%v: expected usage exit 2, got %v", args, err)
+			}
+		})
+	}
+}
+
+func TestRetryOverridesFromFlags(t *testing.T) {
+	rc, err := retryOverridesFromFlags("5", "2m")
+	if err != nil {
+		t.Fatalf("overrides: %v", err)
+	}
+	if rc.MaxRetries429 == nil || *rc.MaxRetries429 != 5 || *rc.MaxRetries5xx != 5 || *rc.MaxRetriesNetwork != 5 || rc.Timeout != "2m" {
+		t.Fatalf("unexpected overrides: %#v", rc)
+	}
+
+	rc, err = retryOverridesFromFlags("", "")
+	if err != nil || rc.MaxRetries5xx != nil || rc.Timeout != "" {
+		t.Fatalf("expected empty overrides, got %#v err=%v", rc, err)
+	}
+}
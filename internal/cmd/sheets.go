@@ -38,7 +38,7 @@ type SheetsCmd struct {
 }
 
 type SheetsExportCmd struct {
-	SpreadsheetID string         `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string         `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Output        OutputPathFlag `embed:""`
 	Format        string         `name:"format" help:"Export format: pdf|xlsx|csv" default:"xlsx"`
 }
@@ -55,7 +55,7 @@ func (c *SheetsExportCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsCopyCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Title         string `arg:"" name:"title" help:"New spreadsheet title"`
 	Parent        string `name:"parent" help:"Destination folder ID"`
 }
@@ -69,7 +69,7 @@ func (c *SheetsCopyCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsGetCmd struct {
-	SpreadsheetID     string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID     string `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Range             string `arg:"" name:"range" help:"Range (eg. Sheet1!A1:B10)"`
 	MajorDimension    string `name:"dimension" help:"Major dimension: ROWS or COLUMNS"`
 	ValueRenderOption string `name:"render" help:"Value render option: FORMATTED_VALUE, UNFORMATTED_VALUE, or FORMULA"`
@@ -134,7 +134,7 @@ func (c *SheetsGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsUpdateCmd struct {
-	SpreadsheetID      string   `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID      string   `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Range              string   `arg:"" name:"range" help:"Range (eg. Sheet1!A1:B2)"`
 	Values             []string `arg:"" optional:"" name:"values" help:"Values (comma-separated rows, pipe-separated cells)"`
 	ValueInput         string   `name:"input" help:"Value input option: RAW or USER_ENTERED" default:"USER_ENTERED"`
@@ -242,7 +242,7 @@ func (c *SheetsUpdateCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsAppendCmd struct {
-	SpreadsheetID      string   `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID      string   `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Range              string   `arg:"" name:"range" help:"Range (eg. Sheet1!A:C)"`
 	Values             []string `arg:"" optional:"" name:"values" help:"Values (comma-separated rows, pipe-separated cells)"`
 	ValueInput         string   `name:"input" help:"Value input option: RAW or USER_ENTERED" default:"USER_ENTERED"`
@@ -354,7 +354,7 @@ func (c *SheetsAppendCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsClearCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Range         string `arg:"" name:"range" help:"Range (eg. Sheet1!A1:B2)"`
 }
 
@@ -402,7 +402,7 @@ func (c *SheetsClearCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SheetsMetadataCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 }
 
 func (c *SheetsMetadataCmd) Run(ctx context.Context, flags *RootFlags) error {
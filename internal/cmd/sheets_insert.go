@@ -12,7 +12,7 @@ import (
 )
 
 type SheetsInsertCmd struct {
-	SpreadsheetID string `arg:"" name:"spreadsheetId" help:"Spreadsheet ID"`
+	SpreadsheetID string `arg:"" stdin:"" name:"spreadsheetId" help:"Spreadsheet ID"`
 	Sheet         string `arg:"" name:"sheet" help:"Sheet name (eg. Sheet1)"`
 	Dimension     string `arg:"" name:"dimension" help:"Dimension to insert: rows or cols"`
 	Start         int64  `arg:"" name:"start" help:"Position before which to insert (1-based; for cols 1=A, 2=B)"`
@@ -34,7 +34,7 @@ type SlidesCmd struct {
 }
 
 type SlidesExportCmd struct {
-	PresentationID string         `arg:"" name:"presentationId" help:"Presentation ID"`
+	PresentationID string         `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
 	Output         OutputPathFlag `embed:""`
 	Format         string         `name:"format" help:"Export format: pdf|pptx" default:"pptx"`
 }
@@ -49,7 +49,7 @@ func (c *SlidesExportCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type SlidesInfoCmd struct {
-	PresentationID string `arg:"" name:"presentationId" help:"Presentation ID"`
+	PresentationID string `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
 }
 
 func (c *SlidesInfoCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -244,7 +244,7 @@ func (c *SlidesCreateFromMarkdownCmd) Run(ctx context.Context, flags *RootFlags)
 }
 
 type SlidesCopyCmd struct {
-	PresentationID string `arg:"" name:"presentationId" help:"Presentation ID"`
+	PresentationID string `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
 	Title          string `arg:"" name:"title" help:"New title"`
 	Parent         string `name:"parent" help:"Destination folder ID"`
 }
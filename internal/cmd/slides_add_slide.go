@@ -16,7 +16,7 @@ import (
 )
 
 type SlidesAddSlideCmd struct {
-	PresentationID string `arg:"" name:"presentationId" help:"Presentation ID"`
+	PresentationID string `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
 	Image          string `arg:"" name:"image" help:"Local image file (PNG/JPG)" type:"existingfile"`
 	Notes          string `name:"notes" help:"Speaker notes text"`
 	NotesFile      string `name:"notes-file" help:"Path to file containing speaker notes" type:"existingfile"`
@@ -11,8 +11,8 @@ import (
 )
 
 type SlidesDeleteSlideCmd struct {
-	PresentationID string `arg:"" name:"presentationId" help:"Presentation ID"`
-	SlideID        string `arg:"" name:"slideId" help:"Slide object ID to delete (use 'slides list-slides' to find IDs)"`
+	PresentationID string `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
+	SlideID        string `arg:"" stdin:"" name:"slideId" help:"Slide object ID to delete (use 'slides list-slides' to find IDs)"`
 }
 
 func (c *SlidesDeleteSlideCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -12,7 +12,7 @@ import (
 )
 
 type SlidesListSlidesCmd struct {
-	PresentationID string `arg:"" name:"presentationId" help:"Presentation ID"`
+	PresentationID string `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
 }
 
 func (c *SlidesListSlidesCmd) Run(ctx context.Context, flags *RootFlags) error {
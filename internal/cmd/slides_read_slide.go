@@ -12,8 +12,8 @@ import (
 )
 
 type SlidesReadSlideCmd struct {
-	PresentationID string `arg:"" name:"presentationId" help:"Presentation ID"`
-	SlideID        string `arg:"" name:"slideId" help:"Slide object ID (use 'slides list-slides' to find IDs)"`
+	PresentationID string `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
+	SlideID        string `arg:"" stdin:"" name:"slideId" help:"Slide object ID (use 'slides list-slides' to find IDs)"`
 }
 
 func (c *SlidesReadSlideCmd) Run(ctx context.Context, flags *RootFlags) error {
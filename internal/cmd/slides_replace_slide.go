@@ -15,8 +15,8 @@ import (
 )
 
 type SlidesReplaceSlideCmd struct {
-	PresentationID string  `arg:"" name:"presentationId" help:"Presentation ID"`
-	SlideID        string  `arg:"" name:"slideId" help:"Slide object ID to replace"`
+	PresentationID string  `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
+	SlideID        string  `arg:"" stdin:"" name:"slideId" help:"Slide object ID to replace"`
 	Image          string  `arg:"" name:"image" help:"Local image file (PNG/JPG/GIF)" type:"existingfile"`
 	Notes          *string `name:"notes" help:"New speaker notes text (omit to preserve existing notes; use --notes '' to clear)"`
 	NotesFile      string  `name:"notes-file" help:"Path to file containing new speaker notes" type:"existingfile"`
@@ -12,8 +12,8 @@ import (
 )
 
 type SlidesUpdateNotesCmd struct {
-	PresentationID string  `arg:"" name:"presentationId" help:"Presentation ID"`
-	SlideID        string  `arg:"" name:"slideId" help:"Slide object ID"`
+	PresentationID string  `arg:"" stdin:"" name:"presentationId" help:"Presentation ID"`
+	SlideID        string  `arg:"" stdin:"" name:"slideId" help:"Slide object ID"`
 	Notes          *string `name:"notes" help:"Speaker notes text (use --notes '' to clear notes)"`
 	NotesFile      string  `name:"notes-file" help:"Path to file containing speaker notes" type:"existingfile"`
 }
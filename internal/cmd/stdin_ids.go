@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/kong"
+	"golang.org/x/term"
+
+	"github.com/steipete/gogcli/internal/outfmt"
+)
+
+// Pipeline mode: a bare "-" in place of an ID argument (or --stdin-ids, which appends
+// one) reads IDs from stdin. ID arguments carry a `stdin:""` struct tag. List arguments (e.g. `gmail batch modify <messageId>...`)
+// receive every ID in a single invocation; single-ID arguments run the command once
+// per ID in child processes with bounded concurrency and report per-item results.
+const (
+	stdinIDsArg             = "-"
+	stdinIDsFlag            = "--stdin-ids"
+	stdinIDsSentinel        = "\x00gog-stdin-id-"
+	stdinIDsTag             = "stdin"
+	defaultStdinConcurrency = 4
+	maxStdinIDLineBytes     = 1 << 20
+)
+
+// plainHeaderField matches the first column header of --plain tables.
+var plainHeaderField = regexp.MustCompile(`^([A-Z_]*ID|RESOURCE)$`)
+
+var errNoStdinIDs = errors.New("no IDs on stdin")
+
+// stdinIDsInput returns the reader IDs are read from; tests replace it.
+var stdinIDsInput = func() (io.Reader, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, usage("pipe IDs on stdin (one per line) when using - or --stdin-ids")
+	}
+	return os.Stdin, nil
+}
+
+// runStdinIDsItem runs one per-ID invocation of gog and returns its exit code.
+var runStdinIDsItem = func(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 1, fmt.Errorf("locate gog executable: %w", err)
+	}
+
+	c := exec.CommandContext(ctx, exe, args...) //nolint:gosec // re-invokes this binary with the user's own args
+	c.Stdout = stdout
+	c.Stderr = stderr
+	// Only the parent writes --trace output.
+	c.Env = append(os.Environ(), "GOG_TRACE=")
+
+	err = c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, fmt.Errorf("run gog: %w", err)
+	}
+	return 0, nil
+}
+
+type stdinIDsPlan struct {
+	args   []string
+	index  int
+	splice bool
+	// trailing is set when no positional value follows the ID argument and args has
+	// no "--", so IDs starting with "-" can follow a "--" terminator.
+	trailing bool
+}
+
+// planStdinIDs finds the "-" positional that should be filled from stdin. It returns
+// nil when the command line has none (and --stdin-ids is not set).
+func planStdinIDs(args []string) (*stdinIDsPlan, error) {
+	if hasHelpOrVersionArg(args) {
+		return nil, nil
+	}
+
+	plan := findStdinIDsPlaceholder(args)
+	if plan != nil || !hasStdinIDsFlag(args) {
+		return plan, nil
+	}
+
+	if plan = findStdinIDsPlaceholder(insertPositionalArg(args, stdinIDsArg)); plan == nil {
+		return nil, usage("--stdin-ids: this command has no ID argument to fill from stdin")
+	}
+	return plan, nil
+}
+
+func findStdinIDsPlaceholder(args []string) *stdinIDsPlan {
+	marked := make([]string, len(args))
+	copy(marked, args)
+	found := false
+	for i, a := range args {
+		if a == "--" {
+			break
+		}
+		if a == stdinIDsArg {
+			marked[i] = stdinIDsSentinel + strconv.Itoa(i)
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	parser, _, err := newParser(helpDescription())
+	if err != nil {
+		return nil
+	}
+	kctx, err := parser.Parse(marked)
+	if err != nil {
+		// The real parse reports the error.
+		return nil
+	}
+
+	for i, p := range kctx.Path {
+		pos := p.Positional
+		if pos == nil || !pos.Target.IsValid() || !pos.Tag.Has(stdinIDsTag) {
+			continue
+		}
+		plan := &stdinIDsPlan{args: args, trailing: !hasLaterPositional(kctx.Path[i+1:], pos) && !slices.Contains(args, "--")}
+		switch v := pos.Target.Interface().(type) {
+		case string:
+			if idx, ok := stdinSentinelIndex(v); ok {
+				plan.index = idx
+				return plan
+			}
+		case []string:
+			for _, item := range v {
+				if idx, ok := stdinSentinelIndex(item); ok {
+					plan.index, plan.splice = idx, true
+					return plan
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasLaterPositional(path []*kong.Path, pos *kong.Value) bool {
+	for _, p := range path {
+		if p.Positional != nil && p.Positional != pos {
+			return true
+		}
+	}
+	return false
+}
+
+func stdinSentinelIndex(v string) (int, bool) {
+	raw, ok := strings.CutPrefix(v, stdinIDsSentinel)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(raw)
+	return n, err == nil
+}
+
+func hasStdinIDsFlag(args []string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		if a == stdinIDsFlag || a == stdinIDsFlag+"=true" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasHelpOrVersionArg(args []string) bool {
+	for _, a := range args {
+		switch a {
+		case "--":
+			return false
+		case "--help", "-h", "--version":
+			return true
+		}
+	}
+	return false
+}
+
+// insertPositionalArg appends v as the last positional (before a "--" terminator).
+func insertPositionalArg(args []string, v string) []string {
+	out := make([]string, 0, len(args)+1)
+	for i, a := range args {
+		if a == "--" {
+			out = append(out, v)
+			return append(out, args[i:]...)
+		}
+		out = append(out, a)
+	}
+	return append(out, v)
+}
+
+// checkIDs rejects IDs that the parser would read as flags: those starting with "-"
+// can only be passed after a "--" terminator, which needs the ID argument last.
+func (p *stdinIDsPlan) checkIDs(ids []string) error {
+	if p.trailing {
+		return nil
+	}
+	for _, id := range ids {
+		if strings.HasPrefix(id, "-") {
+			return usagef("stdin ID %q starts with \"-\"; this command only accepts such IDs as its last argument", id)
+		}
+	}
+	return nil
+}
+
+// afterTerminator reports whether ids must follow a "--" terminator instead of
+// replacing the placeholder in place.
+func (p *stdinIDsPlan) afterTerminator(ids ...string) bool {
+	return p.trailing && slices.ContainsFunc(ids, func(id string) bool { return strings.HasPrefix(id, "-") })
+}
+
+// expand replaces the placeholder with every ID (list arguments).
+func (p *stdinIDsPlan) expand(ids []string) []string {
+	out := make([]string, 0, len(p.args)+len(ids)+1)
+	out = append(out, p.args[:p.index]...)
+	if p.afterTerminator(ids...) {
+		out = append(out, p.args[p.index+1:]...)
+		out = append(out, "--")
+		return append(out, ids...)
+	}
+	out = append(out, ids...)
+	return append(out, p.args[p.index+1:]...)
+}
+
+// argsFor builds the child command line for one ID, dropping parent-only flags.
+func (p *stdinIDsPlan) argsFor(id string) []string {
+	out := make([]string, 0, len(p.args)+1)
+	terminate := p.afterTerminator(id)
+	for i := 0; i < len(p.args); i++ {
+		a := p.args[i]
+		if i == p.index {
+			if !terminate {
+				out = append(out, id)
+			}
+			continue
+		}
+		if a == "--" {
+			out = append(out, p.args[i:]...)
+			break
+		}
+		name, _, hasValue := strings.Cut(a, "=")
+		switch name {
+		case stdinIDsFlag:
+			continue
+		case "--stdin-concurrency", "--trace", "--trace-format":
+			if !hasValue {
+				i++
+			}
+			continue
+		}
+		out = append(out, a)
+	}
+	if terminate {
+		out = append(out, "--", id)
+	}
+	return out
+}
+
+// readStdinIDs reads one ID per line. Lines may be plain IDs, TSV (first column, as
+// printed by --plain, header row skipped), JSON strings, or NDJSON objects with an
+// "id" or "resourceName".
+func readStdinIDs() ([]string, error) {
+	r, err := stdinIDsInput()
+	if err != nil {
+		return nil, err
+	}
+	return parseStdinIDs(r)
+}
+
+func parseStdinIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxStdinIDLineBytes)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		switch text[0] {
+		case '{':
+			var obj map[string]any
+			if err := json.Unmarshal([]byte(text), &obj); err != nil {
+				return nil, usagef("stdin line %d: invalid JSON: %v", line, err)
+			}
+			id := ""
+			for _, key := range []string{"id", "resourceName"} {
+				if s, ok := obj[key].(string); ok && strings.TrimSpace(s) != "" {
+					id = strings.TrimSpace(s)
+					break
+				}
+			}
+			if id == "" {
+				return nil, usagef("stdin line %d: JSON object has no \"id\"", line)
+			}
+			ids = append(ids, id)
+		case '"':
+			var s string
+			if err := json.Unmarshal([]byte(text), &s); err != nil {
+				return nil, usagef("stdin line %d: invalid JSON string: %v", line, err)
+			}
+			if s = strings.TrimSpace(s); s != "" {
+				ids = append(ids, s)
+			}
+		default:
+			first, _, _ := strings.Cut(text, "\t")
+			first = strings.TrimSpace(first)
+			// Skip the header row of --plain tables (ID, RESOURCE, THREAD_ID, ...).
+			if len(ids) == 0 && plainHeaderField.MatchString(first) {
+				continue
+			}
+			ids = append(ids, first)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, usage(errNoStdinIDs.Error())
+	}
+	return ids, nil
+}
+
+type stdinIDResult struct {
+	ID       string `json:"id"`
+	OK       bool   `json:"ok"`
+	ExitCode int    `json:"exitCode"`
+	Result   any    `json:"result,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	stdout []byte
+	stderr []byte
+}
+
+// runStdinIDs runs the command once per ID and writes results in input order.
+// The exit code is 0 when every item succeeded, the shared exit code when all
+// failures agree, and 1 otherwise.
+func runStdinIDs(ctx context.Context, plan *stdinIDsPlan, concurrency int) error {
+	ids, err := readStdinIDs()
+	if err != nil {
+		return err
+	}
+	if err := plan.checkIDs(ids); err != nil {
+		return err
+	}
+	if concurrency <= 0 {
+		concurrency = defaultStdinConcurrency
+	}
+
+	results := make([]stdinIDResult, len(ids))
+	done := make([]chan struct{}, len(ids))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			defer close(done[i])
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = stdinIDResult{ID: id, ExitCode: exitCodeCancelled, Error: ctx.Err().Error()}
+				return
+			}
+
+			var stdout, stderr bytes.Buffer
+			code, runErr := runStdinIDsItem(ctx, plan.argsFor(id), &stdout, &stderr)
+			res := stdinIDResult{ID: id, OK: code == 0 && runErr == nil, ExitCode: code, stdout: stdout.Bytes(), stderr: stderr.Bytes()}
+			if runErr != nil {
+				res.Error = runErr.Error()
+			} else if !res.OK {
+				res.Error = lastLine(stderr.String())
+			}
+			results[i] = res
+		}(i, id)
+	}
+
+	mode := outfmt.FromContext(ctx)
+	envelope := mode.JSON && !mode.NDJSON && mode.Format == ""
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+
+	failed := 0
+	failCode := 0
+	for i := range results {
+		<-done[i]
+		res := &results[i]
+
+		for _, l := range strings.Split(strings.TrimRight(string(res.stderr), "\n"), "\n") {
+			if l != "" {
+				_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", res.ID, l)
+			}
+		}
+
+		if !res.OK {
+			failed++
+			if failCode == 0 {
+				failCode = res.ExitCode
+			} else if failCode != res.ExitCode {
+				failCode = 1
+			}
+		}
+
+		switch {
+		case mode.NDJSON:
+			res.Result = parseItemOutput(res.stdout)
+			if err := enc.Encode(res); err != nil {
+				return fmt.Errorf("encode json: %w", err)
+			}
+		case envelope:
+			res.Result = parseItemOutput(res.stdout)
+		default:
+			_, _ = os.Stdout.Write(res.stdout)
+		}
+	}
+	wg.Wait()
+
+	if envelope {
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(struct {
+			Results   []stdinIDResult `json:"results"`
+			Succeeded int             `json:"succeeded"`
+			Failed    int             `json:"failed"`
+		}{results, len(results) - failed, failed}); err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+	}
+
+	if failed == 0 {
+		return nil
+	}
+	if failCode == 0 {
+		failCode = 1
+	}
+	return &ExitError{Code: failCode, Err: fmt.Errorf("%d of %d items failed", failed, len(results))}
+}
+
+// parseItemOutput decodes a child's JSON (or NDJSON) stdout; anything else is kept as text.
+func parseItemOutput(b []byte) any {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil
+	}
+
+	var v any
+	if json.Unmarshal(b, &v) == nil {
+		return v
+	}
+
+	var items []any
+	for _, line := range bytes.Split(b, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var item any
+		if json.Unmarshal(line, &item) != nil {
+			return string(b)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
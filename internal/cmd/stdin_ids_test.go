@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestParseStdinIDs(t *testing.T) {
+	input := strings.Join([]string{
+		"# comment",
+		"",
+		"ID\tDATE\tFROM",
+		"plain1",
+		"tsv1\tSubject\t2025-01-01",
+		`{"id":"json1","subject":"x"}`,
+		`{"resourceName":"people/c1"}`,
+		`"quoted1"`,
+		"  padded1  ",
+	}, "\n")
+
+	got, err := parseStdinIDs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseStdinIDs: %v", err)
+	}
+	want := []string{"plain1", "tsv1", "json1", "people/c1", "quoted1", "padded1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseStdinIDs_Errors(t *testing.T) {
+	for _, input := range []string{"", "# only a comment\n", `{"subject":"no id"}`} {
+		_, err := parseStdinIDs(strings.NewReader(input))
+		if ExitCode(err) != 2 {
+			t.Fatalf("input %q: expected usage error, got %v", input, err)
+		}
+	}
+}
+
+func TestPlanStdinIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     []string
+		index    int
+		splice   bool
+		trailing bool
+		none     bool
+	}{
+		{name: "single id", args: []string{"gmail", "thread", "modify", "-", "--add", "X"}, index: 3, trailing: true},
+		{name: "id list", args: []string{"gmail", "batch", "modify", "-", "--add", "X"}, index: 3, splice: true, trailing: true},
+		{name: "flag appends placeholder", args: []string{"--stdin-ids", "drive", "get"}, want: []string{"--stdin-ids", "drive", "get", "-"}, index: 3, trailing: true},
+		{name: "id before another positional", args: []string{"drive", "comments", "get", "-", "c1"}, index: 3},
+		{name: "second id", args: []string{"drive", "comments", "get", "f1", "-"}, index: 4, trailing: true},
+		{name: "non-id positional", args: []string{"auth", "credentials", "set", "-"}, none: true},
+		{name: "no placeholder", args: []string{"gmail", "thread", "get", "abc"}, none: true},
+		{name: "help", args: []string{"gmail", "thread", "get", "-", "--help"}, none: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planStdinIDs(tt.args)
+			if err != nil {
+				t.Fatalf("planStdinIDs: %v", err)
+			}
+			if tt.none {
+				if plan != nil {
+					t.Fatalf("expected no plan, got %+v", plan)
+				}
+				return
+			}
+			if plan == nil {
+				t.Fatalf("expected plan")
+			}
+			want := tt.want
+			if want == nil {
+				want = tt.args
+			}
+			if !reflect.DeepEqual(plan.args, want) || plan.index != tt.index || plan.splice != tt.splice || plan.trailing != tt.trailing {
+				t.Fatalf("unexpected plan: %+v", plan)
+			}
+		})
+	}
+}
+
+func TestPlanStdinIDs_FlagWithoutIDArg(t *testing.T) {
+	_, err := planStdinIDs([]string{"--stdin-ids", "gmail", "labels", "list"})
+	if ExitCode(err) != 2 {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
+func TestStdinIDsPlan_ArgsFor(t *testing.T) {
+	plan := &stdinIDsPlan{
+		args:  []string{"--stdin-ids", "--trace", "t.json", "--stdin-concurrency=2", "--json", "gmail", "thread", "modify", "--add", "X", "-"},
+		index: 10,
+	}
+	got := plan.argsFor("abc")
+	want := []string{"--json", "gmail", "thread", "modify", "--add", "X", "abc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestStdinIDsPlan_DashPrefixedIDs(t *testing.T) {
+	plan := &stdinIDsPlan{args: []string{"gmail", "thread", "modify", "-", "--add", "X"}, index: 3, trailing: true}
+	if got, want := plan.argsFor("-abc"), []string{"gmail", "thread", "modify", "--add", "X", "--", "-abc"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("argsFor: got %v, want %v", got, want)
+	}
+	if got, want := plan.expand([]string{"a", "-b"}), []string{"gmail", "thread", "modify", "--add", "X", "--", "a", "-b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expand: got %v, want %v", got, want)
+	}
+	if err := plan.checkIDs([]string{"-abc"}); err != nil {
+		t.Fatalf("checkIDs: %v", err)
+	}
+
+	plan.trailing = false
+	if err := plan.checkIDs([]string{"a", "-abc"}); ExitCode(err) != 2 || !strings.Contains(err.Error(), `"-abc"`) {
+		t.Fatalf("expected usage error naming the ID, got %v", err)
+	}
+}
+
+func stubStdinIDsItem(t *testing.T, fn func(args []string, stdout io.Writer, stderr io.Writer) int) *[][]string {
+	t.Helper()
+
+	orig := runStdinIDsItem
+	t.Cleanup(func() { runStdinIDsItem = orig })
+
+	var mu sync.Mutex
+	var calls [][]string
+	runStdinIDsItem = func(_ context.Context, args []string, stdout io.Writer, stderr io.Writer) (int, error) {
+		mu.Lock()
+		calls = append(calls, args)
+		mu.Unlock()
+		return fn(args, stdout, stderr), nil
+	}
+	return &calls
+}
+
+func TestExecute_StdinIDs_NDJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	calls := stubStdinIDsItem(t, func(args []string, stdout io.Writer, stderr io.Writer) int {
+		id := args[len(args)-1]
+		if id == "bad" {
+			_, _ = fmt.Fprintln(stderr, "Google API error (404 notFound): not found")
+			return 5
+		}
+		_, _ = fmt.Fprintf(stdout, "{\"thread\":{\"id\":%q}}\n", id)
+		return 0
+	})
+
+	var execErr error
+	var stderr string
+	out := captureStdout(t, func() {
+		stderr = captureStderr(t, func() {
+			withStdin(t, "t1\tsubject\nbad\n{\"id\":\"t3\"}\n", func() {
+				execErr = Execute([]string{"--ndjson", "--account", "a@b.com", "gmail", "thread", "get", "-"})
+			})
+		})
+	})
+
+	if got := ExitCode(execErr); got != 5 {
+		t.Fatalf("expected exit code 5, got %d (%v)", got, execErr)
+	}
+	if len(*calls) != 3 {
+		t.Fatalf("expected 3 runs, got %d", len(*calls))
+	}
+	if !strings.Contains(stderr, "bad: Google API error (404 notFound)") || !strings.Contains(stderr, "1 of 3 items failed") {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", out)
+	}
+	var results []stdinIDResult
+	for _, line := range lines {
+		var r stdinIDResult
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		results = append(results, r)
+	}
+	if results[0].ID != "t1" || !results[0].OK || results[1].ID != "bad" || results[1].OK || results[1].ExitCode != 5 || results[2].ID != "t3" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[1].Error != "Google API error (404 notFound): not found" {
+		t.Fatalf("unexpected error: %q", results[1].Error)
+	}
+	thread, _ := results[0].Result.(map[string]any)["thread"].(map[string]any)
+	if thread["id"] != "t1" {
+		t.Fatalf("unexpected result: %#v", results[0].Result)
+	}
+}
+
+func TestExecute_StdinIDs_PlainPassthrough(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	_ = stubStdinIDsItem(t, func(args []string, stdout io.Writer, _ io.Writer) int {
+		_, _ = fmt.Fprintf(stdout, "%s\tok\n", args[len(args)-1])
+		return 0
+	})
+
+	var execErr error
+	out := captureStdout(t, func() {
+		withStdin(t, "a\nb\nc\n", func() {
+			execErr = Execute([]string{"--plain", "--stdin-ids", "--stdin-concurrency", "2", "--account", "a@b.com", "drive", "get"})
+		})
+	})
+	if execErr != nil {
+		t.Fatalf("Execute: %v", execErr)
+	}
+	if out != "a\tok\nb\tok\nc\tok\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestExecute_StdinIDs_SpliceList(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	calls := stubStdinIDsItem(t, func([]string, io.Writer, io.Writer) int { return 0 })
+
+	var execErr error
+	out := captureStdout(t, func() {
+		withStdin(t, "m1\n-m2\n", func() {
+			execErr = Execute([]string{"--json", "--dry-run", "--account", "a@b.com", "gmail", "batch", "modify", "-", "--add", "X"})
+		})
+	})
+	if execErr != nil {
+		t.Fatalf("Execute: %v", execErr)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("list arguments should run in-process, got %d child runs", len(*calls))
+	}
+
+	var got struct {
+		Request struct {
+			MessageIDs []string `json:"message_ids"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decode: %v (%q)", err, out)
+	}
+	if !reflect.DeepEqual(got.Request.MessageIDs, []string{"m1", "-m2"}) {
+		t.Fatalf("unexpected ids: %v", got.Request.MessageIDs)
+	}
+}
+
+func TestStdinIDsTagOnlyOnStringArgs(t *testing.T) {
+	parser, _, err := newParser("test")
+	if err != nil {
+		t.Fatalf("newParser: %v", err)
+	}
+	_ = kong.Visit(parser.Model, func(node kong.Visitable, next kong.Next) error {
+		if v, ok := node.(*kong.Value); ok && v.Tag.Has(stdinIDsTag) {
+			if !v.Tag.Arg {
+				t.Errorf("%s: %s tag on a flag", v.Name, stdinIDsTag)
+			}
+			switch v.Target.Interface().(type) {
+			case string, []string:
+			default:
+				t.Errorf("%s: %s tag on a %s argument", v.Name, stdinIDsTag, v.Target.Type())
+			}
+		}
+		return next(nil)
+	})
+}
@@ -20,7 +20,7 @@ const (
 )
 
 type TasksListCmd struct {
-	TasklistID    string `arg:"" name:"tasklistId" help:"Task list ID"`
+	TasklistID    string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
 	Max           int64  `name:"max" aliases:"limit" help:"Max results (max allowed: 100)" default:"20"`
 	Page          string `name:"page" aliases:"cursor" help:"Page token"`
 	All           bool   `name:"all" aliases:"all-pages,allpages" help:"Fetch all pages"`
@@ -89,6 +89,10 @@ func (c *TasksListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Items, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var items []*tasks.Task
 	nextPageToken := ""
 	if c.All {
@@ -138,8 +142,8 @@ func (c *TasksListCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksGetCmd struct {
-	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
-	TaskID     string `arg:"" name:"taskId" help:"Task ID"`
+	TasklistID string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
+	TaskID     string `arg:"" stdin:"" name:"taskId" help:"Task ID"`
 }
 
 func (c *TasksGetCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -188,7 +192,7 @@ func (c *TasksGetCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksAddCmd struct {
-	TasklistID  string `arg:"" name:"tasklistId" help:"Task list ID"`
+	TasklistID  string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
 	Title       string `name:"title" help:"Task title (required)"`
 	Notes       string `name:"notes" help:"Task notes/description"`
 	Due         string `name:"due" help:"Due date (RFC3339 or YYYY-MM-DD; time may be ignored by Google Tasks)"`
@@ -413,8 +417,8 @@ func (c *TasksAddCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksUpdateCmd struct {
-	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
-	TaskID     string `arg:"" name:"taskId" help:"Task ID"`
+	TasklistID string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
+	TaskID     string `arg:"" stdin:"" name:"taskId" help:"Task ID"`
 	Title      string `name:"title" help:"New title (set empty to clear)"`
 	Notes      string `name:"notes" help:"New notes (set empty to clear)"`
 	Due        string `name:"due" help:"New due date (RFC3339 or YYYY-MM-DD; time may be ignored; set empty to clear)"`
@@ -510,8 +514,8 @@ func (c *TasksUpdateCmd) Run(ctx context.Context, kctx *kong.Context, flags *Roo
 }
 
 type TasksDoneCmd struct {
-	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
-	TaskID     string `arg:"" name:"taskId" help:"Task ID"`
+	TasklistID string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
+	TaskID     string `arg:"" stdin:"" name:"taskId" help:"Task ID"`
 }
 
 func (c *TasksDoneCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -559,8 +563,8 @@ func (c *TasksDoneCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksUndoCmd struct {
-	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
-	TaskID     string `arg:"" name:"taskId" help:"Task ID"`
+	TasklistID string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
+	TaskID     string `arg:"" stdin:"" name:"taskId" help:"Task ID"`
 }
 
 func (c *TasksUndoCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -608,8 +612,8 @@ func (c *TasksUndoCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksDeleteCmd struct {
-	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
-	TaskID     string `arg:"" name:"taskId" help:"Task ID"`
+	TasklistID string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
+	TaskID     string `arg:"" stdin:"" name:"taskId" help:"Task ID"`
 }
 
 func (c *TasksDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
@@ -651,7 +655,7 @@ func (c *TasksDeleteCmd) Run(ctx context.Context, flags *RootFlags) error {
 }
 
 type TasksClearCmd struct {
-	TasklistID string `arg:"" name:"tasklistId" help:"Task list ID"`
+	TasklistID string `arg:"" stdin:"" name:"tasklistId" help:"Task list ID"`
 }
 
 func (c *TasksClearCmd) Run(ctx context.Context, flags *RootFlags) error {
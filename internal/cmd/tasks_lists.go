@@ -48,6 +48,10 @@ func (c *TasksListsListCmd) Run(ctx context.Context, flags *RootFlags) error {
 		return resp.Items, resp.NextPageToken, nil
 	}
 
+	if handled, err := handleAllPagesNDJSON(ctx, c.All, c.Page, c.FailEmpty, fetch); handled {
+		return err
+	}
+
 	var items []*tasks.TaskList
 	nextPageToken := ""
 	if c.All {
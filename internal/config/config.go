@@ -15,6 +15,12 @@ type File struct {
 	AccountAliases  map[string]string `json:"account_aliases,omitempty"`
 	AccountClients  map[string]string `json:"account_clients,omitempty"`
 	ClientDomains   map[string]string `json:"client_domains,omitempty"`
+	HTTPCacheTTL    string            `json:"http_cache_ttl,omitempty"`
+	// Retry holds retry/timeout settings keyed by service name ("gmail", "drive", ...)
+	// plus a "default" entry applied to every service.
+	Retry map[string]RetryConfig `json:"retry,omitempty"`
+	// RateLimit holds client-side QPS limits keyed like Retry.
+	RateLimit map[string]RateLimitConfig `json:"rate_limit,omitempty"`
 }
 
 func ConfigPath() (string, error) {
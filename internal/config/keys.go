@@ -12,6 +12,7 @@ type Key string
 const (
 	KeyTimezone       Key = "timezone"
 	KeyKeyringBackend Key = "keyring_backend"
+	KeyHTTPCacheTTL   Key = "http_cache_ttl"
 )
 
 type KeySpec struct {
@@ -25,6 +26,7 @@ type KeySpec struct {
 var keyOrder = []Key{
 	KeyTimezone,
 	KeyKeyringBackend,
+	KeyHTTPCacheTTL,
 }
 
 var keySpecs = map[Key]KeySpec{
@@ -63,6 +65,40 @@ var keySpecs = map[Key]KeySpec{
 			return "(not set, using auto)"
 		},
 	},
+	KeyHTTPCacheTTL: {
+		Key: KeyHTTPCacheTTL,
+		Get: func(cfg File) string {
+			return cfg.HTTPCacheTTL
+		},
+		Set: func(cfg *File, value string) error {
+			if _, err := ParseHTTPCacheTTL(value); err != nil {
+				return err
+			}
+			cfg.HTTPCacheTTL = value
+			return nil
+		},
+		Unset: func(cfg *File) {
+			cfg.HTTPCacheTTL = ""
+		},
+		EmptyHint: func() string {
+			return "(not set, cache disabled)"
+		},
+	},
+}
+
+// ParseHTTPCacheTTL parses a cache TTL like "5m" or "1h". Empty and "0" disable the cache.
+func ParseHTTPCacheTTL(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid cache ttl %q (use a duration like 30s, 5m, 1h)", value)
+	}
+
+	return ttl, nil
 }
 
 var (
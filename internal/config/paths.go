@@ -241,6 +241,144 @@ func EnsureGmailWatchDir() (string, error) {
 	return dir, nil
 }
 
+// HTTPCacheDir holds cached Google API GET responses (see googleapi.CacheTransport).
+func HTTPCacheDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "cache", "http"), nil
+}
+
+func EnsureHTTPCacheDir() (string, error) {
+	dir, err := HTTPCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("ensure http cache dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// RateLimitDir holds the token-bucket state files shared by concurrent gog processes.
+func RateLimitDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "state", "ratelimit"), nil
+}
+
+func EnsureRateLimitDir() (string, error) {
+	dir, err := RateLimitDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("ensure rate limit dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// GmailQueueDir holds the scheduled-send queue (`gmail send --at`).
+func GmailQueueDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "state", "gmail-queue"), nil
+}
+
+func EnsureGmailQueueDir() (string, error) {
+	dir, err := GmailQueueDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("ensure gmail queue dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// GmailBatchDir holds checkpoints for resumable `gmail batch modify --query` runs.
+func GmailBatchDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "state", "gmail-batch"), nil
+}
+
+func EnsureGmailBatchDir() (string, error) {
+	dir, err := GmailBatchDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("ensure gmail batch dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// CalendarSyncDir holds Calendar API sync tokens per account and calendar (`calendar changes`).
+func CalendarSyncDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "state", "calendar-sync"), nil
+}
+
+func EnsureCalendarSyncDir() (string, error) {
+	dir, err := CalendarSyncDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("ensure calendar sync dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// CalendarWatchDir holds Calendar push channel state per account and calendar (`calendar watch`).
+func CalendarWatchDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "state", "calendar-watch"), nil
+}
+
+func EnsureCalendarWatchDir() (string, error) {
+	dir, err := CalendarWatchDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("ensure calendar watch dir: %w", err)
+	}
+
+	return dir, nil
+}
+
 // ExpandPath expands ~ at the beginning of a path to the user's home directory.
 // This is needed because ~ is a shell feature and is not expanded when paths
 // are quoted (e.g., --out "~/Downloads/file.pdf").
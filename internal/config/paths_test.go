@@ -61,6 +61,42 @@ func TestPaths_CreateDirs(t *testing.T) {
 		t.Fatalf("expected watch dir: %v", statErr)
 	}
 
+	queueDir, err := EnsureGmailQueueDir()
+	if err != nil {
+		t.Fatalf("EnsureGmailQueueDir: %v", err)
+	}
+
+	if _, statErr := os.Stat(queueDir); statErr != nil {
+		t.Fatalf("expected queue dir: %v", statErr)
+	}
+
+	batchDir, err := EnsureGmailBatchDir()
+	if err != nil {
+		t.Fatalf("EnsureGmailBatchDir: %v", err)
+	}
+
+	if _, statErr := os.Stat(batchDir); statErr != nil {
+		t.Fatalf("expected batch dir: %v", statErr)
+	}
+
+	syncDir, err := EnsureCalendarSyncDir()
+	if err != nil {
+		t.Fatalf("EnsureCalendarSyncDir: %v", err)
+	}
+
+	if _, statErr := os.Stat(syncDir); statErr != nil {
+		t.Fatalf("expected calendar sync dir: %v", statErr)
+	}
+
+	calWatchDir, err := EnsureCalendarWatchDir()
+	if err != nil {
+		t.Fatalf("EnsureCalendarWatchDir: %v", err)
+	}
+
+	if _, statErr := os.Stat(calWatchDir); statErr != nil {
+		t.Fatalf("expected calendar watch dir: %v", statErr)
+	}
+
 	credsPath, err := ClientCredentialsPath()
 	if err != nil {
 		t.Fatalf("ClientCredentialsPath: %v", err)
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RateLimitConfig sets a client-side token bucket for an API. QPS <= 0 disables it.
+// Burst defaults to max(1, QPS). Unset fields inherit from the next layer down
+// ("default" entry < service entry < GOG_RATE_LIMIT_* env), so a service entry
+// with qps: 0 turns off a limit set by "default".
+type RateLimitConfig struct {
+	QPS   *float64 `json:"qps,omitempty"`
+	Burst *int     `json:"burst,omitempty"`
+}
+
+// Merge returns c with every field that is set in over replaced.
+func (c RateLimitConfig) Merge(over RateLimitConfig) RateLimitConfig {
+	if over.QPS != nil {
+		v := *over.QPS
+		c.QPS = &v
+	}
+	if over.Burst != nil {
+		v := *over.Burst
+		c.Burst = &v
+	}
+
+	return c
+}
+
+// Limit returns the effective QPS and burst; unset fields are 0.
+func (c RateLimitConfig) Limit() (qps float64, burst int) {
+	if c.QPS != nil {
+		qps = *c.QPS
+	}
+	if c.Burst != nil {
+		burst = *c.Burst
+	}
+
+	return qps, burst
+}
+
+// ResolveRateLimit layers the "default" entry, the service entry and GOG_RATE_LIMIT_QPS /
+// GOG_RATE_LIMIT_BURST (which apply to every service).
+func ResolveRateLimit(cfg File, service string) (RateLimitConfig, error) {
+	out := cfg.RateLimit[RetryDefaultKey]
+	if service = strings.ToLower(strings.TrimSpace(service)); service != "" {
+		out = out.Merge(cfg.RateLimit[service])
+	}
+
+	env, err := RateLimitConfigFromEnv()
+	if err != nil {
+		return RateLimitConfig{}, err
+	}
+	out = out.Merge(env)
+
+	if out.Burst != nil && *out.Burst < 0 {
+		return RateLimitConfig{}, fmt.Errorf("invalid rate_limit for %s (burst must be >= 0)", service)
+	}
+
+	return out, nil
+}
+
+// RateLimitConfigFromEnv reads GOG_RATE_LIMIT_QPS and GOG_RATE_LIMIT_BURST.
+func RateLimitConfigFromEnv() (RateLimitConfig, error) {
+	var out RateLimitConfig
+
+	if raw := strings.TrimSpace(os.Getenv("GOG_RATE_LIMIT_QPS")); raw != "" {
+		qps, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return RateLimitConfig{}, fmt.Errorf("invalid GOG_RATE_LIMIT_QPS %q (expected a number)", raw)
+		}
+		out.QPS = &qps
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("GOG_RATE_LIMIT_BURST")); raw != "" {
+		burst, err := strconv.Atoi(raw)
+		if err != nil || burst < 0 {
+			return RateLimitConfig{}, fmt.Errorf("invalid GOG_RATE_LIMIT_BURST %q (expected a non-negative integer)", raw)
+		}
+		out.Burst = &burst
+	}
+
+	return out, nil
+}
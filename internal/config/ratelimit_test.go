@@ -0,0 +1,47 @@
+package config
+
+import "testing"
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestResolveRateLimit_ServiceZeroDisablesDefault(t *testing.T) {
+	cfg := File{RateLimit: map[string]RateLimitConfig{
+		RetryDefaultKey: {QPS: floatPtr(5), Burst: intPtr(10)},
+		"drive":         {QPS: floatPtr(0)},
+		"gmail":         {Burst: intPtr(2)},
+	}}
+
+	drive, err := ResolveRateLimit(cfg, "Drive")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if qps, _ := drive.Limit(); qps != 0 {
+		t.Fatalf("expected qps: 0 to disable the default limit, got %v", qps)
+	}
+
+	gmail, err := ResolveRateLimit(cfg, "gmail")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if qps, burst := gmail.Limit(); qps != 5 || burst != 2 {
+		t.Fatalf("expected default qps 5 with burst 2, got %v %v", qps, burst)
+	}
+}
+
+func TestResolveRateLimit_Env(t *testing.T) {
+	cfg := File{RateLimit: map[string]RateLimitConfig{RetryDefaultKey: {QPS: floatPtr(5)}}}
+
+	t.Setenv("GOG_RATE_LIMIT_QPS", "-1")
+	got, err := ResolveRateLimit(cfg, "drive")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if qps, _ := got.Limit(); qps != -1 {
+		t.Fatalf("expected env qps -1 (disabled), got %v", qps)
+	}
+
+	t.Setenv("GOG_RATE_LIMIT_BURST", "-3")
+	if _, err := ResolveRateLimit(cfg, "drive"); err == nil {
+		t.Fatalf("expected error for a negative burst")
+	}
+}
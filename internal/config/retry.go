@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryDefaultKey is the File.Retry entry that applies to every service.
+const RetryDefaultKey = "default"
+
+// RetryConfig tunes HTTP retries, backoff, the circuit breaker and the request timeout.
+// Unset fields inherit from the next layer down (defaults < "default" entry < service
+// entry < GOG_RETRY_* env < flags). Durations use Go syntax ("500ms", "2s", "1m").
+type RetryConfig struct {
+	MaxRetries429           *int   `json:"max_retries_429,omitempty"`
+	MaxRetries5xx           *int   `json:"max_retries_5xx,omitempty"`
+	MaxRetriesNetwork       *int   `json:"max_retries_network,omitempty"`
+	BaseDelay               string `json:"base_delay,omitempty"`
+	ServerErrorDelay        string `json:"server_error_delay,omitempty"`
+	MaxDelay                string `json:"max_delay,omitempty"`
+	CircuitBreakerThreshold *int   `json:"circuit_breaker_threshold,omitempty"`
+	CircuitBreakerReset     string `json:"circuit_breaker_reset,omitempty"`
+	Timeout                 string `json:"timeout,omitempty"`
+}
+
+// Merge returns c with every field that is set in over replaced.
+func (c RetryConfig) Merge(over RetryConfig) RetryConfig {
+	mergeInt := func(dst **int, src *int) {
+		if src != nil {
+			v := *src
+			*dst = &v
+		}
+	}
+	mergeString := func(dst *string, src string) {
+		if strings.TrimSpace(src) != "" {
+			*dst = src
+		}
+	}
+
+	mergeInt(&c.MaxRetries429, over.MaxRetries429)
+	mergeInt(&c.MaxRetries5xx, over.MaxRetries5xx)
+	mergeInt(&c.MaxRetriesNetwork, over.MaxRetriesNetwork)
+	mergeString(&c.BaseDelay, over.BaseDelay)
+	mergeString(&c.ServerErrorDelay, over.ServerErrorDelay)
+	mergeString(&c.MaxDelay, over.MaxDelay)
+	mergeInt(&c.CircuitBreakerThreshold, over.CircuitBreakerThreshold)
+	mergeString(&c.CircuitBreakerReset, over.CircuitBreakerReset)
+	mergeString(&c.Timeout, over.Timeout)
+
+	return c
+}
+
+// ResolveRetryConfig layers the "default" entry, the service entry and GOG_RETRY_* env vars.
+func ResolveRetryConfig(cfg File, service string) (RetryConfig, error) {
+	out := cfg.Retry[RetryDefaultKey]
+	if service = strings.ToLower(strings.TrimSpace(service)); service != "" {
+		out = out.Merge(cfg.Retry[service])
+	}
+
+	env, err := RetryConfigFromEnv()
+	if err != nil {
+		return RetryConfig{}, err
+	}
+
+	return out.Merge(env), nil
+}
+
+// RetryConfigFromEnv reads GOG_RETRY_<KEY> overrides, e.g. GOG_RETRY_MAX_RETRIES_5XX=5
+// or GOG_RETRY_SERVER_ERROR_DELAY=2s.
+func RetryConfigFromEnv() (RetryConfig, error) {
+	var out RetryConfig
+
+	ints := []struct {
+		name string
+		dst  **int
+	}{
+		{"GOG_RETRY_MAX_RETRIES_429", &out.MaxRetries429},
+		{"GOG_RETRY_MAX_RETRIES_5XX", &out.MaxRetries5xx},
+		{"GOG_RETRY_MAX_RETRIES_NETWORK", &out.MaxRetriesNetwork},
+		{"GOG_RETRY_CIRCUIT_BREAKER_THRESHOLD", &out.CircuitBreakerThreshold},
+	}
+	for _, it := range ints {
+		raw := strings.TrimSpace(os.Getenv(it.name))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return RetryConfig{}, fmt.Errorf("invalid %s %q (expected a non-negative integer)", it.name, raw)
+		}
+		*it.dst = &n
+	}
+
+	durations := []struct {
+		name string
+		dst  *string
+	}{
+		{"GOG_RETRY_BASE_DELAY", &out.BaseDelay},
+		{"GOG_RETRY_SERVER_ERROR_DELAY", &out.ServerErrorDelay},
+		{"GOG_RETRY_MAX_DELAY", &out.MaxDelay},
+		{"GOG_RETRY_CIRCUIT_BREAKER_RESET", &out.CircuitBreakerReset},
+		{"GOG_RETRY_TIMEOUT", &out.Timeout},
+	}
+	for _, it := range durations {
+		raw := strings.TrimSpace(os.Getenv(it.name))
+		if raw == "" {
+			continue
+		}
+		if _, err := ParseRetryDuration(raw); err != nil {
+			return RetryConfig{}, fmt.Errorf("invalid %s: %w", it.name, err)
+		}
+		*it.dst = raw
+	}
+
+	return out, nil
+}
+
+// ParseRetryDuration parses a non-negative duration; a bare "0" is accepted.
+func ParseRetryDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (use a duration like 500ms, 2s, 1m)", value)
+	}
+
+	return d, nil
+}
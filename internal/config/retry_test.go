@@ -0,0 +1,58 @@
+package config
+
+import "testing"
+
+func intPtr(n int) *int { return &n }
+
+func TestResolveRetryConfig_Layers(t *testing.T) {
+	cfg := File{Retry: map[string]RetryConfig{
+		RetryDefaultKey: {MaxRetries5xx: intPtr(3), ServerErrorDelay: "2s", Timeout: "1m"},
+		"drive":         {MaxRetries5xx: intPtr(6)},
+	}}
+
+	t.Setenv("GOG_RETRY_TIMEOUT", "5m")
+
+	got, err := ResolveRetryConfig(cfg, "Drive")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got.MaxRetries5xx == nil || *got.MaxRetries5xx != 6 {
+		t.Fatalf("expected service override 6, got %v", got.MaxRetries5xx)
+	}
+	if got.ServerErrorDelay != "2s" {
+		t.Fatalf("expected default entry delay, got %q", got.ServerErrorDelay)
+	}
+	if got.Timeout != "5m" {
+		t.Fatalf("expected env timeout, got %q", got.Timeout)
+	}
+
+	other, err := ResolveRetryConfig(cfg, "gmail")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if other.MaxRetries5xx == nil || *other.MaxRetries5xx != 3 {
+		t.Fatalf("expected default 3, got %v", other.MaxRetries5xx)
+	}
+}
+
+func TestRetryConfigFromEnv_Invalid(t *testing.T) {
+	t.Setenv("GOG_RETRY_MAX_RETRIES_5XX", "lots")
+	if _, err := RetryConfigFromEnv(); err == nil {
+		t.Fatalf("expected error for invalid int")
+	}
+
+	t.Setenv("GOG_RETRY_MAX_RETRIES_5XX", "")
+	t.Setenv("GOG_RETRY_MAX_DELAY", "-1s")
+	if _, err := RetryConfigFromEnv(); err == nil {
+		t.Fatalf("expected error for negative duration")
+	}
+}
+
+func TestRetryConfig_MergeCopiesInts(t *testing.T) {
+	n := 2
+	merged := RetryConfig{}.Merge(RetryConfig{MaxRetries429: &n})
+	n = 9
+	if merged.MaxRetries429 == nil || *merged.MaxRetries429 != 2 {
+		t.Fatalf("expected merged copy 2, got %v", merged.MaxRetries429)
+	}
+}
@@ -0,0 +1,372 @@
+package googleapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weak"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+)
+
+// Google's batch endpoint packs up to MaxBatchSize independent API calls into a single
+// multipart/mixed HTTP request. Each sub-request succeeds or fails on its own, so the
+// helper returns one BatchResponse per request and retries throttled parts.
+const (
+	MaxBatchSize = 100
+
+	batchPartRetries = 3
+	batchPartDelay   = 1 * time.Second
+)
+
+var (
+	errBatchResponse  = errors.New("batch: malformed response")
+	errBatchPartMiss  = errors.New("batch: no response for request")
+	errBatchNoBatcher = errors.New("batch: nil batcher")
+)
+
+// BatchRequest is one call inside a batch. Path is relative to the API root
+// (e.g. "users/me/threads/abc?format=metadata" for gmail/v1).
+type BatchRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// BatchResponse is the outcome of one BatchRequest. Err is a *googleapi.Error for
+// non-2xx parts, matching what the generated clients return for single calls.
+type BatchResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Err        error
+}
+
+// Decode unmarshals a successful part into v.
+func (r BatchResponse) Decode(v any) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("batch: decode response: %w", err)
+	}
+
+	return nil
+}
+
+// Batcher sends batch requests for one API (e.g. "gmail/v1") through an authorized client.
+type Batcher struct {
+	Client *http.Client
+	// Endpoint is the batch URL, e.g. https://gmail.googleapis.com/batch/gmail/v1.
+	Endpoint string
+	// Prefix is prepended to every BatchRequest.Path, e.g. "/gmail/v1/".
+	Prefix string
+
+	sleep func(context.Context, time.Duration) error
+}
+
+// NewBatcher builds a Batcher for api using the root (scheme://host/) of basePath.
+func NewBatcher(client *http.Client, basePath string, api string) (*Batcher, error) {
+	u, err := url.Parse(basePath)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("batch: invalid base path %q", basePath)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	api = strings.Trim(api, "/")
+	root := u.Scheme + "://" + u.Host + "/"
+
+	return &Batcher{
+		Client:   client,
+		Endpoint: root + "batch/" + api,
+		Prefix:   "/" + api + "/",
+		sleep:    SleepContext,
+	}, nil
+}
+
+// batchers maps services created by NewGmail/NewDrive/NewCalendar to a Batcher that
+// shares their authorized HTTP client. Keys are weak pointers and each entry is
+// removed once its service is garbage collected, so long-running processes that
+// build a service per account or request do not accumulate batchers.
+var batchers sync.Map
+
+func registerBatcher[T any](svc *T, client *http.Client, basePath string, api string) {
+	b, err := NewBatcher(client, basePath, api)
+	if err != nil {
+		slog.Debug("batch endpoint unavailable", "api", api, "err", err)
+		return
+	}
+
+	key := weak.Make(svc)
+	batchers.Store(key, b)
+	runtime.AddCleanup(svc, func(key weak.Pointer[T]) { batchers.Delete(key) }, key)
+}
+
+// BatcherFor returns the Batcher registered for svc, or nil when svc was not created
+// by this package (callers then fall back to individual requests).
+func BatcherFor(svc any) *Batcher {
+	var key any
+	switch s := svc.(type) {
+	case *gmail.Service:
+		key = weak.Make(s)
+	case *drive.Service:
+		key = weak.Make(s)
+	case *calendar.Service:
+		key = weak.Make(s)
+	default:
+		return nil
+	}
+
+	if v, ok := batchers.Load(key); ok {
+		if b, ok := v.(*Batcher); ok {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// Do sends reqs in chunks of MaxBatchSize and returns responses in request order.
+// The returned error is set only when a whole batch call fails; per-request failures
+// are reported in BatchResponse.Err. Parts answered with 429 or 5xx are retried.
+func (b *Batcher) Do(ctx context.Context, reqs []BatchRequest) ([]BatchResponse, error) {
+	if b == nil {
+		return nil, errBatchNoBatcher
+	}
+
+	out := make([]BatchResponse, len(reqs))
+	for start := 0; start < len(reqs); start += MaxBatchSize {
+		end := min(start+MaxBatchSize, len(reqs))
+		if err := b.doChunk(ctx, reqs[start:end], out[start:end]); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (b *Batcher) doChunk(ctx context.Context, reqs []BatchRequest, out []BatchResponse) error {
+	pending := make([]int, len(reqs))
+	for i := range reqs {
+		pending[i] = i
+	}
+
+	for attempt := 0; ; attempt++ {
+		parts := make([]BatchRequest, len(pending))
+		for i, idx := range pending {
+			parts[i] = reqs[idx]
+		}
+
+		resps, err := b.send(ctx, parts)
+		if err != nil {
+			return err
+		}
+
+		var retry []int
+		var delay time.Duration
+		for i, idx := range pending {
+			out[idx] = resps[i]
+			if attempt < batchPartRetries && retryableBatchStatus(resps[i].StatusCode) {
+				retry = append(retry, idx)
+				if d, ok := retryAfter(&http.Response{Header: resps[i].Header}); ok && d > delay {
+					delay = d
+				}
+			}
+		}
+		if len(retry) == 0 {
+			return nil
+		}
+
+		delay = max(delay, min(backoffWithJitter(batchPartDelay, attempt), MaxRetryDelay))
+		slog.Debug("batch parts throttled, retrying", "count", len(retry), "delay", delay, "attempt", attempt+1)
+		if err := b.sleep(ctx, delay); err != nil {
+			return err
+		}
+		pending = retry
+	}
+}
+
+func retryableBatchStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func (b *Batcher) send(ctx context.Context, reqs []BatchRequest) ([]BatchResponse, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	for i, r := range reqs {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "application/http")
+		header.Set("Content-ID", "<item-"+strconv.Itoa(i)+">")
+
+		pw, err := mw.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("batch: encode request: %w", err)
+		}
+
+		method := r.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		fmt.Fprintf(pw, "%s %s%s HTTP/1.1\r\n", method, b.Prefix, strings.TrimPrefix(r.Path, "/"))
+		if len(r.Body) > 0 {
+			fmt.Fprintf(pw, "Content-Type: application/json\r\nContent-Length: %d\r\n\r\n", len(r.Body))
+			_, _ = pw.Write(r.Body)
+		} else {
+			_, _ = io.WriteString(pw, "\r\n")
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("batch: encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.Endpoint, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("batch: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("batch request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := gapi.CheckResponse(resp); err != nil {
+		return nil, err //nolint:wrapcheck // keep *googleapi.Error for callers
+	}
+
+	return parseBatchResponse(resp, len(reqs))
+}
+
+func parseBatchResponse(resp *http.Response, n int) ([]BatchResponse, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, fmt.Errorf("%w: content type %q", errBatchResponse, resp.Header.Get("Content-Type"))
+	}
+
+	out := make([]BatchResponse, n)
+	seen := make([]bool, n)
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+
+	for pos := 0; ; pos++ {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errBatchResponse, err)
+		}
+
+		idx, ok := batchPartIndex(part.Header.Get("Content-ID"))
+		if !ok {
+			// Google always echoes Content-ID; fall back to order if it did not.
+			idx = pos
+		}
+		if idx < 0 || idx >= n {
+			_ = part.Close()
+			continue
+		}
+
+		out[idx] = readBatchPart(part)
+		seen[idx] = true
+		_ = part.Close()
+	}
+
+	for i := range out {
+		if !seen[i] {
+			out[i] = BatchResponse{Err: errBatchPartMiss}
+		}
+	}
+
+	return out, nil
+}
+
+func readBatchPart(part io.Reader) BatchResponse {
+	inner, err := http.ReadResponse(bufio.NewReader(part), nil)
+	if err != nil {
+		return BatchResponse{Err: fmt.Errorf("%w: %w", errBatchResponse, err)}
+	}
+	defer inner.Body.Close()
+
+	body, err := io.ReadAll(inner.Body)
+	if err != nil {
+		return BatchResponse{StatusCode: inner.StatusCode, Err: fmt.Errorf("batch: read part: %w", err)}
+	}
+
+	out := BatchResponse{StatusCode: inner.StatusCode, Header: inner.Header, Body: body}
+
+	inner.Body = io.NopCloser(bytes.NewReader(body))
+	if err := gapi.CheckResponse(inner); err != nil {
+		out.Err = err
+	}
+
+	return out
+}
+
+// batchPartIndex parses "<response-item-N>" (or "<item-N>") into N.
+func batchPartIndex(contentID string) (int, bool) {
+	id := strings.Trim(strings.TrimSpace(contentID), "<>")
+	id = strings.TrimPrefix(id, "response-")
+
+	raw, ok := strings.CutPrefix(id, "item-")
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// BatchGet fetches every path with GET and decodes successes into T. errs[i] is set
+// for requests that failed individually; err is set when the batch call itself failed.
+func BatchGet[T any](ctx context.Context, b *Batcher, paths []string) (items []*T, errs []error, err error) {
+	reqs := make([]BatchRequest, len(paths))
+	for i, p := range paths {
+		reqs[i] = BatchRequest{Method: http.MethodGet, Path: p}
+	}
+
+	resps, err := b.Do(ctx, reqs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items = make([]*T, len(resps))
+	errs = make([]error, len(resps))
+	for i, r := range resps {
+		var v T
+		if decErr := r.Decode(&v); decErr != nil {
+			errs[i] = decErr
+			continue
+		}
+		items[i] = &v
+	}
+
+	return items, errs, nil
+}
@@ -0,0 +1,242 @@
+package googleapi
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	gapi "google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// fakeBatchServer answers Google-style multipart/mixed batch calls; handle returns the
+// status and JSON body for each sub-request path.
+func fakeBatchServer(t *testing.T, handle func(method, path string) (int, string)) (*httptest.Server, *[]int) {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		sizes []int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/batch/gmail/v1" {
+			http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusBadRequest)
+			return
+		}
+
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		type reply struct {
+			contentID string
+			status    int
+			body      string
+		}
+		var replies []reply
+
+		// Read every part before writing so the server does not close the request body early.
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Errorf("next part: %v", err)
+				return
+			}
+
+			inner, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				t.Errorf("read inner request: %v", err)
+				return
+			}
+			status, body := handle(inner.Method, inner.URL.RequestURI())
+			replies = append(replies, reply{contentID: part.Header.Get("Content-ID"), status: status, body: body})
+		}
+
+		mw := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+		for _, rep := range replies {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", "application/http")
+			header.Set("Content-ID", "<response-"+strings.Trim(rep.contentID, "<>")+">")
+			pw, _ := mw.CreatePart(header)
+			fmt.Fprintf(pw, "HTTP/1.1 %d %s\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s",
+				rep.status, http.StatusText(rep.status), len(rep.body), rep.body)
+		}
+		_ = mw.Close()
+
+		mu.Lock()
+		sizes = append(sizes, len(replies))
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &sizes
+}
+
+func TestBatcher_ChunksAndPerPartErrors(t *testing.T) {
+	srv, sizes := fakeBatchServer(t, func(_ string, path string) (int, string) {
+		if strings.HasSuffix(path, "/m7") {
+			return http.StatusNotFound, `{"error":{"code":404,"message":"Not Found"}}`
+		}
+		id := path[strings.LastIndex(path, "/")+1:]
+		return http.StatusOK, `{"id":"` + id + `"}`
+	})
+
+	b, err := NewBatcher(srv.Client(), srv.URL+"/", "gmail/v1")
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+
+	paths := make([]string, 150)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("users/me/messages/m%d", i)
+	}
+
+	type msg struct {
+		ID string `json:"id"`
+	}
+	items, errs, err := BatchGet[msg](context.Background(), b, paths)
+	if err != nil {
+		t.Fatalf("BatchGet: %v", err)
+	}
+
+	if got := *sizes; len(got) != 2 || got[0] != MaxBatchSize || got[1] != 50 {
+		t.Fatalf("unexpected batch sizes: %v", got)
+	}
+
+	for i := range paths {
+		if i == 7 {
+			var gerr *gapi.Error
+			if !errors.As(errs[i], &gerr) || gerr.Code != http.StatusNotFound {
+				t.Fatalf("expected 404 for item 7, got %v", errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil || items[i] == nil || items[i].ID != fmt.Sprintf("m%d", i) {
+			t.Fatalf("item %d: %#v err=%v", i, items[i], errs[i])
+		}
+	}
+}
+
+func TestBatcher_RetriesThrottledParts(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]int{}
+
+	srv, sizes := fakeBatchServer(t, func(_ string, path string) (int, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[path]++
+		if strings.HasSuffix(path, "/t1") && seen[path] == 1 {
+			return http.StatusTooManyRequests, `{"error":{"code":429,"message":"slow down"}}`
+		}
+		return http.StatusOK, `{"id":"ok"}`
+	})
+
+	b, err := NewBatcher(srv.Client(), srv.URL+"/", "gmail/v1")
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+	var slept time.Duration
+	b.sleep = func(_ context.Context, d time.Duration) error {
+		slept += d
+		return nil
+	}
+
+	resps, err := b.Do(context.Background(), []BatchRequest{
+		{Path: "users/me/threads/t0"},
+		{Path: "users/me/threads/t1"},
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	for i, r := range resps {
+		if r.Err != nil || r.StatusCode != http.StatusOK {
+			t.Fatalf("part %d: status=%d err=%v", i, r.StatusCode, r.Err)
+		}
+	}
+	if got := *sizes; len(got) != 2 || got[1] != 1 {
+		t.Fatalf("expected a retry round with only the throttled part, got %v", got)
+	}
+	if slept <= 0 {
+		t.Fatalf("expected backoff before retry")
+	}
+}
+
+func TestBatcher_WholeBatchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":{"code":401,"message":"nope"}}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	b, err := NewBatcher(srv.Client(), srv.URL+"/gmail/v1/", "gmail/v1")
+	if err != nil {
+		t.Fatalf("NewBatcher: %v", err)
+	}
+	if b.Endpoint != srv.URL+"/batch/gmail/v1" || b.Prefix != "/gmail/v1/" {
+		t.Fatalf("unexpected endpoint %q prefix %q", b.Endpoint, b.Prefix)
+	}
+
+	_, err = b.Do(context.Background(), []BatchRequest{{Path: "users/me/labels"}})
+	var gerr *gapi.Error
+	if !errors.As(err, &gerr) || gerr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 googleapi error, got %v", err)
+	}
+}
+
+func TestBatcherFor_Unregistered(t *testing.T) {
+	if BatcherFor(&struct{}{}) != nil {
+		t.Fatalf("expected nil batcher for unknown service")
+	}
+}
+
+func TestBatcherFor_DroppedWithService(t *testing.T) {
+	countBatchers := func() int {
+		n := 0
+		batchers.Range(func(any, any) bool { n++; return true })
+		return n
+	}
+	before := countBatchers()
+
+	func() {
+		svc, err := gmail.NewService(context.Background(), option.WithHTTPClient(http.DefaultClient))
+		if err != nil {
+			t.Fatalf("NewService: %v", err)
+		}
+		registerBatcher(svc, http.DefaultClient, svc.BasePath, "gmail/v1")
+		if b := BatcherFor(svc); b == nil || b.Prefix != "/gmail/v1/" {
+			t.Fatalf("expected a gmail batcher, got %+v", b)
+		}
+		if BatcherFor(&gmail.Service{}) != nil {
+			t.Fatalf("expected nil batcher for another service")
+		}
+	}()
+
+	for i := 0; i < 50 && countBatchers() > before; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := countBatchers(); n != before {
+		t.Fatalf("expected the batcher to be dropped with its service, have %d entries (was %d)", n, before)
+	}
+}
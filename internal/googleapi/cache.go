@@ -0,0 +1,488 @@
+package googleapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// maxCachedBodyBytes caps the size of a single cached response body.
+// Larger responses (exports, big listings) are passed through untouched.
+const maxCachedBodyBytes = 4 << 20
+
+// CacheStatusHeader is set on responses served or revalidated from the cache.
+const CacheStatusHeader = "X-Gog-Cache"
+
+// CacheOptions controls the on-disk HTTP response cache.
+//
+// The cache is opt-in: it is only used when TTL (or the http_cache_ttl config key) is positive.
+type CacheOptions struct {
+	TTL      time.Duration
+	Disabled bool
+}
+
+type cacheOptionsKey struct{}
+
+func WithCacheOptions(ctx context.Context, opts CacheOptions) context.Context {
+	return context.WithValue(ctx, cacheOptionsKey{}, opts)
+}
+
+func cacheOptionsFromContext(ctx context.Context) CacheOptions {
+	if ctx == nil {
+		return CacheOptions{}
+	}
+
+	if v, ok := ctx.Value(cacheOptionsKey{}).(CacheOptions); ok {
+		return v
+	}
+
+	return CacheOptions{}
+}
+
+// resolveCacheTTL returns the effective cache TTL: explicit options win, then config.
+func resolveCacheTTL(ctx context.Context) time.Duration {
+	opts := cacheOptionsFromContext(ctx)
+	if opts.Disabled {
+		return 0
+	}
+
+	if opts.TTL > 0 {
+		return opts.TTL
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return 0
+	}
+
+	ttl, err := config.ParseHTTPCacheTTL(cfg.HTTPCacheTTL)
+	if err != nil {
+		slog.Debug("ignoring invalid http_cache_ttl", "value", cfg.HTTPCacheTTL, "err", err)
+		return 0
+	}
+
+	return ttl
+}
+
+// CacheTransport serves GET responses from an on-disk cache keyed by account and URL.
+// Fresh entries are returned without a network call; stale entries that carry an ETag
+// are revalidated with If-None-Match. Successful non-GET requests invalidate the
+// account's cached entries for the same API.
+type CacheTransport struct {
+	Base    http.RoundTripper
+	Cache   *DiskCache
+	Account string
+	TTL     time.Duration
+
+	now func() time.Time
+}
+
+// NewCacheTransport creates a CacheTransport storing entries in cache.
+func NewCacheTransport(base http.RoundTripper, cache *DiskCache, account string, ttl time.Duration) *CacheTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &CacheTransport{
+		Base:    base,
+		Cache:   cache,
+		Account: strings.ToLower(strings.TrimSpace(account)),
+		TTL:     ttl,
+		now:     time.Now,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.Base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 {
+			if invErr := t.Cache.invalidateGroup(t.Account, req.URL.String()); invErr != nil {
+				slog.Debug("http cache invalidate failed", "url", req.URL.Redacted(), "err", invErr)
+			}
+		}
+
+		return resp, err //nolint:wrapcheck // pass-through transport
+	}
+
+	if !cacheableRequest(req) {
+		return t.Base.RoundTrip(req) //nolint:wrapcheck // pass-through transport
+	}
+
+	entry, ok := t.Cache.load(t.Account, req.URL.String())
+	if ok && t.now().Sub(entry.StoredAt) < t.TTL {
+		slog.Debug("http cache hit", "url", req.URL.Redacted())
+		return entry.response(req, "hit"), nil
+	}
+
+	outReq := req
+	if ok && entry.ETag != "" {
+		outReq = req.Clone(req.Context())
+		outReq.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := t.Base.RoundTrip(outReq)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass-through transport
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		entry.StoredAt = t.now()
+		if storeErr := t.Cache.store(entry); storeErr != nil {
+			slog.Debug("http cache refresh failed", "url", req.URL.Redacted(), "err", storeErr)
+		}
+		slog.Debug("http cache revalidated", "url", req.URL.Redacted())
+
+		return entry.response(req, "revalidated"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !cacheableResponse(resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	if len(body) > maxCachedBodyBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if req.Method == http.MethodGet {
+		newEntry := &cacheEntry{
+			Account:  t.Account,
+			URL:      req.URL.String(),
+			Status:   resp.StatusCode,
+			Header:   resp.Header.Clone(),
+			Body:     body,
+			ETag:     resp.Header.Get("ETag"),
+			StoredAt: t.now(),
+		}
+		if storeErr := t.Cache.store(newEntry); storeErr != nil {
+			slog.Debug("http cache store failed", "url", req.URL.Redacted(), "err", storeErr)
+		}
+	}
+
+	return resp, nil
+}
+
+// uncachedQueryParams mark GET requests that always go to the network.
+var uncachedQueryParams = []string{"syncToken", "startHistoryId", "pageToken"}
+
+func cacheableRequest(req *http.Request) bool {
+	if req.Header.Get("Range") != "" {
+		return false
+	}
+
+	if strings.Contains(strings.ToLower(req.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+
+	query := req.URL.Query()
+
+	// Answers to incremental calls (gmail history.list, calendar events.list with a
+	// syncToken, drive changes.list and paged lists) depend on sync state rather than
+	// on the URL alone, so a cached copy would report "no changes" for up to the TTL.
+	for _, param := range uncachedQueryParams {
+		if query.Has(param) {
+			return false
+		}
+	}
+
+	// Media downloads can be large and are better served by the drive/gmail download paths.
+	return query.Get("alt") != "media"
+}
+
+func cacheableResponse(resp *http.Response) bool {
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return false
+	}
+
+	return resp.ContentLength <= maxCachedBodyBytes
+}
+
+type cacheEntry struct {
+	Account  string      `json:"account"`
+	URL      string      `json:"url"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	ETag     string      `json:"etag,omitempty"`
+	StoredAt time.Time   `json:"storedAt"`
+}
+
+func (e *cacheEntry) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(CacheStatusHeader, status)
+
+	body := e.Body
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// DiskCache stores cached responses as JSON files:
+//
+//	<dir>/<account hash>/<api hash>/<url hash>.json
+//
+// Grouping by API (host plus the first two path segments, e.g. /gmail/v1, with any
+// /upload prefix dropped) lets a write invalidate only the entries it can affect.
+type DiskCache struct {
+	Dir string
+}
+
+// OpenDiskCache returns a cache rooted at config.HTTPCacheDir().
+func OpenDiskCache() (*DiskCache, error) {
+	dir, err := config.HTTPCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiskCache{Dir: dir}, nil
+}
+
+// CacheStats summarizes the on-disk cache.
+type CacheStats struct {
+	Dir      string         `json:"dir"`
+	Entries  int            `json:"entries"`
+	Bytes    int64          `json:"bytes"`
+	Oldest   *time.Time     `json:"oldest,omitempty"`
+	Newest   *time.Time     `json:"newest,omitempty"`
+	Accounts map[string]int `json:"accounts,omitempty"`
+}
+
+func hashKey(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func apiGroup(rawURL string) string {
+	s := rawURL
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok {
+		return s
+	}
+
+	// Media uploads go to /upload/<api>/... (or /resumable/upload/<api>/...) but
+	// change the same resources as the base API, so they share its group.
+	host, path, _ := strings.Cut(rest, "/")
+	for _, prefix := range []string{"resumable/upload/", "upload/"} {
+		if trimmed, ok := strings.CutPrefix(path, prefix); ok {
+			rest = host + "/" + trimmed
+			break
+		}
+	}
+
+	parts := strings.SplitN(rest, "/", 4)
+	if len(parts) > 3 {
+		parts = parts[:3]
+	}
+
+	return scheme + "://" + strings.Join(parts, "/")
+}
+
+func (c *DiskCache) accountDir(account string) string {
+	return filepath.Join(c.Dir, hashKey(account)[:16])
+}
+
+func (c *DiskCache) groupDir(account string, rawURL string) string {
+	return filepath.Join(c.accountDir(account), hashKey(apiGroup(rawURL))[:16])
+}
+
+func (c *DiskCache) entryPath(account string, rawURL string) string {
+	return filepath.Join(c.groupDir(account, rawURL), hashKey(rawURL)+".json")
+}
+
+func (c *DiskCache) load(account string, rawURL string) (*cacheEntry, bool) {
+	b, err := os.ReadFile(c.entryPath(account, rawURL)) //nolint:gosec // path derived from hashes under the cache dir
+	if err != nil {
+		return nil, false
+	}
+
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, false
+	}
+
+	if e.Account != account || e.URL != rawURL {
+		return nil, false
+	}
+
+	return &e, true
+}
+
+func (c *DiskCache) store(e *cacheEntry) error {
+	dir := c.groupDir(e.Account, e.URL)
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("ensure http cache dir: %w", err)
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+
+	path := c.entryPath(e.Account, e.URL)
+	tmp, err := os.CreateTemp(dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("commit cache entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *DiskCache) invalidateGroup(account string, rawURL string) error {
+	if err := os.RemoveAll(c.groupDir(account, rawURL)); err != nil {
+		return fmt.Errorf("invalidate http cache: %w", err)
+	}
+
+	return nil
+}
+
+// Clear removes cached entries. An empty account clears everything.
+// It returns the number of entries removed.
+func (c *DiskCache) Clear(account string) (int, error) {
+	root := c.Dir
+	if account = strings.ToLower(strings.TrimSpace(account)); account != "" {
+		root = c.accountDir(account)
+	}
+
+	count := 0
+	err := c.walk(root, func(string, os.FileInfo) {
+		count++
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.RemoveAll(root); err != nil {
+		return 0, fmt.Errorf("clear http cache: %w", err)
+	}
+
+	return count, nil
+}
+
+// Stats scans the cache directory.
+func (c *DiskCache) Stats() (CacheStats, error) {
+	stats := CacheStats{Dir: c.Dir, Accounts: map[string]int{}}
+
+	err := c.walk(c.Dir, func(path string, info os.FileInfo) {
+		stats.Entries++
+		stats.Bytes += info.Size()
+
+		b, err := os.ReadFile(path) //nolint:gosec // path found under the cache dir
+		if err != nil {
+			return
+		}
+
+		var e struct {
+			Account  string    `json:"account"`
+			StoredAt time.Time `json:"storedAt"`
+		}
+		if json.Unmarshal(b, &e) != nil {
+			return
+		}
+
+		stats.Accounts[e.Account]++
+		if stats.Oldest == nil || e.StoredAt.Before(*stats.Oldest) {
+			at := e.StoredAt
+			stats.Oldest = &at
+		}
+		if stats.Newest == nil || e.StoredAt.After(*stats.Newest) {
+			at := e.StoredAt
+			stats.Newest = &at
+		}
+	})
+	if err != nil {
+		return CacheStats{}, err
+	}
+
+	return stats, nil
+}
+
+func (c *DiskCache) walk(root string, fn func(path string, info os.FileInfo)) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".json") {
+			return nil
+		}
+
+		fn(path, info)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scan http cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func cacheGet(t *testing.T, rt http.RoundTripper, url string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+
+	return resp, string(b)
+}
+
+func TestCacheTransport_HitAndRevalidate(t *testing.T) {
+	var calls, conditional atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"items":[]}`)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rt := NewCacheTransport(srv.Client().Transport, &DiskCache{Dir: t.TempDir()}, "A@B.com", time.Minute)
+	rt.now = func() time.Time { return now }
+
+	url := srv.URL + "/gmail/v1/users/me/labels"
+
+	if _, body := cacheGet(t, rt, url); body != `{"items":[]}` {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	resp, body := cacheGet(t, rt, url)
+	if body != `{"items":[]}` || resp.Header.Get(CacheStatusHeader) != "hit" {
+		t.Fatalf("expected cache hit, got status=%q body=%q", resp.Header.Get(CacheStatusHeader), body)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", calls.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	resp, body = cacheGet(t, rt, url)
+	if body != `{"items":[]}` || resp.Header.Get(CacheStatusHeader) != "revalidated" {
+		t.Fatalf("expected revalidated, got status=%q body=%q", resp.Header.Get(CacheStatusHeader), body)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if conditional.Load() != 1 {
+		t.Fatalf("expected conditional request, got %d", conditional.Load())
+	}
+
+	// Revalidation refreshes the entry, so the next read is a hit again.
+	if resp, _ := cacheGet(t, rt, url); resp.Header.Get(CacheStatusHeader) != "hit" {
+		t.Fatalf("expected hit after revalidation, got %q", resp.Header.Get(CacheStatusHeader))
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestCacheTransport_KeyedByAccount(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	cache := &DiskCache{Dir: t.TempDir()}
+	a := NewCacheTransport(srv.Client().Transport, cache, "a@b.com", time.Hour)
+	b := NewCacheTransport(srv.Client().Transport, cache, "c@d.com", time.Hour)
+
+	url := srv.URL + "/calendar/v3/users/me/calendarList"
+	cacheGet(t, a, url)
+	cacheGet(t, a, url)
+	cacheGet(t, b, url)
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", calls.Load())
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if stats.Entries != 2 || stats.Accounts["a@b.com"] != 1 || stats.Accounts["c@d.com"] != 1 {
+		t.Fatalf("unexpected stats: %#v", stats)
+	}
+
+	removed, err := cache.Clear("A@B.com")
+	if err != nil || removed != 1 {
+		t.Fatalf("clear account: removed=%d err=%v", removed, err)
+	}
+
+	removed, err = cache.Clear("")
+	if err != nil || removed != 1 {
+		t.Fatalf("clear all: removed=%d err=%v", removed, err)
+	}
+}
+
+func TestCacheTransport_WriteInvalidatesAPI(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, r.Method)
+	}))
+	defer srv.Close()
+
+	rt := NewCacheTransport(srv.Client().Transport, &DiskCache{Dir: t.TempDir()}, "a@b.com", time.Hour)
+	labels := srv.URL + "/gmail/v1/users/me/labels"
+	events := srv.URL + "/calendar/v3/calendars/primary/events"
+
+	cacheGet(t, rt, labels)
+	cacheGet(t, rt, events)
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, labels, strings.NewReader(`{}`))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp, _ := cacheGet(t, rt, labels); resp.Header.Get(CacheStatusHeader) != "" {
+		t.Fatalf("expected gmail entry to be invalidated")
+	}
+	if resp, _ := cacheGet(t, rt, events); resp.Header.Get(CacheStatusHeader) != "hit" {
+		t.Fatalf("expected calendar entry to survive")
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected 4 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestCacheTransport_UploadInvalidatesBaseAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Method)
+	}))
+	defer srv.Close()
+
+	rt := NewCacheTransport(srv.Client().Transport, &DiskCache{Dir: t.TempDir()}, "a@b.com", time.Hour)
+	files := srv.URL + "/drive/v3/files?q=trashed%3Dfalse"
+
+	cacheGet(t, rt, files)
+	if resp, _ := cacheGet(t, rt, files); resp.Header.Get(CacheStatusHeader) != "hit" {
+		t.Fatalf("expected drive listing to be cached")
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL+"/upload/drive/v3/files?uploadType=multipart", strings.NewReader(`{}`))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp, _ := cacheGet(t, rt, files); resp.Header.Get(CacheStatusHeader) != "" {
+		t.Fatalf("expected upload to invalidate the drive listing")
+	}
+}
+
+func TestCacheTransport_SkipsSyncStateRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+
+	rt := NewCacheTransport(srv.Client().Transport, &DiskCache{Dir: t.TempDir()}, "a@b.com", time.Hour)
+	for _, path := range []string{
+		"/gmail/v1/users/me/history?startHistoryId=42",
+		"/calendar/v3/calendars/primary/events?syncToken=s1",
+		"/drive/v3/files?pageToken=p2",
+	} {
+		calls.Store(0)
+		cacheGet(t, rt, srv.URL+path)
+		if resp, _ := cacheGet(t, rt, srv.URL+path); resp.Header.Get(CacheStatusHeader) != "" {
+			t.Fatalf("%s: expected no cache, got %q", path, resp.Header.Get(CacheStatusHeader))
+		}
+		if calls.Load() != 2 {
+			t.Fatalf("%s: expected 2 upstream calls, got %d", path, calls.Load())
+		}
+	}
+}
+
+func TestAPIGroup(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://www.googleapis.com/drive/v3/files?q=x":                    "https://www.googleapis.com/drive/v3",
+		"https://www.googleapis.com/upload/drive/v3/files":                 "https://www.googleapis.com/drive/v3",
+		"https://www.googleapis.com/resumable/upload/drive/v3/files":       "https://www.googleapis.com/drive/v3",
+		"https://gmail.googleapis.com/upload/gmail/v1/users/me/messages/x": "https://gmail.googleapis.com/gmail/v1",
+		"https://gmail.googleapis.com/gmail/v1/users/me/labels":            "https://gmail.googleapis.com/gmail/v1",
+	} {
+		if got := apiGroup(in); got != want {
+			t.Errorf("apiGroup(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCacheTransport_SkipsMediaAndErrors(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "data")
+	}))
+	defer srv.Close()
+
+	rt := NewCacheTransport(srv.Client().Transport, &DiskCache{Dir: t.TempDir()}, "a@b.com", time.Hour)
+
+	for range 2 {
+		cacheGet(t, rt, srv.URL+"/drive/v3/files/x?alt=media")
+		cacheGet(t, rt, srv.URL+"/drive/v3/files/missing")
+	}
+
+	if calls.Load() != 4 {
+		t.Fatalf("expected every request to reach upstream, got %d", calls.Load())
+	}
+}
+
+func TestResolveCacheTTL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if got := resolveCacheTTL(context.Background()); got != 0 {
+		t.Fatalf("expected cache disabled by default, got %v", got)
+	}
+
+	ctx := WithCacheOptions(context.Background(), CacheOptions{TTL: time.Minute})
+	if got := resolveCacheTTL(ctx); got != time.Minute {
+		t.Fatalf("expected 1m, got %v", got)
+	}
+
+	ctx = WithCacheOptions(context.Background(), CacheOptions{TTL: time.Minute, Disabled: true})
+	if got := resolveCacheTTL(ctx); got != 0 {
+		t.Fatalf("expected --no-cache to win, got %v", got)
+	}
+}
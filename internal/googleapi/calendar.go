@@ -5,16 +5,18 @@ import (
 	"fmt"
 
 	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
 
 	"github.com/steipete/gogcli/internal/googleauth"
 )
 
 func NewCalendar(ctx context.Context, email string) (*calendar.Service, error) {
-	if opts, err := optionsForAccount(ctx, googleauth.ServiceCalendar, email); err != nil {
+	if client, err := httpClientForAccount(ctx, googleauth.ServiceCalendar, email); err != nil {
 		return nil, fmt.Errorf("calendar options: %w", err)
-	} else if svc, err := calendar.NewService(ctx, opts...); err != nil {
+	} else if svc, err := calendar.NewService(ctx, option.WithHTTPClient(client)); err != nil {
 		return nil, fmt.Errorf("create calendar service: %w", err)
 	} else {
+		registerBatcher(svc, client, svc.BasePath, "calendar/v3")
 		return svc, nil
 	}
 }
@@ -20,12 +20,36 @@ type CircuitBreaker struct {
 	failures    int
 	lastFailure time.Time
 	open        bool
+	threshold   int
+	resetTime   time.Duration
 }
 
 func NewCircuitBreaker() *CircuitBreaker {
 	return &CircuitBreaker{}
 }
 
+// NewCircuitBreakerWith creates a breaker with a custom threshold and reset time.
+// Non-positive values fall back to CircuitBreakerThreshold / CircuitBreakerResetTime.
+func NewCircuitBreakerWith(threshold int, resetTime time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, resetTime: resetTime}
+}
+
+func (cb *CircuitBreaker) thresholdOrDefault() int {
+	if cb.threshold > 0 {
+		return cb.threshold
+	}
+
+	return CircuitBreakerThreshold
+}
+
+func (cb *CircuitBreaker) resetTimeOrDefault() time.Duration {
+	if cb.resetTime > 0 {
+		return cb.resetTime
+	}
+
+	return CircuitBreakerResetTime
+}
+
 func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
@@ -45,7 +69,7 @@ func (cb *CircuitBreaker) RecordFailure() bool {
 	cb.failures++
 	cb.lastFailure = time.Now()
 
-	if cb.failures >= CircuitBreakerThreshold {
+	if cb.failures >= cb.thresholdOrDefault() {
 		cb.open = true
 		slog.Warn("circuit breaker opened", "failures", cb.failures)
 
@@ -63,7 +87,7 @@ func (cb *CircuitBreaker) IsOpen() bool {
 		return false
 	}
 	// Check if reset time has passed
-	if time.Since(cb.lastFailure) > CircuitBreakerResetTime {
+	if time.Since(cb.lastFailure) > cb.resetTimeOrDefault() {
 		cb.open = false
 		cb.failures = 0
 
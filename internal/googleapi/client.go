@@ -18,6 +18,7 @@ import (
 	"github.com/steipete/gogcli/internal/config"
 	"github.com/steipete/gogcli/internal/googleauth"
 	"github.com/steipete/gogcli/internal/secrets"
+	"github.com/steipete/gogcli/internal/tracing"
 )
 
 const defaultHTTPTimeout = 30 * time.Second
@@ -84,17 +85,43 @@ func tokenSourceForAccountScopes(ctx context.Context, serviceLabel string, email
 }
 
 func optionsForAccount(ctx context.Context, service googleauth.Service, email string) ([]option.ClientOption, error) {
+	c, err := httpClientForAccount(ctx, service, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+}
+
+func optionsForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) ([]option.ClientOption, error) {
+	c, err := httpClientForAccountScopes(ctx, serviceLabel, email, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+}
+
+func httpClientForAccount(ctx context.Context, service googleauth.Service, email string) (*http.Client, error) {
 	scopes, err := googleauth.Scopes(service)
 	if err != nil {
 		return nil, fmt.Errorf("resolve scopes: %w", err)
 	}
 
-	return optionsForAccountScopes(ctx, string(service), email, scopes)
+	return httpClientForAccountScopes(ctx, string(service), email, scopes)
 }
 
-func optionsForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) ([]option.ClientOption, error) {
+func httpClientForAccountScopes(ctx context.Context, serviceLabel string, email string, scopes []string) (*http.Client, error) {
 	slog.Debug("creating client options with custom scopes", "serviceLabel", serviceLabel, "email", email)
 
+	// Replay mode serves recorded cassettes, so no credentials or tokens are needed.
+	if replay, err := replayTransportFromEnv(); err != nil {
+		return nil, err
+	} else if replay != nil {
+		slog.Debug("replaying recorded API responses", "serviceLabel", serviceLabel, "email", email)
+		return &http.Client{Transport: wrapTraceFromContext(tracing.FromContext(ctx), replay, serviceLabel, email)}, nil
+	}
+
 	var creds config.ClientCredentials
 
 	var ts oauth2.TokenSource
@@ -122,20 +149,49 @@ func optionsForAccountScopes(ctx context.Context, serviceLabel string, email str
 			ts = tokenSource
 		}
 	}
+	policy, err := resolveRetryPolicy(ctx, serviceLabel)
+	if err != nil {
+		return nil, fmt.Errorf("retry policy: %w", err)
+	}
+
+	limiter, err := resolveRateLimiter(serviceLabel, email)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
 	baseTransport := newBaseTransport()
-	// Wrap with retry logic for 429 and 5xx errors
-	retryTransport := NewRetryTransport(&oauth2.Transport{
+	var authTransport http.RoundTripper = &oauth2.Transport{
 		Source: ts,
 		Base:   baseTransport,
-	})
+	}
+	// Proactive client-side throttling; every attempt (including retries) takes a token.
+	if limiter != nil {
+		authTransport = &RateLimitTransport{Base: authTransport, Limiter: limiter}
+	}
+	// Wrap with retry logic for 429, 5xx and transient network errors
+	retryTransport := NewRetryTransportWithPolicy(authTransport, policy)
+	var transport http.RoundTripper = retryTransport
+	// Optional on-disk cache sits outside retry/auth so hits never touch the network.
+	if ttl := resolveCacheTTL(ctx); ttl > 0 {
+		if cache, err := OpenDiskCache(); err == nil {
+			transport = NewCacheTransport(retryTransport, cache, email, ttl)
+		} else {
+			slog.Debug("http cache unavailable", "err", err)
+		}
+	}
+	if transport, err = wrapRecordFromEnv(transport); err != nil {
+		return nil, err
+	}
+	// Tracing is outermost so cache hits and recorded calls still show up as spans.
+	transport = wrapTraceFromContext(tracing.FromContext(ctx), transport, serviceLabel, email)
 	c := &http.Client{
-		Transport: retryTransport,
-		Timeout:   defaultHTTPTimeout,
+		Transport: transport,
+		Timeout:   policy.Timeout,
 	}
 
 	slog.Debug("client options with custom scopes created successfully", "serviceLabel", serviceLabel, "email", email)
 
-	return []option.ClientOption{option.WithHTTPClient(c)}, nil
+	return c, nil
 }
 
 func newBaseTransport() *http.Transport {
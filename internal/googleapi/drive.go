@@ -5,16 +5,18 @@ import (
 	"fmt"
 
 	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
 
 	"github.com/steipete/gogcli/internal/googleauth"
 )
 
 func NewDrive(ctx context.Context, email string) (*drive.Service, error) {
-	if opts, err := optionsForAccount(ctx, googleauth.ServiceDrive, email); err != nil {
+	if client, err := httpClientForAccount(ctx, googleauth.ServiceDrive, email); err != nil {
 		return nil, fmt.Errorf("drive options: %w", err)
-	} else if svc, err := drive.NewService(ctx, opts...); err != nil {
+	} else if svc, err := drive.NewService(ctx, option.WithHTTPClient(client)); err != nil {
 		return nil, fmt.Errorf("create drive service: %w", err)
 	} else {
+		registerBatcher(svc, client, svc.BasePath, "drive/v3")
 		return svc, nil
 	}
 }
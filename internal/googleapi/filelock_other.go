@@ -0,0 +1,28 @@
+//go:build !windows
+
+package googleapi
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// LockFile takes an exclusive advisory lock shared with other processes. It
+// blocks until the lock is available.
+func LockFile(f *os.File) error {
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("flock: %w", err)
+	}
+
+	return nil
+}
+
+// UnlockFile releases a lock taken by LockFile.
+func UnlockFile(f *os.File) error {
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_UN); err != nil {
+		return fmt.Errorf("flock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package googleapi
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// LockFile takes an exclusive lock shared with other processes.
+func LockFile(f *os.File) error {
+	ol := new(windows.Overlapped)
+	if err := windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, ol); err != nil {
+		return fmt.Errorf("LockFileEx: %w", err)
+	}
+
+	return nil
+}
+
+// UnlockFile releases a lock taken by LockFile.
+func UnlockFile(f *os.File) error {
+	ol := new(windows.Overlapped)
+	if err := windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, ol); err != nil {
+		return fmt.Errorf("UnlockFileEx: %w", err)
+	}
+
+	return nil
+}
@@ -5,16 +5,18 @@ import (
 	"fmt"
 
 	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
 
 	"github.com/steipete/gogcli/internal/googleauth"
 )
 
 func NewGmail(ctx context.Context, email string) (*gmail.Service, error) {
-	if opts, err := optionsForAccount(ctx, googleauth.ServiceGmail, email); err != nil {
+	if client, err := httpClientForAccount(ctx, googleauth.ServiceGmail, email); err != nil {
 		return nil, fmt.Errorf("gmail options: %w", err)
-	} else if svc, err := gmail.NewService(ctx, opts...); err != nil {
+	} else if svc, err := gmail.NewService(ctx, option.WithHTTPClient(client)); err != nil {
 		return nil, fmt.Errorf("create gmail service: %w", err)
 	} else {
+		registerBatcher(svc, client, svc.BasePath, "gmail/v1")
 		return svc, nil
 	}
 }
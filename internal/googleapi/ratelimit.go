@@ -0,0 +1,177 @@
+package googleapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+	"github.com/steipete/gogcli/internal/tracing"
+)
+
+// FileRateLimiter is a token bucket whose state lives in a file, so every gog process
+// using the same account+API shares one budget. Each Wait takes the file lock,
+// refills the bucket, reserves one token and sleeps off any deficit outside the lock.
+type FileRateLimiter struct {
+	Path  string
+	QPS   float64
+	Burst float64
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+type rateLimitState struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+// NewFileRateLimiter creates a limiter; burst <= 0 defaults to max(1, qps).
+func NewFileRateLimiter(path string, qps float64, burst int) *FileRateLimiter {
+	b := float64(burst)
+	if b <= 0 {
+		b = math.Max(1, math.Floor(qps))
+	}
+
+	return &FileRateLimiter{
+		Path:  path,
+		QPS:   qps,
+		Burst: b,
+		now:   time.Now,
+		sleep: SleepContext,
+	}
+}
+
+// Wait blocks until the caller may send one request.
+func (l *FileRateLimiter) Wait(ctx context.Context) error {
+	delay, err := l.reserve()
+	if err != nil {
+		return err
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	slog.Debug("rate limiter throttling", "delay", delay, "qps", l.QPS, "path", filepath.Base(l.Path))
+
+	return l.sleep(ctx, delay)
+}
+
+func (l *FileRateLimiter) reserve() (time.Duration, error) {
+	f, err := os.OpenFile(l.Path, os.O_RDWR|os.O_CREATE, 0o600) //nolint:gosec // path under the config dir
+	if err != nil {
+		return 0, fmt.Errorf("open rate limit state: %w", err)
+	}
+	defer f.Close()
+
+	if err := LockFile(f); err != nil {
+		return 0, fmt.Errorf("lock rate limit state: %w", err)
+	}
+	defer func() { _ = UnlockFile(f) }()
+
+	now := l.now()
+	state := rateLimitState{Tokens: l.Burst, Updated: now}
+
+	if b, readErr := io.ReadAll(f); readErr == nil && len(b) > 0 {
+		var prev rateLimitState
+		if json.Unmarshal(b, &prev) == nil {
+			state = prev
+		}
+	}
+
+	if elapsed := now.Sub(state.Updated); elapsed > 0 {
+		state.Tokens = math.Min(l.Burst, state.Tokens+elapsed.Seconds()*l.QPS)
+	}
+	state.Tokens--
+	state.Updated = now
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return 0, fmt.Errorf("encode rate limit state: %w", err)
+	}
+	if err := f.Truncate(0); err != nil {
+		return 0, fmt.Errorf("write rate limit state: %w", err)
+	}
+	if _, err := f.WriteAt(b, 0); err != nil {
+		return 0, fmt.Errorf("write rate limit state: %w", err)
+	}
+
+	if state.Tokens >= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(-state.Tokens / l.QPS * float64(time.Second)), nil
+}
+
+// RateLimitTransport waits on Limiter before every request. Limiter failures (for
+// example an unwritable state dir) are logged and do not block the request.
+type RateLimitTransport struct {
+	Base    http.RoundTripper
+	Limiter *FileRateLimiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if err := t.Limiter.Wait(req.Context()); err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, fmt.Errorf("rate limit wait: %w", ctxErr)
+		}
+		slog.Debug("rate limiter unavailable", "err", err)
+	}
+	if waited := time.Since(start); waited >= time.Millisecond {
+		tracing.SpanFromContext(req.Context()).AddEvent("ratelimit.wait", map[string]any{"gog.ratelimit.wait": waited})
+	}
+
+	return t.Base.RoundTrip(req) //nolint:wrapcheck // pass-through transport
+}
+
+// resolveRateLimiter returns the limiter for account+service, or nil when no QPS is configured.
+func resolveRateLimiter(service string, email string) (*FileRateLimiter, error) {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	rl, err := config.ResolveRateLimit(cfg, service)
+	if err != nil {
+		return nil, err
+	}
+	qps, burst := rl.Limit()
+	if qps <= 0 {
+		return nil, nil
+	}
+
+	dir, err := config.EnsureRateLimitDir()
+	if err != nil {
+		return nil, err
+	}
+
+	safeEmail := base64.RawURLEncoding.EncodeToString([]byte(strings.ToLower(strings.TrimSpace(email))))
+	name := fmt.Sprintf("%s-%s.json", safeEmail, strings.ToLower(strings.TrimSpace(service)))
+
+	return NewFileRateLimiter(filepath.Join(dir, name), qps, burst), nil
+}
+
+// SleepContext waits for d or until ctx is done, whichever comes first.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("sleep interrupted: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,146 @@
+package googleapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestFileRateLimiter_SharedBucket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bucket.json")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Two limiters on one file stand in for two gog processes.
+	var slept []time.Duration
+	newLimiter := func() *FileRateLimiter {
+		l := NewFileRateLimiter(path, 10, 2)
+		l.now = func() time.Time { return now }
+		l.sleep = func(_ context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			return nil
+		}
+		return l
+	}
+	a, b := newLimiter(), newLimiter()
+
+	for _, l := range []*FileRateLimiter{a, b, a, b} {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+
+	// Burst of 2 is free; the next two reservations wait 100ms and 200ms at 10 QPS.
+	if len(slept) != 2 || slept[0] != 100*time.Millisecond || slept[1] != 200*time.Millisecond {
+		t.Fatalf("unexpected sleeps: %v", slept)
+	}
+
+	// After a second the bucket refills to its burst again.
+	now = now.Add(time.Second)
+	slept = nil
+	if err := a.Wait(context.Background()); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if len(slept) != 0 {
+		t.Fatalf("expected refilled bucket, got sleeps %v", slept)
+	}
+}
+
+func TestFileRateLimiter_ConcurrentReservationsAreSerialized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bucket.json")
+	now := time.Now()
+
+	var mu sync.Mutex
+	var total time.Duration
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l := NewFileRateLimiter(path, 100, 1)
+			l.now = func() time.Time { return now }
+			l.sleep = func(_ context.Context, d time.Duration) error {
+				mu.Lock()
+				total += d
+				mu.Unlock()
+				return nil
+			}
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("wait: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 19 queued reservations wait 10ms, 20ms, ... 190ms: sum = 1.9s.
+	if want := 1900 * time.Millisecond; total < want-time.Millisecond || total > want+time.Millisecond {
+		t.Fatalf("expected total wait ~%v, got %v", want, total)
+	}
+}
+
+func TestRateLimitTransport_ContextCanceled(t *testing.T) {
+	l := NewFileRateLimiter(filepath.Join(t.TempDir(), "bucket.json"), 0.001, 1)
+	calls := 0
+	rt := &RateLimitTransport{
+		Base: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			calls++
+			return newTestResponse(http.StatusOK, "ok"), nil
+		}),
+		Limiter: l,
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	if resp, err := rt.RoundTrip(req); err == nil {
+		_ = resp.Body.Close()
+		t.Fatalf("expected canceled wait")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", calls)
+	}
+}
+
+func TestResolveRateLimiter(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if l, err := resolveRateLimiter("gmail", "a@b.com"); err != nil || l != nil {
+		t.Fatalf("expected no limiter by default, got %v %v", l, err)
+	}
+
+	dir, err := config.EnsureDir()
+	if err != nil {
+		t.Fatalf("ensure dir: %v", err)
+	}
+	cfg := `{ rate_limit: { default: { qps: 5 }, drive: { qps: 2, burst: 4 }, calendar: { qps: 0 } } }`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(cfg), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	l, err := resolveRateLimiter("drive", "A@B.com")
+	if err != nil || l == nil {
+		t.Fatalf("expected limiter, got %v %v", l, err)
+	}
+	if l.QPS != 2 || l.Burst != 4 {
+		t.Fatalf("unexpected limiter: qps=%v burst=%v", l.QPS, l.Burst)
+	}
+	if other, _ := resolveRateLimiter("gmail", "a@b.com"); other == nil || other.QPS != 5 || other.Burst != 5 || other.Path == l.Path {
+		t.Fatalf("unexpected gmail limiter: %#v", other)
+	}
+	if off, err := resolveRateLimiter("calendar", "a@b.com"); err != nil || off != nil {
+		t.Fatalf("expected calendar qps: 0 to disable the default limit, got %v %v", off, err)
+	}
+}
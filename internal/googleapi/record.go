@@ -0,0 +1,414 @@
+package googleapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Record/replay ("cassette") support.
+//
+// GOG_RECORD=dir wraps every API client so each request/response pair is written to
+// dir as a sanitized JSON file. GOG_REPLAY=dir serves responses from those files
+// instead of the network (no credentials needed), so any command can run
+// end-to-end against recorded fixtures.
+const (
+	envRecordDir = "GOG_RECORD"
+	envReplayDir = "GOG_REPLAY"
+)
+
+var (
+	errRecordReplayConflict = errors.New("GOG_RECORD and GOG_REPLAY are mutually exclusive")
+	errReplayMiss           = errors.New("replay: no recorded response")
+)
+
+// sensitiveHeaders are never written to a cassette.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Goog-Api-Key",
+}
+
+// sensitiveQueryParams are dropped from recorded URLs and ignored when matching.
+var sensitiveQueryParams = []string{"access_token", "key", "oauth_token"}
+
+// Interaction is one recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// BodyBase64 is set when Body holds base64 because the payload was not UTF-8.
+	BodyBase64 bool `json:"bodyBase64,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 bool        `json:"bodyBase64,omitempty"`
+}
+
+// replayTransportFromEnv returns a ReplayTransport when GOG_REPLAY is set.
+func replayTransportFromEnv() (http.RoundTripper, error) {
+	replayDir := strings.TrimSpace(os.Getenv(envReplayDir))
+	if replayDir == "" {
+		return nil, nil
+	}
+
+	if strings.TrimSpace(os.Getenv(envRecordDir)) != "" {
+		return nil, errRecordReplayConflict
+	}
+
+	return NewReplayTransport(replayDir)
+}
+
+// wrapRecordFromEnv wraps base with a RecordTransport when GOG_RECORD is set.
+func wrapRecordFromEnv(base http.RoundTripper) (http.RoundTripper, error) {
+	recordDir := strings.TrimSpace(os.Getenv(envRecordDir))
+	if recordDir == "" {
+		return base, nil
+	}
+
+	return NewRecordTransport(base, recordDir)
+}
+
+// RecordTransport writes every request/response pair that passes through it to Dir.
+type RecordTransport struct {
+	Base http.RoundTripper
+	Dir  string
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewRecordTransport creates Dir if needed and continues numbering after existing cassettes.
+func NewRecordTransport(base http.RoundTripper, dir string) (*RecordTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("ensure record dir: %w", err)
+	}
+
+	existing, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordTransport{Base: base, Dir: dir, seq: len(existing)}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := ensureReplayableBody(req); err != nil {
+		return nil, err
+	}
+
+	var reqBody []byte
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		reqBody, err = io.ReadAll(body)
+		_ = body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // pass-through transport
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    sanitizeURL(req.URL),
+			Header: sanitizeHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: sanitizeHeader(resp.Header),
+		},
+	}
+	in.Request.Body, in.Request.BodyBase64 = encodeBody(reqBody)
+	in.Response.Body, in.Response.BodyBase64 = encodeBody(respBody)
+
+	if err := t.write(in); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+var slugUnsafe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func (t *RecordTransport) write(in Interaction) error {
+	b, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	b = append(b, '\n')
+
+	slug := "request"
+	if u, parseErr := url.Parse(in.Request.URL); parseErr == nil {
+		if s := strings.Trim(slugUnsafe.ReplaceAllString(u.Path, "_"), "_"); s != "" {
+			slug = s
+		}
+	}
+	if len(slug) > 80 {
+		slug = slug[len(slug)-80:]
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for {
+		t.seq++
+		name := fmt.Sprintf("%04d-%s-%s.json", t.seq, in.Request.Method, slug)
+		f, err := os.OpenFile(filepath.Join(t.Dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // record dir chosen by the user
+		if errors.Is(err, os.ErrExist) {
+			// Another gog process recorded into the same dir; take the next number.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("write cassette: %w", err)
+		}
+		if _, err := f.Write(b); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("write cassette: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("write cassette: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// ReplayTransport serves recorded responses. Requests are matched by method, path and
+// query (host and sensitive params are ignored); repeated requests consume matching
+// interactions in recording order and reuse the last one once exhausted.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayTransport loads every cassette in dir.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	files, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &ReplayTransport{}
+	for _, path := range files {
+		b, err := os.ReadFile(path) //nolint:gosec // replay dir chosen by the user
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+
+		var in Interaction
+		if err := json.Unmarshal(b, &in); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", filepath.Base(path), err)
+		}
+		t.interactions = append(t.interactions, in)
+	}
+	t.used = make([]bool, len(t.interactions))
+
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+
+	key := matchKey(req.Method, sanitizeURL(req.URL))
+
+	t.mu.Lock()
+	last := -1
+	found := -1
+	for i, in := range t.interactions {
+		if matchKey(in.Request.Method, in.Request.URL) != key {
+			continue
+		}
+		last = i
+		if !t.used[i] {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		found = last
+	}
+	if found >= 0 {
+		t.used[found] = true
+	}
+	t.mu.Unlock()
+
+	if found < 0 {
+		return nil, fmt.Errorf("%w for %s", errReplayMiss, key)
+	}
+
+	rec := t.interactions[found].Response
+
+	body, err := decodeBody(rec.Body, rec.BodyBase64)
+	if err != nil {
+		return nil, fmt.Errorf("replay: decode body for %s: %w", key, err)
+	}
+
+	header := rec.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func cassetteFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette dir: %w", err)
+	}
+
+	var out []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		out = append(out, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(out)
+
+	return out, nil
+}
+
+func sanitizeURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	clean := *u
+	clean.User = nil
+	q := clean.Query()
+	for _, p := range sensitiveQueryParams {
+		q.Del(p)
+	}
+	clean.RawQuery = q.Encode()
+
+	return clean.String()
+}
+
+func sanitizeHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	out := h.Clone()
+	for _, k := range sensitiveHeaders {
+		out.Del(k)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
+}
+
+// matchKey normalizes a request for replay matching: method + path + sorted query.
+func matchKey(method string, rawURL string) string {
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL
+	}
+
+	q := u.Query()
+	for _, p := range sensitiveQueryParams {
+		q.Del(p)
+	}
+	// Client-library defaults; ignoring them keeps hand-written fixtures short.
+	q.Del("prettyPrint")
+	if q.Get("alt") == "json" {
+		q.Del("alt")
+	}
+
+	key := method + " " + u.EscapedPath()
+	if enc := q.Encode(); enc != "" {
+		key += "?" + enc
+	}
+
+	return key
+}
+
+func encodeBody(b []byte) (string, bool) {
+	if len(b) == 0 {
+		return "", false
+	}
+
+	if utf8.Valid(b) {
+		return string(b), false
+	}
+
+	return base64.StdEncoding.EncodeToString(b), true
+}
+
+func decodeBody(s string, isBase64 bool) ([]byte, error) {
+	if !isBase64 {
+		return []byte(s), nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 body: %w", err)
+	}
+
+	return b, nil
+}
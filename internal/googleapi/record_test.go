@@ -0,0 +1,141 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/drive/v3/files" && r.URL.Query().Get("pageToken") == "":
+			_, _ = io.WriteString(w, `{"files":[{"id":"1"}],"nextPageToken":"p2"}`)
+		case r.URL.Path == "/drive/v3/files":
+			_, _ = io.WriteString(w, `{"files":[{"id":"2"}]}`)
+		case r.URL.Path == "/bin":
+			_, _ = w.Write([]byte{0xff, 0x00, 0xfe})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	rec, err := NewRecordTransport(srv.Client().Transport, dir)
+	if err != nil {
+		t.Fatalf("record transport: %v", err)
+	}
+
+	do := func(rt http.RoundTripper, method, url string) (int, string) {
+		t.Helper()
+
+		var body io.Reader
+		if method == http.MethodPost {
+			body = strings.NewReader(`{"name":"x"}`)
+		}
+		req, _ := http.NewRequestWithContext(context.Background(), method, url, body)
+		req.Header.Set("Authorization", "Bearer secret-token")
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, url, err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+
+		return resp.StatusCode, string(b)
+	}
+
+	do(rec, http.MethodGet, srv.URL+"/drive/v3/files?alt=json&key=k1")
+	do(rec, http.MethodGet, srv.URL+"/drive/v3/files?alt=json&pageToken=p2")
+	do(rec, http.MethodGet, srv.URL+"/bin")
+	do(rec, http.MethodPost, srv.URL+"/missing")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 4 {
+		t.Fatalf("expected 4 cassettes, got %v (%v)", files, err)
+	}
+	if filepath.Base(files[0]) != "0001-GET-drive_v3_files.json" {
+		t.Fatalf("unexpected cassette name %q", filepath.Base(files[0]))
+	}
+	for _, f := range files {
+		b, _ := os.ReadFile(f)
+		if strings.Contains(string(b), "secret-token") || strings.Contains(string(b), "k1") {
+			t.Fatalf("cassette %s leaks credentials: %s", f, b)
+		}
+	}
+
+	srv.Close()
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("replay transport: %v", err)
+	}
+
+	// Host differs and key is dropped; alt=json is ignored for matching.
+	if _, body := do(replay, http.MethodGet, "https://www.googleapis.com/drive/v3/files?key=other"); !strings.Contains(body, `"nextPageToken":"p2"`) {
+		t.Fatalf("unexpected first page: %q", body)
+	}
+	if _, body := do(replay, http.MethodGet, "https://www.googleapis.com/drive/v3/files?pageToken=p2"); !strings.Contains(body, `"id":"2"`) {
+		t.Fatalf("unexpected second page: %q", body)
+	}
+	if _, body := do(replay, http.MethodGet, "https://x/bin"); body != string([]byte{0xff, 0x00, 0xfe}) {
+		t.Fatalf("unexpected binary body: %q", body)
+	}
+	if status, _ := do(replay, http.MethodPost, "https://x/missing"); status != http.StatusNotFound {
+		t.Fatalf("expected recorded 404, got %d", status)
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://x/unknown", nil)
+	if resp, err := replay.RoundTrip(req); err == nil {
+		_ = resp.Body.Close()
+		t.Fatalf("expected replay miss")
+	}
+}
+
+func TestReplayConsumesRepeatedRequestsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		cassette := `{"request":{"method":"GET","url":"https://gmail.googleapis.com/gmail/v1/users/me/profile"},"response":{"status":200,"body":` + body + `}}`
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(cassette), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("0001.json", `"first"`)
+	write("0002.json", `"second"`)
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	var got []string
+	for range 3 {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://gmail.googleapis.com/gmail/v1/users/me/profile?alt=json&prettyPrint=false", nil)
+		resp, err := replay.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("round trip: %v", err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		got = append(got, string(b))
+	}
+
+	if strings.Join(got, ",") != "first,second,second" {
+		t.Fatalf("unexpected order: %v", got)
+	}
+}
+
+func TestReplayTransportFromEnvConflict(t *testing.T) {
+	t.Setenv(envReplayDir, t.TempDir())
+	t.Setenv(envRecordDir, t.TempDir())
+
+	if _, err := replayTransportFromEnv(); err == nil {
+		t.Fatalf("expected conflict error")
+	}
+}
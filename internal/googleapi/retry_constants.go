@@ -9,6 +9,10 @@ const (
 	RateLimitBaseDelay = 1 * time.Second
 	// Max5xxRetries is the maximum retries for server errors.
 	Max5xxRetries = 1
-	// ServerErrorRetryDelay is the delay before retrying on 5xx errors.
+	// ServerErrorRetryDelay is the initial delay for 5xx exponential backoff.
 	ServerErrorRetryDelay = 1 * time.Second
+	// MaxNetworkRetries is the maximum retries for transient network errors on idempotent requests.
+	MaxNetworkRetries = 2
+	// MaxRetryDelay caps a single backoff sleep (including Retry-After).
+	MaxRetryDelay = 60 * time.Second
 )
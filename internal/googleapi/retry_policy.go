@@ -0,0 +1,124 @@
+package googleapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+// RetryPolicy is the resolved retry/backoff, circuit breaker and timeout configuration
+// for one API client.
+type RetryPolicy struct {
+	MaxRetries429     int
+	MaxRetries5xx     int
+	MaxRetriesNetwork int
+	BaseDelay         time.Duration
+	ServerErrorDelay  time.Duration
+	MaxDelay          time.Duration
+	// CircuitBreakerThreshold of 0 disables the circuit breaker.
+	CircuitBreakerThreshold int
+	CircuitBreakerReset     time.Duration
+	// Timeout bounds a whole API call including retries; 0 means no timeout.
+	Timeout time.Duration
+}
+
+// DefaultRetryPolicy returns the built-in policy used when nothing is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries429:           MaxRateLimitRetries,
+		MaxRetries5xx:           Max5xxRetries,
+		MaxRetriesNetwork:       MaxNetworkRetries,
+		BaseDelay:               RateLimitBaseDelay,
+		ServerErrorDelay:        ServerErrorRetryDelay,
+		MaxDelay:                MaxRetryDelay,
+		CircuitBreakerThreshold: CircuitBreakerThreshold,
+		CircuitBreakerReset:     CircuitBreakerResetTime,
+		Timeout:                 defaultHTTPTimeout,
+	}
+}
+
+// RetryPolicyFromConfig applies the set fields of rc on top of DefaultRetryPolicy.
+func RetryPolicyFromConfig(rc config.RetryConfig) (RetryPolicy, error) {
+	p := DefaultRetryPolicy()
+
+	ints := []struct {
+		name string
+		src  *int
+		dst  *int
+	}{
+		{"max_retries_429", rc.MaxRetries429, &p.MaxRetries429},
+		{"max_retries_5xx", rc.MaxRetries5xx, &p.MaxRetries5xx},
+		{"max_retries_network", rc.MaxRetriesNetwork, &p.MaxRetriesNetwork},
+		{"circuit_breaker_threshold", rc.CircuitBreakerThreshold, &p.CircuitBreakerThreshold},
+	}
+	for _, it := range ints {
+		if it.src == nil {
+			continue
+		}
+		if *it.src < 0 {
+			return RetryPolicy{}, fmt.Errorf("invalid retry %s %d (must be >= 0)", it.name, *it.src)
+		}
+		*it.dst = *it.src
+	}
+
+	durations := []struct {
+		name string
+		src  string
+		dst  *time.Duration
+	}{
+		{"base_delay", rc.BaseDelay, &p.BaseDelay},
+		{"server_error_delay", rc.ServerErrorDelay, &p.ServerErrorDelay},
+		{"max_delay", rc.MaxDelay, &p.MaxDelay},
+		{"circuit_breaker_reset", rc.CircuitBreakerReset, &p.CircuitBreakerReset},
+		{"timeout", rc.Timeout, &p.Timeout},
+	}
+	for _, it := range durations {
+		if it.src == "" {
+			continue
+		}
+		d, err := config.ParseRetryDuration(it.src)
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("retry %s: %w", it.name, err)
+		}
+		*it.dst = d
+	}
+
+	return p, nil
+}
+
+type retryOverridesKey struct{}
+
+// WithRetryOverrides attaches flag-level overrides (--max-retries, --timeout) that win
+// over config and env.
+func WithRetryOverrides(ctx context.Context, rc config.RetryConfig) context.Context {
+	return context.WithValue(ctx, retryOverridesKey{}, rc)
+}
+
+func retryOverridesFromContext(ctx context.Context) config.RetryConfig {
+	if ctx == nil {
+		return config.RetryConfig{}
+	}
+
+	if v, ok := ctx.Value(retryOverridesKey{}).(config.RetryConfig); ok {
+		return v
+	}
+
+	return config.RetryConfig{}
+}
+
+// resolveRetryPolicy layers defaults, config (default + service entries), env and flags.
+func resolveRetryPolicy(ctx context.Context, service string) (RetryPolicy, error) {
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return RetryPolicy{}, fmt.Errorf("read config: %w", err)
+	}
+
+	rc, err := config.ResolveRetryConfig(cfg, service)
+	if err != nil {
+		return RetryPolicy{}, err
+	}
+
+	return RetryPolicyFromConfig(rc.Merge(retryOverridesFromContext(ctx)))
+}
@@ -0,0 +1,192 @@
+package googleapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/steipete/gogcli/internal/config"
+)
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	zero := 0
+	five := 5
+
+	p, err := RetryPolicyFromConfig(config.RetryConfig{
+		MaxRetries5xx:           &five,
+		CircuitBreakerThreshold: &zero,
+		ServerErrorDelay:        "250ms",
+		Timeout:                 "0",
+	})
+	if err != nil {
+		t.Fatalf("policy: %v", err)
+	}
+
+	if p.MaxRetries5xx != 5 || p.MaxRetries429 != MaxRateLimitRetries {
+		t.Fatalf("unexpected retries: %#v", p)
+	}
+	if p.ServerErrorDelay != 250*time.Millisecond || p.Timeout != 0 {
+		t.Fatalf("unexpected durations: %#v", p)
+	}
+
+	rt := NewRetryTransportWithPolicy(nil, p)
+	if rt.CircuitBreaker != nil {
+		t.Fatalf("expected circuit breaker disabled")
+	}
+
+	if _, err := RetryPolicyFromConfig(config.RetryConfig{MaxDelay: "soon"}); err == nil {
+		t.Fatalf("expected invalid duration error")
+	}
+}
+
+func TestResolveRetryPolicy_ConfigEnvFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir, err := config.EnsureDir()
+	if err != nil {
+		t.Fatalf("ensure dir: %v", err)
+	}
+	cfg := `{ retry: { default: { max_retries_5xx: 4, timeout: "45s" }, gmail: { circuit_breaker_threshold: 20 } } }`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(cfg), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("GOG_RETRY_BASE_DELAY", "3s")
+
+	p, err := resolveRetryPolicy(context.Background(), "gmail")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if p.MaxRetries5xx != 4 || p.CircuitBreakerThreshold != 20 || p.BaseDelay != 3*time.Second || p.Timeout != 45*time.Second {
+		t.Fatalf("unexpected policy: %#v", p)
+	}
+
+	one := 1
+	ctx := WithRetryOverrides(context.Background(), config.RetryConfig{MaxRetries5xx: &one, Timeout: "2m"})
+	p, err = resolveRetryPolicy(ctx, "drive")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if p.MaxRetries5xx != 1 || p.CircuitBreakerThreshold != CircuitBreakerThreshold || p.Timeout != 2*time.Minute {
+		t.Fatalf("unexpected policy with overrides: %#v", p)
+	}
+}
+
+func TestRetryTransport_RetriesTransientNetworkErrors(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return nil, syscall.ECONNRESET
+		}
+
+		return newTestResponse(http.StatusOK, "ok"), nil
+	})
+
+	rt := &RetryTransport{Base: base, MaxRetriesNetwork: 2}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_NoNetworkRetryForPost(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, syscall.ECONNRESET
+	})
+
+	rt := &RetryTransport{Base: base, MaxRetriesNetwork: 3}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://example.com", strings.NewReader("x"))
+	resp, err := rt.RoundTrip(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("expected ECONNRESET, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryTransport_NoRetryForPermanentNetworkError(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errBoom
+	})
+
+	rt := &RetryTransport{Base: base, MaxRetriesNetwork: 3}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	if resp, err := rt.RoundTrip(req); err == nil {
+		_ = resp.Body.Close()
+		t.Fatalf("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryTransport_ServerErrorBackoffExponentialAndCapped(t *testing.T) {
+	rt := &RetryTransport{ServerErrorDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	resp := &http.Response{Header: http.Header{}}
+
+	if got := rt.serverErrorBackoff(0, resp); got < 100*time.Millisecond || got >= 150*time.Millisecond {
+		t.Fatalf("attempt 0: unexpected delay %v", got)
+	}
+	if got := rt.serverErrorBackoff(1, resp); got < 200*time.Millisecond || got > 300*time.Millisecond {
+		t.Fatalf("attempt 1: unexpected delay %v", got)
+	}
+	if got := rt.serverErrorBackoff(4, resp); got != 300*time.Millisecond {
+		t.Fatalf("attempt 4: expected cap, got %v", got)
+	}
+
+	resp.Header.Set("Retry-After", "1")
+	if got := rt.serverErrorBackoff(0, resp); got != 300*time.Millisecond {
+		t.Fatalf("expected Retry-After capped, got %v", got)
+	}
+}
+
+func TestRetryTransport_Retries5xxUpToConfiguredLimit(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls <= 4 {
+			return newTestResponse(http.StatusBadGateway, "bad"), nil
+		}
+
+		return newTestResponse(http.StatusOK, "ok"), nil
+	})
+
+	rt := NewRetryTransportWithPolicy(base, RetryPolicy{MaxRetries5xx: 4, CircuitBreakerThreshold: 10})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" || calls != 5 {
+		t.Fatalf("unexpected result: status=%d body=%q calls=%d", resp.StatusCode, body, calls)
+	}
+}
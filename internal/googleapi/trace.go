@@ -0,0 +1,150 @@
+package googleapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/steipete/gogcli/internal/tracing"
+)
+
+// quotaReasonPeekBytes bounds how much of an error body is read for the quota reason.
+const quotaReasonPeekBytes = 64 << 10
+
+// TraceTransport records one span per API call (method, URL, status, cache status,
+// quota reason). RetryTransport adds attempt spans and retry decisions underneath.
+type TraceTransport struct {
+	Base    http.RoundTripper
+	Tracer  *tracing.Tracer
+	Service string
+	Account string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.Tracer.Start(req.Context(), req.Method+" "+req.URL.Path)
+	defer span.Finish()
+
+	span.SetAttr("http.method", req.Method)
+	span.SetAttr("http.url", sanitizeURL(req.URL))
+	span.SetAttr("gog.service", t.Service)
+	span.SetAttr("gog.account", t.Account)
+
+	resp, err := t.Base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.SetError(err)
+		return nil, err //nolint:wrapcheck // pass-through transport
+	}
+
+	span.SetAttr("http.status_code", resp.StatusCode)
+	if status := resp.Header.Get(CacheStatusHeader); status != "" {
+		span.SetAttr("gog.cache", status)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		if reason := quotaReason(resp); reason != "" {
+			span.SetAttr("gcp.error_reason", reason)
+		}
+		span.SetError(errorStatus(resp))
+	}
+
+	return resp, nil
+}
+
+// wrapTraceFromContext adds a TraceTransport when the command runs with --trace.
+func wrapTraceFromContext(tr *tracing.Tracer, base http.RoundTripper, service string, email string) http.RoundTripper {
+	if tr == nil {
+		return base
+	}
+
+	return &TraceTransport{Base: base, Tracer: tr, Service: service, Account: email}
+}
+
+// traceAttempt starts an attempt span below the API call span in req's context.
+func traceAttempt(req *http.Request, attempt int) (*http.Request, *tracing.Span) {
+	if tracing.SpanFromContext(req.Context()) == nil {
+		return req, nil
+	}
+
+	ctx, span := tracing.Start(req.Context(), "attempt "+strconv.Itoa(attempt))
+	span.SetAttr("gog.attempt", attempt)
+
+	return req.WithContext(ctx), span
+}
+
+func finishAttempt(span *tracing.Span, resp *http.Response, err error) {
+	if span == nil {
+		return
+	}
+
+	if err != nil {
+		span.SetError(err)
+	} else {
+		span.SetAttr("http.status_code", resp.StatusCode)
+		if resp.StatusCode >= http.StatusBadRequest {
+			if reason := quotaReason(resp); reason != "" {
+				span.SetAttr("gcp.error_reason", reason)
+			}
+			span.SetError(errorStatus(resp))
+		}
+	}
+	span.Finish()
+}
+
+// traceRetry records a retry decision on the API call span.
+func traceRetry(req *http.Request, reason string, attempt int, delay time.Duration) {
+	tracing.SpanFromContext(req.Context()).AddEvent("retry", map[string]any{
+		"gog.retry.reason":  reason,
+		"gog.retry.attempt": attempt,
+		"gog.retry.delay":   delay,
+	})
+}
+
+type statusError struct{ status string }
+
+func (e statusError) Error() string { return e.status }
+
+func errorStatus(resp *http.Response) error {
+	if resp.Status != "" {
+		return statusError{status: resp.Status}
+	}
+	return statusError{status: strconv.Itoa(resp.StatusCode) + " " + http.StatusText(resp.StatusCode)}
+}
+
+// quotaReason extracts error.errors[0].reason (e.g. rateLimitExceeded) or error.status
+// from a Google error body, leaving the body readable for the caller.
+func quotaReason(resp *http.Response) string {
+	if resp == nil || resp.Body == nil {
+		return ""
+	}
+
+	head, err := io.ReadAll(io.LimitReader(resp.Body, quotaReasonPeekBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Error struct {
+			Status string `json:"status"`
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(head, &body) != nil {
+		return ""
+	}
+
+	for _, e := range body.Error.Errors {
+		if e.Reason != "" {
+			return e.Reason
+		}
+	}
+	return body.Error.Status
+}
@@ -0,0 +1,71 @@
+package googleapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/steipete/gogcli/internal/tracing"
+)
+
+func TestTraceTransport_RecordsAttemptsAndRetries(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return newTestResponse(http.StatusTooManyRequests, `{"error":{"code":429,"errors":[{"reason":"rateLimitExceeded"}]}}`), nil
+		}
+		return newTestResponse(http.StatusOK, "ok"), nil
+	})
+
+	tracer := tracing.New()
+	ctx, root := tracer.Start(context.Background(), "gog test")
+
+	rt := &TraceTransport{
+		Base:    &RetryTransport{Base: base, MaxRetries429: 1},
+		Tracer:  tracer,
+		Service: "gmail",
+		Account: "a@b.com",
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://gmail.googleapis.com/gmail/v1/users/me/labels?access_token=secret", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	if b, _ := io.ReadAll(resp.Body); string(b) != "ok" {
+		t.Fatalf("unexpected body %q", b)
+	}
+	_ = resp.Body.Close()
+	root.Finish()
+
+	spans := tracer.Spans()
+	if len(spans) != 4 {
+		t.Fatalf("expected root, call and 2 attempts, got %d", len(spans))
+	}
+
+	call, first, second := spans[1], spans[2], spans[3]
+	if call.ParentID != root.SpanID || first.ParentID != call.SpanID || second.ParentID != call.SpanID {
+		t.Fatalf("unexpected span tree: %#v", spans)
+	}
+	if call.Attributes["http.status_code"] != http.StatusOK || call.Attributes["http.url"] != "https://gmail.googleapis.com/gmail/v1/users/me/labels" {
+		t.Fatalf("unexpected call attributes: %#v", call.Attributes)
+	}
+	if first.Attributes["gcp.error_reason"] != "rateLimitExceeded" || first.Err == "" {
+		t.Fatalf("expected quota reason on first attempt: %#v", first)
+	}
+	if len(call.Events) != 1 || call.Events[0].Name != "retry" || call.Events[0].Attributes["gog.retry.reason"] != "rate_limited" {
+		t.Fatalf("expected retry event, got %#v", call.Events)
+	}
+}
+
+func TestQuotaReason_KeepsBody(t *testing.T) {
+	resp := newTestResponse(http.StatusForbidden, `{"error":{"status":"PERMISSION_DENIED"}}`)
+	if got := quotaReason(resp); got != "PERMISSION_DENIED" {
+		t.Fatalf("unexpected reason %q", got)
+	}
+	if b, _ := io.ReadAll(resp.Body); string(b) != `{"error":{"status":"PERMISSION_DENIED"}}` {
+		t.Fatalf("body not restored: %q", b)
+	}
+}
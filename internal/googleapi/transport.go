@@ -2,43 +2,67 @@ package googleapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math/rand/v2"
+	"net"
 	"net/http"
 	"strconv"
+	"syscall"
 	"time"
+
+	"github.com/steipete/gogcli/internal/tracing"
 )
 
 // RetryTransport wraps an http.RoundTripper with retry logic for
-// rate limits (429) and server errors (5xx).
+// rate limits (429), server errors (5xx) and, for idempotent methods,
+// transient network errors.
 type RetryTransport struct {
-	Base           http.RoundTripper
-	MaxRetries429  int
-	MaxRetries5xx  int
-	BaseDelay      time.Duration
-	CircuitBreaker *CircuitBreaker
+	Base              http.RoundTripper
+	MaxRetries429     int
+	MaxRetries5xx     int
+	MaxRetriesNetwork int
+	// BaseDelay is the initial 429 backoff; ServerErrorDelay the initial 5xx/network backoff.
+	// Both double per attempt (plus jitter) and are capped by MaxDelay when it is set.
+	BaseDelay        time.Duration
+	ServerErrorDelay time.Duration
+	MaxDelay         time.Duration
+	CircuitBreaker   *CircuitBreaker
 }
 
 // NewRetryTransport creates a RetryTransport with sensible defaults.
 func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	return NewRetryTransportWithPolicy(base, DefaultRetryPolicy())
+}
+
+// NewRetryTransportWithPolicy creates a RetryTransport configured by p.
+func NewRetryTransportWithPolicy(base http.RoundTripper, p RetryPolicy) *RetryTransport {
 	if base == nil {
 		base = http.DefaultTransport
 	}
 
-	return &RetryTransport{
-		Base:           base,
-		MaxRetries429:  MaxRateLimitRetries,
-		MaxRetries5xx:  Max5xxRetries,
-		BaseDelay:      RateLimitBaseDelay,
-		CircuitBreaker: NewCircuitBreaker(),
+	t := &RetryTransport{
+		Base:              base,
+		MaxRetries429:     p.MaxRetries429,
+		MaxRetries5xx:     p.MaxRetries5xx,
+		MaxRetriesNetwork: p.MaxRetriesNetwork,
+		BaseDelay:         p.BaseDelay,
+		ServerErrorDelay:  p.ServerErrorDelay,
+		MaxDelay:          p.MaxDelay,
+	}
+	if p.CircuitBreakerThreshold > 0 {
+		t.CircuitBreaker = NewCircuitBreakerWith(p.CircuitBreakerThreshold, p.CircuitBreakerReset)
 	}
+
+	return t
 }
 
 // RoundTrip implements http.RoundTripper with retry logic.
 func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if t.CircuitBreaker != nil && t.CircuitBreaker.IsOpen() {
+		tracing.SpanFromContext(req.Context()).AddEvent("circuit_breaker.open", nil)
 		return nil, &CircuitBreakerError{}
 	}
 
@@ -50,6 +74,7 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	var err error
 	retries429 := 0
 	retries5xx := 0
+	retriesNetwork := 0
 
 	for {
 		// Reset body for retry
@@ -65,9 +90,29 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			}
 		}
 
-		resp, err = t.Base.RoundTrip(req)
+		attemptReq, attemptSpan := traceAttempt(req, retries429+retries5xx+retriesNetwork+1)
+		resp, err = t.Base.RoundTrip(attemptReq)
+		finishAttempt(attemptSpan, resp, err)
 		if err != nil {
-			return nil, fmt.Errorf("round trip: %w", err)
+			if retriesNetwork >= t.MaxRetriesNetwork || !idempotentMethod(req.Method) || !isTransientNetworkError(req.Context(), err) {
+				return nil, fmt.Errorf("round trip: %w", err)
+			}
+
+			delay := t.capDelay(backoffWithJitter(t.ServerErrorDelay, retriesNetwork))
+			slog.Debug("transient network error, retrying",
+				"err", err,
+				"delay", delay,
+				"attempt", retriesNetwork+1,
+				"max_retries", t.MaxRetriesNetwork)
+			traceRetry(req, "network", retriesNetwork+1, delay)
+
+			if err := t.sleep(req.Context(), delay); err != nil {
+				return nil, err
+			}
+
+			retriesNetwork++
+
+			continue
 		}
 
 		// Success
@@ -90,6 +135,7 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 				"delay", delay,
 				"attempt", retries429+1,
 				"max_retries", t.MaxRetries429)
+			traceRetry(req, "rate_limited", retries429+1, delay)
 
 			drainAndClose(resp.Body)
 
@@ -112,13 +158,17 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 				return resp, nil
 			}
 
+			delay := t.serverErrorBackoff(retries5xx, resp)
 			slog.Debug("server error, retrying",
 				"status", resp.StatusCode,
-				"attempt", retries5xx+1)
+				"delay", delay,
+				"attempt", retries5xx+1,
+				"max_retries", t.MaxRetries5xx)
+			traceRetry(req, "server_error", retries5xx+1, delay)
 
 			drainAndClose(resp.Body)
 
-			if err := t.sleep(req.Context(), ServerErrorRetryDelay); err != nil {
+			if err := t.sleep(req.Context(), delay); err != nil {
 				return nil, err
 			}
 
@@ -133,34 +183,68 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 }
 
 func (t *RetryTransport) calculateBackoff(attempt int, resp *http.Response) time.Duration {
-	// Check Retry-After header
-	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-		if seconds, err := strconv.Atoi(retryAfter); err == nil {
-			if seconds < 0 {
-				return 0
-			}
+	if d, ok := retryAfter(resp); ok {
+		return t.capDelay(d)
+	}
+
+	// Exponential backoff with jitter: 1s, 2s, 4s...
+	return t.capDelay(backoffWithJitter(t.BaseDelay, attempt))
+}
+
+func (t *RetryTransport) serverErrorBackoff(attempt int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		return t.capDelay(d)
+	}
+
+	return t.capDelay(backoffWithJitter(t.ServerErrorDelay, attempt))
+}
 
-			return time.Duration(seconds) * time.Second
+func (t *RetryTransport) capDelay(d time.Duration) time.Duration {
+	if t.MaxDelay > 0 && d > t.MaxDelay {
+		return t.MaxDelay
+	}
+
+	return d
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	retryAfter := resp.Header.Get("Retry-After")
+	if retryAfter == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0, true
 		}
 
-		if t, err := http.ParseTime(retryAfter); err == nil {
-			d := time.Until(t)
-			if d < 0 {
-				return 0
-			}
+		return time.Duration(seconds) * time.Second, true
+	}
 
-			return d
+	if t, err := http.ParseTime(retryAfter); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			return 0, true
 		}
+
+		return d, true
 	}
 
-	// Exponential backoff with jitter: 1s, 2s, 4s...
-	if t.BaseDelay <= 0 {
+	return 0, false
+}
+
+func backoffWithJitter(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
 		return 0
 	}
 
 	var baseDelay time.Duration
 
-	if bd := t.BaseDelay * time.Duration(1<<attempt); bd <= 0 {
+	if bd := base * time.Duration(1<<attempt); bd <= 0 {
 		return 0
 	} else {
 		baseDelay = bd
@@ -175,6 +259,36 @@ func (t *RetryTransport) calculateBackoff(attempt int, resp *http.Response) time
 	return baseDelay + jitter
 }
 
+// idempotentMethod reports whether a request can be safely re-sent after a
+// network error where the server may or may not have seen it.
+func idempotentMethod(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isTransientNetworkError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	// Covers dial/read timeouts and "net/http: TLS handshake timeout".
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
 func (t *RetryTransport) sleep(ctx context.Context, d time.Duration) error {
 	if d <= 0 {
 		return nil
@@ -0,0 +1,429 @@
+package outfmt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format selects a rendered output format for the value passed to WriteJSON.
+// The zero value means "no formatter" (plain JSON or the command's own text output).
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatYAML     Format = "yaml"
+	FormatMarkdown Format = "markdown"
+	FormatTable    Format = "table"
+)
+
+// FromFormat maps an --output-format value to a Mode. json/ndjson/plain select the
+// existing modes; csv/yaml/markdown/table select a shared formatter.
+func FromFormat(name string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json":
+		return Mode{JSON: true}, nil
+	case "ndjson", "jsonl":
+		return Mode{JSON: true, NDJSON: true}, nil
+	case "plain", "tsv":
+		return Mode{Plain: true}, nil
+	case "csv":
+		return Mode{Format: FormatCSV}, nil
+	case "yaml", "yml":
+		return Mode{Format: FormatYAML}, nil
+	case "markdown", "md":
+		return Mode{Format: FormatMarkdown}, nil
+	case "table":
+		return Mode{Format: FormatTable}, nil
+	default:
+		return Mode{}, &ParseError{msg: fmt.Sprintf("invalid output format %q (expected json|ndjson|plain|csv|yaml|markdown|table)", name)}
+	}
+}
+
+// IsFormatName reports whether name is accepted by FromFormat.
+func IsFormatName(name string) bool {
+	_, err := FromFormat(name)
+	return err == nil
+}
+
+// writeFormatted renders v with the given formatter.
+// Tabular formats always unwrap the result envelope (like --results-only) and use
+// --select to pick and order columns; YAML mirrors the JSON shape.
+func writeFormatted(w io.Writer, f Format, v any, t JSONTransform) error {
+	ov, err := toOrdered(v)
+	if err != nil {
+		return err
+	}
+
+	if f == FormatYAML {
+		return writeYAML(w, ov, t)
+	}
+
+	ov = unwrapOrdered(ov)
+
+	rows, ok := ov.([]any)
+	if !ok {
+		rows = []any{ov}
+	}
+
+	exprs, err := parseSelectExprs(t.Select)
+	if err != nil {
+		return err
+	}
+
+	cols := tableColumns(rows, exprs)
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.key
+	}
+	cells := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		line := make([]string, len(cols))
+		for i, col := range cols {
+			if val, ok := col.value(row); ok {
+				line[i] = formatCell(val)
+			}
+		}
+		cells = append(cells, line)
+	}
+
+	switch f {
+	case FormatCSV:
+		return writeCSV(w, header, cells)
+	case FormatMarkdown:
+		return writeMarkdown(w, header, cells)
+	case FormatTable:
+		return writeTable(w, header, cells)
+	default:
+		return fmt.Errorf("unsupported output format %q", f)
+	}
+}
+
+func unwrapOrdered(v any) any {
+	obj, ok := v.(*orderedObject)
+	if !ok {
+		return v
+	}
+
+	// unwrapPrimary hands back the map itself when there is no single primary result.
+	if _, same := unwrapPrimary(obj.values).(map[string]any); same {
+		return obj
+	}
+
+	return unwrapPrimary(obj.values)
+}
+
+// tableColumn is either a --select expression or a plain top-level key.
+type tableColumn struct {
+	key  string
+	expr *selectExpr
+}
+
+func (c tableColumn) value(row any) (any, bool) {
+	if c.expr != nil {
+		return c.expr.eval(row)
+	}
+	if _, ok := row.(*orderedObject); !ok {
+		return row, c.key == "value"
+	}
+
+	return fieldOf(row, c.key)
+}
+
+func tableColumns(rows []any, exprs []*selectExpr) []tableColumn {
+	if len(exprs) > 0 {
+		cols := make([]tableColumn, 0, len(exprs))
+		for _, e := range exprs {
+			cols = append(cols, tableColumn{key: e.key, expr: e})
+		}
+
+		return cols
+	}
+
+	seen := map[string]bool{}
+	var cols []tableColumn
+	scalar := false
+	for _, row := range rows {
+		obj, ok := row.(*orderedObject)
+		if !ok {
+			scalar = true
+			continue
+		}
+		for _, k := range obj.keys {
+			if !seen[k] {
+				seen[k] = true
+				cols = append(cols, tableColumn{key: k})
+			}
+		}
+	}
+	if len(cols) == 0 && scalar {
+		return []tableColumn{{key: "value"}}
+	}
+
+	return cols
+}
+
+func formatCell(v any) string {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return vv
+	case json.Number:
+		return vv.String()
+	case bool:
+		if vv {
+			return "true"
+		}
+
+		return "false"
+	default:
+		b, err := json.Marshal(vv)
+		if err != nil {
+			return fmt.Sprint(vv)
+		}
+
+		return string(b)
+	}
+}
+
+func writeCSV(w io.Writer, cols []string, cells [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	if err := cw.WriteAll(cells); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+
+	return nil
+}
+
+func writeMarkdown(w io.Writer, cols []string, cells [][]string) error {
+	var b strings.Builder
+	writeRow := func(vals []string) {
+		b.WriteString("|")
+		for _, v := range vals {
+			b.WriteString(" ")
+			b.WriteString(markdownEscape(v))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(cols)
+	b.WriteString("|" + strings.Repeat(" --- |", len(cols)) + "\n")
+	for _, row := range cells {
+		writeRow(row)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write markdown: %w", err)
+	}
+
+	return nil
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func writeTable(w io.Writer, cols []string, cells [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c)
+	}
+	if _, err := fmt.Fprintln(tw, strings.Join(header, "\t")); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+	for _, row := range cells {
+		clean := make([]string, len(row))
+		for i, v := range row {
+			clean[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(v)
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(clean, "\t")); err != nil {
+			return fmt.Errorf("write table: %w", err)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+
+	return nil
+}
+
+func writeYAML(w io.Writer, v any, t JSONTransform) error {
+	if t.ResultsOnly {
+		v = unwrapOrdered(v)
+	}
+
+	if len(t.Select) > 0 {
+		exprs, err := parseSelectExprs(t.Select)
+		if err != nil {
+			return err
+		}
+		v = projectSelect(v, exprs)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(v)); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return nil
+}
+
+func yamlNode(v any) *yaml.Node {
+	switch vv := v.(type) {
+	case *orderedObject:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range vv.keys {
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k},
+				yamlNode(vv.values[k]),
+			)
+		}
+
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, it := range vv {
+			n.Content = append(n.Content, yamlNode(it))
+		}
+
+		return n
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: formatCell(vv)}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(vv.String(), ".eE") {
+			tag = "!!float"
+		}
+
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: vv.String()}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: formatCell(vv)}
+	}
+}
+
+// orderedObject is a decoded JSON object that remembers key order, so rendered
+// columns follow the struct field order of the original value.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func toOrdered(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	out, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return out, nil
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := &orderedObject{values: map[string]any{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return nil, errors.New("invalid object key")
+			}
+			val, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := obj.values[key]; !exists {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = val
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return obj, nil
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			val, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, val)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %q", delim)
+	}
+}
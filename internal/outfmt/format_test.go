@@ -0,0 +1,142 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type formatFile struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Size  int64  `json:"size,omitempty"`
+	Owner struct {
+		Email string `json:"email"`
+	} `json:"owner"`
+}
+
+func formatTestPayload() map[string]any {
+	a := formatFile{ID: "1", Name: "one, two", Size: 10}
+	a.Owner.Email = "a@b.com"
+	b := formatFile{ID: "2", Name: "pipe|name"}
+	b.Owner.Email = "c@d.com"
+
+	return map[string]any{
+		"files":         []formatFile{a, b},
+		"nextPageToken": "tok",
+	}
+}
+
+func writeWithFormat(t *testing.T, f Format, tr JSONTransform, v any) string {
+	t.Helper()
+
+	ctx := WithMode(context.Background(), Mode{Format: f})
+	ctx = WithJSONTransform(ctx, tr)
+
+	var buf bytes.Buffer
+	if err := WriteJSON(ctx, &buf, v); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestFromFormat(t *testing.T) {
+	cases := map[string]Mode{
+		"json":     {JSON: true},
+		"ndjson":   {JSON: true, NDJSON: true},
+		"plain":    {Plain: true},
+		"CSV":      {Format: FormatCSV},
+		"yml":      {Format: FormatYAML},
+		"md":       {Format: FormatMarkdown},
+		" table ":  {Format: FormatTable},
+		"markdown": {Format: FormatMarkdown},
+	}
+	for in, want := range cases {
+		got, err := FromFormat(in)
+		if err != nil {
+			t.Fatalf("FromFormat(%q): %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("FromFormat(%q) = %#v, want %#v", in, got, want)
+		}
+	}
+
+	if _, err := FromFormat("xml"); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+	if IsFormatName("report.txt") {
+		t.Fatalf("expected file names to be rejected")
+	}
+	if !IsJSON(WithMode(context.Background(), Mode{Format: FormatCSV})) {
+		t.Fatalf("expected formatted modes to take the structured output path")
+	}
+}
+
+func TestWriteJSON_CSVKeepsFieldOrder(t *testing.T) {
+	got := writeWithFormat(t, FormatCSV, JSONTransform{}, formatTestPayload())
+	want := "id,name,size,owner\n" +
+		"1,\"one, two\",10,\"{\"\"email\"\":\"\"a@b.com\"\"}\"\n" +
+		"2,pipe|name,,\"{\"\"email\"\":\"\"c@d.com\"\"}\"\n"
+	if got != want {
+		t.Fatalf("unexpected csv:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJSON_CSVSelectOrdersColumns(t *testing.T) {
+	got := writeWithFormat(t, FormatCSV, JSONTransform{Select: []string{"owner.email", "id"}}, formatTestPayload())
+	want := "owner.email,id\na@b.com,1\nc@d.com,2\n"
+	if got != want {
+		t.Fatalf("unexpected csv:\n%s", got)
+	}
+}
+
+func TestWriteJSON_Markdown(t *testing.T) {
+	got := writeWithFormat(t, FormatMarkdown, JSONTransform{Select: []string{"id", "name"}}, formatTestPayload())
+	want := "| id | name |\n| --- | --- |\n| 1 | one, two |\n| 2 | pipe\\|name |\n"
+	if got != want {
+		t.Fatalf("unexpected markdown:\n%s", got)
+	}
+}
+
+func TestWriteJSON_Table(t *testing.T) {
+	got := writeWithFormat(t, FormatTable, JSONTransform{Select: []string{"id", "name"}}, formatTestPayload())
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected table:\n%s", got)
+	}
+	if !strings.HasPrefix(lines[0], "ID  ") || !strings.Contains(lines[0], "NAME") {
+		t.Fatalf("unexpected header: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "2   pipe|name") {
+		t.Fatalf("unexpected row: %q", lines[2])
+	}
+}
+
+func TestWriteJSON_TableSingleObjectAndScalars(t *testing.T) {
+	got := writeWithFormat(t, FormatCSV, JSONTransform{}, map[string]any{"file": map[string]any{"id": "x"}})
+	if got != "id\nx\n" {
+		t.Fatalf("unexpected single-object csv: %q", got)
+	}
+
+	got = writeWithFormat(t, FormatCSV, JSONTransform{}, map[string]any{"keys": []string{"a", "b"}})
+	if got != "value\na\nb\n" {
+		t.Fatalf("unexpected scalar csv: %q", got)
+	}
+}
+
+func TestWriteJSON_YAML(t *testing.T) {
+	got := writeWithFormat(t, FormatYAML, JSONTransform{}, formatTestPayload())
+	if !strings.Contains(got, "files:\n  - id: \"1\"\n    name: one, two\n    size: 10\n") {
+		t.Fatalf("unexpected yaml:\n%s", got)
+	}
+	if !strings.Contains(got, "nextPageToken: tok") {
+		t.Fatalf("expected envelope in yaml:\n%s", got)
+	}
+
+	got = writeWithFormat(t, FormatYAML, JSONTransform{ResultsOnly: true, Select: []string{"id"}}, formatTestPayload())
+	if got != "- id: \"1\"\n- id: \"2\"\n" {
+		t.Fatalf("unexpected projected yaml:\n%s", got)
+	}
+}
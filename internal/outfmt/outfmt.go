@@ -6,31 +6,39 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
 )
 
 type Mode struct {
 	JSON  bool
 	Plain bool
+	// NDJSON emits newline-delimited JSON: one compact object per result item.
+	// It implies JSON, so commands that branch on IsJSON pick it up automatically.
+	NDJSON bool
+	// Format renders structured results as csv/yaml/markdown/table via WriteJSON.
+	Format Format
 }
 
 type ParseError struct{ msg string }
 
 func (e *ParseError) Error() string { return e.msg }
 
-func FromFlags(jsonOut bool, plainOut bool) (Mode, error) {
+func FromFlags(jsonOut bool, plainOut bool, ndjsonOut bool) (Mode, error) {
 	if jsonOut && plainOut {
 		return Mode{}, &ParseError{msg: "invalid output mode (cannot combine --json and --plain)"}
 	}
+	if ndjsonOut && plainOut {
+		return Mode{}, &ParseError{msg: "invalid output mode (cannot combine --ndjson and --plain)"}
+	}
 
-	return Mode{JSON: jsonOut, Plain: plainOut}, nil
+	return Mode{JSON: jsonOut || ndjsonOut, Plain: plainOut, NDJSON: ndjsonOut}, nil
 }
 
 func FromEnv() Mode {
 	return Mode{
-		JSON:  envBool("GOG_JSON"),
-		Plain: envBool("GOG_PLAIN"),
+		JSON:   envBool("GOG_JSON"),
+		Plain:  envBool("GOG_PLAIN"),
+		NDJSON: envBool("GOG_NDJSON"),
 	}
 }
 
@@ -50,15 +58,22 @@ func FromContext(ctx context.Context) Mode {
 	return Mode{}
 }
 
-func IsJSON(ctx context.Context) bool  { return FromContext(ctx).JSON }
-func IsPlain(ctx context.Context) bool { return FromContext(ctx).Plain }
+// IsJSON reports whether commands should emit their structured (WriteJSON) output.
+// This is true for --json, --ndjson and the --output-format formatters.
+func IsJSON(ctx context.Context) bool {
+	m := FromContext(ctx)
+	return m.JSON || m.NDJSON || m.Format != ""
+}
+func IsPlain(ctx context.Context) bool  { return FromContext(ctx).Plain }
+func IsNDJSON(ctx context.Context) bool { return FromContext(ctx).NDJSON }
 
 type JSONTransform struct {
 	// ResultsOnly unwraps the top-level envelope and emits only the primary results
 	// (best-effort; drops metadata like nextPageToken).
 	ResultsOnly bool
-	// Select projects objects to only the requested fields (comma-separated; supports dot paths).
-	// When applied to a list, it projects each element.
+	// Select projects objects to the requested fields. Each entry is a path
+	// expression (indexes, wildcards, [?filters], :alias renames; see select.go).
+	// When applied to a list, it projects each element unless the expression is $-rooted.
 	Select []string
 }
 
@@ -80,6 +95,11 @@ func JSONTransformFromContext(ctx context.Context) (JSONTransform, bool) {
 }
 
 func WriteJSON(ctx context.Context, w io.Writer, v any) error {
+	if f := FromContext(ctx).Format; f != "" {
+		t, _ := JSONTransformFromContext(ctx)
+		return writeFormatted(w, f, v, t)
+	}
+
 	if t, ok := JSONTransformFromContext(ctx); ok && (t.ResultsOnly || len(t.Select) > 0) {
 		transformed, err := applyJSONTransform(v, t)
 		if err != nil {
@@ -88,6 +108,10 @@ func WriteJSON(ctx context.Context, w io.Writer, v any) error {
 		v = transformed
 	}
 
+	if IsNDJSON(ctx) {
+		return writeNDJSONValue(w, v)
+	}
+
 	enc := json.NewEncoder(w)
 	enc.SetEscapeHTML(false)
 	enc.SetIndent("", "  ")
@@ -99,6 +123,48 @@ func WriteJSON(ctx context.Context, w io.Writer, v any) error {
 	return nil
 }
 
+// WriteJSONLine writes a single result item as one compact JSON line.
+// --select is applied to the item; --results-only has nothing to unwrap here.
+// Streaming commands use it to emit NDJSON items as pages arrive.
+func WriteJSONLine(ctx context.Context, w io.Writer, item any) error {
+	if t, ok := JSONTransformFromContext(ctx); ok && len(t.Select) > 0 {
+		transformed, err := applyJSONTransform(item, JSONTransform{Select: t.Select})
+		if err != nil {
+			return fmt.Errorf("transform json: %w", err)
+		}
+		item = transformed
+	}
+
+	return encodeJSONLine(w, item)
+}
+
+// writeNDJSONValue emits one line per element when v is a list (after transforms),
+// otherwise a single line.
+func writeNDJSONValue(w io.Writer, v any) error {
+	if items, ok := v.([]any); ok {
+		for _, it := range items {
+			if err := encodeJSONLine(w, it); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return encodeJSONLine(w, v)
+}
+
+func encodeJSONLine(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	return nil
+}
+
 func applyJSONTransform(v any, t JSONTransform) (any, error) {
 	// Convert typed structs into a generic representation so we can manipulate them.
 	b, err := json.Marshal(v)
@@ -116,7 +182,11 @@ func applyJSONTransform(v any, t JSONTransform) (any, error) {
 	}
 
 	if len(t.Select) > 0 {
-		anyV = selectFields(anyV, t.Select)
+		exprs, err := parseSelectExprs(t.Select)
+		if err != nil {
+			return nil, err
+		}
+		anyV = projectSelect(anyV, exprs)
 	}
 
 	return anyV, nil
@@ -205,71 +275,6 @@ func unwrapPrimary(v any) any {
 	return v
 }
 
-func selectFields(v any, fields []string) any {
-	switch vv := v.(type) {
-	case []any:
-		out := make([]any, 0, len(vv))
-		for _, it := range vv {
-			out = append(out, selectFieldsFromItem(it, fields))
-		}
-
-		return out
-	default:
-		return selectFieldsFromItem(v, fields)
-	}
-}
-
-func selectFieldsFromItem(v any, fields []string) any {
-	m, ok := v.(map[string]any)
-	if !ok {
-		return v
-	}
-
-	out := make(map[string]any, len(fields))
-	for _, f := range fields {
-		if val, ok := getAtPath(m, f); ok {
-			out[f] = val
-		}
-	}
-
-	return out
-}
-
-func getAtPath(v any, path string) (any, bool) {
-	path = strings.TrimSpace(path)
-	if path == "" {
-		return nil, false
-	}
-	segs := strings.Split(path, ".")
-	cur := v
-
-	for _, seg := range segs {
-		seg = strings.TrimSpace(seg)
-		if seg == "" {
-			return nil, false
-		}
-
-		switch c := cur.(type) {
-		case map[string]any:
-			next, ok := c[seg]
-			if !ok {
-				return nil, false
-			}
-			cur = next
-		case []any:
-			i, err := strconv.Atoi(seg)
-			if err != nil || i < 0 || i >= len(c) {
-				return nil, false
-			}
-			cur = c[i]
-		default:
-			return nil, false
-		}
-	}
-
-	return cur, true
-}
-
 func KeyValuePayload(key string, value any) map[string]any {
 	return map[string]any{
 		"key":   key,
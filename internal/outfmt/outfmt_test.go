@@ -4,15 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
 func TestFromFlags(t *testing.T) {
-	if _, err := FromFlags(true, true); err == nil {
+	if _, err := FromFlags(true, true, false); err == nil {
 		t.Fatalf("expected error when combining --json and --plain")
 	}
 
-	got, err := FromFlags(true, false)
+	got, err := FromFlags(true, false, false)
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
@@ -22,6 +23,26 @@ func TestFromFlags(t *testing.T) {
 	}
 }
 
+func TestFromFlags_NDJSON(t *testing.T) {
+	if _, err := FromFlags(false, true, true); err == nil {
+		t.Fatalf("expected error when combining --ndjson and --plain")
+	}
+
+	got, err := FromFlags(false, false, true)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	if !got.NDJSON || !got.JSON {
+		t.Fatalf("expected ndjson to imply json: %#v", got)
+	}
+
+	ctx := WithMode(context.Background(), got)
+	if !IsJSON(ctx) || !IsNDJSON(ctx) || IsPlain(ctx) {
+		t.Fatalf("unexpected context mode: %#v", FromContext(ctx))
+	}
+}
+
 func TestContextMode(t *testing.T) {
 	ctx := context.Background()
 
@@ -81,12 +102,71 @@ func TestWriteJSON_ResultsOnlyAndSelect(t *testing.T) {
 	}
 }
 
+func TestWriteJSON_NDJSONSplitsResults(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{NDJSON: true})
+	ctx = WithJSONTransform(ctx, JSONTransform{ResultsOnly: true, Select: []string{"id"}})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(ctx, &buf, map[string]any{
+		"files": []map[string]any{
+			{"id": "1", "name": "one"},
+			{"id": "2", "name": "two"},
+		},
+		"nextPageToken": "",
+	}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+
+	if lines[0] != `{"id":"1"}` || lines[1] != `{"id":"2"}` {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+}
+
+func TestWriteJSON_NDJSONWithoutTransformKeepsEnvelope(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{NDJSON: true})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(ctx, &buf, map[string]any{"dry_run": true, "op": "x"}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	if got := buf.String(); got != `{"dry_run":true,"op":"x"}`+"\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestWriteJSONLine_Select(t *testing.T) {
+	ctx := WithJSONTransform(context.Background(), JSONTransform{
+		ResultsOnly: true,
+		Select:      []string{"id", "owner.email"},
+	})
+
+	var buf bytes.Buffer
+	if err := WriteJSONLine(ctx, &buf, map[string]any{
+		"id":    "1",
+		"name":  "one",
+		"owner": map[string]any{"email": "a@b.com"},
+	}); err != nil {
+		t.Fatalf("WriteJSONLine: %v", err)
+	}
+
+	if got := buf.String(); got != `{"id":"1","owner.email":"a@b.com"}`+"\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
 func TestFromEnvAndParseError(t *testing.T) {
 	t.Setenv("GOG_JSON", "yes")
 	t.Setenv("GOG_PLAIN", "0")
+	t.Setenv("GOG_NDJSON", "")
 	mode := FromEnv()
 
-	if !mode.JSON || mode.Plain {
+	if !mode.JSON || mode.Plain || mode.NDJSON {
 		t.Fatalf("unexpected env mode: %#v", mode)
 	}
 
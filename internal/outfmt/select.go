@@ -0,0 +1,809 @@
+package outfmt
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// --select expression language (a pragmatic JSONPath/jq subset):
+//
+//	id,name                      dot paths (numeric segments index arrays)
+//	owner.email:email            rename the output key with a trailing :alias
+//	messages[0].id, items[-1]    array indexing (negative counts from the end)
+//	messages[*].headers          wildcards over arrays/objects (results become lists)
+//	items[1:3]                   slices
+//	files[?mimeType=='application/pdf'].name
+//	                             filters: == != < <= > >= =~ (regex), && and ||;
+//	                             a bare path (e.g. [?starred]) tests truthiness
+//	$.files[?size>1000]          $ evaluates against the whole document instead of
+//	                             each list item; a lone unaliased $ expression emits
+//	                             its result directly (no wrapping object)
+
+type selectExpr struct {
+	raw   string
+	key   string
+	root  bool
+	multi bool
+	steps []pathStep
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepWildcard
+	stepSlice
+	stepFilter
+)
+
+type pathStep struct {
+	kind   stepKind
+	name   string
+	index  int
+	start  *int
+	end    *int
+	filter filterExpr
+}
+
+// filterExpr is a disjunction of conjunctions: a && b || c.
+type filterExpr [][]filterCond
+
+type filterCond struct {
+	path []pathStep
+	op   string
+	lit  any
+	re   *regexp.Regexp
+}
+
+// SplitSelect splits a --select value on commas that are not inside brackets,
+// parentheses or quotes, so filters like [?name=='a,b'] survive intact.
+func SplitSelect(s string) []string {
+	var out []string
+	depth := 0
+	var quote byte
+	start := 0
+	flush := func(end int) {
+		if part := strings.TrimSpace(s[start:end]); part != "" {
+			out = append(out, part)
+		}
+	}
+
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if closesQuote(s, i, quote) {
+				quote = 0
+			}
+		case opensQuote(s, i):
+			quote = s[i]
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case r == ',' && depth == 0:
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(s))
+
+	return out
+}
+
+// ValidateSelect reports the first invalid --select expression, if any.
+func ValidateSelect(fields []string) error {
+	_, err := parseSelectExprs(fields)
+	return err
+}
+
+func parseSelectExprs(fields []string) ([]*selectExpr, error) {
+	out := make([]*selectExpr, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		e, err := parseSelectExpr(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --select expression %q: %w", f, err)
+		}
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+func parseSelectExpr(raw string) (*selectExpr, error) {
+	e := &selectExpr{raw: raw, key: raw}
+	path := raw
+	if i := lastTopLevel(raw, ':'); i >= 0 {
+		alias := strings.TrimSpace(raw[i+1:])
+		if alias == "" {
+			return nil, fmt.Errorf("empty alias")
+		}
+		e.key = alias
+		path = strings.TrimSpace(raw[:i])
+	}
+
+	if rest, ok := strings.CutPrefix(path, "$"); ok {
+		e.root = true
+		path = strings.TrimPrefix(rest, ".")
+	}
+
+	steps, err := parsePath(path, !e.root)
+	if err != nil {
+		return nil, err
+	}
+	e.steps = steps
+	for _, st := range steps {
+		if st.kind == stepWildcard || st.kind == stepSlice || st.kind == stepFilter {
+			e.multi = true
+		}
+	}
+
+	return e, nil
+}
+
+// lastTopLevel finds the last occurrence of sep outside brackets and quotes.
+func lastTopLevel(s string, sep rune) int {
+	depth := 0
+	var quote byte
+	last := -1
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if closesQuote(s, i, quote) {
+				quote = 0
+			}
+		case opensQuote(s, i):
+			quote = s[i]
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			if depth > 0 {
+				depth--
+			}
+		case r == sep && depth == 0:
+			last = i
+		}
+	}
+
+	return last
+}
+
+func parsePath(path string, requireSteps bool) ([]pathStep, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		if requireSteps {
+			return nil, fmt.Errorf("empty path")
+		}
+
+		return nil, nil
+	}
+
+	var steps []pathStep
+	i := 0
+	expectName := true
+	for i < len(path) {
+		switch c := path[i]; {
+		case c == '[':
+			end, err := matchingBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			st, err := parseBracket(path[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, st)
+			i = end + 1
+			expectName = false
+		case c == '.':
+			if expectName {
+				return nil, fmt.Errorf("empty path segment")
+			}
+			i++
+			expectName = true
+			if i == len(path) {
+				return nil, fmt.Errorf("empty path segment")
+			}
+		default:
+			if !expectName {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			j := i
+			for j < len(path) && path[j] != '.' && path[j] != '[' {
+				j++
+			}
+			name := strings.TrimSpace(path[i:j])
+			if name == "" {
+				return nil, fmt.Errorf("empty path segment")
+			}
+			if name == "*" {
+				steps = append(steps, pathStep{kind: stepWildcard})
+			} else {
+				steps = append(steps, pathStep{kind: stepField, name: name})
+			}
+			i = j
+			expectName = false
+		}
+	}
+
+	return steps, nil
+}
+
+func matchingBracket(s string, open int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if closesQuote(s, i, quote) {
+				quote = 0
+			}
+		case opensQuote(s, i):
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated '['")
+}
+
+func parseBracket(inner string) (pathStep, error) {
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "*":
+		return pathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "?"):
+		body := strings.TrimSpace(inner[1:])
+		if strings.HasPrefix(body, "(") && strings.HasSuffix(body, ")") {
+			body = strings.TrimSpace(body[1 : len(body)-1])
+		}
+		f, err := parseFilter(body)
+		if err != nil {
+			return pathStep{}, err
+		}
+
+		return pathStep{kind: stepFilter, filter: f}, nil
+	case isQuoted(inner):
+		return pathStep{kind: stepField, name: inner[1 : len(inner)-1]}, nil
+	case strings.Contains(inner, ":"):
+		parts := strings.SplitN(inner, ":", 2)
+		st := pathStep{kind: stepSlice}
+		for i, p := range parts {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return pathStep{}, fmt.Errorf("invalid slice %q", inner)
+			}
+			if i == 0 {
+				st.start = &n
+			} else {
+				st.end = &n
+			}
+		}
+
+		return st, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return pathStep{}, fmt.Errorf("invalid index %q", inner)
+		}
+
+		return pathStep{kind: stepIndex, index: n}, nil
+	}
+}
+
+// opensQuote reports whether s[i] starts a quoted span: a '...' or "..." string,
+// or a /regex/ literal directly after =~ (spaces allowed). Separators and
+// brackets inside the span are not structural.
+func opensQuote(s string, i int) bool {
+	switch s[i] {
+	case '\'', '"':
+		return true
+	case '/':
+		return strings.HasSuffix(strings.TrimRight(s[:i], " \t"), "=~")
+	}
+
+	return false
+}
+
+// closesQuote reports whether s[i] ends the span opened by quote. A slash escaped
+// as \/ does not end a /regex/ literal.
+func closesQuote(s string, i int, quote byte) bool {
+	return s[i] == quote && (quote != '/' || s[i-1] != '\\')
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+func parseFilter(body string) (filterExpr, error) {
+	if body == "" {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	var out filterExpr
+	for _, alt := range splitTopLevel(body, "||") {
+		var conds []filterCond
+		for _, part := range splitTopLevel(alt, "&&") {
+			c, err := parseCond(part)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, c)
+		}
+		out = append(out, conds)
+	}
+
+	return out, nil
+}
+
+func splitTopLevel(s, sep string) []string {
+	var out []string
+	var quote byte
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if closesQuote(s, i, quote) {
+				quote = 0
+			}
+		case opensQuote(s, i):
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			out = append(out, s[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+
+	return append(out, s[start:])
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func parseCond(s string) (filterCond, error) {
+	s = strings.TrimSpace(s)
+	opAt, op := -1, ""
+	var quote byte
+	for i := 0; i < len(s) && opAt < 0; i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '\'' || c == '"' {
+			quote = c
+			continue
+		}
+		for _, candidate := range filterOps {
+			if strings.HasPrefix(s[i:], candidate) {
+				opAt, op = i, candidate
+				break
+			}
+		}
+	}
+
+	left := s
+	if opAt >= 0 {
+		left = s[:opAt]
+	}
+	left = strings.TrimSpace(left)
+	left = strings.TrimPrefix(strings.TrimPrefix(left, "@"), ".")
+	path, err := parsePath(left, false)
+	if err != nil {
+		return filterCond{}, err
+	}
+
+	cond := filterCond{path: path, op: op}
+	if opAt < 0 {
+		return cond, nil
+	}
+
+	right := strings.TrimSpace(s[opAt+len(op):])
+	if op == "=~" {
+		pattern := right
+		switch {
+		case len(right) >= 2 && right[0] == '/' && right[len(right)-1] == '/':
+			pattern = right[1 : len(right)-1]
+		case isQuoted(right):
+			pattern = right[1 : len(right)-1]
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return filterCond{}, fmt.Errorf("invalid regex: %w", err)
+		}
+		cond.re = re
+
+		return cond, nil
+	}
+
+	lit, err := parseLiteral(right)
+	if err != nil {
+		return filterCond{}, err
+	}
+	cond.lit = lit
+
+	return cond, nil
+}
+
+func parseLiteral(s string) (any, error) {
+	switch {
+	case isQuoted(s):
+		return s[1 : len(s)-1], nil
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal %q (quote strings)", s)
+	}
+
+	return f, nil
+}
+
+// eval resolves the expression against v. Multi-valued expressions (wildcards,
+// slices, filters) always return a list, even when nothing matched.
+func (e *selectExpr) eval(v any) (any, bool) {
+	cur := []any{v}
+	for _, st := range e.steps {
+		next := make([]any, 0, len(cur))
+		for _, c := range cur {
+			next = append(next, st.apply(c)...)
+		}
+		cur = next
+	}
+
+	if e.multi {
+		return cur, true
+	}
+
+	if len(cur) == 0 {
+		return nil, false
+	}
+
+	return cur[0], true
+}
+
+func (st pathStep) apply(v any) []any {
+	switch st.kind {
+	case stepField:
+		if val, ok := fieldOf(v, st.name); ok {
+			return []any{val}
+		}
+		// Backwards-compatible numeric dot segments (items.0.id).
+		if arr, ok := v.([]any); ok {
+			if n, err := strconv.Atoi(st.name); err == nil {
+				return indexOf(arr, n)
+			}
+		}
+
+		return nil
+	case stepIndex:
+		if arr, ok := v.([]any); ok {
+			return indexOf(arr, st.index)
+		}
+
+		return nil
+	case stepWildcard:
+		return childrenOf(v)
+	case stepSlice:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		start, end := 0, len(arr)
+		if st.start != nil {
+			start = clampIndex(*st.start, len(arr))
+		}
+		if st.end != nil {
+			end = clampIndex(*st.end, len(arr))
+		}
+		if start >= end {
+			return nil
+		}
+
+		return append([]any(nil), arr[start:end]...)
+	case stepFilter:
+		var out []any
+		for _, c := range childrenOf(v) {
+			if st.filter.match(c) {
+				out = append(out, c)
+			}
+		}
+
+		return out
+	default:
+		return nil
+	}
+}
+
+func indexOf(arr []any, n int) []any {
+	if n < 0 {
+		n += len(arr)
+	}
+	if n < 0 || n >= len(arr) {
+		return nil
+	}
+
+	return []any{arr[n]}
+}
+
+func clampIndex(n, length int) int {
+	if n < 0 {
+		n += length
+	}
+	if n < 0 {
+		return 0
+	}
+	if n > length {
+		return length
+	}
+
+	return n
+}
+
+func fieldOf(v any, name string) (any, bool) {
+	switch vv := v.(type) {
+	case map[string]any:
+		val, ok := vv[name]
+		return val, ok
+	case *orderedObject:
+		val, ok := vv.values[name]
+		return val, ok
+	default:
+		return nil, false
+	}
+}
+
+func childrenOf(v any) []any {
+	switch vv := v.(type) {
+	case []any:
+		return vv
+	case *orderedObject:
+		out := make([]any, 0, len(vv.keys))
+		for _, k := range vv.keys {
+			out = append(out, vv.values[k])
+		}
+
+		return out
+	case map[string]any:
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]any, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, vv[k])
+		}
+
+		return out
+	default:
+		return nil
+	}
+}
+
+func (f filterExpr) match(v any) bool {
+	for _, conj := range f {
+		ok := true
+		for _, c := range conj {
+			if !c.match(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c filterCond) match(v any) bool {
+	got := []any{v}
+	for _, st := range c.path {
+		next := make([]any, 0, len(got))
+		for _, g := range got {
+			next = append(next, st.apply(g)...)
+		}
+		got = next
+	}
+
+	if c.op == "" {
+		for _, g := range got {
+			if truthy(g) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, g := range got {
+		if c.compare(g) {
+			return true
+		}
+	}
+
+	// `field == null` / `field != null` also match missing fields.
+	if len(got) == 0 && c.lit == nil && c.re == nil {
+		return c.op == "=="
+	}
+
+	return false
+}
+
+func (c filterCond) compare(v any) bool {
+	if c.re != nil {
+		s, ok := v.(string)
+		if !ok {
+			s = formatCell(v)
+		}
+
+		return c.re.MatchString(s)
+	}
+
+	if lf, ok := c.lit.(float64); ok {
+		vf, ok := toFloat(v)
+		if !ok {
+			return c.op == "!="
+		}
+
+		return compareOrdered(vf, lf, c.op)
+	}
+
+	if ls, ok := c.lit.(string); ok {
+		vs, ok := v.(string)
+		if !ok {
+			return c.op == "!="
+		}
+
+		return compareOrdered(vs, ls, c.op)
+	}
+
+	equal := v == c.lit
+	switch c.op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	default:
+		return false
+	}
+}
+
+func compareOrdered[T float64 | string](a, b T, op string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	default:
+		return false
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch vv := v.(type) {
+	case float64:
+		return vv, true
+	case interface{ Float64() (float64, error) }:
+		f, err := vv.Float64()
+		return f, err == nil
+	case string:
+		// Google APIs encode int64 fields (sizes, counts) as strings.
+		f, err := strconv.ParseFloat(vv, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func truthy(v any) bool {
+	switch vv := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return vv
+	case string:
+		return vv != ""
+	case []any:
+		return len(vv) > 0
+	default:
+		return true
+	}
+}
+
+// projectSelect applies parsed --select expressions. Lists are projected per item
+// unless an expression is $-rooted; a lone unaliased $ expression returns its raw result.
+func projectSelect(v any, exprs []*selectExpr) any {
+	rooted := false
+	for _, e := range exprs {
+		if e.root {
+			rooted = true
+		}
+	}
+
+	if rooted {
+		if len(exprs) == 1 && exprs[0].key == exprs[0].raw {
+			val, _ := exprs[0].eval(v)
+			return val
+		}
+
+		return projectItem(v, exprs)
+	}
+
+	if items, ok := v.([]any); ok {
+		out := make([]any, 0, len(items))
+		for _, it := range items {
+			out = append(out, projectItem(it, exprs))
+		}
+
+		return out
+	}
+
+	return projectItem(v, exprs)
+}
+
+func projectItem(v any, exprs []*selectExpr) any {
+	switch v.(type) {
+	case map[string]any, *orderedObject:
+	default:
+		return v
+	}
+
+	out := &orderedObject{values: make(map[string]any, len(exprs))}
+	for _, e := range exprs {
+		val, ok := e.eval(v)
+		if !ok {
+			continue
+		}
+		if _, exists := out.values[e.key]; !exists {
+			out.keys = append(out.keys, e.key)
+		}
+		out.values[e.key] = val
+	}
+
+	return out
+}
@@ -0,0 +1,161 @@
+package outfmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func selectTestDoc() map[string]any {
+	return map[string]any{
+		"files": []any{
+			map[string]any{"id": "1", "name": "a.pdf", "mimeType": "application/pdf", "size": "2048", "starred": true},
+			map[string]any{"id": "2", "name": "b.txt", "mimeType": "text/plain", "size": "10"},
+			map[string]any{"id": "3", "name": "c.pdf", "mimeType": "application/pdf", "size": "99"},
+		},
+		"messages": []any{
+			map[string]any{"id": "m1", "headers": []any{map[string]any{"name": "From", "value": "x@y"}}},
+			map[string]any{"id": "m2", "headers": []any{map[string]any{"name": "From", "value": "z@y"}}},
+		},
+		"nextPageToken": "tok",
+	}
+}
+
+func evalSelect(t *testing.T, v any, fields ...string) any {
+	t.Helper()
+
+	exprs, err := parseSelectExprs(fields)
+	if err != nil {
+		t.Fatalf("parse %v: %v", fields, err)
+	}
+
+	// Round-trip through JSON so assertions compare plain values.
+	b, err := json.Marshal(projectSelect(v, exprs))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	return out
+}
+
+func TestSplitSelect(t *testing.T) {
+	got := SplitSelect(" id, files[?name=='a,b'].id:ids ,, name, files[?name=~/[)\\/,]/].id ")
+	want := []string{"id", "files[?name=='a,b'].id:ids", "name", "files[?name=~/[)\\/,]/].id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SplitSelect = %#v, want %#v", got, want)
+	}
+}
+
+func TestSelect_DotPathsAndAlias(t *testing.T) {
+	got := evalSelect(t, selectTestDoc(), "nextPageToken:next", "files.0.id", "files[-1].name:last")
+	want := map[string]any{"next": "tok", "files.0.id": "1", "last": "c.pdf"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+}
+
+func TestSelect_WildcardFlattens(t *testing.T) {
+	got := evalSelect(t, selectTestDoc(), "messages[*].headers[*].value:from")
+	want := map[string]any{"from": []any{"x@y", "z@y"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+
+	got = evalSelect(t, selectTestDoc(), "messages[*].headers")
+	if m, ok := got.(map[string]any); !ok || len(m["messages[*].headers"].([]any)) != 2 {
+		t.Fatalf("got %#v", got)
+	}
+}
+
+func TestSelect_FilterPredicates(t *testing.T) {
+	cases := []struct {
+		expr string
+		want []any
+	}{
+		{"files[?mimeType=='application/pdf'].id", []any{"1", "3"}},
+		{`files[?mimeType=="application/pdf" && size>100].id`, []any{"1"}},
+		{"files[?size<50 || starred].id", []any{"1", "2"}},
+		{"files[?name=~/\\.txt$/].id", []any{"2"}},
+		{"files[?name=~/^(a||c)\\./].id", []any{"1", "3"}},
+		{"files[?name=~ /^[ab]\\.(pdf|txt)$/ && size>0].id", []any{"1", "2"}},
+		{"files[?(@.id!='1')].id", []any{"2", "3"}},
+		{"files[?starred==null].id", []any{"2", "3"}},
+		{"files[1:].id", []any{"2", "3"}},
+		{"files[?id=='nope'].id", []any{}},
+	}
+	for _, tc := range cases {
+		got := evalSelect(t, selectTestDoc(), tc.expr+":out")
+		m, ok := got.(map[string]any)
+		if !ok || !reflect.DeepEqual(m["out"], tc.want) {
+			t.Fatalf("%s: got %#v want %#v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestSelect_ListItemsProjectedPerItem(t *testing.T) {
+	files := selectTestDoc()["files"]
+	got := evalSelect(t, files, "id", "name:title")
+	want := []any{
+		map[string]any{"id": "1", "title": "a.pdf"},
+		map[string]any{"id": "2", "title": "b.txt"},
+		map[string]any{"id": "3", "title": "c.pdf"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+}
+
+func TestSelect_RootExpression(t *testing.T) {
+	files := selectTestDoc()["files"]
+	got := evalSelect(t, files, "$[?mimeType=='application/pdf']")
+	list, ok := got.([]any)
+	if !ok || len(list) != 2 {
+		t.Fatalf("expected raw filtered list, got %#v", got)
+	}
+
+	got = evalSelect(t, selectTestDoc(), "$.files[*].id:ids", "$.nextPageToken:next")
+	want := map[string]any{"ids": []any{"1", "2", "3"}, "next": "tok"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v", got)
+	}
+}
+
+func TestSelect_InvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"files[", "files[?]", "a..b", "files[x]", "files[?size>big]", "name:", "files[?name=~'(']"} {
+		if err := ValidateSelect([]string{expr}); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestWriteJSON_SelectKeepsRequestedOrder(t *testing.T) {
+	ctx := WithJSONTransform(context.Background(), JSONTransform{Select: []string{"name", "id"}})
+
+	var buf bytes.Buffer
+	if err := WriteJSONLine(ctx, &buf, map[string]any{"id": "1", "name": "one"}); err != nil {
+		t.Fatalf("WriteJSONLine: %v", err)
+	}
+	if got := buf.String(); got != `{"name":"one","id":"1"}`+"\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestWriteJSON_CSVWithFilterColumn(t *testing.T) {
+	ctx := WithMode(context.Background(), Mode{Format: FormatCSV})
+	ctx = WithJSONTransform(ctx, JSONTransform{Select: []string{"id", "headers[?name=='From'].value:from"}})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(ctx, &buf, map[string]any{"messages": selectTestDoc()["messages"]}); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, "id,from\nm1,\"[\"\"x@y\"\"]\"\n") {
+		t.Fatalf("unexpected csv: %q", got)
+	}
+}
@@ -3,6 +3,8 @@ package timeparse
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,6 +19,8 @@ var (
 	ErrEmptySince         = errors.New("empty since value")
 	ErrInvalidSince       = errors.New("invalid since value")
 	ErrInvalidDateLayouts = errors.New("invalid date format")
+	ErrEmptyAt            = errors.New("empty time")
+	ErrInvalidAt          = errors.New("invalid time")
 )
 
 // ParsedDateTime represents a parsed time expression and whether the input
@@ -157,6 +161,126 @@ func ParseSince(value string, now time.Time, loc *time.Location) (SinceResult, e
 	return SinceResult{}, fmt.Errorf("%w: %q", ErrInvalidSince, value)
 }
 
+// ParseAt parses a point in time for scheduling. Supported: everything
+// ParseRangeExpr accepts, relative offsets ("in 2h", "+90m", "in 3d"), a clock
+// time ("9am", "14:30", "noon") and a day followed by a clock time
+// ("tomorrow 9am", "next monday at 8:15", "2026-03-01 5pm"). A bare clock time
+// that has already passed today means tomorrow.
+func ParseAt(expr string, now time.Time, loc *time.Location) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return time.Time{}, ErrEmptyAt
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	now = now.In(loc)
+	lower := strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+	lower = strings.NewReplacer(" am", "am", " pm", "pm").Replace(lower)
+
+	if offset, ok := strings.CutPrefix(lower, "in "); ok {
+		if d, ok := parseOffset(strings.ReplaceAll(offset, " ", "")); ok {
+			return now.Add(d), nil
+		}
+	}
+
+	if offset, ok := strings.CutPrefix(lower, "+"); ok {
+		if d, ok := parseOffset(offset); ok {
+			return now.Add(d), nil
+		}
+	}
+
+	if hour, minute, ok := parseClock(lower); ok {
+		t := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+
+		return t, nil
+	}
+
+	if i := strings.LastIndex(lower, " "); i > 0 {
+		day := strings.TrimSuffix(strings.TrimSpace(lower[:i]), " at")
+		if hour, minute, ok := parseClock(lower[i+1:]); ok {
+			if d, err := ParseRangeExpr(day, now, loc); err == nil {
+				return time.Date(d.Year(), d.Month(), d.Day(), hour, minute, 0, 0, loc), nil
+			}
+		}
+	}
+
+	if t, err := ParseRangeExpr(expr, now, loc); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q (try: tomorrow 9am, monday 14:30, in 2h, 2026-01-05T09:00)", ErrInvalidAt, expr)
+}
+
+var clockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+
+func parseClock(s string) (int, int, bool) {
+	switch s {
+	case "noon":
+		return 12, 0, true
+	case "midnight":
+		return 0, 0, true
+	}
+
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	if minute > 59 {
+		return 0, 0, false
+	}
+
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, false
+		}
+	}
+
+	return hour, minute, true
+}
+
+// parseOffset accepts Go durations plus a whole-day suffix ("3d").
+func parseOffset(s string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+
+	return d, true
+}
+
 func parseWeekday(expr string, now time.Time) (time.Time, bool) {
 	expr = strings.TrimSpace(expr)
 
@@ -179,3 +179,51 @@ func TestParseSince(t *testing.T) {
 		})
 	}
 }
+
+//nolint:wsl_v5
+func TestParseAt(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("Offset", 2*3600)
+	now := time.Date(2026, 2, 13, 15, 45, 0, 0, loc) // Friday
+	testCases := []struct {
+		name    string
+		value   string
+		wantErr bool
+		want    time.Time
+	}{
+		{name: "tomorrow clock", value: "tomorrow 9am", want: time.Date(2026, 2, 14, 9, 0, 0, 0, loc)},
+		{name: "spaced meridiem", value: "Tomorrow at 9 AM", want: time.Date(2026, 2, 14, 9, 0, 0, 0, loc)},
+		{name: "weekday 24h", value: "next monday 14:30", want: time.Date(2026, 2, 16, 14, 30, 0, 0, loc)},
+		{name: "date pm", value: "2026-03-01 5pm", want: time.Date(2026, 3, 1, 17, 0, 0, 0, loc)},
+		{name: "clock later today", value: "6:15pm", want: time.Date(2026, 2, 13, 18, 15, 0, 0, loc)},
+		{name: "clock passed", value: "noon", want: time.Date(2026, 2, 14, 12, 0, 0, 0, loc)},
+		{name: "in duration", value: "in 90m", want: now.Add(90 * time.Minute)},
+		{name: "plus days", value: "+2d", want: now.AddDate(0, 0, 2)},
+		{name: "rfc3339", value: "2026-02-20T08:00:00Z", want: time.Date(2026, 2, 20, 8, 0, 0, 0, time.UTC)},
+		{name: "day only", value: "tomorrow", want: time.Date(2026, 2, 14, 0, 0, 0, 0, loc)},
+		{name: "bad clock", value: "tomorrow 13pm", wantErr: true},
+		{name: "bare number", value: "9", wantErr: true},
+		{name: "negative", value: "in -2h", wantErr: true},
+		{name: "empty", value: " ", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseAt(tc.value, now, loc)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAt: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("got %v want %v", got, tc.want)
+			}
+		})
+	}
+}
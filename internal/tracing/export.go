@@ -0,0 +1,357 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatOTLP   = "otlp"
+	FormatChrome = "chrome"
+)
+
+// ParseFormat normalizes a --trace-format value; empty means OTLP-JSON.
+func ParseFormat(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", FormatOTLP, "otlp-json", "otel":
+		return FormatOTLP, nil
+	case FormatChrome, "chrome-trace", "perfetto":
+		return FormatChrome, nil
+	default:
+		return "", fmt.Errorf("invalid trace format %q (expected otlp|chrome)", value)
+	}
+}
+
+// WriteFile exports the trace to path in format, creating parent directories.
+func (t *Tracer) WriteFile(path string, format string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("create trace dir: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) //nolint:gosec // trace path chosen by the user
+	if err != nil {
+		return fmt.Errorf("create trace file: %w", err)
+	}
+
+	if err := t.Write(f, format); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write trace file: %w", err)
+	}
+	return nil
+}
+
+// Write exports the trace to w in format (FormatOTLP or FormatChrome).
+func (t *Tracer) Write(w io.Writer, format string) error {
+	var doc any
+	switch format {
+	case FormatChrome:
+		doc = t.chromeTrace()
+	default:
+		doc = t.otlpTrace()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("write trace: %w", err)
+	}
+	return nil
+}
+
+func (t *Tracer) finishedSpans() []Span {
+	spans := t.Spans()
+	var now time.Time
+	if t != nil {
+		now = t.now()
+	}
+	for i := range spans {
+		if spans[i].End.IsZero() {
+			spans[i].End = now
+		}
+	}
+	return spans
+}
+
+// OTLP-JSON (opentelemetry-proto ExportTraceServiceRequest).
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpEvent struct {
+	Name         string         `json:"name"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+const (
+	otlpKindInternal = 1
+	otlpKindClient   = 3
+
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+func (t *Tracer) otlpTrace() map[string]any {
+	spans := t.finishedSpans()
+	traceID := ""
+	if t != nil {
+		traceID = t.TraceID
+	}
+
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		kind := otlpKindInternal
+		if _, ok := s.Attributes["http.method"]; ok {
+			kind = otlpKindClient
+		}
+
+		status := otlpStatus{Code: otlpStatusOK}
+		if s.Err != "" {
+			status = otlpStatus{Code: otlpStatusError, Message: s.Err}
+		}
+
+		span := otlpSpan{
+			TraceID:           traceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              kind,
+			StartTimeUnixNano: unixNano(s.Start),
+			EndTimeUnixNano:   unixNano(s.End),
+			Attributes:        otlpAttributes(s.Attributes),
+			Status:            status,
+		}
+		for _, e := range s.Events {
+			span.Events = append(span.Events, otlpEvent{
+				Name:         e.Name,
+				TimeUnixNano: unixNano(e.Time),
+				Attributes:   otlpAttributes(e.Attributes),
+			})
+		}
+		out = append(out, span)
+	}
+
+	serviceName := "gog"
+	return map[string]any{
+		"resourceSpans": []any{
+			map[string]any{
+				"resource": map[string]any{
+					"attributes": []otlpKeyValue{{Key: "service.name", Value: otlpAnyValue{StringValue: &serviceName}}},
+				},
+				"scopeSpans": []any{
+					map[string]any{
+						"scope": map[string]any{"name": "github.com/steipete/gogcli"},
+						"spans": out,
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attrs map[string]any) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		var v otlpAnyValue
+		switch val := attrs[k].(type) {
+		case bool:
+			v.BoolValue = &val
+		case int:
+			s := strconv.Itoa(val)
+			v.IntValue = &s
+		case int64:
+			s := strconv.FormatInt(val, 10)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &val
+		case time.Duration:
+			f := val.Seconds()
+			v.DoubleValue = &f
+		default:
+			s := fmt.Sprint(val)
+			v.StringValue = &s
+		}
+		out = append(out, otlpKeyValue{Key: k, Value: v})
+	}
+	return out
+}
+
+func unixNano(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// Chrome trace event format (chrome://tracing, Perfetto).
+
+type chromeEvent struct {
+	Name  string         `json:"name"`
+	Cat   string         `json:"cat,omitempty"`
+	Phase string         `json:"ph"`
+	TS    float64        `json:"ts"`
+	Dur   *float64       `json:"dur,omitempty"`
+	PID   int            `json:"pid"`
+	TID   int            `json:"tid"`
+	Scope string         `json:"s,omitempty"`
+	Args  map[string]any `json:"args,omitempty"`
+}
+
+// chromeTrace lays spans out on "threads": the root on tid 0, concurrent API calls on
+// the lowest free lane, and their attempts on the same lane as the call.
+func (t *Tracer) chromeTrace() map[string]any {
+	spans := t.finishedSpans()
+	if len(spans) == 0 {
+		return map[string]any{"traceEvents": []chromeEvent{}, "displayTimeUnit": "ms"}
+	}
+
+	origin := spans[0].Start
+	for _, s := range spans {
+		if s.Start.Before(origin) {
+			origin = s.Start
+		}
+	}
+	micros := func(ts time.Time) float64 {
+		return float64(ts.Sub(origin).Nanoseconds()) / 1e3
+	}
+
+	order := make([]int, len(spans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return spans[order[a]].Start.Before(spans[order[b]].Start) })
+
+	rootID := ""
+	laneOf := map[string]int{}
+	var laneEnds []time.Time
+	for _, i := range order {
+		s := spans[i]
+		switch {
+		case s.ParentID == "":
+			rootID = s.SpanID
+			laneOf[s.SpanID] = 0
+		case s.ParentID == rootID:
+			lane := -1
+			for l, end := range laneEnds {
+				if !end.After(s.Start) {
+					lane = l
+					break
+				}
+			}
+			if lane < 0 {
+				laneEnds = append(laneEnds, time.Time{})
+				lane = len(laneEnds) - 1
+			}
+			laneEnds[lane] = s.End
+			laneOf[s.SpanID] = lane + 1
+		default:
+			laneOf[s.SpanID] = laneOf[s.ParentID]
+		}
+	}
+
+	events := make([]chromeEvent, 0, len(spans))
+	for _, i := range order {
+		s := spans[i]
+		args := cloneAttrs(s.Attributes)
+		if s.Err != "" {
+			args["error"] = s.Err
+		}
+		for k, v := range args {
+			if d, ok := v.(time.Duration); ok {
+				args[k] = d.String()
+			}
+		}
+		dur := float64(s.End.Sub(s.Start).Nanoseconds()) / 1e3
+		events = append(events, chromeEvent{
+			Name:  s.Name,
+			Cat:   chromeCategory(s),
+			Phase: "X",
+			TS:    micros(s.Start),
+			Dur:   &dur,
+			PID:   1,
+			TID:   laneOf[s.SpanID],
+			Args:  args,
+		})
+		for _, e := range s.Events {
+			eargs := cloneAttrs(e.Attributes)
+			for k, v := range eargs {
+				if d, ok := v.(time.Duration); ok {
+					eargs[k] = d.String()
+				}
+			}
+			events = append(events, chromeEvent{
+				Name:  e.Name,
+				Cat:   chromeCategory(s),
+				Phase: "i",
+				TS:    micros(e.Time),
+				PID:   1,
+				TID:   laneOf[s.SpanID],
+				Scope: "t",
+				Args:  eargs,
+			})
+		}
+	}
+
+	return map[string]any{"traceEvents": events, "displayTimeUnit": "ms"}
+}
+
+func chromeCategory(s Span) string {
+	switch {
+	case s.ParentID == "":
+		return "command"
+	case s.Attributes["http.method"] != nil:
+		return "http"
+	default:
+		return "gog"
+	}
+}
@@ -0,0 +1,187 @@
+// Package tracing records a command run as a tree of timed spans
+// (command → API call → attempt) and exports it as OTLP-JSON or Chrome trace events.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Tracer collects the spans of one gog invocation. A nil *Tracer is valid and records nothing.
+type Tracer struct {
+	TraceID string
+
+	mu    sync.Mutex
+	spans []*Span
+	root  *Span
+	now   func() time.Time
+}
+
+// Span is one timed operation. Attributes hold string, int, int64, float64 or bool values.
+type Span struct {
+	tracer *Tracer
+
+	Name       string
+	SpanID     string
+	ParentID   string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]any
+	Events     []Event
+	Err        string
+}
+
+// Event is a point-in-time annotation on a span (e.g. a retry decision).
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]any
+}
+
+// New creates a Tracer with a random trace ID.
+func New() *Tracer {
+	return &Tracer{TraceID: randomHex(16), now: time.Now}
+}
+
+type tracerKey struct{}
+
+type spanKey struct{}
+
+// WithTracer attaches t to ctx.
+func WithTracer(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// FromContext returns the Tracer attached to ctx, or nil.
+func FromContext(ctx context.Context) *Tracer {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(tracerKey{}).(*Tracer)
+	return t
+}
+
+// SpanFromContext returns the innermost span started on ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// Start begins a span on the tracer in ctx. The parent is the span in ctx, falling back
+// to the tracer's first (root) span. Without a tracer it returns ctx and a nil span.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return FromContext(ctx).Start(ctx, name)
+}
+
+// Start begins a span under the span in ctx (or the root span) and returns a context
+// carrying both the tracer and the new span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+
+	s := &Span{
+		tracer:     t,
+		Name:       name,
+		SpanID:     randomHex(8),
+		Start:      t.now(),
+		Attributes: map[string]any{},
+	}
+
+	t.mu.Lock()
+	parent := SpanFromContext(ctx)
+	if parent == nil || parent.tracer != t {
+		parent = t.root
+	}
+	if parent != nil {
+		s.ParentID = parent.SpanID
+	} else {
+		t.root = s
+	}
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+
+	ctx = context.WithValue(ctx, tracerKey{}, t)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Spans returns a snapshot of all recorded spans in start order.
+func (t *Tracer) Spans() []Span {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]Span, 0, len(t.spans))
+	for _, s := range t.spans {
+		c := *s
+		c.Attributes = cloneAttrs(s.Attributes)
+		c.Events = append([]Event(nil), s.Events...)
+		out = append(out, c)
+	}
+	return out
+}
+
+// SetAttr sets a span attribute. Safe on a nil span.
+func (s *Span) SetAttr(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	s.Attributes[key] = value
+	s.tracer.mu.Unlock()
+}
+
+// AddEvent records a point-in-time event. Safe on a nil span.
+func (s *Span) AddEvent(name string, attrs map[string]any) {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	s.Events = append(s.Events, Event{Name: name, Time: s.tracer.now(), Attributes: cloneAttrs(attrs)})
+	s.tracer.mu.Unlock()
+}
+
+// SetError marks the span as failed. Safe on a nil span or nil error.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	s.Err = err.Error()
+	s.tracer.mu.Unlock()
+}
+
+// Finish ends the span. Calling it more than once keeps the first end time.
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	if s.End.IsZero() {
+		s.End = s.tracer.now()
+	}
+	s.tracer.mu.Unlock()
+}
+
+func cloneAttrs(in map[string]any) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
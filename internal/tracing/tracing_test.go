@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNilTracerIsNoop(t *testing.T) {
+	ctx, span := Start(context.Background(), "x")
+	if span != nil || FromContext(ctx) != nil {
+		t.Fatalf("expected no span without a tracer")
+	}
+	span.SetAttr("k", "v")
+	span.AddEvent("e", nil)
+	span.Finish()
+}
+
+func TestChromeLanesForConcurrentCalls(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := New()
+	tr.now = func() time.Time { return now }
+
+	ctx, root := tr.Start(context.Background(), "gog calendar team")
+
+	// Two overlapping calls need separate lanes; a third after both ends reuses lane 1.
+	_, a := tr.Start(ctx, "GET a")
+	now = now.Add(time.Millisecond)
+	bctx, b := tr.Start(ctx, "GET b")
+	_, attempt := tr.Start(bctx, "attempt 1")
+	now = now.Add(time.Millisecond)
+	attempt.Finish()
+	a.Finish()
+	b.Finish()
+	_, c := tr.Start(context.Background(), "GET c") // no span in ctx: parented to root
+	now = now.Add(time.Millisecond)
+	c.Finish()
+	root.Finish()
+
+	if c.ParentID != root.SpanID {
+		t.Fatalf("expected root fallback parent")
+	}
+
+	var buf bytes.Buffer
+	if err := tr.Write(&buf, FormatChrome); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var doc struct {
+		TraceEvents []struct {
+			Name string  `json:"name"`
+			TID  int     `json:"tid"`
+			TS   float64 `json:"ts"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	lanes := map[string]int{}
+	for _, e := range doc.TraceEvents {
+		lanes[e.Name] = e.TID
+	}
+	want := map[string]int{"gog calendar team": 0, "GET a": 1, "GET b": 2, "attempt 1": 2, "GET c": 1}
+	for name, lane := range want {
+		if lanes[name] != lane {
+			t.Fatalf("lane for %q = %d, want %d (all: %v)", name, lanes[name], lane, lanes)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatOTLP, "OTLP": FormatOTLP, "chrome": FormatChrome, "perfetto": FormatChrome} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Fatalf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}